@@ -23,6 +23,7 @@ import (
 	"github.com/RHsyseng/operator-utils/pkg/olm"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -56,6 +57,15 @@ const (
 	defaultApicastRegistryURL   = "http://apicast-staging:8090/policies"
 )
 
+const (
+	// DeploymentConfigWorkloadType manages 3scale components as OpenShift
+	// DeploymentConfigs fed by ImageStreams
+	DeploymentConfigWorkloadType = "DeploymentConfig"
+	// DeploymentWorkloadType manages 3scale components as apps/v1 Deployments
+	// referencing the container images directly
+	DeploymentWorkloadType = "Deployment"
+)
+
 const (
 	DefaultHTTPPort  int32 = 8080
 	DefaultHTTPSPort int32 = 8443
@@ -84,6 +94,8 @@ type APIManagerSpec struct {
 	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
 	// +optional
 	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+	// +optional
+	Ingress *IngressSpec `json:"ingress,omitempty"`
 }
 
 // APIManagerStatus defines the observed state of APIManager
@@ -166,6 +178,13 @@ type APIManagerCommonSpec struct {
 	ResourceRequirementsEnabled *bool `json:"resourceRequirementsEnabled,omitempty"`
 	// +optional
 	ImagePullSecrets []v1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// WorkloadType selects the kind of workload objects the operator manages.
+	// DeploymentConfig (default) requires the OpenShift apps and image APIs.
+	// Deployment renders apps/v1 Deployments with plain image references and
+	// does not create any ImageStream.
+	// +kubebuilder:validation:Enum=DeploymentConfig;Deployment
+	// +optional
+	WorkloadType *string `json:"workloadType,omitempty"`
 }
 
 // CustomEnvironmentSpec contains or has reference to an APIcast custom environment
@@ -260,6 +279,10 @@ type ApicastProductionSpec struct {
 	// * character, which matches all hosts, effectively disables the proxy.
 	// +optional
 	NoProxy *string `json:"noProxy,omitempty"` // NO_PROXY
+	// HPA enables a HorizontalPodAutoscaler managing the number of replicas.
+	// The replicas field is not reconciled while it is set.
+	// +optional
+	HPA *HorizontalPodAutoscalerSpec `json:"hpa,omitempty"`
 }
 
 type ApicastStagingSpec struct {
@@ -352,6 +375,10 @@ type BackendListenerSpec struct {
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
 	// +optional
 	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// HPA enables a HorizontalPodAutoscaler managing the number of replicas.
+	// The replicas field is not reconciled while it is set.
+	// +optional
+	HPA *HorizontalPodAutoscalerSpec `json:"hpa,omitempty"`
 }
 
 type BackendWorkerSpec struct {
@@ -363,6 +390,10 @@ type BackendWorkerSpec struct {
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
 	// +optional
 	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// HPA enables a HorizontalPodAutoscaler managing the number of replicas.
+	// The replicas field is not reconciled while it is set.
+	// +optional
+	HPA *HorizontalPodAutoscalerSpec `json:"hpa,omitempty"`
 }
 
 type BackendCronSpec struct {
@@ -438,6 +469,10 @@ type SystemAppSpec struct {
 	ProviderContainerResources *v1.ResourceRequirements `json:"providerContainerResources,omitempty"`
 	// +optional
 	DeveloperContainerResources *v1.ResourceRequirements `json:"developerContainerResources,omitempty"`
+	// HPA enables a HorizontalPodAutoscaler managing the number of replicas.
+	// The replicas field is not reconciled while it is set.
+	// +optional
+	HPA *HorizontalPodAutoscalerSpec `json:"hpa,omitempty"`
 }
 
 type SystemSidekiqSpec struct {
@@ -635,10 +670,83 @@ type ExternalZyncComponents struct {
 	Database *bool `json:"database,omitempty"`
 }
 
+// HorizontalPodAutoscalerSpec defines the autoscaling of a component
+type HorizontalPodAutoscalerSpec struct {
+	// MinReplicas is the lower limit for the number of replicas. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the upper limit for the number of replicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// CPUAverageUtilization is the target average CPU utilization,
+	// as a percentage of the requested CPU of the pods.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CPUAverageUtilization *int32 `json:"cpuAverageUtilization,omitempty"`
+	// MemoryAverageUtilization is the target average memory utilization,
+	// as a percentage of the requested memory of the pods.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MemoryAverageUtilization *int32 `json:"memoryAverageUtilization,omitempty"`
+	// Metrics contains additional metrics (pods, object or external metrics
+	// served by a custom metrics adapter) used to calculate the desired replica count.
+	// +optional
+	Metrics []autoscalingv2beta2.MetricSpec `json:"metrics,omitempty"`
+}
+
+func (h *HorizontalPodAutoscalerSpec) Validate(fldPath *field.Path) field.ErrorList {
+	fieldErrors := field.ErrorList{}
+
+	if h.MaxReplicas < 1 {
+		fieldErrors = append(fieldErrors, field.Invalid(fldPath.Child("maxReplicas"), h.MaxReplicas, "must be greater than or equal to 1"))
+	}
+
+	if h.MinReplicas != nil && *h.MinReplicas > h.MaxReplicas {
+		fieldErrors = append(fieldErrors, field.Invalid(fldPath.Child("minReplicas"), *h.MinReplicas, "must be less than or equal to maxReplicas"))
+	}
+
+	return fieldErrors
+}
+
 type PodDisruptionBudgetSpec struct {
 	Enabled bool `json:"enabled,omitempty"`
 }
 
+// IngressSpec defines the Kubernetes Ingress objects exposing the 3scale endpoints
+type IngressSpec struct {
+	// Enabled exposes the endpoints through Ingress objects instead of OpenShift Routes
+	Enabled bool `json:"enabled,omitempty"`
+	// IngressClassName is the name of the IngressClass handling the Ingress objects
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// Annotations are added to all the Ingress objects
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// TLSSecretRef references the secret with the TLS certificate used for the default hosts
+	// +optional
+	TLSSecretRef *v1.LocalObjectReference `json:"tlsSecretRef,omitempty"`
+	// Tenants exposes the admin and developer portals of additional tenants
+	// +optional
+	Tenants []IngressTenantSpec `json:"tenants,omitempty"`
+}
+
+// IngressTenantSpec defines the portal hosts of a tenant
+type IngressTenantSpec struct {
+	// Name identifies the tenant. Used to name its Ingress objects
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// AdminHost is the host of the tenant admin portal
+	AdminHost string `json:"adminHost"`
+	// DeveloperHost is the host of the tenant developer portal
+	// +optional
+	DeveloperHost *string `json:"developerHost,omitempty"`
+	// TLSSecretRef references the secret with the TLS certificate used for the tenant hosts.
+	// Defaults to the ingress TLSSecretRef
+	// +optional
+	TLSSecretRef *v1.LocalObjectReference `json:"tlsSecretRef,omitempty"`
+}
+
 type MonitoringSpec struct {
 	Enabled bool `json:"enabled,omitempty"`
 	// +optional
@@ -1063,6 +1171,30 @@ func (apimanager *APIManager) IsPDBEnabled() bool {
 	return apimanager.Spec.PodDisruptionBudget != nil && apimanager.Spec.PodDisruptionBudget.Enabled
 }
 
+func (apimanager *APIManager) IsDeploymentWorkloadEnabled() bool {
+	return apimanager.Spec.WorkloadType != nil && *apimanager.Spec.WorkloadType == DeploymentWorkloadType
+}
+
+func (apimanager *APIManager) IsAPIcastProductionHPAEnabled() bool {
+	return apimanager.Spec.Apicast != nil && apimanager.Spec.Apicast.ProductionSpec != nil &&
+		apimanager.Spec.Apicast.ProductionSpec.HPA != nil
+}
+
+func (apimanager *APIManager) IsBackendListenerHPAEnabled() bool {
+	return apimanager.Spec.Backend != nil && apimanager.Spec.Backend.ListenerSpec != nil &&
+		apimanager.Spec.Backend.ListenerSpec.HPA != nil
+}
+
+func (apimanager *APIManager) IsBackendWorkerHPAEnabled() bool {
+	return apimanager.Spec.Backend != nil && apimanager.Spec.Backend.WorkerSpec != nil &&
+		apimanager.Spec.Backend.WorkerSpec.HPA != nil
+}
+
+func (apimanager *APIManager) IsSystemAppHPAEnabled() bool {
+	return apimanager.Spec.System != nil && apimanager.Spec.System.AppSpec != nil &&
+		apimanager.Spec.System.AppSpec.HPA != nil
+}
+
 func (apimanager *APIManager) IsSystemPostgreSQLEnabled() bool {
 	return !apimanager.IsExternal(SystemDatabase) &&
 		apimanager.Spec.System.DatabaseSpec != nil &&
@@ -1073,6 +1205,10 @@ func (apimanager *APIManager) IsSystemMysqlEnabled() bool {
 	return !apimanager.IsExternal(SystemDatabase) && !apimanager.IsSystemPostgreSQLEnabled()
 }
 
+func (apimanager *APIManager) IsIngressEnabled() bool {
+	return apimanager.Spec.Ingress != nil && apimanager.Spec.Ingress.Enabled
+}
+
 func (apimanager *APIManager) IsMonitoringEnabled() bool {
 	return apimanager.Spec.Monitoring != nil && apimanager.Spec.Monitoring.Enabled
 }
@@ -1248,6 +1384,42 @@ func (apimanager *APIManager) Validate() field.ErrorList {
 		}
 	}
 
+	if apimanager.IsAPIcastProductionHPAEnabled() {
+		hpaFldPath := specFldPath.Child("apicast").Child("productionSpec").Child("hpa")
+		fieldErrors = append(fieldErrors, apimanager.Spec.Apicast.ProductionSpec.HPA.Validate(hpaFldPath)...)
+	}
+
+	if apimanager.IsBackendListenerHPAEnabled() {
+		hpaFldPath := specFldPath.Child("backend").Child("listenerSpec").Child("hpa")
+		fieldErrors = append(fieldErrors, apimanager.Spec.Backend.ListenerSpec.HPA.Validate(hpaFldPath)...)
+	}
+
+	if apimanager.IsBackendWorkerHPAEnabled() {
+		hpaFldPath := specFldPath.Child("backend").Child("workerSpec").Child("hpa")
+		fieldErrors = append(fieldErrors, apimanager.Spec.Backend.WorkerSpec.HPA.Validate(hpaFldPath)...)
+	}
+
+	if apimanager.IsSystemAppHPAEnabled() {
+		hpaFldPath := specFldPath.Child("system").Child("appSpec").Child("hpa")
+		fieldErrors = append(fieldErrors, apimanager.Spec.System.AppSpec.HPA.Validate(hpaFldPath)...)
+	}
+
+	if apimanager.IsIngressEnabled() {
+		tenantsFldPath := specFldPath.Child("ingress").Child("tenants")
+		tenantNames := map[string]interface{}{}
+		for idx, tenant := range apimanager.Spec.Ingress.Tenants {
+			tenantIdxFldPath := tenantsFldPath.Index(idx)
+			if _, ok := tenantNames[tenant.Name]; ok {
+				fieldErrors = append(fieldErrors, field.Duplicate(tenantIdxFldPath.Child("name"), tenant.Name))
+			}
+			tenantNames[tenant.Name] = nil
+
+			if tenant.AdminHost == "" {
+				fieldErrors = append(fieldErrors, field.Required(tenantIdxFldPath.Child("adminHost"), "tenant admin host not provided"))
+			}
+		}
+	}
+
 	return fieldErrors
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	"github.com/3scale/3scale-operator/version"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 func TestSetDefaults(t *testing.T) {
@@ -176,6 +177,33 @@ func TestZyncExternalDatabaseIsEnabled(t *testing.T) {
 	}
 }
 
+func TestHorizontalPodAutoscalerSpecValidate(t *testing.T) {
+	var (
+		oneValue   int32 = 1
+		threeValue int32 = 3
+	)
+
+	cases := []struct {
+		testName       string
+		spec           *HorizontalPodAutoscalerSpec
+		expectedErrors int
+	}{
+		{"MaxReplicasOnly", &HorizontalPodAutoscalerSpec{MaxReplicas: 2}, 0},
+		{"MinReplicasLowerThanMax", &HorizontalPodAutoscalerSpec{MinReplicas: &oneValue, MaxReplicas: 2}, 0},
+		{"MinReplicasGreaterThanMax", &HorizontalPodAutoscalerSpec{MinReplicas: &threeValue, MaxReplicas: 2}, 1},
+		{"MaxReplicasZero", &HorizontalPodAutoscalerSpec{}, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			errs := tc.spec.Validate(field.NewPath("hpa"))
+			if len(errs) != tc.expectedErrors {
+				subT.Errorf("Expected %d errors, received: %v", tc.expectedErrors, errs)
+			}
+		})
+	}
+}
+
 func minimumAPIManagerTest() *APIManager {
 	return &APIManager{
 		Spec: APIManagerSpec{
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -30,6 +31,48 @@ type APIManagerBackupSpec struct {
 
 	// Backup data destination configuration
 	BackupDestination APIManagerBackupDestination `json:"backupDestination"`
+
+	// Data of the databases managed by the operator to be included
+	// in the backup. Databases configured as external components are
+	// never included
+	// +optional
+	Data *APIManagerBackupDataSpec `json:"data,omitempty"`
+}
+
+// APIManagerBackupDataSpec defines which databases managed by the
+// operator are backed up
+type APIManagerBackupDataSpec struct {
+	// Dump of the system database. mysqldump or pg_dump is used
+	// depending on the configured system database
+	// +optional
+	SystemDatabase *bool `json:"systemDatabase,omitempty"`
+	// Dump of the zync database, using pg_dump
+	// +optional
+	ZyncDatabase *bool `json:"zyncDatabase,omitempty"`
+	// RDB snapshot of the backend Redis
+	// +optional
+	BackendRedis *bool `json:"backendRedis,omitempty"`
+	// RDB snapshot of the system Redis
+	// +optional
+	SystemRedis *bool `json:"systemRedis,omitempty"`
+}
+
+// APIManagerDataArtifacts lists the database data artifacts of a backup.
+// Each field holds the path of the artifact, relative to the root
+// of the backup data
+type APIManagerDataArtifacts struct {
+	// System database dump
+	// +optional
+	SystemDatabase *string `json:"systemDatabase,omitempty"`
+	// Zync database dump
+	// +optional
+	ZyncDatabase *string `json:"zyncDatabase,omitempty"`
+	// Backend Redis RDB snapshot
+	// +optional
+	BackendRedis *string `json:"backendRedis,omitempty"`
+	// System Redis RDB snapshot
+	// +optional
+	SystemRedis *string `json:"systemRedis,omitempty"`
 }
 
 // APIManagerBackupDestination defines the backup data destination
@@ -39,6 +82,44 @@ type APIManagerBackupDestination struct {
 	// PersistentVolumeClaim as backup data destination configuration
 	// +optional
 	PersistentVolumeClaim *PersistentVolumeClaimBackupDestination `json:"persistentVolumeClaim,omitempty"`
+	// S3 API-compatible object storage as backup data destination
+	// +optional
+	S3 *S3BackupDestination `json:"s3,omitempty"`
+}
+
+// S3Location defines an S3 API-compatible object storage location
+// and how to access it
+type S3Location struct {
+	// Name of the bucket
+	Bucket string `json:"bucket"`
+	// Prefix of the object keys
+	// +optional
+	Prefix *string `json:"prefix,omitempty"`
+	// Region of the bucket. Defaults to us-east-1
+	// +optional
+	Region *string `json:"region,omitempty"`
+	// URL of the S3 API endpoint. Set it for S3 API-compatible
+	// storages other than AWS S3
+	// +optional
+	Endpoint *string `json:"endpoint,omitempty"`
+	// Secret with the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY keys
+	CredentialsSecretRef v1.LocalObjectReference `json:"credentialsSecretRef"`
+	// Secret with the PEM encoded CA certificates, in the ca-bundle.crt key,
+	// trusted to verify the endpoint certificate
+	// +optional
+	CABundleSecretRef *v1.LocalObjectReference `json:"caBundleSecretRef,omitempty"`
+}
+
+// S3BackupDestination defines the configuration of the S3 API-compatible
+// object storage used as the backup data destination. The backup data is
+// stored as a gzipped tar archive with the <prefix>/<APIManagerBackup name>.tar.gz key
+type S3BackupDestination struct {
+	S3Location `json:",inline"`
+	// Temporary PersistentVolumeClaim where the backup data is gathered
+	// before being uploaded. Defaults to a 10Gi PersistentVolumeClaim
+	// of the default storage class
+	// +optional
+	WorkVolume *PersistentVolumeClaimBackupDestination `json:"workVolume,omitempty"`
 }
 
 // PersistentVolumeClaimBackupDestination defines the configuration
@@ -71,6 +152,15 @@ type APIManagerBackupStatus struct {
 	// +optional
 	Completed *bool `json:"completed,omitempty"`
 
+	// Set to true when one of the backup steps has failed. A failed
+	// backup is not reconciled anymore
+	// +optional
+	Failed *bool `json:"failed,omitempty"`
+
+	// Reason of the backup failure
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
 	// Set to true when main steps have been completed. At this point
 	// backup still cannot be considered  fully completed due to some remaining
 	// post-backup tasks are pending (cleanup, ...)
@@ -92,6 +182,15 @@ type APIManagerBackupStatus struct {
 	// PersistentVolumeClaim is used as the backup data destination
 	// +optional
 	BackupPersistentVolumeClaimName *string `json:"backupPersistentVolumeClaimName,omitempty"`
+
+	// Database data artifacts stored in the backup
+	// +optional
+	DataArtifacts *APIManagerDataArtifacts `json:"dataArtifacts,omitempty"`
+
+	// URL of the backup data archive, in s3://<bucket>/<key> form. Only set
+	// when S3 is used as the backup data destination
+	// +optional
+	BackupS3ObjectURL *string `json:"backupS3ObjectURL,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -116,6 +215,10 @@ func (a *APIManagerBackup) BackupCompleted() bool {
 	return a.Status.Completed != nil && *a.Status.Completed
 }
 
+func (a *APIManagerBackup) BackupFailed() bool {
+	return a.Status.Failed != nil && *a.Status.Failed
+}
+
 func (a *APIManagerBackup) MainStepsCompleted() bool {
 	return a.Status.MainStepsCompleted != nil && *a.Status.MainStepsCompleted
 }
@@ -0,0 +1,182 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// APIManagerBackupScheduleLabelKey is the label of the APIManagerBackup
+	// objects created by an APIManagerBackupSchedule. Its value is the
+	// schedule name
+	APIManagerBackupScheduleLabelKey = "apps.3scale.net/apimanagerbackupschedule"
+
+	// APIManagerBackupScheduledTimeAnnotationKey is the annotation holding
+	// the time, in RFC3339 form, an APIManagerBackup was scheduled for
+	APIManagerBackupScheduledTimeAnnotationKey = "apps.3scale.net/scheduled-time"
+
+	// DefaultActiveBackupDeadlineSeconds is the time, in seconds, a
+	// scheduled backup has to finish when the schedule does not set it
+	DefaultActiveBackupDeadlineSeconds int64 = 86400
+)
+
+// APIManagerBackupScheduleSpec defines the desired state of APIManagerBackupSchedule
+type APIManagerBackupScheduleSpec struct {
+	// Schedule in Cron format. The standard five fields format and the
+	// @hourly, @daily, @weekly and @monthly descriptors are accepted.
+	// Times are evaluated in UTC
+	Schedule string `json:"schedule"`
+
+	// Suspend stops the creation of new backups. Retention rules are
+	// still applied
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// Template of the APIManagerBackup objects created on schedule
+	BackupTemplate APIManagerBackupTemplate `json:"backupTemplate"`
+
+	// Rules selecting the backups to keep. Backups not selected by any
+	// of the rules are deleted together with their backup data. When
+	// not set all backups are kept
+	// +optional
+	Retention *APIManagerBackupRetention `json:"retention,omitempty"`
+
+	// Time, in seconds since its scheduled time, a backup has to finish.
+	// A backup not finished by then is reported as stalled and no longer
+	// prevents the creation of new backups. Defaults to 86400 (one day)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveBackupDeadlineSeconds *int64 `json:"activeBackupDeadlineSeconds,omitempty"`
+}
+
+// APIManagerBackupTemplate describes the APIManagerBackup objects created
+// by an APIManagerBackupSchedule
+type APIManagerBackupTemplate struct {
+	// Labels added to the created APIManagerBackup objects
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Specification of the created APIManagerBackup objects
+	Spec APIManagerBackupSpec `json:"spec"`
+}
+
+// APIManagerBackupRetention defines which completed backups are kept.
+// A backup is kept when it is selected by at least one of the rules
+type APIManagerBackupRetention struct {
+	// Number of most recent completed backups to keep
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepLast *int32 `json:"keepLast,omitempty"`
+
+	// Number of days for which the most recent completed backup of the
+	// day is kept
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepDaily *int32 `json:"keepDaily,omitempty"`
+
+	// Number of weeks for which the most recent completed backup of the
+	// week is kept
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepWeekly *int32 `json:"keepWeekly,omitempty"`
+}
+
+// APIManagerBackupScheduleStatus defines the observed state of APIManagerBackupSchedule
+type APIManagerBackupScheduleStatus struct {
+	// Time the last backup was scheduled for
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// Time the next backup is scheduled for
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// Name of the backup being performed
+	// +optional
+	ActiveBackupName *string `json:"activeBackupName,omitempty"`
+
+	// Name of the most recent successfully completed backup
+	// +optional
+	LastSuccessfulBackupName *string `json:"lastSuccessfulBackupName,omitempty"`
+
+	// Completion time of the most recent successfully completed backup
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// Name of the most recent failed backup
+	// +optional
+	LastFailedBackupName *string `json:"lastFailedBackupName,omitempty"`
+
+	// Failure reason of the most recent failed backup
+	// +optional
+	LastFailureMessage *string `json:"lastFailureMessage,omitempty"`
+
+	// Error found when evaluating the schedule
+	// +optional
+	ScheduleError *string `json:"scheduleError,omitempty"`
+
+	// Error found when deleting the data of a pruned backup
+	// +optional
+	PruneError *string `json:"pruneError,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// APIManagerBackupSchedule creates APIManagerBackup objects on a schedule
+// and prunes them according to retention rules
+// +kubebuilder:resource:path=apimanagerbackupschedules,scope=Namespaced
+// +kubebuilder:printcolumn:JSONPath=".spec.schedule",name=Schedule,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.lastScheduleTime",name="Last Schedule",type=date
+// +kubebuilder:printcolumn:JSONPath=".status.lastSuccessfulBackupName",name="Last Successful",type=string
+// +operator-sdk:csv:customresourcedefinitions:displayName="APIManagerBackupSchedule"
+type APIManagerBackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   APIManagerBackupScheduleSpec   `json:"spec,omitempty"`
+	Status APIManagerBackupScheduleStatus `json:"status,omitempty"`
+}
+
+func (a *APIManagerBackupSchedule) IsSuspended() bool {
+	return a.Spec.Suspend != nil && *a.Spec.Suspend
+}
+
+// ActiveBackupDeadline returns the time a scheduled backup has to finish
+func (a *APIManagerBackupSchedule) ActiveBackupDeadline() time.Duration {
+	deadlineSeconds := DefaultActiveBackupDeadlineSeconds
+	if a.Spec.ActiveBackupDeadlineSeconds != nil {
+		deadlineSeconds = *a.Spec.ActiveBackupDeadlineSeconds
+	}
+	return time.Duration(deadlineSeconds) * time.Second
+}
+
+// +kubebuilder:object:root=true
+
+// APIManagerBackupScheduleList contains a list of APIManagerBackupSchedule
+type APIManagerBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []APIManagerBackupSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&APIManagerBackupSchedule{}, &APIManagerBackupScheduleList{})
+}
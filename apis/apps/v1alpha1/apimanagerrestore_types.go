@@ -39,6 +39,23 @@ type APIManagerRestoreSource struct {
 	// +optional
 	// Restore data soure configuration
 	PersistentVolumeClaim *PersistentVolumeClaimRestoreSource `json:"persistentVolumeClaim,omitempty"`
+	// S3 API-compatible object storage restore data source configuration
+	// +optional
+	S3 *S3RestoreSource `json:"s3,omitempty"`
+}
+
+// S3RestoreSource defines the configuration of the S3 API-compatible
+// object storage holding the backup data archive to restore
+type S3RestoreSource struct {
+	S3Location `json:",inline"`
+	// Name of the APIManagerBackup that uploaded the backup data archive.
+	// The archive with the <prefix>/<backupName>.tar.gz key is restored
+	BackupName string `json:"backupName"`
+	// Temporary PersistentVolumeClaim where the backup data archive is
+	// extracted. Defaults to a 10Gi PersistentVolumeClaim of the default
+	// storage class
+	// +optional
+	WorkVolume *PersistentVolumeClaimBackupDestination `json:"workVolume,omitempty"`
 }
 
 // PersistentVolumeClaimRestoreSource defines the configuration
@@ -75,6 +92,10 @@ type APIManagerRestoreStatus struct {
 	// Restore completion time. It is represented in RFC3339 form and is in UTC.
 	// +optional
 	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Database data artifacts of the backup that have been restored
+	// +optional
+	RestoredDataArtifacts *APIManagerDataArtifacts `json:"restoredDataArtifacts,omitempty"`
 }
 
 // +kubebuilder:object:root=true
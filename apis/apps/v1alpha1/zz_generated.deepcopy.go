@@ -23,6 +23,7 @@ package v1alpha1
 
 import (
 	"github.com/3scale/3scale-operator/pkg/common"
+	"k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -81,6 +82,41 @@ func (in *APIManagerBackup) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackupDataSpec) DeepCopyInto(out *APIManagerBackupDataSpec) {
+	*out = *in
+	if in.SystemDatabase != nil {
+		in, out := &in.SystemDatabase, &out.SystemDatabase
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ZyncDatabase != nil {
+		in, out := &in.ZyncDatabase, &out.ZyncDatabase
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BackendRedis != nil {
+		in, out := &in.BackendRedis, &out.BackendRedis
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SystemRedis != nil {
+		in, out := &in.SystemRedis, &out.SystemRedis
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupDataSpec.
+func (in *APIManagerBackupDataSpec) DeepCopy() *APIManagerBackupDataSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackupDataSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIManagerBackupDestination) DeepCopyInto(out *APIManagerBackupDestination) {
 	*out = *in
@@ -89,6 +125,11 @@ func (in *APIManagerBackupDestination) DeepCopyInto(out *APIManagerBackupDestina
 		*out = new(PersistentVolumeClaimBackupDestination)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupDestination)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupDestination.
@@ -133,10 +174,192 @@ func (in *APIManagerBackupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackupRetention) DeepCopyInto(out *APIManagerBackupRetention) {
+	*out = *in
+	if in.KeepLast != nil {
+		in, out := &in.KeepLast, &out.KeepLast
+		*out = new(int32)
+		**out = **in
+	}
+	if in.KeepDaily != nil {
+		in, out := &in.KeepDaily, &out.KeepDaily
+		*out = new(int32)
+		**out = **in
+	}
+	if in.KeepWeekly != nil {
+		in, out := &in.KeepWeekly, &out.KeepWeekly
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupRetention.
+func (in *APIManagerBackupRetention) DeepCopy() *APIManagerBackupRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackupRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackupSchedule) DeepCopyInto(out *APIManagerBackupSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupSchedule.
+func (in *APIManagerBackupSchedule) DeepCopy() *APIManagerBackupSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackupSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIManagerBackupSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackupScheduleList) DeepCopyInto(out *APIManagerBackupScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIManagerBackupSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupScheduleList.
+func (in *APIManagerBackupScheduleList) DeepCopy() *APIManagerBackupScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackupScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIManagerBackupScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackupScheduleSpec) DeepCopyInto(out *APIManagerBackupScheduleSpec) {
+	*out = *in
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	in.BackupTemplate.DeepCopyInto(&out.BackupTemplate)
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(APIManagerBackupRetention)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveBackupDeadlineSeconds != nil {
+		in, out := &in.ActiveBackupDeadlineSeconds, &out.ActiveBackupDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupScheduleSpec.
+func (in *APIManagerBackupScheduleSpec) DeepCopy() *APIManagerBackupScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackupScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackupScheduleStatus) DeepCopyInto(out *APIManagerBackupScheduleStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ActiveBackupName != nil {
+		in, out := &in.ActiveBackupName, &out.ActiveBackupName
+		*out = new(string)
+		**out = **in
+	}
+	if in.LastSuccessfulBackupName != nil {
+		in, out := &in.LastSuccessfulBackupName, &out.LastSuccessfulBackupName
+		*out = new(string)
+		**out = **in
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailedBackupName != nil {
+		in, out := &in.LastFailedBackupName, &out.LastFailedBackupName
+		*out = new(string)
+		**out = **in
+	}
+	if in.LastFailureMessage != nil {
+		in, out := &in.LastFailureMessage, &out.LastFailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScheduleError != nil {
+		in, out := &in.ScheduleError, &out.ScheduleError
+		*out = new(string)
+		**out = **in
+	}
+	if in.PruneError != nil {
+		in, out := &in.PruneError, &out.PruneError
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupScheduleStatus.
+func (in *APIManagerBackupScheduleStatus) DeepCopy() *APIManagerBackupScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackupScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIManagerBackupSpec) DeepCopyInto(out *APIManagerBackupSpec) {
 	*out = *in
 	in.BackupDestination.DeepCopyInto(&out.BackupDestination)
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = new(APIManagerBackupDataSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupSpec.
@@ -157,6 +380,16 @@ func (in *APIManagerBackupStatus) DeepCopyInto(out *APIManagerBackupStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
 	if in.MainStepsCompleted != nil {
 		in, out := &in.MainStepsCompleted, &out.MainStepsCompleted
 		*out = new(bool)
@@ -180,6 +413,16 @@ func (in *APIManagerBackupStatus) DeepCopyInto(out *APIManagerBackupStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.DataArtifacts != nil {
+		in, out := &in.DataArtifacts, &out.DataArtifacts
+		*out = new(APIManagerDataArtifacts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupS3ObjectURL != nil {
+		in, out := &in.BackupS3ObjectURL, &out.BackupS3ObjectURL
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupStatus.
@@ -192,6 +435,29 @@ func (in *APIManagerBackupStatus) DeepCopy() *APIManagerBackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackupTemplate) DeepCopyInto(out *APIManagerBackupTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupTemplate.
+func (in *APIManagerBackupTemplate) DeepCopy() *APIManagerBackupTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackupTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIManagerCommonSpec) DeepCopyInto(out *APIManagerCommonSpec) {
 	*out = *in
@@ -220,6 +486,11 @@ func (in *APIManagerCommonSpec) DeepCopyInto(out *APIManagerCommonSpec) {
 		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.WorkloadType != nil {
+		in, out := &in.WorkloadType, &out.WorkloadType
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerCommonSpec.
@@ -232,6 +503,41 @@ func (in *APIManagerCommonSpec) DeepCopy() *APIManagerCommonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerDataArtifacts) DeepCopyInto(out *APIManagerDataArtifacts) {
+	*out = *in
+	if in.SystemDatabase != nil {
+		in, out := &in.SystemDatabase, &out.SystemDatabase
+		*out = new(string)
+		**out = **in
+	}
+	if in.ZyncDatabase != nil {
+		in, out := &in.ZyncDatabase, &out.ZyncDatabase
+		*out = new(string)
+		**out = **in
+	}
+	if in.BackendRedis != nil {
+		in, out := &in.BackendRedis, &out.BackendRedis
+		*out = new(string)
+		**out = **in
+	}
+	if in.SystemRedis != nil {
+		in, out := &in.SystemRedis, &out.SystemRedis
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerDataArtifacts.
+func (in *APIManagerDataArtifacts) DeepCopy() *APIManagerDataArtifacts {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerDataArtifacts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIManagerList) DeepCopyInto(out *APIManagerList) {
 	*out = *in
@@ -331,6 +637,11 @@ func (in *APIManagerRestoreSource) DeepCopyInto(out *APIManagerRestoreSource) {
 		*out = new(PersistentVolumeClaimRestoreSource)
 		**out = **in
 	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3RestoreSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerRestoreSource.
@@ -385,6 +696,11 @@ func (in *APIManagerRestoreStatus) DeepCopyInto(out *APIManagerRestoreStatus) {
 		in, out := &in.CompletionTime, &out.CompletionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.RestoredDataArtifacts != nil {
+		in, out := &in.RestoredDataArtifacts, &out.RestoredDataArtifacts
+		*out = new(APIManagerDataArtifacts)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerRestoreStatus.
@@ -441,6 +757,11 @@ func (in *APIManagerSpec) DeepCopyInto(out *APIManagerSpec) {
 		*out = new(MonitoringSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerSpec.
@@ -595,6 +916,11 @@ func (in *ApicastProductionSpec) DeepCopyInto(out *ApicastProductionSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.HPA != nil {
+		in, out := &in.HPA, &out.HPA
+		*out = new(HorizontalPodAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApicastProductionSpec.
@@ -815,6 +1141,11 @@ func (in *BackendListenerSpec) DeepCopyInto(out *BackendListenerSpec) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HPA != nil {
+		in, out := &in.HPA, &out.HPA
+		*out = new(HorizontalPodAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendListenerSpec.
@@ -934,6 +1265,11 @@ func (in *BackendWorkerSpec) DeepCopyInto(out *BackendWorkerSpec) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HPA != nil {
+		in, out := &in.HPA, &out.HPA
+		*out = new(HorizontalPodAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendWorkerSpec.
@@ -1117,6 +1453,107 @@ func (in *HighAvailabilitySpec) DeepCopy() *HighAvailabilitySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HorizontalPodAutoscalerSpec) DeepCopyInto(out *HorizontalPodAutoscalerSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CPUAverageUtilization != nil {
+		in, out := &in.CPUAverageUtilization, &out.CPUAverageUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MemoryAverageUtilization != nil {
+		in, out := &in.MemoryAverageUtilization, &out.MemoryAverageUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]v2beta2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HorizontalPodAutoscalerSpec.
+func (in *HorizontalPodAutoscalerSpec) DeepCopy() *HorizontalPodAutoscalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HorizontalPodAutoscalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLSSecretRef != nil {
+		in, out := &in.TLSSecretRef, &out.TLSSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = make([]IngressTenantSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressSpec.
+func (in *IngressSpec) DeepCopy() *IngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressTenantSpec) DeepCopyInto(out *IngressTenantSpec) {
+	*out = *in
+	if in.DeveloperHost != nil {
+		in, out := &in.DeveloperHost, &out.DeveloperHost
+		*out = new(string)
+		**out = **in
+	}
+	if in.TLSSecretRef != nil {
+		in, out := &in.TLSSecretRef, &out.TLSSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressTenantSpec.
+func (in *IngressTenantSpec) DeepCopy() *IngressTenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressTenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
 	*out = *in
@@ -1214,6 +1651,84 @@ func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BackupDestination) DeepCopyInto(out *S3BackupDestination) {
+	*out = *in
+	in.S3Location.DeepCopyInto(&out.S3Location)
+	if in.WorkVolume != nil {
+		in, out := &in.WorkVolume, &out.WorkVolume
+		*out = new(PersistentVolumeClaimBackupDestination)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupDestination.
+func (in *S3BackupDestination) DeepCopy() *S3BackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(S3BackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Location) DeepCopyInto(out *S3Location) {
+	*out = *in
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(string)
+		**out = **in
+	}
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+	if in.CABundleSecretRef != nil {
+		in, out := &in.CABundleSecretRef, &out.CABundleSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Location.
+func (in *S3Location) DeepCopy() *S3Location {
+	if in == nil {
+		return nil
+	}
+	out := new(S3Location)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3RestoreSource) DeepCopyInto(out *S3RestoreSource) {
+	*out = *in
+	in.S3Location.DeepCopyInto(&out.S3Location)
+	if in.WorkVolume != nil {
+		in, out := &in.WorkVolume, &out.WorkVolume
+		*out = new(PersistentVolumeClaimBackupDestination)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3RestoreSource.
+func (in *S3RestoreSource) DeepCopy() *S3RestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(S3RestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SystemAppSpec) DeepCopyInto(out *SystemAppSpec) {
 	*out = *in
@@ -1249,6 +1764,11 @@ func (in *SystemAppSpec) DeepCopyInto(out *SystemAppSpec) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HPA != nil {
+		in, out := &in.HPA, &out.HPA
+		*out = new(HorizontalPodAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemAppSpec.
@@ -49,6 +49,14 @@ type TenantStatus struct {
 
 	TenantId int64 `json:"tenantId"`
 	AdminId  int64 `json:"adminId"`
+
+	// AdminDomain is the admin portal domain of the tenant
+	// +optional
+	AdminDomain string `json:"adminDomain,omitempty"`
+
+	// DeveloperDomain is the developer portal domain of the tenant
+	// +optional
+	DeveloperDomain string `json:"developerDomain,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -80,7 +80,12 @@ type ActiveDocSpec struct {
 
 	// ProviderAccountRef references account provider credentials
 	// +optional
-	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
+	ProviderAccountRef *ProviderAccountReference `json:"providerAccountRef,omitempty"`
+
+	// DeletionPolicy defines whether the 3scale activedoc is deleted or kept when the custom resource is deleted.
+	// Defaults to Delete
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
 
 	// Name is human readable name for the activedoc
 	Name string `json:"name"`
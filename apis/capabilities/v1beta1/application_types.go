@@ -0,0 +1,263 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	ApplicationKind = "Application"
+
+	// ApplicationInvalidConditionType represents that the combination of configuration
+	// in the spec is not supported. This is not a transient error, but
+	// indicates a state that must be fixed before progress can be made.
+	ApplicationInvalidConditionType common.ConditionType = "Invalid"
+
+	// ApplicationOrphanConditionType represents that the configuration in the spec
+	// contains reference to non existing resource.
+	// This is (should be) a transient error, but
+	// indicates a state that must be fixed before progress can be made.
+	// Example: the ApplicationSpec references non existing application plan
+	ApplicationOrphanConditionType common.ConditionType = "Orphan"
+
+	// ApplicationReadyConditionType indicates the application has been successfully synchronized.
+	// Steady state
+	ApplicationReadyConditionType common.ConditionType = "Ready"
+
+	// ApplicationFailedConditionType indicates that an error occurred during synchronization.
+	// The operator will retry.
+	ApplicationFailedConditionType common.ConditionType = "Failed"
+
+	// ApplicationUserKeySecretField indicates the secret field name with the application user key
+	ApplicationUserKeySecretField = "user_key"
+
+	// ApplicationAppIDSecretField indicates the secret field name with the application id
+	ApplicationAppIDSecretField = "app_id"
+
+	// ApplicationAppKeySecretField indicates the secret field name with the application key
+	ApplicationAppKeySecretField = "app_key"
+)
+
+// ApplicationSpec defines the desired state of Application
+type ApplicationSpec struct {
+	// AccountCR is the reference to the developer account custom resource the application belongs to
+	AccountCR corev1.LocalObjectReference `json:"accountCR"`
+
+	// ProductCR is the reference to the product custom resource the application subscribes to
+	ProductCR corev1.LocalObjectReference `json:"productCR"`
+
+	// ApplicationPlanName is the system name of the product's application plan
+	ApplicationPlanName string `json:"applicationPlanName"`
+
+	// Name is the human readable name of the application
+	Name string `json:"name"`
+
+	// Description is the human readable text of the application
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Suspend defines the desired state. Defaults to "false", ie, live
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// AuthSecretRef references a secret with the credentials to set to the application.
+	// Fields: "user_key" for user key authentication, "app_id" and "app_key" for app id authentication.
+	// Credentials not provided are generated by 3scale.
+	// +optional
+	AuthSecretRef *corev1.LocalObjectReference `json:"authSecretRef,omitempty"`
+
+	// CredentialsSecretName is the name of the secret the application credentials are written to.
+	// Defaults to "<application CR name>-credentials"
+	// +optional
+	CredentialsSecretName *string `json:"credentialsSecretName,omitempty"`
+
+	// ProviderAccountRef references account provider credentials
+	// +optional
+	ProviderAccountRef *ProviderAccountReference `json:"providerAccountRef,omitempty"`
+}
+
+// ApplicationStatus defines the observed state of Application
+type ApplicationStatus struct {
+	// +optional
+	ID *int64 `json:"applicationID,omitempty"`
+
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// +optional
+	AccountID *int64 `json:"accountID,omitempty"`
+
+	// +optional
+	ProductID *int64 `json:"productID,omitempty"`
+
+	// +optional
+	PlanID *int64 `json:"planID,omitempty"`
+
+	// CredentialsSecretName is the name of the secret with the application credentials
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+
+	// 3scale control plane host
+	// +optional
+	ProviderAccountHost string `json:"providerAccountHost,omitempty"`
+
+	// ObservedGeneration reflects the generation of the most recently observed Application Spec.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Current state of the 3scale application.
+	// Conditions represent the latest available observations of an object's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions common.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,2,rep,name=conditions"`
+}
+
+func (a *ApplicationStatus) Equals(other *ApplicationStatus, logger logr.Logger) bool {
+	if !reflect.DeepEqual(a.ID, other.ID) {
+		diff := cmp.Diff(a.ID, other.ID)
+		logger.V(1).Info("ID not equal", "difference", diff)
+		return false
+	}
+
+	if a.State != other.State {
+		diff := cmp.Diff(a.State, other.State)
+		logger.V(1).Info("State not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(a.AccountID, other.AccountID) {
+		diff := cmp.Diff(a.AccountID, other.AccountID)
+		logger.V(1).Info("AccountID not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(a.ProductID, other.ProductID) {
+		diff := cmp.Diff(a.ProductID, other.ProductID)
+		logger.V(1).Info("ProductID not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(a.PlanID, other.PlanID) {
+		diff := cmp.Diff(a.PlanID, other.PlanID)
+		logger.V(1).Info("PlanID not equal", "difference", diff)
+		return false
+	}
+
+	if a.CredentialsSecretName != other.CredentialsSecretName {
+		diff := cmp.Diff(a.CredentialsSecretName, other.CredentialsSecretName)
+		logger.V(1).Info("CredentialsSecretName not equal", "difference", diff)
+		return false
+	}
+
+	if a.ProviderAccountHost != other.ProviderAccountHost {
+		diff := cmp.Diff(a.ProviderAccountHost, other.ProviderAccountHost)
+		logger.V(1).Info("ProviderAccountHost not equal", "difference", diff)
+		return false
+	}
+
+	if a.ObservedGeneration != other.ObservedGeneration {
+		diff := cmp.Diff(a.ObservedGeneration, other.ObservedGeneration)
+		logger.V(1).Info("ObservedGeneration not equal", "difference", diff)
+		return false
+	}
+
+	// Marshalling sorts by condition type
+	currentMarshaledJSON, _ := a.Conditions.MarshalJSON()
+	otherMarshaledJSON, _ := other.Conditions.MarshalJSON()
+	if string(currentMarshaledJSON) != string(otherMarshaledJSON) {
+		diff := cmp.Diff(string(currentMarshaledJSON), string(otherMarshaledJSON))
+		logger.V(1).Info("Conditions not equal", "difference", diff)
+		return false
+	}
+
+	return true
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Application is the Schema for the applications API
+type Application struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationSpec   `json:"spec,omitempty"`
+	Status ApplicationStatus `json:"status,omitempty"`
+}
+
+func (a *Application) IsOrphan() bool {
+	return a.Status.Conditions.IsTrueFor(ApplicationOrphanConditionType)
+}
+
+// CredentialsSecretName returns the name of the secret the application credentials are written to
+func (a *Application) CredentialsSecretName() string {
+	if a.Spec.CredentialsSecretName != nil && *a.Spec.CredentialsSecretName != "" {
+		return *a.Spec.CredentialsSecretName
+	}
+	return a.Name + "-credentials"
+}
+
+func (a *Application) Validate() field.ErrorList {
+	errors := field.ErrorList{}
+	specFldPath := field.NewPath("spec")
+
+	if a.Spec.AccountCR.Name == "" {
+		errors = append(errors, field.Required(specFldPath.Child("accountCR").Child("name"), "account reference name must not be empty"))
+	}
+
+	if a.Spec.ProductCR.Name == "" {
+		errors = append(errors, field.Required(specFldPath.Child("productCR").Child("name"), "product reference name must not be empty"))
+	}
+
+	if a.Spec.ApplicationPlanName == "" {
+		errors = append(errors, field.Required(specFldPath.Child("applicationPlanName"), "application plan name must not be empty"))
+	}
+
+	if a.Spec.Name == "" {
+		errors = append(errors, field.Required(specFldPath.Child("name"), "application name must not be empty"))
+	}
+
+	// The credentials secret is owned by the application, it cannot be the user provided one
+	if a.Spec.AuthSecretRef != nil && a.Spec.AuthSecretRef.Name == a.CredentialsSecretName() {
+		errors = append(errors, field.Invalid(specFldPath.Child("authSecretRef"), a.Spec.AuthSecretRef, "auth secret must be different from the credentials secret"))
+	}
+
+	return errors
+}
+
+// +kubebuilder:object:root=true
+
+// ApplicationList contains a list of Application
+type ApplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Application `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Application{}, &ApplicationList{})
+}
@@ -0,0 +1,60 @@
+package v1beta1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testingApplication() Application {
+	return Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec: ApplicationSpec{
+			AccountCR:           corev1.LocalObjectReference{Name: "account"},
+			ProductCR:           corev1.LocalObjectReference{Name: "product"},
+			ApplicationPlanName: "plan01",
+			Name:                "my app",
+		},
+	}
+}
+
+func TestApplicationValid(t *testing.T) {
+	application := testingApplication()
+
+	errors := application.Validate()
+	if len(errors) > 0 {
+		t.Errorf("application is invalid: %s", errors.ToAggregate().Error())
+	}
+}
+
+func TestValidateApplicationMissingFields(t *testing.T) {
+	application := testingApplication()
+	application.Spec.ProductCR.Name = ""
+	application.Spec.ApplicationPlanName = ""
+
+	errors := application.Validate()
+	if len(errors) != 2 {
+		t.Fatalf("expected 2 errors, got: %v", errors)
+	}
+}
+
+func TestApplicationCredentialsSecretName(t *testing.T) {
+	application := testingApplication()
+	if application.CredentialsSecretName() != "app-credentials" {
+		t.Errorf("unexpected default credentials secret name: %s", application.CredentialsSecretName())
+	}
+
+	secretName := "mysecret"
+	application.Spec.CredentialsSecretName = &secretName
+	if application.CredentialsSecretName() != secretName {
+		t.Errorf("unexpected credentials secret name: %s", application.CredentialsSecretName())
+	}
+
+	// credentials secret is owned by the application and cannot be the auth secret
+	application.Spec.AuthSecretRef = &corev1.LocalObjectReference{Name: secretName}
+	errors := application.Validate()
+	if len(errors) != 1 {
+		t.Errorf("expected 1 error, got: %v", errors)
+	}
+}
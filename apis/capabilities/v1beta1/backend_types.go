@@ -24,8 +24,8 @@ import (
 	"github.com/3scale/3scale-operator/pkg/common"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -48,6 +48,22 @@ const (
 	// BackendFailedConditionType indicates that an error occurred during synchronization.
 	// The operator will retry.
 	BackendFailedConditionType common.ConditionType = "Failed"
+
+	// BackendDriftedConditionType indicates that the last synchronization found differences
+	// between the 3scale backend and the BackendSpec that were not caused by a spec change.
+	// The differences have been corrected. The condition message lists the sections that differed.
+	// Example: methods modified in the 3scale admin portal
+	BackendDriftedConditionType common.ConditionType = "Drifted"
+
+	// BackendInUseConditionType indicates the backend custom resource has been marked for deletion
+	// and the deletion is on hold because products still reference the backend.
+	// The condition message lists the products.
+	BackendInUseConditionType common.ConditionType = "InUse"
+
+	// BackendPlannedConditionType indicates that the changes to the 3scale backend have been planned
+	// without being applied, as the BackendSpec enables dry run. The planned changes are in the status.
+	// The condition is false when some sections could not be planned. The condition message lists them.
+	BackendPlannedConditionType common.ConditionType = "Planned"
 )
 
 var (
@@ -74,9 +90,17 @@ type BackendSpec struct {
 	// +optional
 	SystemName string `json:"systemName,omitempty"`
 
-	// PrivateBaseURL Private Base URL of the API
+	// PrivateBaseURL Private Base URL of the API.
+	// Exactly one of privateBaseURL or privateServiceRef must be set
 	// +kubebuilder:validation:Pattern=`^https?:\/\/.*$`
-	PrivateBaseURL string `json:"privateBaseURL"`
+	// +optional
+	PrivateBaseURL string `json:"privateBaseURL,omitempty"`
+
+	// PrivateServiceRef references the Kubernetes Service implementing the API.
+	// The Private Base URL is the cluster-local URL of the Service.
+	// Exactly one of privateBaseURL or privateServiceRef must be set
+	// +optional
+	PrivateServiceRef *BackendPrivateServiceReference `json:"privateServiceRef,omitempty"`
 
 	// Description is a human readable text of the backend
 	// +optional
@@ -101,7 +125,46 @@ type BackendSpec struct {
 
 	// ProviderAccountRef references account provider credentials
 	// +optional
-	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
+	ProviderAccountRef *ProviderAccountReference `json:"providerAccountRef,omitempty"`
+
+	// DeletionPolicy defines whether the 3scale backend is deleted or kept when the custom resource is deleted.
+	// Defaults to Delete
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// ResyncPeriod is the period to synchronize the backend again with 3scale,
+	// correcting changes made outside the operator.
+	// Overrides the operator resync period. Zero disables periodic synchronization.
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+
+	// DryRun computes the changes the synchronization would apply to the 3scale backend
+	// and reports them in the status, without applying them.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// BackendPrivateServiceReference references a Kubernetes Service and the port serving the API
+type BackendPrivateServiceReference struct {
+	// Name of the Service
+	Name string `json:"name"`
+
+	// Namespace of the Service. Defaults to the namespace of the backend.
+	// Services in other namespaces must be allowed by the operator
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Port of the Service, either the port name or the port number
+	Port intstr.IntOrString `json:"port"`
+
+	// Scheme of the URL. Defaults to http
+	// +kubebuilder:validation:Enum=http;https
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+
+	// Path appended to the URL, for instance /api
+	// +optional
+	Path string `json:"path,omitempty"`
 }
 
 // BackendStatus defines the observed state of Backend
@@ -116,10 +179,20 @@ type BackendStatus struct {
 	// +optional
 	ProviderAccountHost string `json:"providerAccountHost,omitempty"`
 
+	// PrivateBaseURL is the Private Base URL of the API synchronized with 3scale.
+	// Resolved from the Service when the spec references one
+	// +optional
+	PrivateBaseURL string `json:"privateBaseURL,omitempty"`
+
 	// ObservedGeneration reflects the generation of the most recently observed Backend Spec.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// PlannedChanges lists the changes to the 3scale backend planned by the last synchronization.
+	// Only set when the spec enables dry run.
+	// +optional
+	PlannedChanges []PlannedChange `json:"plannedChanges,omitempty"`
+
 	// Current state of the 3scale backend.
 	// Conditions represent the latest available observations of an object's state
 	// +optional
@@ -141,12 +214,24 @@ func (b *BackendStatus) Equals(other *BackendStatus, logger logr.Logger) bool {
 		return false
 	}
 
+	if b.PrivateBaseURL != other.PrivateBaseURL {
+		diff := cmp.Diff(b.PrivateBaseURL, other.PrivateBaseURL)
+		logger.V(1).Info("PrivateBaseURL not equal", "difference", diff)
+		return false
+	}
+
 	if b.ObservedGeneration != other.ObservedGeneration {
 		diff := cmp.Diff(b.ObservedGeneration, other.ObservedGeneration)
 		logger.V(1).Info("ObservedGeneration not equal", "difference", diff)
 		return false
 	}
 
+	if !reflect.DeepEqual(b.PlannedChanges, other.PlannedChanges) {
+		diff := cmp.Diff(b.PlannedChanges, other.PlannedChanges)
+		logger.V(1).Info("PlannedChanges not equal", "difference", diff)
+		return false
+	}
+
 	// Marshalling sorts by condition type
 	currentMarshaledJSON, _ := b.Conditions.MarshalJSON()
 	otherMarshaledJSON, _ := other.Conditions.MarshalJSON()
@@ -264,6 +349,31 @@ func (backend *Backend) Validate() field.ErrorList {
 			errors = append(errors, field.Invalid(mappingRulesIdxFldPath, spec.MetricMethodRef, "mappingrule does not have valid metric or method reference."))
 		}
 	}
+
+	// Check the private base URL is either set or referenced
+	privateBaseURLFldPath := specFldPath.Child("privateBaseURL")
+	privateServiceRefFldPath := specFldPath.Child("privateServiceRef")
+	if backend.Spec.PrivateBaseURL == "" && backend.Spec.PrivateServiceRef == nil {
+		errors = append(errors, field.Required(privateBaseURLFldPath, "either privateBaseURL or privateServiceRef must be set."))
+	}
+	if backend.Spec.PrivateBaseURL != "" && backend.Spec.PrivateServiceRef != nil {
+		errors = append(errors, field.Invalid(privateServiceRefFldPath, backend.Spec.PrivateServiceRef.Name, "privateBaseURL and privateServiceRef are mutually exclusive."))
+	}
+	if ref := backend.Spec.PrivateServiceRef; ref != nil {
+		if ref.Name == "" {
+			errors = append(errors, field.Required(privateServiceRefFldPath.Child("name"), "service name is required."))
+		}
+		if ref.Port.String() == "" || ref.Port.String() == "0" {
+			errors = append(errors, field.Required(privateServiceRefFldPath.Child("port"), "service port name or number is required."))
+		}
+		if ref.Path != "" && !strings.HasPrefix(ref.Path, "/") {
+			errors = append(errors, field.Invalid(privateServiceRefFldPath.Child("path"), ref.Path, "path must start with '/'."))
+		}
+	}
+
+	if backend.Spec.ResyncPeriod != nil && backend.Spec.ResyncPeriod.Duration < 0 {
+		errors = append(errors, field.Invalid(specFldPath.Child("resyncPeriod"), backend.Spec.ResyncPeriod.Duration.String(), "resync period cannot be negative."))
+	}
 	return errors
 }
 
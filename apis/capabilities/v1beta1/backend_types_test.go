@@ -0,0 +1,42 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestValidateBackendPrivateBaseURL(t *testing.T) {
+	cases := []struct {
+		testName          string
+		privateBaseURL    string
+		privateServiceRef *BackendPrivateServiceReference
+		expectedErrors    int
+	}{
+		{"Private base URL", "https://api.example.com", nil, 0},
+		{"Private service by port name", "", &BackendPrivateServiceReference{Name: "echo-api", Port: intstr.FromString("http")}, 0},
+		{"Private service by port number", "", &BackendPrivateServiceReference{Name: "echo-api", Port: intstr.FromInt(8080), Path: "/v1"}, 0},
+		{"Neither set", "", nil, 1},
+		{"Both set", "https://api.example.com", &BackendPrivateServiceReference{Name: "echo-api", Port: intstr.FromInt(8080)}, 1},
+		{"Missing service name and port", "", &BackendPrivateServiceReference{}, 2},
+		{"Relative path", "", &BackendPrivateServiceReference{Name: "echo-api", Port: intstr.FromInt(8080), Path: "v1"}, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			backend := Backend{
+				Spec: BackendSpec{
+					Name:              "backendA",
+					PrivateBaseURL:    tc.privateBaseURL,
+					PrivateServiceRef: tc.privateServiceRef,
+				},
+			}
+			backend.SetDefaults(getv1beta1TestLogger())
+
+			errors := backend.Validate()
+			if len(errors) != tc.expectedErrors {
+				subT.Errorf("unexpected validation errors: got %d, expected %d: %v", len(errors), tc.expectedErrors, errors)
+			}
+		})
+	}
+}
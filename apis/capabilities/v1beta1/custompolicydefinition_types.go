@@ -22,7 +22,6 @@ import (
 	"github.com/3scale/3scale-operator/pkg/common"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -76,7 +75,12 @@ type CustomPolicyDefinitionSpec struct {
 
 	// ProviderAccountRef references account provider credentials
 	// +optional
-	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
+	ProviderAccountRef *ProviderAccountReference `json:"providerAccountRef,omitempty"`
+
+	// DeletionPolicy defines whether the 3scale custom policy is deleted or kept when the custom resource is deleted.
+	// Defaults to Delete
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
 
 	// Name is the name of the custom policy
 	Name string `json:"name"`
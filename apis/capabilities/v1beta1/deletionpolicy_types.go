@@ -0,0 +1,55 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// DeletionPolicy defines what happens to the 3scale object when the custom resource is deleted
+// +kubebuilder:validation:Enum=Delete;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the 3scale object with the custom resource
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+
+	// DeletionPolicyOrphan keeps the 3scale object when the custom resource is deleted
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// DefaultDeletionPolicyAnnotation holds the deletion policy of custom resources without deletionPolicy spec field.
+// Set by the operator on resources synchronized before the deletion policy was introduced
+const DefaultDeletionPolicyAnnotation = "capabilities.3scale.net/default-deletion-policy"
+
+// EffectiveDeletionPolicy returns the deletion policy of the custom resource: the spec field when set,
+// then the DefaultDeletionPolicyAnnotation, then the Delete policy.
+func EffectiveDeletionPolicy(deletionPolicy *DeletionPolicy, annotations map[string]string) *DeletionPolicy {
+	if deletionPolicy != nil {
+		return deletionPolicy
+	}
+
+	switch defaultPolicy := DeletionPolicy(annotations[DefaultDeletionPolicyAnnotation]); defaultPolicy {
+	case DeletionPolicyDelete, DeletionPolicyOrphan:
+		return &defaultPolicy
+	}
+
+	deletePolicy := DeletionPolicyDelete
+	return &deletePolicy
+}
+
+// OrphanOnDeletion returns true when the 3scale object must be kept on custom resource deletion.
+// Defaults to the Delete policy.
+func OrphanOnDeletion(deletionPolicy *DeletionPolicy) bool {
+	return deletionPolicy != nil && *deletionPolicy == DeletionPolicyOrphan
+}
@@ -0,0 +1,53 @@
+package v1beta1
+
+import "testing"
+
+func TestOrphanOnDeletion(t *testing.T) {
+	deletePolicy := DeletionPolicyDelete
+	orphanPolicy := DeletionPolicyOrphan
+
+	cases := []struct {
+		name     string
+		policy   *DeletionPolicy
+		expected bool
+	}{
+		{"default", nil, false},
+		{"delete", &deletePolicy, false},
+		{"orphan", &orphanPolicy, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if got := OrphanOnDeletion(tc.policy); got != tc.expected {
+				subT.Errorf("got %t, expected %t", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEffectiveDeletionPolicy(t *testing.T) {
+	deletePolicy := DeletionPolicyDelete
+	orphanPolicy := DeletionPolicyOrphan
+	orphanAnnotation := map[string]string{DefaultDeletionPolicyAnnotation: string(DeletionPolicyOrphan)}
+
+	cases := []struct {
+		name        string
+		policy      *DeletionPolicy
+		annotations map[string]string
+		expected    DeletionPolicy
+	}{
+		{"default", nil, nil, DeletionPolicyDelete},
+		{"annotation", nil, orphanAnnotation, DeletionPolicyOrphan},
+		{"invalid annotation", nil, map[string]string{DefaultDeletionPolicyAnnotation: "Keep"}, DeletionPolicyDelete},
+		{"spec over annotation", &deletePolicy, orphanAnnotation, DeletionPolicyDelete},
+		{"spec", &orphanPolicy, nil, DeletionPolicyOrphan},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			if got := EffectiveDeletionPolicy(tc.policy, tc.annotations); *got != tc.expected {
+				subT.Errorf("got %s, expected %s", *got, tc.expected)
+			}
+		})
+	}
+}
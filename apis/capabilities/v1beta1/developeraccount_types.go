@@ -23,7 +23,6 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -68,7 +67,12 @@ type DeveloperAccountSpec struct {
 
 	// ProviderAccountRef references account provider credentials
 	// +optional
-	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
+	ProviderAccountRef *ProviderAccountReference `json:"providerAccountRef,omitempty"`
+
+	// DeletionPolicy defines whether the 3scale developer account is deleted or kept when the custom resource is deleted.
+	// Defaults to Delete
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // DeveloperAccountStatus defines the observed state of DeveloperAccount
@@ -87,7 +87,12 @@ type DeveloperUserSpec struct {
 
 	// ProviderAccountRef references account provider credentials
 	// +optional
-	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
+	ProviderAccountRef *ProviderAccountReference `json:"providerAccountRef,omitempty"`
+
+	// DeletionPolicy defines whether the 3scale developer user is deleted or kept when the custom resource is deleted.
+	// Defaults to Delete
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // DeveloperUserStatus defines the observed state of DeveloperUser
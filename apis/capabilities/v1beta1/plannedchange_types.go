@@ -0,0 +1,46 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// PlannedChangeAction is the kind of modification of a planned change
+type PlannedChangeAction string
+
+const (
+	PlannedChangeActionCreate PlannedChangeAction = "create"
+	PlannedChangeActionUpdate PlannedChangeAction = "update"
+	PlannedChangeActionDelete PlannedChangeAction = "delete"
+)
+
+// PlannedChange is a modification of a 3scale object that the synchronization would apply
+type PlannedChange struct {
+	// Section of the 3scale object, like methods or mappingRules
+	Section string `json:"section"`
+
+	// Action is one of create, update or delete
+	Action PlannedChangeAction `json:"action"`
+
+	// Path of the 3scale API resource
+	Path string `json:"path"`
+
+	// Before holds the current values of the modified fields
+	// +optional
+	Before map[string]string `json:"before,omitempty"`
+
+	// After holds the desired values of the modified fields
+	// +optional
+	After map[string]string `json:"after,omitempty"`
+}
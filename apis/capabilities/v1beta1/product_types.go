@@ -26,7 +26,6 @@ import (
 	"github.com/3scale/3scale-operator/pkg/common"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -58,6 +57,17 @@ const (
 	// ProductFailedConditionType indicates that an error occurred during synchronization.
 	// The operator will retry.
 	ProductFailedConditionType common.ConditionType = "Failed"
+
+	// ProductDriftedConditionType indicates that the last synchronization found differences
+	// between the 3scale product and the ProductSpec that were not caused by a spec change.
+	// The differences have been corrected. The condition message lists the sections that differed.
+	// Example: mapping rules modified in the 3scale admin portal
+	ProductDriftedConditionType common.ConditionType = "Drifted"
+
+	// ProductPlannedConditionType indicates that the changes to the 3scale product have been planned
+	// without being applied, as the ProductSpec enables dry run. The planned changes are in the status.
+	// The condition is false when some sections could not be planned. The condition message lists them.
+	ProductPlannedConditionType common.ConditionType = "Planned"
 )
 
 var (
@@ -916,11 +926,27 @@ type ProductSpec struct {
 
 	// ProviderAccountRef references account provider credentials
 	// +optional
-	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
+	ProviderAccountRef *ProviderAccountReference `json:"providerAccountRef,omitempty"`
+
+	// DeletionPolicy defines whether the 3scale product is deleted or kept when the custom resource is deleted.
+	// Defaults to Delete
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
 
 	// Policies holds the product's policy chain
 	// +optional
 	Policies []PolicyConfig `json:"policies,omitempty"`
+
+	// ResyncPeriod is the period to synchronize the product again with 3scale,
+	// correcting changes made outside the operator.
+	// Overrides the operator resync period. Zero disables periodic synchronization.
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+
+	// DryRun computes the changes the synchronization would apply to the 3scale product
+	// and reports them in the status, without applying them.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 func (s *ProductSpec) DeploymentOption() *string {
@@ -1073,10 +1099,23 @@ type ProductStatus struct {
 	// +optional
 	ProviderAccountHost string `json:"providerAccountHost,omitempty"`
 
+	// StagingPublicBaseURL is the staging public base URL of the 3scale product
+	// +optional
+	StagingPublicBaseURL string `json:"stagingPublicBaseURL,omitempty"`
+
+	// ProductionPublicBaseURL is the production public base URL of the 3scale product
+	// +optional
+	ProductionPublicBaseURL string `json:"productionPublicBaseURL,omitempty"`
+
 	// ObservedGeneration reflects the generation of the most recently observed Product Spec.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// PlannedChanges lists the changes to the 3scale product planned by the last synchronization.
+	// Only set when the spec enables dry run.
+	// +optional
+	PlannedChanges []PlannedChange `json:"plannedChanges,omitempty"`
+
 	// Current state of the 3scale product.
 	// Conditions represent the latest available observations of an object's state
 	// +optional
@@ -1104,12 +1143,30 @@ func (p *ProductStatus) Equals(other *ProductStatus, logger logr.Logger) bool {
 		return false
 	}
 
+	if p.StagingPublicBaseURL != other.StagingPublicBaseURL {
+		diff := cmp.Diff(p.StagingPublicBaseURL, other.StagingPublicBaseURL)
+		logger.V(1).Info("StagingPublicBaseURL not equal", "difference", diff)
+		return false
+	}
+
+	if p.ProductionPublicBaseURL != other.ProductionPublicBaseURL {
+		diff := cmp.Diff(p.ProductionPublicBaseURL, other.ProductionPublicBaseURL)
+		logger.V(1).Info("ProductionPublicBaseURL not equal", "difference", diff)
+		return false
+	}
+
 	if p.ObservedGeneration != other.ObservedGeneration {
 		diff := cmp.Diff(p.ObservedGeneration, other.ObservedGeneration)
 		logger.V(1).Info("ObservedGeneration not equal", "difference", diff)
 		return false
 	}
 
+	if !reflect.DeepEqual(p.PlannedChanges, other.PlannedChanges) {
+		diff := cmp.Diff(p.PlannedChanges, other.PlannedChanges)
+		logger.V(1).Info("PlannedChanges not equal", "difference", diff)
+		return false
+	}
+
 	// Marshalling sorts by condition type
 	currentMarshaledJSON, _ := p.Conditions.MarshalJSON()
 	otherMarshaledJSON, _ := other.Conditions.MarshalJSON()
@@ -1136,39 +1193,28 @@ type Product struct {
 	Status ProductStatus `json:"status,omitempty"`
 }
 
-// RemoveBackendReferences returns true if product CR has mentions of a backend that matches
-// backendSystemName in: backendUsage, Pricing Plans, Limits and removes the references of backend.
-func (product *Product) RemoveBackendReferences(backendSystemName string) bool {
-	removalDone := false
-
+// UsesBackend returns true if product CR has mentions of a backend that matches
+// backendSystemName in: backendUsage, Pricing Plans, Limits.
+func (product *Product) UsesBackend(backendSystemName string) bool {
 	if _, ok := product.Spec.BackendUsages[backendSystemName]; ok {
-		delete(product.Spec.BackendUsages, backendSystemName)
-		removalDone = true
+		return true
 	}
 
-	for appIDX, applicationPlan := range product.Spec.ApplicationPlans {
-		currentApplicationPlan := applicationPlan
-
-		// remove pricing rules that mention the systemName
-		for pricingRuleIDX, pricingRule := range applicationPlan.PricingRules {
+	for _, applicationPlan := range product.Spec.ApplicationPlans {
+		for _, pricingRule := range applicationPlan.PricingRules {
 			if pricingRule.MetricMethodRef.BackendSystemName != nil && *pricingRule.MetricMethodRef.BackendSystemName == backendSystemName {
-				currentApplicationPlan.PricingRules = removePricingRule(applicationPlan.PricingRules, pricingRuleIDX)
-				removalDone = true
+				return true
 			}
 		}
 
-		// remove limit rules that mention the systemName
-		for limitRuleIDX, limitRule := range applicationPlan.Limits {
+		for _, limitRule := range applicationPlan.Limits {
 			if limitRule.MetricMethodRef.BackendSystemName != nil && *limitRule.MetricMethodRef.BackendSystemName == backendSystemName {
-				currentApplicationPlan.Limits = removeLimitRule(applicationPlan.Limits, limitRuleIDX)
-				removalDone = true
+				return true
 			}
 		}
-
-		product.Spec.ApplicationPlans[appIDX] = currentApplicationPlan
 	}
 
-	return removalDone
+	return false
 }
 
 func (product *Product) SetDefaults(logger logr.Logger) bool {
@@ -1362,6 +1408,10 @@ func (product *Product) Validate() field.ErrorList {
 		}
 	}
 
+	if product.Spec.ResyncPeriod != nil && product.Spec.ResyncPeriod.Duration < 0 {
+		errors = append(errors, field.Invalid(specFldPath.Child("resyncPeriod"), product.Spec.ResyncPeriod.Duration.String(), "resync period cannot be negative."))
+	}
+
 	return errors
 }
 
@@ -1442,11 +1492,3 @@ type ProductList struct {
 func init() {
 	SchemeBuilder.Register(&Product{}, &ProductList{})
 }
-
-func removePricingRule(s []PricingRuleSpec, index int) []PricingRuleSpec {
-	return append(s[:index], s[index+1:]...)
-}
-
-func removeLimitRule(s []LimitSpec, index int) []LimitSpec {
-	return append(s[:index], s[index+1:]...)
-}
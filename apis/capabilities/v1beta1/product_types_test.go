@@ -3,8 +3,10 @@ package v1beta1
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -207,6 +209,17 @@ func TestValidateProductPlanPricingRuleOverlappingRange(t *testing.T) {
 	}
 }
 
+func TestValidateProductNegativeResyncPeriod(t *testing.T) {
+	product := defaultTestingProduct()
+
+	product.Spec.ResyncPeriod = &metav1.Duration{Duration: -time.Minute}
+
+	errors := product.Validate()
+	if len(errors) == 0 || !strings.Contains(errors.ToAggregate().Error(), "resync period cannot be negative") {
+		t.Error("validation passes and resync period is negative.")
+	}
+}
+
 func TestValidateProductHappyPath(t *testing.T) {
 	product := defaultTestingProduct()
 
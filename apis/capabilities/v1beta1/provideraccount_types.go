@@ -0,0 +1,199 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"net/url"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	ProviderAccountKind = "ProviderAccount"
+
+	// ProviderAccountReadyConditionType indicates the admin portal is reachable
+	// and the token has the permissions required by the capability CRs.
+	ProviderAccountReadyConditionType common.ConditionType = "Ready"
+
+	// ProviderAccountInvalidConditionType indicates the admin portal rejected the token
+	// or the token lacks permissions. This is not a transient error, but
+	// indicates a state that must be fixed before progress can be made.
+	ProviderAccountInvalidConditionType common.ConditionType = "Invalid"
+
+	// ProviderAccountAllNamespaces allows the references from every namespace
+	ProviderAccountAllNamespaces = "*"
+)
+
+// ProviderAccountReference references a ProviderAccount CR or a provider account secret
+type ProviderAccountReference struct {
+	// Name of the ProviderAccount CR or the secret
+	Name string `json:"name"`
+
+	// Namespace of the ProviderAccount CR. Defaults to the namespace of the referencing CR.
+	// References to other namespaces are resolved only to ProviderAccount CRs allowing the namespace
+	// of the referencing CR, and must be enabled in the operator.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ProviderAccountSpec defines the desired state of ProviderAccount
+type ProviderAccountSpec struct {
+	// AdminURL is the 3scale admin portal URL of the tenant
+	AdminURL string `json:"adminURL"`
+
+	// TokenSecretRef references the secret holding the access token in the token field
+	TokenSecretRef corev1.LocalObjectReference `json:"tokenSecretRef"`
+
+	// CABundleSecretRef references the secret holding the PEM encoded CA certificates
+	// trusted to verify the admin portal certificate in the caBundle field
+	// +optional
+	CABundleSecretRef *corev1.LocalObjectReference `json:"caBundleSecretRef,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the admin portal certificate
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// CheckPeriod is the period to check the admin portal connectivity and the token permissions again.
+	// Defaults to 10 minutes. Zero disables periodic checks.
+	// +optional
+	CheckPeriod *metav1.Duration `json:"checkPeriod,omitempty"`
+
+	// AllowedNamespaces lists the namespaces of the CRs allowed to reference this provider account
+	// from another namespace. "*" allows every namespace.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// ProviderAccountStatus defines the observed state of ProviderAccount
+type ProviderAccountStatus struct {
+	// 3scale control plane host
+	// +optional
+	ProviderAccountHost string `json:"providerAccountHost,omitempty"`
+
+	// ObservedGeneration reflects the generation of the most recently observed ProviderAccount Spec.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Current state of the provider account.
+	// Conditions represent the latest available observations of an object's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions common.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,2,rep,name=conditions"`
+}
+
+func (p *ProviderAccountStatus) Equals(other *ProviderAccountStatus, logger logr.Logger) bool {
+	if p.ProviderAccountHost != other.ProviderAccountHost {
+		diff := cmp.Diff(p.ProviderAccountHost, other.ProviderAccountHost)
+		logger.V(1).Info("ProviderAccountHost not equal", "difference", diff)
+		return false
+	}
+
+	if p.ObservedGeneration != other.ObservedGeneration {
+		diff := cmp.Diff(p.ObservedGeneration, other.ObservedGeneration)
+		logger.V(1).Info("ObservedGeneration not equal", "difference", diff)
+		return false
+	}
+
+	// Marshalling sorts by condition type
+	currentMarshaledJSON, _ := p.Conditions.MarshalJSON()
+	otherMarshaledJSON, _ := other.Conditions.MarshalJSON()
+	if string(currentMarshaledJSON) != string(otherMarshaledJSON) {
+		diff := cmp.Diff(string(currentMarshaledJSON), string(otherMarshaledJSON))
+		logger.V(1).Info("Conditions not equal", "difference", diff)
+		return false
+	}
+
+	return true
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".status.providerAccountHost",name="Provider Account",type=string
+// +kubebuilder:printcolumn:JSONPath=".status.conditions[?(@.type=='Ready')].status",name=Ready,type=string
+
+// ProviderAccount is the Schema for the provideraccounts API
+type ProviderAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProviderAccountSpec   `json:"spec,omitempty"`
+	Status ProviderAccountStatus `json:"status,omitempty"`
+}
+
+// AllowsNamespace returns true when the CRs in the namespace may reference the provider account
+func (p *ProviderAccount) AllowsNamespace(namespace string) bool {
+	if namespace == p.Namespace {
+		return true
+	}
+
+	for _, allowed := range p.Spec.AllowedNamespaces {
+		if allowed == ProviderAccountAllNamespaces || allowed == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *ProviderAccount) Validate() field.ErrorList {
+	errors := field.ErrorList{}
+	specFldPath := field.NewPath("spec")
+
+	adminURL, err := url.Parse(p.Spec.AdminURL)
+	if err != nil || (adminURL.Scheme != "http" && adminURL.Scheme != "https") || adminURL.Host == "" {
+		errors = append(errors, field.Invalid(specFldPath.Child("adminURL"), p.Spec.AdminURL, "admin URL must be an absolute http or https URL."))
+	}
+
+	if p.Spec.TokenSecretRef.Name == "" {
+		errors = append(errors, field.Required(specFldPath.Child("tokenSecretRef", "name"), "token secret name is required."))
+	}
+
+	if p.Spec.CheckPeriod != nil && p.Spec.CheckPeriod.Duration < 0 {
+		errors = append(errors, field.Invalid(specFldPath.Child("checkPeriod"), p.Spec.CheckPeriod.Duration.String(), "check period cannot be negative."))
+	}
+
+	for idx, namespace := range p.Spec.AllowedNamespaces {
+		if namespace == ProviderAccountAllNamespaces {
+			continue
+		}
+		for _, msg := range validation.ValidateNamespaceName(namespace, false) {
+			errors = append(errors, field.Invalid(specFldPath.Child("allowedNamespaces").Index(idx), namespace, msg))
+		}
+	}
+
+	return errors
+}
+
+// +kubebuilder:object:root=true
+
+// ProviderAccountList contains a list of ProviderAccount
+type ProviderAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProviderAccount `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProviderAccount{}, &ProviderAccountList{})
+}
@@ -0,0 +1,67 @@
+package v1beta1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestProviderAccountAllowsNamespace(t *testing.T) {
+	cases := []struct {
+		name              string
+		allowedNamespaces []string
+		namespace         string
+		expected          bool
+	}{
+		{"same namespace", nil, "owner", true},
+		{"not allowed", nil, "app", false},
+		{"allowed", []string{"other", "app"}, "app", true},
+		{"all namespaces", []string{ProviderAccountAllNamespaces}, "app", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			providerAccount := &ProviderAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: "mytenant", Namespace: "owner"},
+				Spec:       ProviderAccountSpec{AllowedNamespaces: tc.allowedNamespaces},
+			}
+			if got := providerAccount.AllowsNamespace(tc.namespace); got != tc.expected {
+				subT.Errorf("got %t, expected %t", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestProviderAccountValidate(t *testing.T) {
+	cases := []struct {
+		name           string
+		spec           ProviderAccountSpec
+		expectedErrors int
+	}{
+		{"valid", ProviderAccountSpec{
+			AdminURL:          "https://3scale-admin.example.com",
+			TokenSecretRef:    corev1.LocalObjectReference{Name: "token"},
+			AllowedNamespaces: []string{"app", ProviderAccountAllNamespaces},
+		}, 0},
+		{"relative admin URL", ProviderAccountSpec{
+			AdminURL:       "3scale-admin.example.com",
+			TokenSecretRef: corev1.LocalObjectReference{Name: "token"},
+		}, 1},
+		{"missing token secret", ProviderAccountSpec{AdminURL: "https://3scale-admin.example.com"}, 1},
+		{"invalid allowed namespace", ProviderAccountSpec{
+			AdminURL:          "https://3scale-admin.example.com",
+			TokenSecretRef:    corev1.LocalObjectReference{Name: "token"},
+			AllowedNamespaces: []string{"App_1"},
+		}, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			providerAccount := &ProviderAccount{Spec: tc.spec}
+			if errors := providerAccount.Validate(); len(errors) != tc.expectedErrors {
+				subT.Errorf("got %v, expected %d errors", errors, tc.expectedErrors)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"reflect"
 )
 
@@ -50,6 +51,19 @@ type ProxyConfigPromoteSpec struct {
 	// deleteCR  deletes this CR when it has successfully completed the promotion
 	// +optional
 	DeleteCR *bool `json:"deleteCR,omitempty"`
+
+	// stagingVersion promotes the given staging proxy configuration version to production
+	// instead of the latest one. The current configuration is not deployed to staging.
+	// Requires production to be true
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StagingVersion *int `json:"stagingVersion,omitempty"`
+
+	// previousProductionVersion promotes the production proxy configuration version
+	// previous to the current one, rolling back the last promotion to production.
+	// Requires production to be true
+	// +optional
+	PreviousProductionVersion *bool `json:"previousProductionVersion,omitempty"`
 }
 
 // ProxyConfigPromoteStatus defines the observed state of ProxyConfigPromote
@@ -64,9 +78,16 @@ type ProxyConfigPromoteStatus struct {
 	// The latest Version in production
 	//+optional
 	LatestProductionVersion int `json:"latestProductionVersion,omitempty"`
+	// The Version in production before the promotion
+	//+optional
+	PreviousProductionVersion int `json:"previousProductionVersion,omitempty"`
 	// The latest Version in staging
 	//+optional
 	LatestStagingVersion int `json:"latestStagingVersion,omitempty"`
+	// The Version being promoted to production when a specific version is targeted.
+	// Recorded before the promotion so retries promote the same version
+	//+optional
+	TargetProductionVersion int `json:"targetProductionVersion,omitempty"`
 
 	// Current state of the activedoc resource.
 	// Conditions represent the latest available observations of an object's state
@@ -88,6 +109,27 @@ type ProxyConfigPromote struct {
 	Status ProxyConfigPromoteStatus `json:"status,omitempty"`
 }
 
+// TargetsVersion returns true when a specific proxy configuration version
+// is promoted to production instead of the latest staging one
+func (p *ProxyConfigPromote) TargetsVersion() bool {
+	return p.Spec.StagingVersion != nil || (p.Spec.PreviousProductionVersion != nil && *p.Spec.PreviousProductionVersion)
+}
+
+func (p *ProxyConfigPromote) Validate() field.ErrorList {
+	errors := field.ErrorList{}
+	specFldPath := field.NewPath("spec")
+
+	if p.Spec.StagingVersion != nil && p.Spec.PreviousProductionVersion != nil && *p.Spec.PreviousProductionVersion {
+		errors = append(errors, field.Invalid(specFldPath.Child("stagingVersion"), *p.Spec.StagingVersion, "stagingVersion and previousProductionVersion are mutually exclusive"))
+	}
+
+	if p.TargetsVersion() && (p.Spec.Production == nil || !*p.Spec.Production) {
+		errors = append(errors, field.Invalid(specFldPath.Child("production"), p.Spec.Production, "promoting a specific version requires production to be true"))
+	}
+
+	return errors
+}
+
 // +kubebuilder:object:root=true
 
 // ProxyConfigPromoteList contains a list of ProxyConfigPromote
@@ -114,6 +156,18 @@ func (o *ProxyConfigPromoteStatus) Equals(other *ProxyConfigPromoteStatus, logge
 		return false
 	}
 
+	if o.PreviousProductionVersion != other.PreviousProductionVersion {
+		diff := cmp.Diff(o.PreviousProductionVersion, other.PreviousProductionVersion)
+		logger.V(1).Info("PreviousProductionVersion not equal", "difference", diff)
+		return false
+	}
+
+	if o.TargetProductionVersion != other.TargetProductionVersion {
+		diff := cmp.Diff(o.TargetProductionVersion, other.TargetProductionVersion)
+		logger.V(1).Info("TargetProductionVersion not equal", "difference", diff)
+		return false
+	}
+
 	if o.LatestStagingVersion != other.LatestStagingVersion {
 		diff := cmp.Diff(o.LatestStagingVersion, other.LatestStagingVersion)
 		logger.V(1).Info("LatestStagingVersion not equal", "difference", diff)
@@ -0,0 +1,58 @@
+package v1beta1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testingProxyConfigPromote() ProxyConfigPromote {
+	production := true
+	return ProxyConfigPromote{
+		ObjectMeta: metav1.ObjectMeta{Name: "promote"},
+		Spec: ProxyConfigPromoteSpec{
+			ProductCRName: "product",
+			Production:    &production,
+		},
+	}
+}
+
+func TestProxyConfigPromoteValid(t *testing.T) {
+	stagingVersion := 3
+	previousProductionVersion := true
+
+	promote := testingProxyConfigPromote()
+	if errors := promote.Validate(); len(errors) > 0 {
+		t.Errorf("promote is invalid: %s", errors.ToAggregate().Error())
+	}
+
+	promote.Spec.StagingVersion = &stagingVersion
+	if errors := promote.Validate(); len(errors) > 0 {
+		t.Errorf("promote with stagingVersion is invalid: %s", errors.ToAggregate().Error())
+	}
+
+	promote = testingProxyConfigPromote()
+	promote.Spec.PreviousProductionVersion = &previousProductionVersion
+	if errors := promote.Validate(); len(errors) > 0 {
+		t.Errorf("promote with previousProductionVersion is invalid: %s", errors.ToAggregate().Error())
+	}
+}
+
+func TestValidateProxyConfigPromoteTargetVersion(t *testing.T) {
+	stagingVersion := 3
+	previousProductionVersion := true
+
+	promote := testingProxyConfigPromote()
+	promote.Spec.StagingVersion = &stagingVersion
+	promote.Spec.PreviousProductionVersion = &previousProductionVersion
+	if errors := promote.Validate(); len(errors) != 1 {
+		t.Errorf("expected 1 error, got: %v", errors)
+	}
+
+	promote = testingProxyConfigPromote()
+	promote.Spec.Production = nil
+	promote.Spec.StagingVersion = &stagingVersion
+	if errors := promote.Validate(); len(errors) != 1 {
+		t.Errorf("expected 1 error, got: %v", errors)
+	}
+}
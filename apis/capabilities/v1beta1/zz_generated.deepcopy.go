@@ -24,6 +24,7 @@ package v1beta1
 import (
 	"github.com/3scale/3scale-operator/pkg/common"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -116,7 +117,12 @@ func (in *ActiveDocSpec) DeepCopyInto(out *ActiveDocSpec) {
 	*out = *in
 	if in.ProviderAccountRef != nil {
 		in, out := &in.ProviderAccountRef, &out.ProviderAccountRef
-		*out = new(v1.LocalObjectReference)
+		*out = new(ProviderAccountReference)
+		**out = **in
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
 		**out = **in
 	}
 	if in.SystemName != nil {
@@ -279,6 +285,65 @@ func (in *AppKeyAppIDAuthenticationSpec) DeepCopy() *AppKeyAppIDAuthenticationSp
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Application) DeepCopyInto(out *Application) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Application.
+func (in *Application) DeepCopy() *Application {
+	if in == nil {
+		return nil
+	}
+	out := new(Application)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Application) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationList) DeepCopyInto(out *ApplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Application, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationList.
+func (in *ApplicationList) DeepCopy() *ApplicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationPlanSpec) DeepCopyInto(out *ApplicationPlanSpec) {
 	*out = *in
@@ -338,6 +403,80 @@ func (in *ApplicationPlanSpec) DeepCopy() *ApplicationPlanSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
+	*out = *in
+	out.AccountCR = in.AccountCR
+	out.ProductCR = in.ProductCR
+	if in.AuthSecretRef != nil {
+		in, out := &in.AuthSecretRef, &out.AuthSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.CredentialsSecretName != nil {
+		in, out := &in.CredentialsSecretName, &out.CredentialsSecretName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ProviderAccountRef != nil {
+		in, out := &in.ProviderAccountRef, &out.ProviderAccountRef
+		*out = new(ProviderAccountReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSpec.
+func (in *ApplicationSpec) DeepCopy() *ApplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationStatus) DeepCopyInto(out *ApplicationStatus) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AccountID != nil {
+		in, out := &in.AccountID, &out.AccountID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ProductID != nil {
+		in, out := &in.ProductID, &out.ProductID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PlanID != nil {
+		in, out := &in.PlanID, &out.PlanID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(common.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationStatus.
+func (in *ApplicationStatus) DeepCopy() *ApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthenticationSpec) DeepCopyInto(out *AuthenticationSpec) {
 	*out = *in
@@ -427,9 +566,30 @@ func (in *BackendList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendPrivateServiceReference) DeepCopyInto(out *BackendPrivateServiceReference) {
+	*out = *in
+	out.Port = in.Port
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendPrivateServiceReference.
+func (in *BackendPrivateServiceReference) DeepCopy() *BackendPrivateServiceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendPrivateServiceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackendSpec) DeepCopyInto(out *BackendSpec) {
 	*out = *in
+	if in.PrivateServiceRef != nil {
+		in, out := &in.PrivateServiceRef, &out.PrivateServiceRef
+		*out = new(BackendPrivateServiceReference)
+		**out = **in
+	}
 	if in.MappingRules != nil {
 		in, out := &in.MappingRules, &out.MappingRules
 		*out = make([]MappingRuleSpec, len(*in))
@@ -453,7 +613,17 @@ func (in *BackendSpec) DeepCopyInto(out *BackendSpec) {
 	}
 	if in.ProviderAccountRef != nil {
 		in, out := &in.ProviderAccountRef, &out.ProviderAccountRef
-		*out = new(v1.LocalObjectReference)
+		*out = new(ProviderAccountReference)
+		**out = **in
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
+		**out = **in
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 }
@@ -476,6 +646,13 @@ func (in *BackendStatus) DeepCopyInto(out *BackendStatus) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]PlannedChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(common.Conditions, len(*in))
@@ -574,7 +751,12 @@ func (in *CustomPolicyDefinitionSpec) DeepCopyInto(out *CustomPolicyDefinitionSp
 	*out = *in
 	if in.ProviderAccountRef != nil {
 		in, out := &in.ProviderAccountRef, &out.ProviderAccountRef
-		*out = new(v1.LocalObjectReference)
+		*out = new(ProviderAccountReference)
+		**out = **in
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
 		**out = **in
 	}
 	in.Schema.DeepCopyInto(&out.Schema)
@@ -716,7 +898,12 @@ func (in *DeveloperAccountSpec) DeepCopyInto(out *DeveloperAccountSpec) {
 	}
 	if in.ProviderAccountRef != nil {
 		in, out := &in.ProviderAccountRef, &out.ProviderAccountRef
-		*out = new(v1.LocalObjectReference)
+		*out = new(ProviderAccountReference)
+		**out = **in
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
 		**out = **in
 	}
 }
@@ -839,7 +1026,12 @@ func (in *DeveloperUserSpec) DeepCopyInto(out *DeveloperUserSpec) {
 	}
 	if in.ProviderAccountRef != nil {
 		in, out := &in.ProviderAccountRef, &out.ProviderAccountRef
-		*out = new(v1.LocalObjectReference)
+		*out = new(ProviderAccountReference)
+		**out = **in
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
 		**out = **in
 	}
 }
@@ -1139,6 +1331,36 @@ func (in *OpenAPI) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenAPIActiveDocSpec) DeepCopyInto(out *OpenAPIActiveDocSpec) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Published != nil {
+		in, out := &in.Published, &out.Published
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SkipSwaggerValidations != nil {
+		in, out := &in.SkipSwaggerValidations, &out.SkipSwaggerValidations
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIActiveDocSpec.
+func (in *OpenAPIActiveDocSpec) DeepCopy() *OpenAPIActiveDocSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenAPIActiveDocSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenAPIList) DeepCopyInto(out *OpenAPIList) {
 	*out = *in
@@ -1171,6 +1393,51 @@ func (in *OpenAPIList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenAPIOIDCSpec) DeepCopyInto(out *OpenAPIOIDCSpec) {
+	*out = *in
+	if in.IssuerType != nil {
+		in, out := &in.IssuerType, &out.IssuerType
+		*out = new(string)
+		**out = **in
+	}
+	if in.IssuerEndpoint != nil {
+		in, out := &in.IssuerEndpoint, &out.IssuerEndpoint
+		*out = new(string)
+		**out = **in
+	}
+	if in.IssuerEndpointRef != nil {
+		in, out := &in.IssuerEndpointRef, &out.IssuerEndpointRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.AuthenticationFlow != nil {
+		in, out := &in.AuthenticationFlow, &out.AuthenticationFlow
+		*out = new(OIDCAuthenticationFlowSpec)
+		**out = **in
+	}
+	if in.JwtClaimWithClientID != nil {
+		in, out := &in.JwtClaimWithClientID, &out.JwtClaimWithClientID
+		*out = new(string)
+		**out = **in
+	}
+	if in.JwtClaimWithClientIDType != nil {
+		in, out := &in.JwtClaimWithClientIDType, &out.JwtClaimWithClientIDType
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIOIDCSpec.
+func (in *OpenAPIOIDCSpec) DeepCopy() *OpenAPIOIDCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenAPIOIDCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenAPIRefSpec) DeepCopyInto(out *OpenAPIRefSpec) {
 	*out = *in
@@ -1184,6 +1451,11 @@ func (in *OpenAPIRefSpec) DeepCopyInto(out *OpenAPIRefSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIRefSpec.
@@ -1196,13 +1468,32 @@ func (in *OpenAPIRefSpec) DeepCopy() *OpenAPIRefSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenAPIRefStatus) DeepCopyInto(out *OpenAPIRefStatus) {
+	*out = *in
+	if in.LastFetchTime != nil {
+		in, out := &in.LastFetchTime, &out.LastFetchTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIRefStatus.
+func (in *OpenAPIRefStatus) DeepCopy() *OpenAPIRefStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenAPIRefStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenAPISpec) DeepCopyInto(out *OpenAPISpec) {
 	*out = *in
 	in.OpenAPIRef.DeepCopyInto(&out.OpenAPIRef)
 	if in.ProviderAccountRef != nil {
 		in, out := &in.ProviderAccountRef, &out.ProviderAccountRef
-		*out = new(v1.LocalObjectReference)
+		*out = new(ProviderAccountReference)
 		**out = **in
 	}
 	if in.ProductionPublicBaseURL != nil {
@@ -1240,6 +1531,16 @@ func (in *OpenAPISpec) DeepCopyInto(out *OpenAPISpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OpenAPIOIDCSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveDoc != nil {
+		in, out := &in.ActiveDoc, &out.ActiveDoc
+		*out = new(OpenAPIActiveDocSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPISpec.
@@ -1265,6 +1566,16 @@ func (in *OpenAPIStatus) DeepCopyInto(out *OpenAPIStatus) {
 		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ActiveDocResourceName != nil {
+		in, out := &in.ActiveDocResourceName, &out.ActiveDocResourceName
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.OpenAPIRef != nil {
+		in, out := &in.OpenAPIRef, &out.OpenAPIRef
+		*out = new(OpenAPIRefStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(common.Conditions, len(*in))
@@ -1284,6 +1595,35 @@ func (in *OpenAPIStatus) DeepCopy() *OpenAPIStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedChange) DeepCopyInto(out *PlannedChange) {
+	*out = *in
+	if in.Before != nil {
+		in, out := &in.Before, &out.Before
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.After != nil {
+		in, out := &in.After, &out.After
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedChange.
+func (in *PlannedChange) DeepCopy() *PlannedChange {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyConfig) DeepCopyInto(out *PolicyConfig) {
 	*out = *in
@@ -1445,7 +1785,12 @@ func (in *ProductSpec) DeepCopyInto(out *ProductSpec) {
 	}
 	if in.ProviderAccountRef != nil {
 		in, out := &in.ProviderAccountRef, &out.ProviderAccountRef
-		*out = new(v1.LocalObjectReference)
+		*out = new(ProviderAccountReference)
+		**out = **in
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
 		**out = **in
 	}
 	if in.Policies != nil {
@@ -1455,6 +1800,11 @@ func (in *ProductSpec) DeepCopyInto(out *ProductSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProductSpec.
@@ -1480,6 +1830,13 @@ func (in *ProductStatus) DeepCopyInto(out *ProductStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]PlannedChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(common.Conditions, len(*in))
@@ -1499,6 +1856,133 @@ func (in *ProductStatus) DeepCopy() *ProductStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderAccount) DeepCopyInto(out *ProviderAccount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderAccount.
+func (in *ProviderAccount) DeepCopy() *ProviderAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProviderAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderAccountList) DeepCopyInto(out *ProviderAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProviderAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderAccountList.
+func (in *ProviderAccountList) DeepCopy() *ProviderAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProviderAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderAccountReference) DeepCopyInto(out *ProviderAccountReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderAccountReference.
+func (in *ProviderAccountReference) DeepCopy() *ProviderAccountReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderAccountReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderAccountSpec) DeepCopyInto(out *ProviderAccountSpec) {
+	*out = *in
+	out.TokenSecretRef = in.TokenSecretRef
+	if in.CABundleSecretRef != nil {
+		in, out := &in.CABundleSecretRef, &out.CABundleSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.CheckPeriod != nil {
+		in, out := &in.CheckPeriod, &out.CheckPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderAccountSpec.
+func (in *ProviderAccountSpec) DeepCopy() *ProviderAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderAccountStatus) DeepCopyInto(out *ProviderAccountStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(common.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderAccountStatus.
+func (in *ProviderAccountStatus) DeepCopy() *ProviderAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProxyConfigPromote) DeepCopyInto(out *ProxyConfigPromote) {
 	*out = *in
@@ -1571,6 +2055,16 @@ func (in *ProxyConfigPromoteSpec) DeepCopyInto(out *ProxyConfigPromoteSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.StagingVersion != nil {
+		in, out := &in.StagingVersion, &out.StagingVersion
+		*out = new(int)
+		**out = **in
+	}
+	if in.PreviousProductionVersion != nil {
+		in, out := &in.PreviousProductionVersion, &out.PreviousProductionVersion
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyConfigPromoteSpec.
@@ -22,6 +22,10 @@ import (
 
 	appsv1 "github.com/openshift/api/apps/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -32,6 +36,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	capabilitiesv1alpha1 "github.com/3scale/3scale-operator/apis/capabilities/v1alpha1"
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/operator"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	"github.com/3scale/3scale-operator/pkg/handlers"
@@ -60,8 +66,12 @@ var _ reconcile.Reconciler = &APIManagerReconciler{}
 // +kubebuilder:rbac:groups=route.openshift.io,namespace=placeholder,resources=routes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=route.openshift.io,namespace=placeholder,resources=routes/custom-host,verbs=create
 // +kubebuilder:rbac:groups=route.openshift.io,namespace=placeholder,resources=routes/status,verbs=get
+// +kubebuilder:rbac:groups=networking.k8s.io,namespace=placeholder,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.openshift.io,namespace=placeholder,resources=deploymentconfigs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=policy,namespace=placeholder,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,namespace=placeholder,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,namespace=placeholder,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=products;tenants,verbs=get;list;watch
 // +kubebuilder:rbac:groups=monitoring.coreos.com,namespace=placeholder,resources=podmonitors;servicemonitors;prometheusrules,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=integreatly.org,namespace=placeholder,resources=grafanadashboards,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch
@@ -145,17 +155,59 @@ func (r *APIManagerReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 }
 
 func (r *APIManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// DeploymentConfigs and Routes are not available outside OpenShift
+	deploymentConfigsAvailable, err := r.HasDeploymentConfigs()
+	if err != nil {
+		return err
+	}
+
+	routesAvailable, err := r.HasRoutes()
+	if err != nil {
+		return err
+	}
+
+	ingressesAvailable, err := r.HasIngresses()
+	if err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&appsv1alpha1.APIManager{}).
-		Owns(&appsv1.DeploymentConfig{}).
+		Owns(&k8sappsv1.Deployment{}).
 		Owns(&policyv1beta1.PodDisruptionBudget{}).
-		Watches(&source.Kind{Type: &routev1.Route{}}, &handler.EnqueueRequestsFromMapFunc{
+		Owns(&autoscalingv2beta2.HorizontalPodAutoscaler{}).
+		// Deployment hook jobs
+		Owns(&batchv1.Job{})
+
+	if deploymentConfigsAvailable {
+		builder = builder.Owns(&appsv1.DeploymentConfig{})
+	}
+
+	if ingressesAvailable {
+		// Products and Tenants hosts are exposed by ingresses
+		apimanagerNamespaceEventMapper := &handlers.APIManagerNamespaceEventMapper{
+			K8sClient: r.Client(),
+			Logger:    r.Logger().WithName("APIManagerNamespaceHandler"),
+		}
+		builder = builder.Owns(&networkingv1beta1.Ingress{}).
+			Watches(&source.Kind{Type: &capabilitiesv1beta1.Product{}}, &handler.EnqueueRequestsFromMapFunc{
+				ToRequests: apimanagerNamespaceEventMapper,
+			}).
+			Watches(&source.Kind{Type: &capabilitiesv1alpha1.Tenant{}}, &handler.EnqueueRequestsFromMapFunc{
+				ToRequests: apimanagerNamespaceEventMapper,
+			})
+	}
+
+	if routesAvailable {
+		builder = builder.Watches(&source.Kind{Type: &routev1.Route{}}, &handler.EnqueueRequestsFromMapFunc{
 			ToRequests: &handlers.APIManagerRoutesEventMapper{
 				K8sClient: r.Client(),
 				Logger:    r.Logger().WithName("APIManagerRoutesHandler"),
 			},
-		}).
-		Complete(r)
+		})
+	}
+
+	return builder.Complete(r)
 }
 
 func (r *APIManagerReconciler) validateCR(cr *appsv1alpha1.APIManager) error {
@@ -265,6 +317,12 @@ func (r *APIManagerReconciler) reconcileAPIManagerLogic(cr *appsv1alpha1.APIMana
 		return result, err
 	}
 
+	ingressReconciler := operator.NewIngressReconciler(baseAPIManagerLogicReconciler)
+	result, err = ingressReconciler.Reconcile()
+	if err != nil || result.Requeue {
+		return result, err
+	}
+
 	genericMonitoringReconciler := operator.NewGenericMonitoringReconciler(baseAPIManagerLogicReconciler)
 	result, err = genericMonitoringReconciler.Reconcile()
 	if err != nil || result.Requeue {
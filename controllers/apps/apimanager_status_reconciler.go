@@ -14,7 +14,9 @@ import (
 	"github.com/go-logr/logr"
 	appsv1 "github.com/openshift/api/apps/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -68,22 +70,31 @@ func (s *APIManagerStatusReconciler) Reconcile() (reconcile.Result, error) {
 func (s *APIManagerStatusReconciler) calculateStatus() (*appsv1alpha1.APIManagerStatus, error) {
 	newStatus := &appsv1alpha1.APIManagerStatus{}
 
-	deployments, err := s.existingDeployments()
-	if err != nil {
-		return nil, err
+	var deploymentsAvailable bool
+	if s.apimanagerResource.IsDeploymentWorkloadEnabled() {
+		deployments, err := s.existingK8sDeployments()
+		if err != nil {
+			return nil, err
+		}
+		deploymentsAvailable = s.k8sDeploymentsAvailable(deployments)
+		newStatus.Deployments = olm.GetDeploymentStatus(deployments)
+	} else {
+		deployments, err := s.existingDeployments()
+		if err != nil {
+			return nil, err
+		}
+		deploymentsAvailable = s.deploymentsAvailable(deployments)
+		newStatus.Deployments = olm.GetDeploymentConfigStatus(deployments)
 	}
 
 	newStatus.Conditions = s.apimanagerResource.Status.Conditions.Copy()
 
-	availableCondition, err := s.apimanagerAvailableCondition(deployments)
+	availableCondition, err := s.apimanagerAvailableCondition(deploymentsAvailable)
 	if err != nil {
 		return nil, err
 	}
 	newStatus.Conditions.SetCondition(availableCondition)
 
-	deploymentStatus := olm.GetDeploymentConfigStatus(deployments)
-	newStatus.Deployments = deploymentStatus
-
 	return newStatus, nil
 }
 
@@ -161,10 +172,58 @@ func (s *APIManagerStatusReconciler) existingDeployments() ([]appsv1.DeploymentC
 	return dcs, nil
 }
 
-func (s *APIManagerStatusReconciler) apimanagerAvailableCondition(existingDeployments []appsv1.DeploymentConfig) (common.Condition, error) {
-	deploymentsAvailable := s.deploymentsAvailable(existingDeployments)
+func (s *APIManagerStatusReconciler) k8sDeploymentsAvailable(existingDeployments []k8sappsv1.Deployment) bool {
+	expectedDeploymentNames := s.expectedDeploymentNames(s.apimanagerResource)
+	for _, deploymentName := range expectedDeploymentNames {
+		foundExistingIdx := -1
+		for idx, existingDeployment := range existingDeployments {
+			if existingDeployment.Name == deploymentName {
+				foundExistingIdx = idx
+				break
+			}
+		}
+		if foundExistingIdx == -1 || !helper.IsDeploymentAvailable(&existingDeployments[foundExistingIdx]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *APIManagerStatusReconciler) existingK8sDeployments() ([]k8sappsv1.Deployment, error) {
+	expectedDeploymentNames := s.expectedDeploymentNames(s.apimanagerResource)
+
+	var deployments []k8sappsv1.Deployment
+	for _, deploymentName := range expectedDeploymentNames {
+		existingDeployment := &k8sappsv1.Deployment{}
+		err := s.Client().Get(context.Background(), types.NamespacedName{Namespace: s.apimanagerResource.Namespace, Name: deploymentName}, existingDeployment)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if err != nil && errors.IsNotFound(err) {
+			continue
+		}
+
+		for _, ownerRef := range existingDeployment.GetOwnerReferences() {
+			if ownerRef.UID == s.apimanagerResource.UID {
+				deployments = append(deployments, *existingDeployment)
+				break
+			}
+		}
+	}
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].Name < deployments[j].Name })
+
+	return deployments, nil
+}
 
-	defaultRoutesReady, err := s.defaultRoutesReady()
+func (s *APIManagerStatusReconciler) apimanagerAvailableCondition(deploymentsAvailable bool) (common.Condition, error) {
+	var defaultRoutesReady bool
+	var err error
+	if s.apimanagerResource.IsIngressEnabled() {
+		defaultRoutesReady, err = s.defaultIngressesReady()
+	} else {
+		defaultRoutesReady, err = s.defaultRoutesReady()
+	}
 	if err != nil {
 		return common.Condition{}, err
 	}
@@ -182,6 +241,15 @@ func (s *APIManagerStatusReconciler) apimanagerAvailableCondition(existingDeploy
 }
 
 func (s *APIManagerStatusReconciler) defaultRoutesReady() (bool, error) {
+	// Without the Route API, no routes are created and there is nothing to wait for
+	routesAvailable, err := s.HasRoutes()
+	if err != nil {
+		return false, err
+	}
+	if !routesAvailable {
+		return true, nil
+	}
+
 	wildcardDomain := s.apimanagerResource.Spec.WildcardDomain
 	expectedRouteHosts := []string{
 		fmt.Sprintf("backend-%s.%s", *s.apimanagerResource.Spec.TenantName, wildcardDomain),                // Backend Listener route
@@ -197,7 +265,7 @@ func (s *APIManagerStatusReconciler) defaultRoutesReady() (bool, error) {
 	}
 
 	routeList := &routev1.RouteList{}
-	err := s.Client().List(context.TODO(), routeList, listOps...)
+	err = s.Client().List(context.TODO(), routeList, listOps...)
 	if err != nil {
 		return false, fmt.Errorf("Failed to list routes: %w", err)
 	}
@@ -221,3 +289,28 @@ func (s *APIManagerStatusReconciler) defaultRoutesReady() (bool, error) {
 
 	return allDefaultRoutesReady, nil
 }
+
+func (s *APIManagerStatusReconciler) defaultIngressesReady() (bool, error) {
+	expectedIngressNames := []string{
+		component.BackendListenerIngressName,
+		component.ApicastProductionIngressName,
+		component.ApicastStagingIngressName,
+		component.SystemMasterIngressName,
+		component.SystemDeveloperIngressName,
+		component.SystemProviderIngressName,
+	}
+
+	for _, ingressName := range expectedIngressNames {
+		ingress := &networkingv1beta1.Ingress{}
+		ingressKey := types.NamespacedName{Name: ingressName, Namespace: s.apimanagerResource.Namespace}
+		err := s.Client().Get(context.TODO(), ingressKey, ingress)
+		if err != nil && !errors.IsNotFound(err) {
+			return false, fmt.Errorf("Failed to get ingress %s: %w", ingressName, err)
+		}
+		if errors.IsNotFound(err) || !helper.IsIngressReady(ingress) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestAPIManagerStatusReconcilerAvailableWithoutRoutes(t *testing.T) {
+	tenantName := "3scale"
+	workloadType := appsv1alpha1.DeploymentWorkloadType
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "apimanager", Namespace: "test"},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				WildcardDomain: "example.com",
+				TenantName:     &tenantName,
+				WorkloadType:   &workloadType,
+			},
+		},
+	}
+
+	cases := []struct {
+		testName       string
+		routeResources []*metav1.APIResourceList
+		expectedStatus v1.ConditionStatus
+	}{
+		// routes are not listed, the scheme does not even know the Route kind
+		{"Route API not served", nil, v1.ConditionTrue},
+		{
+			"Route API served without routes",
+			[]*metav1.APIResourceList{
+				{
+					GroupVersion: routev1.GroupVersion.String(),
+					APIResources: []metav1.APIResource{{Name: "routes", Namespaced: true, Kind: "Route"}},
+				},
+			},
+			v1.ConditionFalse,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			s := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(s); err != nil {
+				subT.Fatal(err)
+			}
+			if err := appsv1alpha1.AddToScheme(s); err != nil {
+				subT.Fatal(err)
+			}
+			if tc.routeResources != nil {
+				if err := routev1.AddToScheme(s); err != nil {
+					subT.Fatal(err)
+				}
+			}
+
+			cl := fake.NewFakeClientWithScheme(s, apimanager)
+			clientset := fakeclientset.NewSimpleClientset()
+			clientset.Resources = tc.routeResources
+			baseReconciler := reconcilers.NewBaseReconciler(context.TODO(), cl, s, cl, logf.Log.WithName("apimanager status test"), clientset.Discovery(), record.NewFakeRecorder(10))
+
+			condition, err := NewAPIManagerStatusReconciler(baseReconciler, apimanager).apimanagerAvailableCondition(true)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if condition.Status != tc.expectedStatus {
+				subT.Errorf("unexpected Available condition status: got %s, expected %s", condition.Status, tc.expectedStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	appscommon "github.com/3scale/3scale-operator/apis/apps"
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/webhooks"
+)
+
+// +kubebuilder:webhook:path=/validate-apps-3scale-net-v1alpha1-apimanager,mutating=false,failurePolicy=fail,groups=apps.3scale.net,resources=apimanagers,verbs=create;update,versions=v1alpha1,name=vapimanager.apps.3scale.net
+
+// SetupAPIManagerWebhookWithManager registers the APIManager validating webhook
+func SetupAPIManagerWebhookWithManager(mgr ctrl.Manager, logger logr.Logger) error {
+	hook, err := webhooks.NewValidatingWebhook(mgr.GetScheme(),
+		func() runtime.Object { return &appsv1alpha1.APIManager{} },
+		validateAPIManager,
+		logger,
+	)
+	if err != nil {
+		return err
+	}
+
+	mgr.GetWebhookServer().Register(webhooks.ValidatePath(appsv1alpha1.GroupVersion.WithKind(appscommon.APIManagerKind)), &webhook.Admission{Handler: hook})
+	return nil
+}
+
+func validateAPIManager(_ context.Context, obj, _ runtime.Object) field.ErrorList {
+	return obj.(*appsv1alpha1.APIManager).Validate()
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -36,7 +37,7 @@ func NewAPIManagerBackupLogicReconciler(b *reconcilers.BaseReconciler, cr *appsv
 		cr:             cr,
 	}
 
-	if cr.BackupCompleted() {
+	if cr.BackupCompleted() || cr.BackupFailed() {
 		return res, nil
 	}
 
@@ -70,6 +71,11 @@ func (r *APIManagerBackupLogicReconciler) Reconcile() (reconcile.Result, error)
 		return reconcile.Result{}, nil
 	}
 
+	if r.cr.BackupFailed() {
+		r.Logger().Info("Backup failed. End of reconciliation")
+		return reconcile.Result{}, nil
+	}
+
 	if !r.cr.MainStepsCompleted() {
 		r.Logger().Info("Reconciling backup steps")
 		result, err := r.reconcileMainSteps()
@@ -104,6 +110,11 @@ func (r *APIManagerBackupLogicReconciler) reconcileMainSteps() (reconcile.Result
 		return result, err
 	}
 
+	result, err = r.reconcileBackupInS3Destination()
+	if result.Requeue || err != nil {
+		return result, err
+	}
+
 	result, err = r.reconcileSetMainStepsCompleted()
 	if result.Requeue || err != nil {
 		return result, err
@@ -118,6 +129,11 @@ func (r *APIManagerBackupLogicReconciler) reconcilePostBackupSteps() (reconcile.
 		return result, err
 	}
 
+	result, err = r.reconcileBackupWorkPVCCleanup()
+	if result.Requeue || err != nil {
+		return result, err
+	}
+
 	result, err = r.reconcileBackupCompletion()
 	if result.Requeue || err != nil {
 		return result, err
@@ -174,6 +190,11 @@ func (r *APIManagerBackupLogicReconciler) reconcileBackupInPVCDestination() (rec
 		return res, err
 	}
 
+	res, err = r.reconcileBackupDataToPVCJobs()
+	if res.Requeue || err != nil {
+		return res, err
+	}
+
 	return res, err
 }
 
@@ -183,12 +204,58 @@ func (r *APIManagerBackupLogicReconciler) reconcileBackupDestinationPVC() error
 		return nil
 	}
 
+	// The work PVC of S3 destinations is temporary so it is owned by
+	// the APIManagerBackup to be garbage collected with it
+	if r.apiManagerBackup.BackupWorkPVC() != nil {
+		if err := r.setOwnerReference(desired); err != nil {
+			return err
+		}
+	}
+
 	// TODO create mutator function for PVC ?
 	err := r.ReconcileResource(&v1.PersistentVolumeClaim{}, desired, reconcilers.CreateOnlyMutator)
 
 	return err
 }
 
+func (r *APIManagerBackupLogicReconciler) reconcileBackupInS3Destination() (reconcile.Result, error) {
+	desired := r.apiManagerBackup.UploadBackupToS3Job()
+	if desired == nil {
+		return reconcile.Result{}, nil
+	}
+
+	res, err := r.reconcileJob(desired)
+	if res.Requeue || err != nil {
+		return res, err
+	}
+
+	return r.reconcileBackupS3ObjectURLStatus()
+}
+
+func (r *APIManagerBackupLogicReconciler) reconcileBackupS3ObjectURLStatus() (reconcile.Result, error) {
+	if r.cr.Status.BackupS3ObjectURL != nil {
+		return reconcile.Result{}, nil
+	}
+
+	objectURL := r.apiManagerBackup.BackupDataS3ObjectURL()
+	r.cr.Status.BackupS3ObjectURL = &objectURL
+	err := r.UpdateResourceStatus(r.cr)
+	return reconcile.Result{Requeue: true}, err
+}
+
+// The work PVC is removed once the backup data has been uploaded to S3.
+// Jobs have to be deleted first so the PVC is not referenced anymore
+func (r *APIManagerBackupLogicReconciler) reconcileBackupWorkPVCCleanup() (reconcile.Result, error) {
+	desired := r.apiManagerBackup.BackupWorkPVC()
+	if desired == nil {
+		return reconcile.Result{}, nil
+	}
+
+	common.TagObjectToDelete(desired)
+	err := r.ReconcileResource(&v1.PersistentVolumeClaim{}, desired, reconcilers.CreateOnlyMutator)
+	return reconcile.Result{}, err
+}
+
 func (r *APIManagerBackupLogicReconciler) setOwnerReference(obj common.KubernetesObject) error {
 	err := controllerutil.SetControllerReference(r.cr, obj, r.BaseReconciler.Scheme())
 	if err != nil {
@@ -223,6 +290,11 @@ func (r *APIManagerBackupLogicReconciler) reconcileJob(desired *batchv1.Job) (re
 	// Jobs ownerReference or labels nor annotations not reconciled
 	// Jobs are one-shot so there's not much point on making updates to them
 
+	if failedCondition := jobFailedCondition(existing); failedCondition != nil {
+		r.Logger().Info("Job failed", "Job Name", desired.Name, "Reason", failedCondition.Reason)
+		return r.reconcileBackupFailure(fmt.Sprintf("Job %s failed: %s", desired.Name, failedCondition.Message))
+	}
+
 	if existing.Status.Succeeded != *desired.Spec.Completions {
 		r.Logger().Info("Job has still not finished", "Job Name", desired.Name, "Actively running Pods", existing.Status.Active, "Failed pods", existing.Status.Failed)
 		return reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Second}, nil
@@ -232,6 +304,27 @@ func (r *APIManagerBackupLogicReconciler) reconcileJob(desired *batchv1.Job) (re
 	return reconcile.Result{}, nil
 }
 
+// jobFailedCondition returns the Failed condition of the Job when it is
+// set. Nil otherwise
+func jobFailedCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for idx := range job.Status.Conditions {
+		condition := &job.Status.Conditions[idx]
+		if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+// Failed jobs are kept so the cause of the failure can be inspected
+func (r *APIManagerBackupLogicReconciler) reconcileBackupFailure(message string) (reconcile.Result, error) {
+	backupFailed := true
+	r.cr.Status.Failed = &backupFailed
+	r.cr.Status.FailureMessage = &message
+	err := r.UpdateResourceStatus(r.cr)
+	return reconcile.Result{Requeue: true}, err
+}
+
 func (r *APIManagerBackupLogicReconciler) reconcileBackupSecretsAndConfigMapsToPVCJob() (reconcile.Result, error) {
 	desired := r.apiManagerBackup.BackupSecretsAndConfigMapsToPVCJob()
 	if desired == nil {
@@ -259,6 +352,28 @@ func (r *APIManagerBackupLogicReconciler) reconcileBackupSystemFileStoragePVCToP
 	return r.reconcileJob(desired)
 }
 
+func (r *APIManagerBackupLogicReconciler) reconcileBackupDataToPVCJobs() (reconcile.Result, error) {
+	for _, desired := range r.apiManagerBackup.DataJobs() {
+		res, err := r.reconcileJob(desired)
+		if res.Requeue || err != nil {
+			return res, err
+		}
+	}
+
+	return r.reconcileDataArtifactsStatus()
+}
+
+func (r *APIManagerBackupLogicReconciler) reconcileDataArtifactsStatus() (reconcile.Result, error) {
+	dataArtifacts := r.apiManagerBackup.DataArtifacts()
+	if dataArtifacts == nil || r.cr.Status.DataArtifacts != nil {
+		return reconcile.Result{}, nil
+	}
+
+	r.cr.Status.DataArtifacts = dataArtifacts
+	err := r.UpdateResourceStatus(r.cr)
+	return reconcile.Result{Requeue: true}, err
+}
+
 func (r *APIManagerBackupLogicReconciler) reconcileBackupCompletion() (reconcile.Result, error) {
 	if !r.cr.BackupCompleted() {
 		// TODO make this more robust only setting it in case all substeps have been completed?
@@ -332,6 +447,10 @@ func (r *APIManagerBackupLogicReconciler) reconcileJobsCleanup() (reconcile.Resu
 		r.apiManagerBackup.BackupAPIManagerCustomResourceToPVCJob(),
 		r.apiManagerBackup.BackupSystemFileStoragePVCToPVCJob(),
 	}
+	jobsToDelete = append(jobsToDelete, r.apiManagerBackup.DataJobs()...)
+	if uploadJob := r.apiManagerBackup.UploadBackupToS3Job(); uploadJob != nil {
+		jobsToDelete = append(jobsToDelete, uploadJob)
+	}
 
 	existingJobFound := false
 	for _, job := range jobsToDelete {
@@ -386,7 +505,9 @@ func (r *APIManagerBackupLogicReconciler) reconcileBackupJobsServiceAccount() (r
 }
 
 func (r *APIManagerBackupLogicReconciler) reconcileBackupJobsRole() (reconcile.Result, error) {
-	err := r.ReconcileResource(&rbacv1.Role{}, r.apiManagerBackup.Role(), reconcilers.CreateOnlyMutator)
+	// Rules are reconciled so that roles created by previous versions
+	// get the permissions required by the data backup jobs
+	err := r.ReconcileResource(&rbacv1.Role{}, r.apiManagerBackup.Role(), reconcilers.RoleRulesMutator)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -0,0 +1,365 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/backup"
+	"github.com/3scale/3scale-operator/pkg/common"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+)
+
+// APIManagerBackupScheduleReconciler reconciles a APIManagerBackupSchedule object
+type APIManagerBackupScheduleReconciler struct {
+	*reconcilers.BaseReconciler
+}
+
+// blank assignment to verify that APIManagerBackupScheduleReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &APIManagerBackupScheduleReconciler{}
+
+// +kubebuilder:rbac:groups=apps.3scale.net,namespace=placeholder,resources=apimanagerbackupschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.3scale.net,namespace=placeholder,resources=apimanagerbackupschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.3scale.net,namespace=placeholder,resources=apimanagerbackupschedules/finalizers,verbs=get;list;watch;create;update;patch;delete
+
+func (r *APIManagerBackupScheduleReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	_ = context.Background()
+	logger := r.Logger().WithValues("apimanagerbackupschedule", req.NamespacedName)
+	logger.Info("Reconciling APIManagerBackupSchedule")
+
+	instance := &appsv1alpha1.APIManagerBackupSchedule{}
+	err := r.Client().Get(context.TODO(), req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("APIManagerBackupSchedule not found")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Error getting APIManagerBackupSchedule")
+		return ctrl.Result{}, err
+	}
+
+	backupList := &appsv1alpha1.APIManagerBackupList{}
+	err = r.Client().List(context.TODO(), backupList,
+		client.InNamespace(instance.Namespace),
+		client.MatchingLabels{appsv1alpha1.APIManagerBackupScheduleLabelKey: instance.Name},
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	backups := backupList.Items
+	backup.SortBackupsByScheduledTime(backups)
+
+	newStatus := instance.Status.DeepCopy()
+	r.reconcileBackupsStatus(instance, newStatus, backups)
+
+	// Backup data deletion failures are reported in the status and
+	// retried after backupPruneRetryDelay
+	pruneRes, err := r.reconcileRetention(logger, instance, newStatus, backups)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	scheduleRes, err := r.reconcileSchedule(logger, instance, newStatus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !reflect.DeepEqual(instance.Status, *newStatus) {
+		instance.Status = *newStatus
+		if err := r.UpdateResourceStatus(instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if pruneRes.Requeue && (!scheduleRes.Requeue || pruneRes.RequeueAfter < scheduleRes.RequeueAfter) {
+		return pruneRes, nil
+	}
+
+	logger.Info("Reconciliation finished", "requeueAfter", scheduleRes.RequeueAfter)
+	return scheduleRes, nil
+}
+
+func (r *APIManagerBackupScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1alpha1.APIManagerBackupSchedule{}).
+		Owns(&appsv1alpha1.APIManagerBackup{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
+
+// reconcileBackupsStatus sets the status fields describing the backups
+// created by the schedule. Backups are sorted, most recent first. Status
+// of pruned backups is kept until a more recent backup finishes. Backups
+// not finished within the deadline are reported as failed
+func (r *APIManagerBackupScheduleReconciler) reconcileBackupsStatus(instance *appsv1alpha1.APIManagerBackupSchedule, status *appsv1alpha1.APIManagerBackupScheduleStatus, backups []appsv1alpha1.APIManagerBackup) {
+	now := apimanagerbackupClock.Now().UTC()
+	deadline := instance.ActiveBackupDeadline()
+
+	var active, lastSuccessful, lastFailed *appsv1alpha1.APIManagerBackup
+	lastFailureMessage := ""
+	for idx := range backups {
+		backupCR := &backups[idx]
+		switch {
+		case backupCR.BackupCompleted():
+			if lastSuccessful == nil {
+				lastSuccessful = backupCR
+			}
+		case backupCR.BackupFailed():
+			if lastFailed == nil {
+				lastFailed = backupCR
+				if backupCR.Status.FailureMessage != nil {
+					lastFailureMessage = *backupCR.Status.FailureMessage
+				}
+			}
+		case backup.BackupStalled(backupCR, deadline, now):
+			if lastFailed == nil {
+				lastFailed = backupCR
+				lastFailureMessage = fmt.Sprintf("backup stalled, not finished within %s", deadline)
+			}
+		default:
+			if active == nil {
+				active = backupCR
+			}
+		}
+	}
+
+	status.ActiveBackupName = nil
+	if active != nil {
+		status.ActiveBackupName = &active.Name
+	}
+	if lastSuccessful != nil {
+		status.LastSuccessfulBackupName = &lastSuccessful.Name
+		status.LastSuccessfulTime = lastSuccessful.Status.CompletionTime
+	}
+	if lastFailed != nil {
+		status.LastFailedBackupName = &lastFailed.Name
+		status.LastFailureMessage = nil
+		if lastFailureMessage != "" {
+			status.LastFailureMessage = &lastFailureMessage
+		}
+	}
+}
+
+func (r *APIManagerBackupScheduleReconciler) reconcileSchedule(logger logr.Logger, instance *appsv1alpha1.APIManagerBackupSchedule, status *appsv1alpha1.APIManagerBackupScheduleStatus) (reconcile.Result, error) {
+	schedule, err := backup.ParseSchedule(instance.Spec.Schedule)
+	if err != nil {
+		logger.Info("Invalid schedule", "schedule", instance.Spec.Schedule, "error", err.Error())
+		errorMessage := err.Error()
+		status.ScheduleError = &errorMessage
+		status.NextScheduleTime = nil
+		return reconcile.Result{}, nil
+	}
+	status.ScheduleError = nil
+
+	now := apimanagerbackupClock.Now().UTC()
+	earliest := instance.CreationTimestamp.Time
+	if status.LastScheduleTime != nil {
+		earliest = status.LastScheduleTime.Time
+	}
+
+	if instance.IsSuspended() {
+		status.NextScheduleTime = nil
+		return reconcile.Result{}, nil
+	}
+
+	mostRecent, err := backup.MostRecentScheduleTime(schedule, earliest, now)
+	if err != nil {
+		// Backups missed for so long are not worth catching up
+		logger.Info("Skipping missed schedule times", "error", err.Error())
+		mostRecent = &now
+	}
+
+	// Only one backup runs at a time. The missed schedule time is caught
+	// up once the active backup finishes
+	if mostRecent != nil && status.ActiveBackupName == nil {
+		desired := backup.ScheduledBackup(instance, *mostRecent)
+		if err := r.SetOwnerReference(instance, desired); err != nil {
+			return reconcile.Result{}, err
+		}
+		err := r.CreateResource(desired)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		}
+		logger.Info("Scheduled backup created", "APIManagerBackup", desired.Name)
+		r.EventRecorder().Eventf(instance, v1.EventTypeNormal, "BackupCreated", "Created APIManagerBackup %s", desired.Name)
+		scheduledTime := metav1.NewTime(*mostRecent)
+		status.LastScheduleTime = &scheduledTime
+		status.ActiveBackupName = &desired.Name
+	}
+
+	next := schedule.Next(now)
+	if next.IsZero() {
+		status.NextScheduleTime = nil
+		return reconcile.Result{}, nil
+	}
+	nextScheduleTime := metav1.NewTime(next)
+	status.NextScheduleTime = &nextScheduleTime
+
+	return reconcile.Result{Requeue: true, RequeueAfter: next.Sub(now)}, nil
+}
+
+// backupPruneRetryDelay is the time failed backup data deletion jobs are
+// kept, so the failure can be inspected, before they are recreated
+const backupPruneRetryDelay = 10 * time.Minute
+
+// backupPruneError is returned when the data of a pruned backup could not
+// be deleted
+type backupPruneError struct {
+	backupName string
+	message    string
+	// retryAfter is the time until the deletion is retried
+	retryAfter time.Duration
+}
+
+func (e *backupPruneError) Error() string {
+	return fmt.Sprintf("backup data of APIManagerBackup %s could not be deleted: %s", e.backupName, e.message)
+}
+
+// reconcileRetention deletes the backups not selected by the retention
+// rules together with their backup data. Backups whose data could not be
+// deleted are kept and reported in the status until the deletion is retried
+func (r *APIManagerBackupScheduleReconciler) reconcileRetention(logger logr.Logger, instance *appsv1alpha1.APIManagerBackupSchedule, status *appsv1alpha1.APIManagerBackupScheduleStatus, backups []appsv1alpha1.APIManagerBackup) (reconcile.Result, error) {
+	pruneInProgress := false
+	var pruneErr *backupPruneError
+	retryAfter := backupPruneRetryDelay
+	for _, backupCR := range backup.BackupsToPrune(backups, instance.Spec.Retention) {
+		pruned, err := r.pruneBackup(logger, instance, &backupCR)
+		if failure, ok := err.(*backupPruneError); ok {
+			if pruneErr == nil {
+				pruneErr = failure
+			}
+			if failure.retryAfter < retryAfter {
+				retryAfter = failure.retryAfter
+			}
+			continue
+		}
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !pruned {
+			pruneInProgress = true
+		}
+	}
+
+	if pruneErr == nil {
+		status.PruneError = nil
+	} else {
+		errorMessage := pruneErr.Error()
+		// The warning is only emitted when the failure changes
+		if status.PruneError == nil || *status.PruneError != errorMessage {
+			r.EventRecorder().Eventf(instance, v1.EventTypeWarning, "PruneFailed", "%s", errorMessage)
+		}
+		status.PruneError = &errorMessage
+	}
+
+	if pruneInProgress {
+		retryAfter = 5 * time.Second
+	}
+	if pruneInProgress || pruneErr != nil {
+		return reconcile.Result{Requeue: true, RequeueAfter: retryAfter}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// pruneBackup deletes the backup data and then the backup. Returns false
+// while the backup data is still being deleted. Returns a backupPruneError
+// when the backup data could not be deleted. Failed deletion jobs are
+// deleted after backupPruneRetryDelay, so they are created again
+func (r *APIManagerBackupScheduleReconciler) pruneBackup(logger logr.Logger, instance *appsv1alpha1.APIManagerBackupSchedule, backupCR *appsv1alpha1.APIManagerBackup) (bool, error) {
+	deleteJob := backup.DeleteS3BackupDataJob(backupCR)
+	if deleteJob != nil {
+		if err := r.SetOwnerReference(instance, deleteJob); err != nil {
+			return false, err
+		}
+
+		existing := &batchv1.Job{}
+		err := r.GetResource(types.NamespacedName{Name: deleteJob.Name, Namespace: deleteJob.Namespace}, existing)
+		if err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+
+		if errors.IsNotFound(err) {
+			logger.Info("Deleting backup data from S3", "APIManagerBackup", backupCR.Name)
+			return false, r.CreateResource(deleteJob)
+		}
+
+		if failedCondition := jobFailedCondition(existing); failedCondition != nil {
+			// The job is kept for a while so the failure can be inspected. The
+			// backup is kept too as it is the only reference to the backup data
+			pruneErr := &backupPruneError{backupName: backupCR.Name, message: failedCondition.Message}
+			pruneErr.retryAfter = failedCondition.LastTransitionTime.Add(backupPruneRetryDelay).Sub(apimanagerbackupClock.Now())
+			if pruneErr.retryAfter <= 0 {
+				logger.Info("Retrying backup data deletion from S3", "APIManagerBackup", backupCR.Name)
+				err := r.DeleteResource(existing, client.PropagationPolicy(metav1.DeletePropagationBackground))
+				if err != nil && !errors.IsNotFound(err) {
+					return false, err
+				}
+				pruneErr.retryAfter = 5 * time.Second
+			}
+			return false, pruneErr
+		}
+
+		if existing.Status.Succeeded != *deleteJob.Spec.Completions {
+			return false, nil
+		}
+
+		common.TagToObjectDeleteWithPropagationPolicy(deleteJob, metav1.DeletePropagationBackground)
+		if err := r.ReconcileResource(&batchv1.Job{}, deleteJob, reconcilers.CreateOnlyMutator); err != nil {
+			return false, err
+		}
+	}
+
+	if backupCR.Spec.BackupDestination.PersistentVolumeClaim != nil && backupCR.Status.BackupPersistentVolumeClaimName != nil {
+		pvc := &v1.PersistentVolumeClaim{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "PersistentVolumeClaim",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      *backupCR.Status.BackupPersistentVolumeClaimName,
+				Namespace: backupCR.Namespace,
+			},
+		}
+		common.TagObjectToDelete(pvc)
+		if err := r.ReconcileResource(&v1.PersistentVolumeClaim{}, pvc, reconcilers.CreateOnlyMutator); err != nil {
+			return false, err
+		}
+	}
+
+	logger.Info("Pruning backup", "APIManagerBackup", backupCR.Name)
+	err := r.DeleteResource(backupCR, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	r.EventRecorder().Eventf(instance, v1.EventTypeNormal, "BackupPruned", "Deleted APIManagerBackup %s", backupCR.Name)
+
+	return true, nil
+}
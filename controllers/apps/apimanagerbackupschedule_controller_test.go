@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/backup"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubeclock "k8s.io/apimachinery/pkg/util/clock"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var backupScheduleTestNow = time.Date(2020, 11, 10, 3, 30, 0, 0, time.UTC)
+
+func backupScheduleTestReconciler(t *testing.T, objs ...runtime.Object) (*APIManagerBackupScheduleReconciler, *record.FakeRecorder) {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := appsv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+	clientset := fakeclientset.NewSimpleClientset()
+	recorder := record.NewFakeRecorder(100)
+	baseReconciler := reconcilers.NewBaseReconciler(context.TODO(), cl, s, cl, logf.Log.WithName("apimanagerbackupschedule test"), clientset.Discovery(), recorder)
+	return &APIManagerBackupScheduleReconciler{BaseReconciler: baseReconciler}, recorder
+}
+
+// setBackupScheduleTestClock sets the test clock. Returns the function restoring the previous clock
+func setBackupScheduleTestClock() func() {
+	previous := apimanagerbackupClock
+	apimanagerbackupClock = kubeclock.NewFakeClock(backupScheduleTestNow)
+	return func() { apimanagerbackupClock = previous }
+}
+
+func testBackupSchedule(suspend bool, retention *appsv1alpha1.APIManagerBackupRetention) *appsv1alpha1.APIManagerBackupSchedule {
+	return &appsv1alpha1.APIManagerBackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "nightly",
+			Namespace:         "test",
+			CreationTimestamp: metav1.NewTime(backupScheduleTestNow.Add(-24 * time.Hour)),
+		},
+		Spec: appsv1alpha1.APIManagerBackupScheduleSpec{
+			Schedule:  "@hourly",
+			Suspend:   &suspend,
+			Retention: retention,
+		},
+	}
+}
+
+func testScheduleBackup(schedule *appsv1alpha1.APIManagerBackupSchedule, scheduledTime time.Time, completed bool) *appsv1alpha1.APIManagerBackup {
+	backupCR := backup.ScheduledBackup(schedule, scheduledTime)
+	backupCR.UID = types.UID("f4a0ab5a-2a4b-4f3c-a6e2-" + scheduledTime.Format("150405") + "000000")
+	backupCR.Status.Completed = &completed
+	return backupCR
+}
+
+func reconcileBackupSchedule(t *testing.T, r *APIManagerBackupScheduleReconciler) (ctrl.Result, *appsv1alpha1.APIManagerBackupSchedule, error) {
+	res, reconcileErr := r.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: "nightly", Namespace: "test"}})
+	schedule := &appsv1alpha1.APIManagerBackupSchedule{}
+	if err := r.Client().Get(context.TODO(), types.NamespacedName{Name: "nightly", Namespace: "test"}, schedule); err != nil {
+		t.Fatal(err)
+	}
+	return res, schedule, reconcileErr
+}
+
+func backupExists(t *testing.T, r *APIManagerBackupScheduleReconciler, name string) bool {
+	err := r.Client().Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "test"}, &appsv1alpha1.APIManagerBackup{})
+	if err != nil && !errors.IsNotFound(err) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+func countEvents(recorder *record.FakeRecorder, prefix string) int {
+	count := 0
+	for {
+		select {
+		case event := <-recorder.Events:
+			if strings.HasPrefix(event, prefix) {
+				count++
+			}
+		default:
+			return count
+		}
+	}
+}
+
+func TestAPIManagerBackupScheduleReconcilerSchedule(t *testing.T) {
+	defer setBackupScheduleTestClock()()
+	r, _ := backupScheduleTestReconciler(t, testBackupSchedule(false, nil))
+
+	res, schedule, err := reconcileBackupSchedule(t, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedName := backup.ScheduledBackupName("nightly", time.Date(2020, 11, 10, 3, 0, 0, 0, time.UTC))
+	if !backupExists(t, r, expectedName) {
+		t.Errorf("backup %s not created", expectedName)
+	}
+	if schedule.Status.ActiveBackupName == nil || *schedule.Status.ActiveBackupName != expectedName {
+		t.Errorf("unexpected active backup: %v", schedule.Status.ActiveBackupName)
+	}
+	if schedule.Status.NextScheduleTime == nil || !schedule.Status.NextScheduleTime.Time.Equal(time.Date(2020, 11, 10, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected next schedule time: %v", schedule.Status.NextScheduleTime)
+	}
+	if res.RequeueAfter != 30*time.Minute {
+		t.Errorf("unexpected requeue after: %s", res.RequeueAfter)
+	}
+
+	// The active backup prevents new backups until it finishes
+	apimanagerbackupClock = kubeclock.NewFakeClock(backupScheduleTestNow.Add(time.Hour))
+	_, schedule, err = reconcileBackupSchedule(t, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backupExists(t, r, backup.ScheduledBackupName("nightly", time.Date(2020, 11, 10, 4, 0, 0, 0, time.UTC))) {
+		t.Error("backup created while another backup is active")
+	}
+	if schedule.Status.ActiveBackupName == nil || *schedule.Status.ActiveBackupName != expectedName {
+		t.Errorf("unexpected active backup: %v", schedule.Status.ActiveBackupName)
+	}
+}
+
+func TestAPIManagerBackupScheduleReconcilerStalledBackup(t *testing.T) {
+	defer setBackupScheduleTestClock()()
+	schedule := testBackupSchedule(false, nil)
+	var deadlineSeconds int64 = 3600
+	schedule.Spec.ActiveBackupDeadlineSeconds = &deadlineSeconds
+	lastScheduleTime := metav1.NewTime(time.Date(2020, 11, 10, 1, 0, 0, 0, time.UTC))
+	schedule.Status.LastScheduleTime = &lastScheduleTime
+	stalled := testScheduleBackup(schedule, lastScheduleTime.Time, false)
+	schedule.Status.ActiveBackupName = &stalled.Name
+
+	r, _ := backupScheduleTestReconciler(t, schedule, stalled)
+
+	_, schedule, err := reconcileBackupSchedule(t, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if schedule.Status.LastFailedBackupName == nil || *schedule.Status.LastFailedBackupName != stalled.Name {
+		t.Errorf("stalled backup not reported as failed: %v", schedule.Status.LastFailedBackupName)
+	}
+	if schedule.Status.LastFailureMessage == nil || !strings.Contains(*schedule.Status.LastFailureMessage, "stalled") {
+		t.Errorf("unexpected failure message: %v", schedule.Status.LastFailureMessage)
+	}
+	expectedName := backup.ScheduledBackupName("nightly", time.Date(2020, 11, 10, 3, 0, 0, 0, time.UTC))
+	if schedule.Status.ActiveBackupName == nil || *schedule.Status.ActiveBackupName != expectedName {
+		t.Errorf("unexpected active backup: %v", schedule.Status.ActiveBackupName)
+	}
+	if !backupExists(t, r, stalled.Name) {
+		t.Error("stalled backup should be kept")
+	}
+}
+
+func TestAPIManagerBackupScheduleReconcilerRetention(t *testing.T) {
+	defer setBackupScheduleTestClock()()
+	var keepLast int32 = 1
+	schedule := testBackupSchedule(true, &appsv1alpha1.APIManagerBackupRetention{KeepLast: &keepLast})
+	older := testScheduleBackup(schedule, time.Date(2020, 11, 10, 1, 0, 0, 0, time.UTC), true)
+	newer := testScheduleBackup(schedule, time.Date(2020, 11, 10, 2, 0, 0, 0, time.UTC), true)
+
+	r, recorder := backupScheduleTestReconciler(t, schedule, older, newer)
+
+	_, schedule, err := reconcileBackupSchedule(t, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if backupExists(t, r, older.Name) {
+		t.Errorf("backup %s should be pruned", older.Name)
+	}
+	if !backupExists(t, r, newer.Name) {
+		t.Errorf("backup %s should be kept", newer.Name)
+	}
+	if schedule.Status.LastSuccessfulBackupName == nil || *schedule.Status.LastSuccessfulBackupName != newer.Name {
+		t.Errorf("unexpected last successful backup: %v", schedule.Status.LastSuccessfulBackupName)
+	}
+	if count := countEvents(recorder, "Normal BackupPruned"); count != 1 {
+		t.Errorf("expected 1 BackupPruned event, got %d", count)
+	}
+}
+
+func TestAPIManagerBackupScheduleReconcilerPruneFailed(t *testing.T) {
+	defer setBackupScheduleTestClock()()
+	var keepLast int32 = 1
+	schedule := testBackupSchedule(true, &appsv1alpha1.APIManagerBackupRetention{KeepLast: &keepLast})
+	older := testScheduleBackup(schedule, time.Date(2020, 11, 10, 1, 0, 0, 0, time.UTC), true)
+	prefix := "/backups/3scale/"
+	older.Spec.BackupDestination = appsv1alpha1.APIManagerBackupDestination{
+		S3: &appsv1alpha1.S3BackupDestination{
+			S3Location: appsv1alpha1.S3Location{
+				Bucket:               "mybucket",
+				Prefix:               &prefix,
+				CredentialsSecretRef: v1.LocalObjectReference{Name: "s3-credentials"},
+			},
+		},
+	}
+	objectURL := "s3://mybucket/backups/3scale/" + older.Name + ".tar.gz"
+	older.Status.BackupS3ObjectURL = &objectURL
+	newer := testScheduleBackup(schedule, time.Date(2020, 11, 10, 2, 0, 0, 0, time.UTC), true)
+
+	deleteJob := backup.DeleteS3BackupDataJob(older)
+	deleteJob.Status.Conditions = []batchv1.JobCondition{
+		{
+			Type:               batchv1.JobFailed,
+			Status:             v1.ConditionTrue,
+			Message:            "BackoffLimitExceeded",
+			LastTransitionTime: metav1.NewTime(backupScheduleTestNow.Add(-time.Minute)),
+		},
+	}
+
+	r, recorder := backupScheduleTestReconciler(t, schedule, older, newer, deleteJob)
+
+	for i := 0; i < 2; i++ {
+		res, schedule, err := reconcileBackupSchedule(t, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.RequeueAfter != backupPruneRetryDelay-time.Minute {
+			t.Errorf("unexpected requeue after: %s", res.RequeueAfter)
+		}
+		if schedule.Status.PruneError == nil || !strings.Contains(*schedule.Status.PruneError, "BackoffLimitExceeded") {
+			t.Errorf("unexpected prune error: %v", schedule.Status.PruneError)
+		}
+	}
+
+	if !backupExists(t, r, older.Name) {
+		t.Error("backup with undeleted data should be kept")
+	}
+	if count := countEvents(recorder, "Warning PruneFailed"); count != 1 {
+		t.Errorf("expected 1 PruneFailed event, got %d", count)
+	}
+
+	jobKey := types.NamespacedName{Name: deleteJob.Name, Namespace: deleteJob.Namespace}
+	if err := r.Client().Get(context.TODO(), jobKey, &batchv1.Job{}); err != nil {
+		t.Fatalf("failed job should be kept until the retry: %v", err)
+	}
+
+	// The failed job is deleted after the retry delay and created again
+	apimanagerbackupClock = kubeclock.NewFakeClock(backupScheduleTestNow.Add(backupPruneRetryDelay))
+	_, _, err := reconcileBackupSchedule(t, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client().Get(context.TODO(), jobKey, &batchv1.Job{}); !errors.IsNotFound(err) {
+		t.Fatalf("failed job should be deleted: %v", err)
+	}
+
+	_, schedule, err = reconcileBackupSchedule(t, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recreated := &batchv1.Job{}
+	if err := r.Client().Get(context.TODO(), jobKey, recreated); err != nil {
+		t.Fatalf("delete job should be created again: %v", err)
+	}
+	if jobFailedCondition(recreated) != nil {
+		t.Error("recreated job should not be failed")
+	}
+	if schedule.Status.PruneError != nil {
+		t.Errorf("prune error should be cleared while retrying: %s", *schedule.Status.PruneError)
+	}
+}
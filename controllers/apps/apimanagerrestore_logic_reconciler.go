@@ -94,6 +94,11 @@ func (r *APIManagerRestoreLogicReconciler) reconcilePostRestoreSteps() (reconcil
 		return result, err
 	}
 
+	result, err = r.reconcileRestoreWorkPVCCleanup()
+	if result.Requeue || err != nil {
+		return result, err
+	}
+
 	result, err = r.reconcileRestoreCompletion()
 	if result.Requeue || err != nil {
 		return result, err
@@ -135,6 +140,11 @@ func (r *APIManagerRestoreLogicReconciler) reconcileRestoreFromPVCSource() (reco
 		return res, err
 	}
 
+	res, err = r.reconcileDownloadFromS3Source()
+	if res.Requeue || err != nil {
+		return res, err
+	}
+
 	res, err = r.reconcileRestoreSecretsAndConfigMapsFromPVCJob()
 	if res.Requeue || err != nil {
 		return res, err
@@ -155,6 +165,11 @@ func (r *APIManagerRestoreLogicReconciler) reconcileRestoreFromPVCSource() (reco
 		return res, err
 	}
 
+	res, err = r.reconcileRestoreDataFromPVCJobs()
+	if res.Requeue || err != nil {
+		return res, err
+	}
+
 	res, err = r.reconcileResynchronizeZyncDomains()
 	if res.Requeue || err != nil {
 		return res, err
@@ -173,6 +188,39 @@ func (r *APIManagerRestoreLogicReconciler) reconcileRestoreFromPVCSource() (reco
 	return res, err
 }
 
+// With S3 restore sources the backup data archive is downloaded and
+// extracted into a work PVC, which is used as the source of the rest
+// of the restore steps
+func (r *APIManagerRestoreLogicReconciler) reconcileDownloadFromS3Source() (reconcile.Result, error) {
+	workPVC := r.apiManagerRestore.RestoreWorkPVC()
+	if workPVC == nil {
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.setOwnerReference(workPVC); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err := r.ReconcileResource(&v1.PersistentVolumeClaim{}, workPVC, reconcilers.CreateOnlyMutator)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return r.reconcileJob(r.apiManagerRestore.DownloadBackupFromS3Job())
+}
+
+// The work PVC is removed once the jobs referencing it have been deleted
+func (r *APIManagerRestoreLogicReconciler) reconcileRestoreWorkPVCCleanup() (reconcile.Result, error) {
+	desired := r.apiManagerRestore.RestoreWorkPVC()
+	if desired == nil {
+		return reconcile.Result{}, nil
+	}
+
+	common.TagObjectToDelete(desired)
+	err := r.ReconcileResource(&v1.PersistentVolumeClaim{}, desired, reconcilers.CreateOnlyMutator)
+	return reconcile.Result{}, err
+}
+
 func (r *APIManagerRestoreLogicReconciler) setOwnerReference(obj common.KubernetesObject) error {
 	err := controllerutil.SetControllerReference(r.cr, obj, r.BaseReconciler.Scheme())
 	if err != nil {
@@ -465,6 +513,75 @@ func (r *APIManagerRestoreLogicReconciler) reconcileWaitForAPIManagerReady() (re
 	return reconcile.Result{}, nil
 }
 
+// Restores the database data artifacts found in the restore source once the
+// deployments of the corresponding databases are ready. Artifacts of databases
+// not managed by the restored APIManager are skipped
+func (r *APIManagerRestoreLogicReconciler) reconcileRestoreDataFromPVCJobs() (reconcile.Result, error) {
+	if r.cr.Status.RestoredDataArtifacts != nil {
+		return reconcile.Result{}, nil
+	}
+
+	artifacts, err := r.dataArtifactsFromSharedBackupSecret()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(artifacts) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	existingAPIManager := &appsv1alpha1.APIManager{}
+	err = r.GetResource(types.NamespacedName{Name: r.cr.Status.APIManagerToRestoreRef.Name, Namespace: r.cr.Namespace}, existingAPIManager)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			r.Logger().Info("APIManager not found. Waiting until it exists", "APIManager", r.cr.Status.APIManagerToRestoreRef.Name)
+			return reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Second}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	restoredArtifacts := []string{}
+	for _, artifact := range artifacts {
+		if !restore.IsDataArtifactRestorable(existingAPIManager, artifact) {
+			r.Logger().Info("Database not managed by the APIManager. Skipping data restore", "Artifact", artifact)
+			continue
+		}
+
+		deploymentName := restore.DataArtifactDeploymentName(artifact)
+		if !helper.ArrayContains(existingAPIManager.Status.Deployments.Ready, deploymentName) {
+			r.Logger().Info("Database deployment not ready. Waiting", "Deployment", deploymentName)
+			return reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Second}, nil
+		}
+
+		res, err := r.reconcileJob(r.apiManagerRestore.RestoreDataFromPVCJob(artifact))
+		if res.Requeue || err != nil {
+			return res, err
+		}
+		restoredArtifacts = append(restoredArtifacts, artifact)
+	}
+
+	restoredDataArtifacts := restore.RestoredDataArtifacts(restoredArtifacts)
+	if restoredDataArtifacts == nil {
+		return reconcile.Result{}, nil
+	}
+
+	r.cr.Status.RestoredDataArtifacts = restoredDataArtifacts
+	err = r.UpdateResourceStatus(r.cr)
+	return reconcile.Result{Requeue: true}, err
+}
+
+func (r *APIManagerRestoreLogicReconciler) dataArtifactsFromSharedBackupSecret() ([]string, error) {
+	secret, err := r.sharedBackupSecret()
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("Secret '%s' not found", r.apiManagerRestore.SecretToShareName())
+	}
+
+	// The listing is empty when the backup does not hold database data
+	return restore.ParseDataArtifacts(secret.Data[restore.DataArtifactsSecretKey]), nil
+}
+
 func (r *APIManagerRestoreLogicReconciler) reconcileResynchronizeZyncDomains() (reconcile.Result, error) {
 	// system-sidekiq pod need to be up&running
 	res, err := r.waitForSystemSidekiq()
@@ -515,6 +632,10 @@ func (r *APIManagerRestoreLogicReconciler) reconcileJobsCleanup() (reconcile.Res
 		r.apiManagerRestore.CreateAPIManagerSharedSecretJob(),
 		r.apiManagerRestore.ZyncResyncDomainsJob(),
 	}
+	jobsToDelete = append(jobsToDelete, r.apiManagerRestore.DataRestoreJobs()...)
+	if downloadJob := r.apiManagerRestore.DownloadBackupFromS3Job(); downloadJob != nil {
+		jobsToDelete = append(jobsToDelete, downloadJob)
+	}
 
 	existingJobFound := false
 	for _, job := range jobsToDelete {
@@ -20,7 +20,9 @@ import (
 	"context"
 	"strings"
 
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -75,8 +77,20 @@ func (r *WebConsoleReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 }
 
 func (r *WebConsoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	routesAvailable, err := r.HasRoutes()
+	if err != nil {
+		return err
+	}
+
+	// Console links are only available on OpenShift, where zync exposes the master portal with a route.
+	// Without routes, watch ingresses so the controller can be started on any kubernetes cluster.
+	var watchedType runtime.Object = &networkingv1beta1.Ingress{}
+	if routesAvailable {
+		watchedType = &routev1.Route{}
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&routev1.Route{}).
+		For(watchedType).
 		Complete(r)
 }
 
@@ -24,10 +24,15 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
 	"github.com/3scale/3scale-operator/pkg/helper"
@@ -35,6 +40,8 @@ import (
 	"github.com/3scale/3scale-operator/version"
 )
 
+const activeDocFinalizer = "activedoc.capabilities.3scale.net/finalizer"
+
 // ActiveDocReconciler reconciles a ActiveDoc object
 type ActiveDocReconciler struct {
 	*reconcilers.BaseReconciler
@@ -45,6 +52,7 @@ var _ reconcile.Reconciler = &ActiveDocReconciler{}
 
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=activedocs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=activedocs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=activedocs/finalizers,verbs=get;list;watch;create;update;patch;delete
 
 func (r *ActiveDocReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	_ = context.Background()
@@ -74,12 +82,44 @@ func (r *ActiveDocReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		reqLogger.V(1).Info(string(jsonData))
 	}
 
+	// ActiveDoc has been marked for deletion
+	if activeDocCR.GetDeletionTimestamp() != nil && controllerutil.ContainsFinalizer(activeDocCR, activeDocFinalizer) {
+		err = r.removeActiveDocFrom3scale(activeDocCR)
+		if err != nil {
+			r.EventRecorder().Eventf(activeDocCR, corev1.EventTypeWarning, "Failed to delete activedoc", "%v", err)
+			return ctrl.Result{}, err
+		}
+
+		controllerutil.RemoveFinalizer(activeDocCR, activeDocFinalizer)
+		err = r.UpdateResource(activeDocCR)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
 	// Ignore deleted resource, this can happen when foregroundDeletion is enabled
 	// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#foreground-cascading-deletion
 	if activeDocCR.DeletionTimestamp != nil {
 		return ctrl.Result{}, nil
 	}
 
+	if !controllerutil.ContainsFinalizer(activeDocCR, activeDocFinalizer) {
+		// ActiveDocs synchronized before the finalizer was introduced keep
+		// the 3scale activedoc on deletion, as they did before the upgrade
+		if activeDocCR.Status.ID != nil {
+			metav1.SetMetaDataAnnotation(&activeDocCR.ObjectMeta, capabilitiesv1beta1.DefaultDeletionPolicyAnnotation,
+				string(capabilitiesv1beta1.DeletionPolicyOrphan))
+		}
+		controllerutil.AddFinalizer(activeDocCR, activeDocFinalizer)
+		err = r.UpdateResource(activeDocCR)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	if activeDocCR.SetDefaults(reqLogger) {
 		err := r.Client().Update(r.Context(), activeDocCR)
 		if err != nil {
@@ -205,3 +245,39 @@ func (r *ActiveDocReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&capabilitiesv1beta1.ActiveDoc{}).
 		Complete(r)
 }
+
+func (r *ActiveDocReconciler) removeActiveDocFrom3scale(activeDocCR *capabilitiesv1beta1.ActiveDoc) error {
+	logger := r.Logger().WithValues("activedoc", client.ObjectKey{Name: activeDocCR.Name, Namespace: activeDocCR.Namespace})
+
+	if capabilitiesv1beta1.OrphanOnDeletion(capabilitiesv1beta1.EffectiveDeletionPolicy(activeDocCR.Spec.DeletionPolicy, activeDocCR.Annotations)) {
+		logger.Info("activedoc not deleted from 3scale, deletion policy is Orphan")
+		return nil
+	}
+
+	// Attempt to remove activedoc only if activeDocCR.Status.ID is present
+	if activeDocCR.Status.ID == nil {
+		logger.Info("could not remove activedoc because ID is missing in status")
+		return nil
+	}
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), activeDocCR.Namespace, activeDocCR.Spec.ProviderAccountRef, logger)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("activedoc not deleted from 3scale, provider account not found")
+			return nil
+		}
+		return err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		return err
+	}
+
+	err = threescaleAPIClient.DeleteActiveDoc(*activeDocCR.Status.ID)
+	if err != nil && !threescaleapi.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
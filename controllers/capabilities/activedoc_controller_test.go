@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+)
+
+func activeDocTestProviderAccount(adminURL string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "threescale-provider-account", Namespace: "test"},
+		Data: map[string][]byte{
+			"adminURL": []byte(adminURL),
+			"token":    []byte("token"),
+		},
+	}
+}
+
+func TestActiveDocReconcilerFinalizer(t *testing.T) {
+	var activeDocID int64 = 5
+	deletePolicy := capabilitiesv1beta1.DeletionPolicyDelete
+
+	tests := []struct {
+		name               string
+		id                 *int64
+		deletionPolicy     *capabilitiesv1beta1.DeletionPolicy
+		wantDeletionPolicy capabilitiesv1beta1.DeletionPolicy
+	}{
+		{"new activedoc", nil, nil, capabilitiesv1beta1.DeletionPolicyDelete},
+		// activedocs synchronized by previous operator versions keep the 3scale activedoc
+		{"synchronized activedoc", &activeDocID, nil, capabilitiesv1beta1.DeletionPolicyOrphan},
+		{"synchronized activedoc with policy", &activeDocID, &deletePolicy, capabilitiesv1beta1.DeletionPolicyDelete},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(subT *testing.T) {
+			activeDoc := &capabilitiesv1beta1.ActiveDoc{
+				ObjectMeta: metav1.ObjectMeta{Name: "activedoc", Namespace: "test"},
+				Spec:       capabilitiesv1beta1.ActiveDocSpec{Name: "activedoc", DeletionPolicy: tt.deletionPolicy},
+				Status:     capabilitiesv1beta1.ActiveDocStatus{ID: tt.id},
+			}
+			r := &ActiveDocReconciler{BaseReconciler: openapiTestReconciler(activeDoc)}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "activedoc", Namespace: "test"}}
+
+			_, err := r.Reconcile(req)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			updated := &capabilitiesv1beta1.ActiveDoc{}
+			err = r.Client().Get(context.TODO(), req.NamespacedName, updated)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if !controllerutil.ContainsFinalizer(updated, activeDocFinalizer) {
+				subT.Error("finalizer not added")
+			}
+			// the spec is owned by users, the upgrade default is kept in the annotations
+			if !reflect.DeepEqual(updated.Spec.DeletionPolicy, tt.deletionPolicy) {
+				subT.Errorf("deletion policy spec changed: %v", updated.Spec.DeletionPolicy)
+			}
+			policy := capabilitiesv1beta1.EffectiveDeletionPolicy(updated.Spec.DeletionPolicy, updated.Annotations)
+			if *policy != tt.wantDeletionPolicy {
+				subT.Errorf("unexpected deletion policy: %s", *policy)
+			}
+		})
+	}
+}
+
+func TestActiveDocReconcilerDeletion(t *testing.T) {
+	orphan := capabilitiesv1beta1.DeletionPolicyOrphan
+	deletePolicy := capabilitiesv1beta1.DeletionPolicyDelete
+
+	orphanAnnotation := map[string]string{capabilitiesv1beta1.DefaultDeletionPolicyAnnotation: string(orphan)}
+
+	tests := []struct {
+		name           string
+		deletionPolicy *capabilitiesv1beta1.DeletionPolicy
+		annotations    map[string]string
+		wantDeleted    bool
+	}{
+		{"default policy", nil, nil, true},
+		{"delete policy", &deletePolicy, nil, true},
+		{"orphan policy", &orphan, nil, false},
+		{"upgrade default policy", nil, orphanAnnotation, false},
+		{"delete policy over upgrade default", &deletePolicy, orphanAnnotation, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(subT *testing.T) {
+			deleted := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.Method == http.MethodDelete && req.URL.Path == "/admin/api/active_docs/5.json" {
+					deleted = true
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer server.Close()
+
+			now := metav1.Now()
+			var activeDocID int64 = 5
+			activeDoc := &capabilitiesv1beta1.ActiveDoc{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "activedoc",
+					Namespace:         "test",
+					DeletionTimestamp: &now,
+					Finalizers:        []string{activeDocFinalizer},
+					Annotations:       tt.annotations,
+				},
+				Spec:   capabilitiesv1beta1.ActiveDocSpec{Name: "activedoc", DeletionPolicy: tt.deletionPolicy},
+				Status: capabilitiesv1beta1.ActiveDocStatus{ID: &activeDocID},
+			}
+			r := &ActiveDocReconciler{BaseReconciler: openapiTestReconciler(activeDocTestProviderAccount(server.URL), activeDoc)}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "activedoc", Namespace: "test"}}
+
+			_, err := r.Reconcile(req)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if deleted != tt.wantDeleted {
+				subT.Errorf("3scale activedoc deleted = %t, want %t", deleted, tt.wantDeleted)
+			}
+
+			updated := &capabilitiesv1beta1.ActiveDoc{}
+			err = r.Client().Get(context.TODO(), req.NamespacedName, updated)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if controllerutil.ContainsFinalizer(updated, activeDocFinalizer) {
+				subT.Error("finalizer not removed")
+			}
+		})
+	}
+}
@@ -0,0 +1,431 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+	"github.com/3scale/3scale-operator/version"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const applicationFinalizer = "application.capabilities.3scale.net/finalizer"
+
+// ApplicationReconciler reconciles a Application object
+type ApplicationReconciler struct {
+	*reconcilers.BaseReconciler
+}
+
+// blank assignment to verify that ApplicationReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ApplicationReconciler{}
+
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=applications,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=applications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=applications/finalizers,verbs=get;list;watch;create;update;patch;delete
+
+func (r *ApplicationReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Logger().WithValues("application", req.NamespacedName)
+	reqLogger.Info("Reconcile Application", "Operator version", version.Version)
+
+	// Fetch the instance
+	applicationCR := &capabilitiesv1beta1.Application{}
+	err := r.Client().Get(context.TODO(), req.NamespacedName, applicationCR)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			reqLogger.Info("resource not found. Ignoring since object must have been deleted")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	if reqLogger.V(1).Enabled() {
+		jsonData, err := json.MarshalIndent(applicationCR, "", "  ")
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		reqLogger.V(1).Info(string(jsonData))
+	}
+
+	// Application has been marked for deletion
+	if applicationCR.GetDeletionTimestamp() != nil && controllerutil.ContainsFinalizer(applicationCR, applicationFinalizer) {
+		err = r.removeApplicationFrom3scale(applicationCR)
+		if err != nil {
+			r.EventRecorder().Eventf(applicationCR, corev1.EventTypeWarning, "Failed to delete application", "%v", err)
+
+			// Update status with err
+			statusResult, statusUpdateErr := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, nil, nil, "", nil, "", err).Reconcile()
+			if statusUpdateErr != nil {
+				return ctrl.Result{}, fmt.Errorf("Failed to update application status: %w", statusUpdateErr)
+			}
+
+			if statusResult.Requeue {
+				return statusResult, nil
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		controllerutil.RemoveFinalizer(applicationCR, applicationFinalizer)
+		err = r.UpdateResource(applicationCR)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// Ignore deleted resource, this can happen when foregroundDeletion is enabled
+	// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#foreground-cascading-deletion
+	if applicationCR.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(applicationCR, applicationFinalizer) {
+		controllerutil.AddFinalizer(applicationCR, applicationFinalizer)
+		err = r.UpdateResource(applicationCR)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// The application is removed with the developer account it belongs to
+	developerAccountCR, err := r.retrieveDeveloperAccountCR(applicationCR)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if developerAccountCR != nil {
+		updated, err := r.EnsureOwnerReference(developerAccountCR, applicationCR)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if updated {
+			err := r.Client().Update(r.Context(), applicationCR)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
+	statusReconciler, reconcileErr := r.reconcileSpec(applicationCR, reqLogger)
+	statusResult, statusUpdateErr := statusReconciler.Reconcile()
+	if statusUpdateErr != nil {
+		if reconcileErr != nil {
+			return ctrl.Result{}, fmt.Errorf("Failed to reconcile application: %v. Failed to update status: %w", reconcileErr, statusUpdateErr)
+		}
+
+		return ctrl.Result{}, fmt.Errorf("Failed to update application status: %w", statusUpdateErr)
+	}
+
+	if statusResult.Requeue {
+		return statusResult, nil
+	}
+
+	if reconcileErr != nil {
+		if helper.IsInvalidSpecError(reconcileErr) {
+			// On Validation error, no need to retry as spec is not valid and needs to be changed
+			reqLogger.Info("ERROR", "spec validation error", reconcileErr)
+			r.EventRecorder().Eventf(applicationCR, corev1.EventTypeWarning, "Invalid application spec", "%v", reconcileErr)
+			return ctrl.Result{}, nil
+		}
+
+		if helper.IsOrphanSpecError(reconcileErr) {
+			// On Orphan spec error, retry
+			reqLogger.Info("orphan", "message", reconcileErr)
+			return ctrl.Result{Requeue: true}, nil
+		}
+
+		reqLogger.Error(reconcileErr, "Failed to reconcile")
+		r.EventRecorder().Eventf(applicationCR, corev1.EventTypeWarning, "ReconcileError", "%v", reconcileErr)
+		return ctrl.Result{}, reconcileErr
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *ApplicationReconciler) reconcileSpec(applicationCR *capabilitiesv1beta1.Application, logger logr.Logger) (*ApplicationStatusReconciler, error) {
+	err := r.validateSpec(applicationCR)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, nil, nil, "", nil, "", err)
+		return statusReconciler, err
+	}
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), applicationCR.Namespace, applicationCR.Spec.ProviderAccountRef, logger)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, nil, nil, "", nil, "", err)
+		return statusReconciler, err
+	}
+
+	accountCR, err := r.findDeveloperAccount(applicationCR, providerAccount, logger)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, nil, nil, providerAccount.AdminURLStr, nil, "", err)
+		return statusReconciler, err
+	}
+
+	productCR, err := r.findProduct(applicationCR, providerAccount, logger)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, accountCR, nil, providerAccount.AdminURLStr, nil, "", err)
+		return statusReconciler, err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, accountCR, productCR, providerAccount.AdminURLStr, nil, "", err)
+		return statusReconciler, err
+	}
+
+	applicationAPIClient, err := controllerhelper.ApplicationAPIClientFromProviderAccount(providerAccount)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, accountCR, productCR, providerAccount.AdminURLStr, nil, "", err)
+		return statusReconciler, err
+	}
+
+	reconciler := NewApplicationThreescaleReconciler(r.BaseReconciler, applicationCR, accountCR, productCR, threescaleAPIClient, applicationAPIClient, providerAccount.AdminURLStr, logger)
+	applicationObj, err := reconciler.Reconcile()
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, accountCR, productCR, providerAccount.AdminURLStr, applicationObj, "", err)
+		return statusReconciler, err
+	}
+
+	err = r.reconcileCredentialsSecret(applicationCR, reconciler.Credentials())
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, accountCR, productCR, providerAccount.AdminURLStr, applicationObj, "", err)
+		return statusReconciler, err
+	}
+
+	statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, accountCR, productCR, providerAccount.AdminURLStr, applicationObj, applicationCR.CredentialsSecretName(), nil)
+	return statusReconciler, nil
+}
+
+func (r *ApplicationReconciler) validateSpec(resource *capabilitiesv1beta1.Application) error {
+	errors := field.ErrorList{}
+	errors = append(errors, resource.Validate()...)
+
+	if len(errors) == 0 {
+		return nil
+	}
+
+	return &helper.SpecFieldError{
+		ErrorType:      helper.InvalidError,
+		FieldErrorList: errors,
+	}
+}
+
+func (r *ApplicationReconciler) findDeveloperAccount(applicationCR *capabilitiesv1beta1.Application, providerAccount *controllerhelper.ProviderAccount, logger logr.Logger) (*capabilitiesv1beta1.DeveloperAccount, error) {
+	accountFldPath := field.NewPath("spec").Child("accountCR")
+
+	accountCR := &capabilitiesv1beta1.DeveloperAccount{}
+	accountKey := types.NamespacedName{Name: applicationCR.Spec.AccountCR.Name, Namespace: applicationCR.Namespace}
+	if err := r.Client().Get(r.Context(), accountKey, accountCR); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, &helper.SpecFieldError{
+				ErrorType: helper.OrphanError,
+				FieldErrorList: field.ErrorList{
+					field.Invalid(accountFldPath, applicationCR.Spec.AccountCR, "developer account resource not found"),
+				},
+			}
+		}
+
+		return nil, err
+	}
+
+	// Check it belongs to the same providerAccount
+	accountProviderAccount, err := controllerhelper.LookupProviderAccount(r.Client(), applicationCR.Namespace, accountCR.Spec.ProviderAccountRef, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if providerAccount.AdminURLStr != accountProviderAccount.AdminURLStr {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.OrphanError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(accountFldPath, applicationCR.Spec.AccountCR, "developer account resource does not belong to the same provider account"),
+			},
+		}
+	}
+
+	if !accountCR.Status.IsReady() || accountCR.Status.ID == nil {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.OrphanError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(accountFldPath, applicationCR.Spec.AccountCR, "developer account resource not ready"),
+			},
+		}
+	}
+
+	return accountCR, nil
+}
+
+func (r *ApplicationReconciler) findProduct(applicationCR *capabilitiesv1beta1.Application, providerAccount *controllerhelper.ProviderAccount, logger logr.Logger) (*capabilitiesv1beta1.Product, error) {
+	productFldPath := field.NewPath("spec").Child("productCR")
+
+	productCR := &capabilitiesv1beta1.Product{}
+	productKey := types.NamespacedName{Name: applicationCR.Spec.ProductCR.Name, Namespace: applicationCR.Namespace}
+	if err := r.Client().Get(r.Context(), productKey, productCR); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, &helper.SpecFieldError{
+				ErrorType: helper.OrphanError,
+				FieldErrorList: field.ErrorList{
+					field.Invalid(productFldPath, applicationCR.Spec.ProductCR, "product resource not found"),
+				},
+			}
+		}
+
+		return nil, err
+	}
+
+	// Check it belongs to the same providerAccount
+	productProviderAccount, err := controllerhelper.LookupProviderAccount(r.Client(), applicationCR.Namespace, productCR.Spec.ProviderAccountRef, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if providerAccount.AdminURLStr != productProviderAccount.AdminURLStr {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.OrphanError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(productFldPath, applicationCR.Spec.ProductCR, "product resource does not belong to the same provider account"),
+			},
+		}
+	}
+
+	if !productCR.IsSynced() || productCR.Status.ID == nil {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.OrphanError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(productFldPath, applicationCR.Spec.ProductCR, "product resource not ready"),
+			},
+		}
+	}
+
+	return productCR, nil
+}
+
+func (r *ApplicationReconciler) reconcileCredentialsSecret(applicationCR *capabilitiesv1beta1.Application, credentials map[string]string) error {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      applicationCR.CredentialsSecretName(),
+			Namespace: applicationCR.Namespace,
+		},
+		StringData: credentials,
+		Type:       corev1.SecretTypeOpaque,
+	}
+
+	err := r.SetOwnerReference(applicationCR, secret)
+	if err != nil {
+		return err
+	}
+
+	mutators := []reconcilers.SecretMutateFn{}
+	for fieldName := range credentials {
+		mutators = append(mutators, reconcilers.SecretReconcileField(fieldName))
+	}
+
+	return r.ReconcileResource(&corev1.Secret{}, secret, reconcilers.DeploymentSecretMutator(mutators...))
+}
+
+func (r *ApplicationReconciler) removeApplicationFrom3scale(applicationCR *capabilitiesv1beta1.Application) error {
+	logger := r.Logger().WithValues("application", client.ObjectKey{Name: applicationCR.Name, Namespace: applicationCR.Namespace})
+
+	// Attempt to remove application only if applicationCR.Status.ID is present
+	if applicationCR.Status.ID == nil {
+		logger.Info("could not remove application because ID is missing in status")
+		return nil
+	}
+
+	if applicationCR.Status.AccountID == nil {
+		logger.Info("could not remove application because Account ID is missing in status")
+		return nil
+	}
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), applicationCR.Namespace, applicationCR.Spec.ProviderAccountRef, logger)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("application not deleted from 3scale, provider account not found")
+			return nil
+		}
+		return err
+	}
+
+	applicationAPIClient, err := controllerhelper.ApplicationAPIClientFromProviderAccount(providerAccount)
+	if err != nil {
+		return err
+	}
+
+	err = applicationAPIClient.DeleteApplication(*applicationCR.Status.AccountID, *applicationCR.Status.ID)
+	if err != nil && !controllerhelper.IsApplicationNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ApplicationReconciler) retrieveDeveloperAccountCR(applicationCR *capabilitiesv1beta1.Application) (*capabilitiesv1beta1.DeveloperAccount, error) {
+	developerAccount := &capabilitiesv1beta1.DeveloperAccount{}
+
+	if err := r.Client().Get(context.TODO(), client.ObjectKey{Namespace: applicationCR.Namespace, Name: applicationCR.Spec.AccountCR.Name}, developerAccount); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return developerAccount, nil
+}
+
+func (r *ApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := indexField(mgr, &capabilitiesv1beta1.Application{}, secretRefIndexField, applicationSecretRefIndexer)
+	if err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&capabilitiesv1beta1.Application{}).
+		Owns(&corev1.Secret{})
+
+	return secretRefWatch(r.BaseReconciler, builder, &capabilitiesv1beta1.ApplicationList{}).Complete(r)
+}
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"fmt"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type ApplicationStatusReconciler struct {
+	*reconcilers.BaseReconciler
+	applicationCR         *capabilitiesv1beta1.Application
+	accountCR             *capabilitiesv1beta1.DeveloperAccount
+	productCR             *capabilitiesv1beta1.Product
+	providerAccountHost   string
+	remoteApplication     *controllerhelper.Application
+	credentialsSecretName string
+	reconcileError        error
+	logger                logr.Logger
+}
+
+func NewApplicationStatusReconciler(b *reconcilers.BaseReconciler,
+	applicationCR *capabilitiesv1beta1.Application,
+	accountCR *capabilitiesv1beta1.DeveloperAccount,
+	productCR *capabilitiesv1beta1.Product,
+	providerAccountHost string,
+	remoteApplication *controllerhelper.Application,
+	credentialsSecretName string,
+	reconcileError error,
+) *ApplicationStatusReconciler {
+	return &ApplicationStatusReconciler{
+		BaseReconciler:        b,
+		applicationCR:         applicationCR,
+		accountCR:             accountCR,
+		productCR:             productCR,
+		providerAccountHost:   providerAccountHost,
+		remoteApplication:     remoteApplication,
+		credentialsSecretName: credentialsSecretName,
+		reconcileError:        reconcileError,
+		logger:                b.Logger().WithValues("Status Reconciler", applicationCR.Name),
+	}
+}
+
+func (s *ApplicationStatusReconciler) Reconcile() (reconcile.Result, error) {
+	s.logger.V(1).Info("START")
+
+	newStatus, err := s.calculateStatus()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	equalStatus := s.applicationCR.Status.Equals(newStatus, s.logger)
+	s.logger.V(1).Info("Status", "status is different", !equalStatus)
+	s.logger.V(1).Info("Status", "generation is different", s.applicationCR.Generation != s.applicationCR.Status.ObservedGeneration)
+	if equalStatus && s.applicationCR.Generation == s.applicationCR.Status.ObservedGeneration {
+		// Steady state
+		s.logger.V(1).Info("Status steady state, status was not updated")
+		return reconcile.Result{}, nil
+	}
+
+	// Save the generation number we acted on, otherwise we might wrongfully indicate
+	// that we've seen a spec update when we retry.
+	// TODO: This can clobber an update if we allow multiple agents to write to the
+	// same status.
+	newStatus.ObservedGeneration = s.applicationCR.Generation
+
+	s.logger.V(1).Info("Updating Status", "sequence no:", fmt.Sprintf("sequence No: %v->%v", s.applicationCR.Status.ObservedGeneration, newStatus.ObservedGeneration))
+
+	s.applicationCR.Status = *newStatus
+	updateErr := s.Client().Status().Update(s.Context(), s.applicationCR)
+	if updateErr != nil {
+		// Ignore conflicts, resource might just be outdated.
+		if errors.IsConflict(updateErr) {
+			s.logger.Info("Failed to update status: resource might just be outdated")
+			return reconcile.Result{Requeue: true}, nil
+		}
+
+		return reconcile.Result{}, fmt.Errorf("Failed to update status: %w", updateErr)
+	}
+	return reconcile.Result{}, nil
+}
+
+func (s *ApplicationStatusReconciler) calculateStatus() (*capabilitiesv1beta1.ApplicationStatus, error) {
+	// If there is an error and s.remoteApplication is nil, do not change status fields read from it
+	// Initialize with existing data for data coming from 3scale
+	// just in case in this reconciliation loop something goes wrong and avoid replacing right data with nil
+	newStatus := &capabilitiesv1beta1.ApplicationStatus{
+		ID:                    s.applicationCR.Status.ID,
+		State:                 s.applicationCR.Status.State,
+		AccountID:             s.applicationCR.Status.AccountID,
+		ProductID:             s.applicationCR.Status.ProductID,
+		PlanID:                s.applicationCR.Status.PlanID,
+		CredentialsSecretName: s.applicationCR.Status.CredentialsSecretName,
+		ProviderAccountHost:   s.applicationCR.Status.ProviderAccountHost,
+		Conditions:            s.applicationCR.Status.Conditions.Copy(),
+		ObservedGeneration:    s.applicationCR.Status.ObservedGeneration,
+	}
+
+	if s.remoteApplication != nil {
+		newStatus.ID = &s.remoteApplication.ID
+		newStatus.State = s.remoteApplication.State
+		newStatus.PlanID = &s.remoteApplication.PlanID
+	}
+
+	if s.accountCR != nil {
+		newStatus.AccountID = s.accountCR.Status.ID
+	}
+
+	if s.productCR != nil {
+		newStatus.ProductID = s.productCR.Status.ID
+	}
+
+	if s.credentialsSecretName != "" {
+		newStatus.CredentialsSecretName = s.credentialsSecretName
+	}
+
+	if s.providerAccountHost != "" {
+		newStatus.ProviderAccountHost = s.providerAccountHost
+	}
+
+	newStatus.Conditions.SetCondition(s.invalidCondition())
+	newStatus.Conditions.SetCondition(s.readyCondition())
+	newStatus.Conditions.SetCondition(s.orphanCondition())
+	newStatus.Conditions.SetCondition(s.failedCondition())
+
+	return newStatus, nil
+}
+
+func (s *ApplicationStatusReconciler) readyCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.ApplicationReadyConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if s.reconcileError == nil {
+		condition.Status = corev1.ConditionTrue
+	}
+
+	return condition
+}
+
+func (s *ApplicationStatusReconciler) invalidCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.ApplicationInvalidConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if helper.IsInvalidSpecError(s.reconcileError) {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = s.reconcileError.Error()
+	}
+
+	return condition
+}
+
+func (s *ApplicationStatusReconciler) failedCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.ApplicationFailedConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if s.reconcileError != nil {
+		// only activate this condition when others are false and still there is an error
+
+		otherConditionsFalse := []bool{
+			s.invalidCondition().IsFalse(),
+			s.orphanCondition().IsFalse(),
+		}
+
+		if helper.All(otherConditionsFalse) {
+			condition.Status = corev1.ConditionTrue
+			condition.Message = s.reconcileError.Error()
+		}
+	}
+
+	return condition
+}
+
+func (s *ApplicationStatusReconciler) orphanCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.ApplicationOrphanConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if helper.IsOrphanSpecError(s.reconcileError) {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = s.reconcileError.Error()
+	}
+
+	return condition
+}
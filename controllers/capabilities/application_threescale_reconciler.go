@@ -0,0 +1,381 @@
+package controllers
+
+import (
+	"net/url"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apimachineryerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type ApplicationThreescaleReconciler struct {
+	*reconcilers.BaseReconciler
+	applicationCR        *capabilitiesv1beta1.Application
+	accountCR            *capabilitiesv1beta1.DeveloperAccount
+	productCR            *capabilitiesv1beta1.Product
+	threescaleAPIClient  *threescaleapi.ThreeScaleClient
+	applicationAPIClient *controllerhelper.ApplicationAPIClient
+	providerAccountHost  string
+	logger               logr.Logger
+
+	planID      *int64
+	credentials map[string]string
+}
+
+func NewApplicationThreescaleReconciler(b *reconcilers.BaseReconciler,
+	applicationCR *capabilitiesv1beta1.Application,
+	accountCR *capabilitiesv1beta1.DeveloperAccount,
+	productCR *capabilitiesv1beta1.Product,
+	threescaleAPIClient *threescaleapi.ThreeScaleClient,
+	applicationAPIClient *controllerhelper.ApplicationAPIClient,
+	providerAccountHost string,
+	logger logr.Logger,
+) *ApplicationThreescaleReconciler {
+	return &ApplicationThreescaleReconciler{
+		BaseReconciler:       b,
+		applicationCR:        applicationCR,
+		accountCR:            accountCR,
+		productCR:            productCR,
+		threescaleAPIClient:  threescaleAPIClient,
+		applicationAPIClient: applicationAPIClient,
+		providerAccountHost:  providerAccountHost,
+		logger:               logger.WithValues("3scale Reconciler", providerAccountHost),
+	}
+}
+
+func (s *ApplicationThreescaleReconciler) Reconcile() (*controllerhelper.Application, error) {
+	s.logger.V(1).Info("START")
+
+	auth, err := s.getAuthCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	planID, err := s.findPlanID()
+	if err != nil {
+		return nil, err
+	}
+	s.planID = &planID
+
+	err = s.checkParentAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	application, err := s.findApplication()
+	if err != nil {
+		return nil, err
+	}
+
+	if application == nil {
+		s.logger.V(1).Info("Application does not exist", "name", s.applicationCR.Spec.Name)
+		application, err = s.createApplication(auth)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		s.logger.V(1).Info("Application already exists", "ID", application.ID)
+	}
+
+	application, err = s.syncApplication(application, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.syncCredentials(application, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
+// Credentials returns the application credentials to be written to the credentials secret
+func (s *ApplicationThreescaleReconciler) Credentials() map[string]string {
+	return s.credentials
+}
+
+func (s *ApplicationThreescaleReconciler) findPlanID() (int64, error) {
+	planList, err := s.threescaleAPIClient.ListApplicationPlansByProduct(*s.productCR.Status.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	for idx := range planList.Plans {
+		if planList.Plans[idx].Element.SystemName == s.applicationCR.Spec.ApplicationPlanName {
+			return planList.Plans[idx].Element.ID, nil
+		}
+	}
+
+	return 0, &helper.SpecFieldError{
+		ErrorType: helper.OrphanError,
+		FieldErrorList: field.ErrorList{
+			field.Invalid(field.NewPath("spec").Child("applicationPlanName"), s.applicationCR.Spec.ApplicationPlanName, "application plan not found in the product"),
+		},
+	}
+}
+
+func (s *ApplicationThreescaleReconciler) checkParentAccount() error {
+	if s.applicationCR.Status.AccountID != nil &&
+		*s.applicationCR.Status.AccountID != *s.accountCR.Status.ID &&
+		s.applicationCR.Status.ID != nil {
+		// The referenced account has changed.
+		// Applications cannot be moved between accounts, it needs to be removed from the old account
+		err := s.applicationAPIClient.DeleteApplication(*s.applicationCR.Status.AccountID, *s.applicationCR.Status.ID)
+		if err != nil && !controllerhelper.IsApplicationNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ApplicationThreescaleReconciler) findApplication() (*controllerhelper.Application, error) {
+	// Reconciliation is based on ID stored in Status field
+	application, err := s.findApplicationByID()
+	if err != nil {
+		return nil, err
+	}
+
+	if application != nil {
+		return application, nil
+	}
+
+	// If not found by ID, try {product, name} set.
+	return s.findApplicationByName()
+}
+
+func (s *ApplicationThreescaleReconciler) findApplicationByID() (*controllerhelper.Application, error) {
+	if s.applicationCR.Status.ID == nil {
+		return nil, nil
+	}
+
+	application, err := s.applicationAPIClient.Application(*s.accountCR.Status.ID, *s.applicationCR.Status.ID)
+	if err != nil && controllerhelper.IsApplicationNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if application.ServiceID != *s.productCR.Status.ID {
+		// The referenced product has changed. The application subscribes to the old product.
+		err = s.applicationAPIClient.DeleteApplication(*s.accountCR.Status.ID, application.ID)
+		if err != nil && !controllerhelper.IsApplicationNotFound(err) {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	return application, nil
+}
+
+func (s *ApplicationThreescaleReconciler) findApplicationByName() (*controllerhelper.Application, error) {
+	applications, err := s.applicationAPIClient.ListApplications(*s.accountCR.Status.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range applications {
+		if applications[idx].ServiceID == *s.productCR.Status.ID && applications[idx].Name == s.applicationCR.Spec.Name {
+			return &applications[idx], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *ApplicationThreescaleReconciler) createApplication(auth map[string]string) (*controllerhelper.Application, error) {
+	params := url.Values{}
+	params.Set("name", s.applicationCR.Spec.Name)
+	params.Set("description", s.applicationDescription())
+
+	if userKey, ok := auth[capabilitiesv1beta1.ApplicationUserKeySecretField]; ok {
+		params.Set("user_key", userKey)
+	}
+	if appID, ok := auth[capabilitiesv1beta1.ApplicationAppIDSecretField]; ok {
+		params.Set("application_id", appID)
+	}
+	if appKey, ok := auth[capabilitiesv1beta1.ApplicationAppKeySecretField]; ok {
+		params.Set("application_key", appKey)
+	}
+
+	return s.applicationAPIClient.CreateApplication(*s.accountCR.Status.ID, *s.planID, params)
+}
+
+func (s *ApplicationThreescaleReconciler) syncApplication(application *controllerhelper.Application, auth map[string]string) (*controllerhelper.Application, error) {
+	accountID := *s.accountCR.Status.ID
+	updatedApplication := application
+
+	credentialsFldPath := field.NewPath("spec").Child("authSecretRef")
+	if userKey, ok := auth[capabilitiesv1beta1.ApplicationUserKeySecretField]; ok && userKey != application.UserKey {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.InvalidError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(credentialsFldPath, s.applicationCR.Spec.AuthSecretRef, "user_key cannot be changed once the application has been created"),
+			},
+		}
+	}
+	if appID, ok := auth[capabilitiesv1beta1.ApplicationAppIDSecretField]; ok && appID != application.ApplicationID {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.InvalidError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(credentialsFldPath, s.applicationCR.Spec.AuthSecretRef, "app_id cannot be changed once the application has been created"),
+			},
+		}
+	}
+
+	params := url.Values{}
+	if application.Name != s.applicationCR.Spec.Name {
+		params.Set("name", s.applicationCR.Spec.Name)
+	}
+	if application.Description != s.applicationDescription() {
+		params.Set("description", s.applicationDescription())
+	}
+
+	if len(params) > 0 {
+		updateRes, err := s.applicationAPIClient.UpdateApplication(accountID, updatedApplication.ID, params)
+		if err != nil {
+			return nil, err
+		}
+		updatedApplication = updateRes
+	}
+
+	if updatedApplication.PlanID != *s.planID {
+		updateRes, err := s.applicationAPIClient.ChangeApplicationPlan(accountID, updatedApplication.ID, *s.planID)
+		if err != nil {
+			return nil, err
+		}
+		updatedApplication = updateRes
+	}
+
+	if updatedApplication.IsSuspended() && !s.applicationCR.Spec.Suspend {
+		updateRes, err := s.applicationAPIClient.ResumeApplication(accountID, updatedApplication.ID)
+		if err != nil {
+			return nil, err
+		}
+		updatedApplication = updateRes
+	}
+
+	if !updatedApplication.IsSuspended() && s.applicationCR.Spec.Suspend {
+		updateRes, err := s.applicationAPIClient.SuspendApplication(accountID, updatedApplication.ID)
+		if err != nil {
+			return nil, err
+		}
+		updatedApplication = updateRes
+	}
+
+	return updatedApplication, nil
+}
+
+func (s *ApplicationThreescaleReconciler) syncCredentials(application *controllerhelper.Application, auth map[string]string) error {
+	// user_key authentication
+	if application.UserKey != "" {
+		s.credentials = map[string]string{
+			capabilitiesv1beta1.ApplicationUserKeySecretField: application.UserKey,
+		}
+		return nil
+	}
+
+	// app_id/app_key authentication
+	keys, err := s.applicationAPIClient.ApplicationKeys(*s.accountCR.Status.ID, application.ID)
+	if err != nil {
+		return err
+	}
+
+	appKey, ok := auth[capabilitiesv1beta1.ApplicationAppKeySecretField]
+	if ok && !helper.ArrayContains(keys, appKey) {
+		err = s.applicationAPIClient.CreateApplicationKey(*s.accountCR.Status.ID, application.ID, appKey)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, appKey)
+	}
+
+	if !ok && len(keys) > 0 {
+		appKey = keys[0]
+	}
+
+	s.credentials = map[string]string{
+		capabilitiesv1beta1.ApplicationAppIDSecretField: application.ApplicationID,
+	}
+
+	// 3scale may return no keys, an empty key is not a valid credential
+	if appKey != "" {
+		s.credentials[capabilitiesv1beta1.ApplicationAppKeySecretField] = appKey
+	} else {
+		s.logger.Info("application has no keys, app_key not written to the credentials secret")
+	}
+
+	return nil
+}
+
+func (s *ApplicationThreescaleReconciler) applicationDescription() string {
+	// 3scale requires application description
+	if s.applicationCR.Spec.Description == "" {
+		return s.applicationCR.Spec.Name
+	}
+	return s.applicationCR.Spec.Description
+}
+
+func (s *ApplicationThreescaleReconciler) getAuthCredentials() (map[string]string, error) {
+	auth := map[string]string{}
+
+	if s.applicationCR.Spec.AuthSecretRef == nil {
+		return auth, nil
+	}
+
+	authFldPath := field.NewPath("spec").Child("authSecretRef")
+
+	secret := &corev1.Secret{}
+	err := s.Client().Get(s.Context(),
+		types.NamespacedName{
+			Name:      s.applicationCR.Spec.AuthSecretRef.Name,
+			Namespace: s.applicationCR.Namespace,
+		},
+		secret)
+	if err != nil {
+		if apimachineryerrors.IsNotFound(err) {
+			// Return spec field error if secret was not found
+			return nil, &helper.SpecFieldError{
+				ErrorType: helper.InvalidError,
+				FieldErrorList: field.ErrorList{
+					field.Invalid(authFldPath, s.applicationCR.Spec.AuthSecretRef, "application auth secret reference not found"),
+				},
+			}
+		}
+
+		return nil, err
+	}
+
+	for _, fieldName := range []string{
+		capabilitiesv1beta1.ApplicationUserKeySecretField,
+		capabilitiesv1beta1.ApplicationAppIDSecretField,
+		capabilitiesv1beta1.ApplicationAppKeySecretField,
+	} {
+		if value, ok := secret.Data[fieldName]; ok && len(value) > 0 {
+			auth[fieldName] = string(value)
+		}
+	}
+
+	_, hasUserKey := auth[capabilitiesv1beta1.ApplicationUserKeySecretField]
+	_, hasAppID := auth[capabilitiesv1beta1.ApplicationAppIDSecretField]
+	_, hasAppKey := auth[capabilitiesv1beta1.ApplicationAppKeySecretField]
+	if hasUserKey && (hasAppID || hasAppKey) {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.InvalidError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(authFldPath, s.applicationCR.Spec.AuthSecretRef, "application auth secret cannot have both user_key and app_id/app_key fields"),
+			},
+		}
+	}
+
+	return auth, nil
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+)
+
+func TestApplicationThreescaleReconcilerSyncCredentials(t *testing.T) {
+	cases := []struct {
+		testName            string
+		keysResponse        string
+		expectedCredentials map[string]string
+	}{
+		{
+			"application with keys", `{"keys":[{"key":{"value":"key1"}},{"key":{"value":"key2"}}]}`,
+			map[string]string{
+				capabilitiesv1beta1.ApplicationAppIDSecretField:  "appid",
+				capabilitiesv1beta1.ApplicationAppKeySecretField: "key1",
+			},
+		},
+		{
+			"application without keys", `{"keys":[]}`,
+			map[string]string{capabilitiesv1beta1.ApplicationAppIDSecretField: "appid"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/admin/api/accounts/3/applications/5/keys.json" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(tc.keysResponse))
+			}))
+			defer server.Close()
+
+			adminURL, err := url.Parse(server.URL)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			accountID := int64(3)
+			accountCR := &capabilitiesv1beta1.DeveloperAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: "account", Namespace: "test"},
+				Status:     capabilitiesv1beta1.DeveloperAccountStatus{ID: &accountID},
+			}
+			applicationCR := &capabilitiesv1beta1.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "application", Namespace: "test"},
+			}
+			applicationAPIClient := controllerhelper.NewApplicationAPIClient(adminURL, "token", server.Client())
+			baseReconciler := getBaseReconciler()
+			reconciler := NewApplicationThreescaleReconciler(baseReconciler, applicationCR, accountCR, nil, nil, applicationAPIClient, server.URL, baseReconciler.Logger())
+
+			err = reconciler.syncCredentials(&controllerhelper.Application{ID: 5, ApplicationID: "appid"}, map[string]string{})
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if !reflect.DeepEqual(reconciler.Credentials(), tc.expectedCredentials) {
+				subT.Errorf("unexpected credentials: got %v, expected %v", reconciler.Credentials(), tc.expectedCredentials)
+			}
+		})
+	}
+}
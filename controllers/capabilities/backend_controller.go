@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
@@ -29,12 +30,15 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
 	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/handlers"
 	"github.com/3scale/3scale-operator/pkg/helper"
 	"github.com/3scale/3scale-operator/pkg/reconcilers"
 	"github.com/3scale/3scale-operator/version"
@@ -43,9 +47,11 @@ import (
 // BackendReconciler reconciles a Backend object
 type BackendReconciler struct {
 	*reconcilers.BaseReconciler
-}
 
-const requeueTime = time.Duration(2) * time.Second
+	// ResyncPeriod is the default period to synchronize backends again with 3scale.
+	// Zero disables periodic synchronization.
+	ResyncPeriod time.Duration
+}
 
 const backendFinalizer = "backend.capabilities.3scale.net/finalizer"
 
@@ -87,14 +93,19 @@ func (r *BackendReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	// Ignore deleted Backends, this can happen when foregroundDeletion is enabled
 	// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#foreground-cascading-deletion
 	if backend.GetDeletionTimestamp() != nil && controllerutil.ContainsFinalizer(backend, backendFinalizer) {
-		res, err := r.removeBackendReferencesFromProducts(backend)
-		if err != nil {
-			return ctrl.Result{}, err
-		}
+		// Deleting a backend used by products would break the products,
+		// hold the deletion until the references are removed.
+		// Orphaned backends are kept in 3scale, the products are not affected
+		if !capabilitiesv1beta1.OrphanOnDeletion(backend.Spec.DeletionPolicy) {
+			productNames, err := r.productsUsingBackend(backend)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
 
-		if res.Requeue {
-			reqLogger.Info("Removed backend references from product CRs. Requeueing.")
-			return res, nil
+			if len(productNames) > 0 {
+				reqLogger.Info("Backend in use. Deletion on hold.", "products", productNames)
+				return ctrl.Result{}, r.holdBackendDeletion(backend, productNames)
+			}
 		}
 
 		err = r.removeBackendFrom3scale(backend)
@@ -191,7 +202,16 @@ func (r *BackendReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	}
 
 	reqLogger.Info("END", "error", reconcileErr)
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.resyncPeriod(backend)}, nil
+}
+
+// resyncPeriod returns the period to synchronize the backend again with 3scale.
+// The backend spec takes precedence over the operator wide period.
+func (r *BackendReconciler) resyncPeriod(backend *capabilitiesv1beta1.Backend) time.Duration {
+	if backend.Spec.ResyncPeriod != nil {
+		return backend.Spec.ResyncPeriod.Duration
+	}
+	return r.ResyncPeriod
 }
 
 func (r *BackendReconciler) reconcile(backendResource *capabilitiesv1beta1.Backend) (*BackendStatusReconciler, error) {
@@ -199,31 +219,49 @@ func (r *BackendReconciler) reconcile(backendResource *capabilitiesv1beta1.Backe
 
 	err := r.validateSpec(backendResource)
 	if err != nil {
-		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, "", err)
+		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, "", "", err)
+		return statusReconciler, err
+	}
+
+	privateBaseURL, err := controllerhelper.BackendPrivateBaseURL(r.Client(), backendResource)
+	if err != nil {
+		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, "", "", err)
 		return statusReconciler, err
 	}
 
 	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), backendResource.Namespace, backendResource.Spec.ProviderAccountRef, logger)
 	if err != nil {
-		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, "", err)
+		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, "", privateBaseURL, err)
 		return statusReconciler, err
 	}
 
-	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	var (
+		threescaleAPIClient *threescaleapi.ThreeScaleClient
+		changeTracker       *controllerhelper.ChangeTracker
+		changePlanner       *controllerhelper.ChangePlanner
+	)
+	if backendResource.Spec.DryRun {
+		threescaleAPIClient, changePlanner, err = controllerhelper.PortaClientWithChangePlanner(providerAccount)
+	} else {
+		threescaleAPIClient, changeTracker, err = controllerhelper.PortaClientWithChangeTracker(providerAccount)
+	}
 	if err != nil {
-		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, providerAccount.AdminURLStr, err)
+		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, providerAccount.AdminURLStr, privateBaseURL, err)
 		return statusReconciler, err
 	}
 
 	backendRemoteIndex, err := controllerhelper.NewBackendAPIRemoteIndex(threescaleAPIClient, logger)
 	if err != nil {
-		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, providerAccount.AdminURLStr, err)
+		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, providerAccount.AdminURLStr, privateBaseURL, err)
 		return statusReconciler, err
 	}
 
-	reconciler := NewThreescaleReconciler(r.BaseReconciler, backendResource, threescaleAPIClient, backendRemoteIndex, providerAccount)
+	reconciler := NewThreescaleReconciler(r.BaseReconciler, backendResource, privateBaseURL, threescaleAPIClient, backendRemoteIndex, providerAccount, changeTracker, changePlanner)
 	backendAPIEntity, err := reconciler.Reconcile()
-	statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, backendAPIEntity, providerAccount.AdminURLStr, err)
+	statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, backendAPIEntity, providerAccount.AdminURLStr, privateBaseURL, err)
+	statusReconciler.modifiedSections = reconciler.ModifiedSections()
+	statusReconciler.plannedChanges = reconciler.PlannedChanges()
+	statusReconciler.unplannedSections = reconciler.UnplannedSections()
 	return statusReconciler, err
 }
 
@@ -242,49 +280,81 @@ func (r *BackendReconciler) validateSpec(backendResource *capabilitiesv1beta1.Ba
 	}
 }
 
-func (r *BackendReconciler) removeBackendReferencesFromProducts(backend *capabilitiesv1beta1.Backend) (ctrl.Result, error) {
-	// Retrieve all product CRs that are under the same ns as the backend CR
-	opts := k8sclient.ListOptions{
-		Namespace: backend.Namespace,
-	}
-	productCRsList := &capabilitiesv1beta1.ProductList{}
-	err := r.Client().List(context.TODO(), productCRsList, &opts)
+// productsUsingBackend returns the names of the product CRs of the backend tenant referencing the backend.
+// Products synchronized at least once hold the deletion whatever their current sync state,
+// as their backend usages may still be live in 3scale
+func (r *BackendReconciler) productsUsingBackend(backend *capabilitiesv1beta1.Backend) ([]string, error) {
+	logger := r.Logger().WithValues("backend", client.ObjectKey{Name: backend.Name, Namespace: backend.Namespace})
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), backend.Namespace, backend.Spec.ProviderAccountRef, logger)
 	if err != nil {
-		return ctrl.Result{}, err
+		if apierrors.IsNotFound(err) {
+			logger.Info("could not look up for products of the same tenant. Tenant not found")
+			return nil, nil
+		}
+		return nil, err
 	}
 
-	// fetch CRs that belong to a tenant and require removal of the backend mentions in
-	// Application Plan pricing rules
-	// Application Plan limits
-	// Backend usages
-	tenantProductCRs, err := r.fetchTenantProductCRs(productCRsList, backend)
+	productList := &capabilitiesv1beta1.ProductList{}
+	err = r.Client().List(r.Context(), productList, client.InNamespace(backend.Namespace))
 	if err != nil {
-		return ctrl.Result{}, err
+		return nil, err
 	}
 
-	productCRUpdated := false
+	var productNames []string
+	for idx := range productList.Items {
+		product := &productList.Items[idx]
+		if product.Status.ID == nil || !product.UsesBackend(backend.Spec.SystemName) {
+			continue
+		}
 
-	// update backendUsages for each product retrieved
-	for _, productCR := range tenantProductCRs {
-		if productCR.RemoveBackendReferences(backend.Spec.SystemName) {
-			err = r.UpdateResource(&productCR)
-			if err != nil {
-				return ctrl.Result{}, err
+		// Products whose provider account is not found do not hold the deletion
+		productProviderAccount, err := controllerhelper.LookupProviderAccount(r.Client(), backend.Namespace, product.Spec.ProviderAccountRef, logger)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				logger.Info("could not look up for the product tenant. Tenant not found", "product", product.Name)
+				continue
 			}
-			productCRUpdated = true
+			return nil, err
+		}
+
+		if productProviderAccount.AdminURLStr == providerAccount.AdminURLStr {
+			productNames = append(productNames, product.Name)
 		}
 	}
 
-	if productCRUpdated {
-		return ctrl.Result{Requeue: productCRUpdated, RequeueAfter: requeueTime}, nil
+	return productNames, nil
+}
+
+func (r *BackendReconciler) holdBackendDeletion(backend *capabilitiesv1beta1.Backend, productNames []string) error {
+	message := fmt.Sprintf("backend used by products: %s", strings.Join(productNames, ", "))
+
+	changed := backend.Status.Conditions.SetCondition(common.Condition{
+		Type:    capabilitiesv1beta1.BackendInUseConditionType,
+		Status:  corev1.ConditionTrue,
+		Message: message,
+	})
+	if !changed {
+		return nil
 	}
 
-	return ctrl.Result{}, nil
+	r.EventRecorder().Eventf(backend, corev1.EventTypeWarning, "InUse", "Deletion on hold, %s", message)
+	return r.UpdateResourceStatus(backend)
 }
 
 func (r *BackendReconciler) removeBackendFrom3scale(backend *capabilitiesv1beta1.Backend) error {
 	logger := r.Logger().WithValues("backend", client.ObjectKey{Name: backend.Name, Namespace: backend.Namespace})
 
+	if capabilitiesv1beta1.OrphanOnDeletion(backend.Spec.DeletionPolicy) {
+		logger.Info("backend not deleted from 3scale, deletion policy is Orphan")
+		return nil
+	}
+
+	if backend.Spec.DryRun {
+		logger.Info("backend not deleted from 3scale, dry run enabled")
+		return nil
+	}
+
 	// Attempt to remove backend only if backend.Status.ID is present
 	if backend.Status.ID == nil {
 		logger.Info("could not remove backend because ID is missing in status")
@@ -314,38 +384,58 @@ func (r *BackendReconciler) removeBackendFrom3scale(backend *capabilitiesv1beta1
 	return nil
 }
 
-func (r *BackendReconciler) fetchTenantProductCRs(productsCRsList *capabilitiesv1beta1.ProductList, backendResource *capabilitiesv1beta1.Backend) ([]capabilitiesv1beta1.Product, error) {
-	logger := r.Logger().WithValues("backend", client.ObjectKey{Name: backendResource.Name, Namespace: backendResource.Namespace})
-
-	var productsList []capabilitiesv1beta1.Product
-	backendProviderAccount, err := controllerhelper.LookupProviderAccount(r.Client(), backendResource.Namespace, backendResource.Spec.ProviderAccountRef, logger)
-
-	if apierrors.IsNotFound(err) {
-		logger.Info("could not look up for products of the same tenant. Tenant not found")
-		return nil, nil
+// deletedBackendsUsedByProduct enqueues the backends on hold for deletion referenced by the product.
+// Updates map both the old and the new product, so removed references are enqueued as well
+func (r *BackendReconciler) deletedBackendsUsedByProduct(mapObject handler.MapObject) []reconcile.Request {
+	product, ok := mapObject.Object.(*capabilitiesv1beta1.Product)
+	if !ok {
+		return nil
 	}
 
+	backendList := &capabilitiesv1beta1.BackendList{}
+	err := r.Client().List(context.TODO(), backendList, client.InNamespace(product.Namespace))
 	if err != nil {
-		return nil, err
+		r.Logger().Error(err, "failed to list backends", "product", product.Name)
+		return nil
 	}
 
-	for _, productCR := range productsCRsList.Items {
-		productProviderAccount, err := controllerhelper.LookupProviderAccount(r.Client(), productCR.Namespace, productCR.Spec.ProviderAccountRef, logger)
-		if err != nil {
-			// skip product CR if productProviderAccount is not found
-			continue
-		}
-
-		if backendProviderAccount.AdminURLStr == productProviderAccount.AdminURLStr {
-			productsList = append(productsList, productCR)
+	var requests []reconcile.Request
+	for idx := range backendList.Items {
+		backend := &backendList.Items[idx]
+		if backend.GetDeletionTimestamp() != nil && product.UsesBackend(backend.Spec.SystemName) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: backend.Name, Namespace: backend.Namespace}})
 		}
 	}
 
-	return productsList, nil
+	return requests
 }
 
 func (r *BackendReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	err := indexField(mgr, &capabilitiesv1beta1.Backend{}, secretRefIndexField, backendSecretRefIndexer)
+	if err != nil {
+		return err
+	}
+
+	err = indexField(mgr, &capabilitiesv1beta1.Backend{}, privateServiceRefIndexField, backendPrivateServiceRefIndexer)
+	if err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&capabilitiesv1beta1.Backend{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &capabilitiesv1beta1.Product{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.deletedBackendsUsedByProduct),
+		}).
+		// Changes of the services referenced by privateServiceRef change the private base URL
+		Watches(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: &handlers.IndexedFieldEventMapper{
+				K8sClient:  r.Client(),
+				Logger:     r.Logger().WithName("PrivateServiceRefHandler"),
+				List:       &capabilitiesv1beta1.BackendList{},
+				IndexField: privateServiceRefIndexField,
+				IndexValue: handlers.NamespacedNameIndexValue,
+			},
+		})
+
+	return secretRefWatch(r.BaseReconciler, builder, &capabilitiesv1beta1.BackendList{}).Complete(r)
 }
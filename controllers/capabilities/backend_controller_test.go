@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+)
+
+// backendTestProduct returns a synchronized product with an application plan limit on a backend metric
+func backendTestProduct(name, backendSystemName string) *capabilitiesv1beta1.Product {
+	var productID int64 = 3
+	return &capabilitiesv1beta1.Product{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec: capabilitiesv1beta1.ProductSpec{
+			SystemName: name,
+			ApplicationPlans: map[string]capabilitiesv1beta1.ApplicationPlanSpec{
+				"plan": {
+					Limits: []capabilitiesv1beta1.LimitSpec{
+						{
+							Period:          "month",
+							Value:           10,
+							MetricMethodRef: capabilitiesv1beta1.MetricMethodRefSpec{SystemName: "hits", BackendSystemName: &backendSystemName},
+						},
+					},
+				},
+			},
+		},
+		Status: capabilitiesv1beta1.ProductStatus{
+			ID: &productID,
+			Conditions: common.Conditions{
+				{Type: capabilitiesv1beta1.ProductSyncedConditionType, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+}
+
+func deletedTestBackend(deletionPolicy *capabilitiesv1beta1.DeletionPolicy) *capabilitiesv1beta1.Backend {
+	now := metav1.Now()
+	return &capabilitiesv1beta1.Backend{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "backend",
+			Namespace:         "test",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{backendFinalizer},
+		},
+		Spec: capabilitiesv1beta1.BackendSpec{SystemName: "backend1", DeletionPolicy: deletionPolicy},
+	}
+}
+
+func TestBackendReconcilerDeletionInUse(t *testing.T) {
+	backend := deletedTestBackend(nil)
+	product := backendTestProduct("product", "backend1")
+
+	r := &BackendReconciler{BaseReconciler: openapiTestReconciler(getProviderAccount(), backend, product)}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "backend", Namespace: "test"}}
+
+	if requests := r.deletedBackendsUsedByProduct(handler.MapObject{Meta: product, Object: product}); len(requests) != 1 || requests[0].NamespacedName != req.NamespacedName {
+		t.Fatalf("unexpected requests for product: %v", requests)
+	}
+
+	_, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := &capabilitiesv1beta1.Backend{}
+	err = r.Client().Get(context.TODO(), req.NamespacedName, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !controllerutil.ContainsFinalizer(updated, backendFinalizer) {
+		t.Fatal("backend in use finalizer removed")
+	}
+	if !updated.Status.Conditions.IsTrueFor(capabilitiesv1beta1.BackendInUseConditionType) {
+		t.Fatalf("backend InUse condition not set: %v", updated.Status.Conditions)
+	}
+	if msg := updated.Status.Conditions.GetCondition(capabilitiesv1beta1.BackendInUseConditionType).Message; msg != "backend used by products: product" {
+		t.Fatalf("unexpected InUse condition message: %s", msg)
+	}
+
+	product.Spec.ApplicationPlans = nil
+	err = r.Client().Update(context.TODO(), product)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = r.Reconcile(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated = &capabilitiesv1beta1.Backend{}
+	err = r.Client().Get(context.TODO(), req.NamespacedName, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if controllerutil.ContainsFinalizer(updated, backendFinalizer) {
+		t.Fatal("unused backend finalizer not removed")
+	}
+}
+
+func TestBackendReconcilerDeletionOrphanInUse(t *testing.T) {
+	orphan := capabilitiesv1beta1.DeletionPolicyOrphan
+	backend := deletedTestBackend(&orphan)
+	product := backendTestProduct("product", "backend1")
+
+	r := &BackendReconciler{BaseReconciler: openapiTestReconciler(getProviderAccount(), backend, product)}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "backend", Namespace: "test"}}
+
+	_, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := &capabilitiesv1beta1.Backend{}
+	err = r.Client().Get(context.TODO(), req.NamespacedName, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// orphaned backends are kept in 3scale, the deletion is not held
+	if controllerutil.ContainsFinalizer(updated, backendFinalizer) {
+		t.Fatal("orphaned backend finalizer not removed")
+	}
+}
+
+func TestBackendReconcilerDeletionProductMissingProviderAccount(t *testing.T) {
+	backend := deletedTestBackend(nil)
+	// unrelated product whose provider account secret does not exist
+	product := backendTestProduct("other-product", "backend1")
+	product.Spec.ProviderAccountRef = &capabilitiesv1beta1.ProviderAccountReference{Name: "missing-provider-account"}
+
+	r := &BackendReconciler{BaseReconciler: openapiTestReconciler(getProviderAccount(), backend, product)}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "backend", Namespace: "test"}}
+
+	_, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := &capabilitiesv1beta1.Backend{}
+	err = r.Client().Get(context.TODO(), req.NamespacedName, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if controllerutil.ContainsFinalizer(updated, backendFinalizer) {
+		t.Fatal("backend finalizer not removed")
+	}
+}
+
+func TestBackendReconcilerDeletionUsedByFailingProduct(t *testing.T) {
+	backend := deletedTestBackend(nil)
+	// the product synchronized before and fails now, its backend usage is still in 3scale
+	product := backendTestProduct("product", "backend1")
+	product.Status.Conditions = common.Conditions{
+		{Type: capabilitiesv1beta1.ProductSyncedConditionType, Status: corev1.ConditionFalse},
+		{Type: capabilitiesv1beta1.ProductFailedConditionType, Status: corev1.ConditionTrue},
+	}
+
+	r := &BackendReconciler{BaseReconciler: openapiTestReconciler(getProviderAccount(), backend, product)}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "backend", Namespace: "test"}}
+
+	_, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := &capabilitiesv1beta1.Backend{}
+	err = r.Client().Get(context.TODO(), req.NamespacedName, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !controllerutil.ContainsFinalizer(updated, backendFinalizer) {
+		t.Fatal("backend used by failing product finalizer removed")
+	}
+	if !updated.Status.Conditions.IsTrueFor(capabilitiesv1beta1.BackendInUseConditionType) {
+		t.Fatalf("backend InUse condition not set: %v", updated.Status.Conditions)
+	}
+}
+
+func TestBackendReconcilerDeletionProductProviderAccountError(t *testing.T) {
+	backend := deletedTestBackend(nil)
+	product := backendTestProduct("product", "backend1")
+	product.Spec.ProviderAccountRef = &capabilitiesv1beta1.ProviderAccountReference{Name: "invalid-provider-account"}
+	// provider account secret without token
+	invalidProviderAccount := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid-provider-account", Namespace: "test"},
+		Data:       map[string][]byte{"adminURL": []byte("https://3scale-admin.test.3scale.net")},
+	}
+
+	r := &BackendReconciler{BaseReconciler: openapiTestReconciler(getProviderAccount(), invalidProviderAccount, backend, product)}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "backend", Namespace: "test"}}
+
+	// the product may be using the backend, the deletion is retried
+	_, err := r.Reconcile(req)
+	if err == nil {
+		t.Fatal("expected product provider account lookup error")
+	}
+
+	updated := &capabilitiesv1beta1.Backend{}
+	err = r.Client().Get(context.TODO(), req.NamespacedName, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !controllerutil.ContainsFinalizer(updated, backendFinalizer) {
+		t.Fatal("backend finalizer removed on product provider account lookup error")
+	}
+}
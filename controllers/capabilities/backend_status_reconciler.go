@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"fmt"
+	"strings"
 
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	"github.com/3scale/3scale-operator/pkg/common"
@@ -20,16 +21,21 @@ type BackendStatusReconciler struct {
 	backendResource     *capabilitiesv1beta1.Backend
 	backendAPIEntity    *controllerhelper.BackendAPIEntity
 	providerAccountHost string
+	privateBaseURL      string
 	syncError           error
+	modifiedSections    []string
+	plannedChanges      []capabilitiesv1beta1.PlannedChange
+	unplannedSections   []string
 	logger              logr.Logger
 }
 
-func NewBackendStatusReconciler(b *reconcilers.BaseReconciler, backendResource *capabilitiesv1beta1.Backend, backendAPIEntity *controllerhelper.BackendAPIEntity, providerAccountHost string, syncError error) *BackendStatusReconciler {
+func NewBackendStatusReconciler(b *reconcilers.BaseReconciler, backendResource *capabilitiesv1beta1.Backend, backendAPIEntity *controllerhelper.BackendAPIEntity, providerAccountHost, privateBaseURL string, syncError error) *BackendStatusReconciler {
 	return &BackendStatusReconciler{
 		BaseReconciler:      b,
 		backendResource:     backendResource,
 		backendAPIEntity:    backendAPIEntity,
 		providerAccountHost: providerAccountHost,
+		privateBaseURL:      privateBaseURL,
 		syncError:           syncError,
 		logger:              b.Logger().WithValues("Status Reconciler", backendResource.Name),
 	}
@@ -73,19 +79,28 @@ func (s *BackendStatusReconciler) Reconcile() (reconcile.Result, error) {
 
 func (s *BackendStatusReconciler) calculateStatus() *capabilitiesv1beta1.BackendStatus {
 	newStatus := &capabilitiesv1beta1.BackendStatus{}
-	if s.backendAPIEntity != nil {
+	// backends planned for creation have no ID
+	if s.backendAPIEntity != nil && s.backendAPIEntity.ID() != 0 {
 		tmp := s.backendAPIEntity.ID()
 		newStatus.ID = &tmp
 	}
 
 	newStatus.ProviderAccountHost = s.providerAccountHost
 
+	newStatus.PrivateBaseURL = s.privateBaseURL
+
 	newStatus.ObservedGeneration = s.backendResource.Status.ObservedGeneration
 
+	if len(s.plannedChanges) > 0 {
+		newStatus.PlannedChanges = s.plannedChanges
+	}
+
 	newStatus.Conditions = s.backendResource.Status.Conditions.Copy()
 	newStatus.Conditions.SetCondition(s.syncCondition())
 	newStatus.Conditions.SetCondition(s.invalidCondition())
 	newStatus.Conditions.SetCondition(s.failedCondition())
+	newStatus.Conditions.SetCondition(s.driftedCondition())
+	newStatus.Conditions.SetCondition(s.plannedCondition())
 
 	return newStatus
 }
@@ -96,7 +111,8 @@ func (s *BackendStatusReconciler) syncCondition() common.Condition {
 		Status: corev1.ConditionFalse,
 	}
 
-	if s.syncError == nil {
+	// nothing is synchronized when planning
+	if s.syncError == nil && !s.backendResource.Spec.DryRun {
 		condition.Status = corev1.ConditionTrue
 	}
 
@@ -131,3 +147,44 @@ func (s *BackendStatusReconciler) failedCondition() common.Condition {
 
 	return condition
 }
+
+func (s *BackendStatusReconciler) driftedCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.BackendDriftedConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	// Modifications are expected when the spec changed or the previous synchronization did not complete.
+	// Otherwise, the backend was changed in 3scale outside the operator.
+	resyncOfSyncedSpec := s.backendResource.Generation == s.backendResource.Status.ObservedGeneration &&
+		s.backendResource.Status.Conditions.IsTrueFor(capabilitiesv1beta1.BackendSyncedConditionType)
+
+	if s.syncError == nil && resyncOfSyncedSpec && len(s.modifiedSections) > 0 {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = fmt.Sprintf("3scale backend differed from the spec and was corrected. Sections: %s",
+			strings.Join(s.modifiedSections, ", "))
+	}
+
+	return condition
+}
+
+func (s *BackendStatusReconciler) plannedCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.BackendPlannedConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if !s.backendResource.Spec.DryRun || s.syncError != nil {
+		return condition
+	}
+
+	if len(s.unplannedSections) > 0 {
+		condition.Message = fmt.Sprintf("Planned changes: %d. Sections not planned: %s",
+			len(s.plannedChanges), strings.Join(s.unplannedSections, "; "))
+		return condition
+	}
+
+	condition.Status = corev1.ConditionTrue
+	condition.Message = fmt.Sprintf("Planned changes: %d", len(s.plannedChanges))
+	return condition
+}
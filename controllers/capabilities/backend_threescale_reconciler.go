@@ -17,40 +17,53 @@ import (
 type BackendThreescaleReconciler struct {
 	*reconcilers.BaseReconciler
 	backendResource     *capabilitiesv1beta1.Backend
+	privateBaseURL      string
 	backendAPIEntity    *controllerhelper.BackendAPIEntity
 	backendRemoteIndex  *controllerhelper.BackendAPIRemoteIndex
 	threescaleAPIClient *threescaleapi.ThreeScaleClient
 	providerAccount     *controllerhelper.ProviderAccount
+	driftTracker        *driftTracker
 	logger              logr.Logger
 }
 
+// NewThreescaleReconciler returns a BackendThreescaleReconciler.
+// The privateBaseURL is the private base URL of the backend, resolved when the spec references a service.
+// The changeTracker, when not nil, is expected to track the requests of threescaleAPIClient
+// and it is used to find the backend sections that had to be modified.
+// The changePlanner, when not nil, is expected to be the transport of threescaleAPIClient
+// and it is used to plan the backend changes without applying them.
 func NewThreescaleReconciler(b *reconcilers.BaseReconciler,
 	backendResource *capabilitiesv1beta1.Backend,
+	privateBaseURL string,
 	threescaleAPIClient *threescaleapi.ThreeScaleClient,
 	backendRemoteIndex *controllerhelper.BackendAPIRemoteIndex,
 	providerAccount *controllerhelper.ProviderAccount,
+	changeTracker *controllerhelper.ChangeTracker,
+	changePlanner *controllerhelper.ChangePlanner,
 ) *BackendThreescaleReconciler {
 
 	return &BackendThreescaleReconciler{
 		BaseReconciler:      b,
 		backendResource:     backendResource,
+		privateBaseURL:      privateBaseURL,
 		backendRemoteIndex:  backendRemoteIndex,
 		threescaleAPIClient: threescaleAPIClient,
 		providerAccount:     providerAccount,
+		driftTracker:        newDriftTracker(changeTracker, changePlanner),
 		logger:              b.Logger().WithValues("3scale Reconciler", backendResource.Name),
 	}
 }
 
 func (t *BackendThreescaleReconciler) Reconcile() (*controllerhelper.BackendAPIEntity, error) {
 	taskRunner := helper.NewTaskRunner(nil, t.logger)
-	taskRunner.AddTask("SyncBackend", t.syncBackend)
+	taskRunner.AddTask("SyncBackend", t.driftTracker.trackRequired("backend", t.syncBackend))
 	// First methods and metrics, then mapping rules.
 	// Mapping rules reference methods and metrics.
 	// When a method/metric is deleted,
 	// any orphan mapping rule will be deleted automatically by 3scale
-	taskRunner.AddTask("SyncMethods", t.syncMethods)
-	taskRunner.AddTask("SyncMetrics", t.syncMetrics)
-	taskRunner.AddTask("SyncMappingRules", t.syncMappingRules)
+	taskRunner.AddTask("SyncMethods", t.driftTracker.track("methods", t.syncMethods))
+	taskRunner.AddTask("SyncMetrics", t.driftTracker.track("metrics", t.syncMetrics))
+	taskRunner.AddTask("SyncMappingRules", t.driftTracker.track("mappingRules", t.syncMappingRules))
 
 	err := taskRunner.Run()
 	if err != nil {
@@ -60,6 +73,21 @@ func (t *BackendThreescaleReconciler) Reconcile() (*controllerhelper.BackendAPIE
 	return t.backendAPIEntity, nil
 }
 
+// ModifiedSections returns the backend sections modified in 3scale by the last Reconcile call
+func (t *BackendThreescaleReconciler) ModifiedSections() []string {
+	return t.driftTracker.Sections()
+}
+
+// PlannedChanges returns the backend changes planned by the last Reconcile call
+func (t *BackendThreescaleReconciler) PlannedChanges() []capabilitiesv1beta1.PlannedChange {
+	return t.driftTracker.PlannedChanges()
+}
+
+// UnplannedSections returns the backend sections that could not be planned by the last Reconcile call
+func (t *BackendThreescaleReconciler) UnplannedSections() []string {
+	return t.driftTracker.UnplannedSections()
+}
+
 func (t *BackendThreescaleReconciler) syncBackend(_ interface{}) error {
 	var (
 		err              error
@@ -74,7 +102,7 @@ func (t *BackendThreescaleReconciler) syncBackend(_ interface{}) error {
 		params := threescaleapi.Params{
 			"system_name":      t.backendResource.Spec.SystemName,
 			"name":             t.backendResource.Spec.Name,
-			"private_endpoint": t.backendResource.Spec.PrivateBaseURL,
+			"private_endpoint": t.privateBaseURL,
 		}
 		backendAPIEntity, err = t.backendRemoteIndex.CreateBackendAPI(params)
 		if err != nil {
@@ -95,8 +123,8 @@ func (t *BackendThreescaleReconciler) syncBackend(_ interface{}) error {
 		updatedParams["description"] = t.backendResource.Spec.Description
 	}
 
-	if t.backendAPIEntity.PrivateEndpoint() != t.backendResource.Spec.PrivateBaseURL {
-		updatedParams["private_endpoint"] = t.backendResource.Spec.PrivateBaseURL
+	if t.backendAPIEntity.PrivateEndpoint() != t.privateBaseURL {
+		updatedParams["private_endpoint"] = t.privateBaseURL
 	}
 
 	if len(updatedParams) > 0 {
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/webhooks"
+)
+
+// +kubebuilder:webhook:path=/validate-capabilities-3scale-net-v1beta1-product,mutating=false,failurePolicy=fail,groups=capabilities.3scale.net,resources=products,verbs=create;update,versions=v1beta1,name=vproduct.capabilities.3scale.net
+// +kubebuilder:webhook:path=/validate-capabilities-3scale-net-v1beta1-backend,mutating=false,failurePolicy=fail,groups=capabilities.3scale.net,resources=backends,verbs=create;update,versions=v1beta1,name=vbackend.capabilities.3scale.net
+// +kubebuilder:webhook:path=/validate-capabilities-3scale-net-v1beta1-openapi,mutating=false,failurePolicy=fail,groups=capabilities.3scale.net,resources=openapis,verbs=create;update,versions=v1beta1,name=vopenapi.capabilities.3scale.net
+// +kubebuilder:webhook:path=/validate-capabilities-3scale-net-v1beta1-activedoc,mutating=false,failurePolicy=fail,groups=capabilities.3scale.net,resources=activedocs,verbs=create;update,versions=v1beta1,name=vactivedoc.capabilities.3scale.net
+// +kubebuilder:webhook:path=/validate-capabilities-3scale-net-v1beta1-application,mutating=false,failurePolicy=fail,groups=capabilities.3scale.net,resources=applications,verbs=create;update,versions=v1beta1,name=vapplication.capabilities.3scale.net
+// +kubebuilder:webhook:path=/validate-capabilities-3scale-net-v1beta1-developeraccount,mutating=false,failurePolicy=fail,groups=capabilities.3scale.net,resources=developeraccounts,verbs=create;update,versions=v1beta1,name=vdeveloperaccount.capabilities.3scale.net
+// +kubebuilder:webhook:path=/validate-capabilities-3scale-net-v1beta1-developeruser,mutating=false,failurePolicy=fail,groups=capabilities.3scale.net,resources=developerusers,verbs=create;update,versions=v1beta1,name=vdeveloperuser.capabilities.3scale.net
+// +kubebuilder:webhook:path=/validate-capabilities-3scale-net-v1beta1-proxyconfigpromote,mutating=false,failurePolicy=fail,groups=capabilities.3scale.net,resources=proxyconfigpromotes,verbs=create;update,versions=v1beta1,name=vproxyconfigpromote.capabilities.3scale.net
+// +kubebuilder:webhook:path=/validate-capabilities-3scale-net-v1beta1-provideraccount,mutating=false,failurePolicy=fail,groups=capabilities.3scale.net,resources=provideraccounts,verbs=create;update,versions=v1beta1,name=vprovideraccount.capabilities.3scale.net
+
+// capabilityValidator is a capability CR with internal validation
+type capabilityValidator interface {
+	runtime.Object
+	Validate() field.ErrorList
+}
+
+// capabilityDefaulter is a capability CR with defaults set by the controller before validation
+type capabilityDefaulter interface {
+	SetDefaults(logr.Logger) bool
+}
+
+// SetupWebhooksWithManager registers the validating webhooks of the capability CRs.
+// CustomPolicyDefinition and Tenant only have the CRD schema validation.
+func SetupWebhooksWithManager(mgr ctrl.Manager, logger logr.Logger) error {
+	validators := map[string]func() capabilityValidator{
+		capabilitiesv1beta1.ProductKind:          func() capabilityValidator { return &capabilitiesv1beta1.Product{} },
+		capabilitiesv1beta1.BackendKind:          func() capabilityValidator { return &capabilitiesv1beta1.Backend{} },
+		capabilitiesv1beta1.OpenAPIKind:          func() capabilityValidator { return &capabilitiesv1beta1.OpenAPI{} },
+		capabilitiesv1beta1.ActiveDocKind:        func() capabilityValidator { return &capabilitiesv1beta1.ActiveDoc{} },
+		capabilitiesv1beta1.ApplicationKind:      func() capabilityValidator { return &capabilitiesv1beta1.Application{} },
+		capabilitiesv1beta1.DeveloperAccountKind: func() capabilityValidator { return &capabilitiesv1beta1.DeveloperAccount{} },
+		capabilitiesv1beta1.DeveloperUserKind:    func() capabilityValidator { return &capabilitiesv1beta1.DeveloperUser{} },
+		"ProxyConfigPromote":                     func() capabilityValidator { return &capabilitiesv1beta1.ProxyConfigPromote{} },
+		capabilitiesv1beta1.ProviderAccountKind:  func() capabilityValidator { return &capabilitiesv1beta1.ProviderAccount{} },
+	}
+
+	for kind, newValidator := range validators {
+		validate := capabilityValidatorFunc(logger)
+		if kind == capabilitiesv1beta1.ProductKind {
+			validate = productValidator(mgr.GetClient(), logger)
+		}
+
+		newObject := newValidator
+		hook, err := webhooks.NewValidatingWebhook(mgr.GetScheme(), func() runtime.Object { return newObject() }, validate, logger.WithValues("kind", kind))
+		if err != nil {
+			return err
+		}
+
+		mgr.GetWebhookServer().Register(webhooks.ValidatePath(capabilitiesv1beta1.GroupVersion.WithKind(kind)), &webhook.Admission{Handler: hook})
+	}
+
+	return nil
+}
+
+func capabilityValidatorFunc(logger logr.Logger) webhooks.ValidateFunc {
+	return func(_ context.Context, obj, _ runtime.Object) field.ErrorList {
+		if defaulter, ok := obj.(capabilityDefaulter); ok {
+			defaulter.SetDefaults(logger)
+		}
+		return obj.(capabilityValidator).Validate()
+	}
+}
+
+// productValidator checks the product backend usages reference Backend CRs in the namespace.
+// Backends do not need to be synchronized, so backends and products can be created together.
+// The product controller checks the backend provider account and the plan limits and pricing rules.
+// Updates not changing the spec, like the controller metadata updates, skip the backend usages check,
+// as the referenced backends may have been deleted since.
+func productValidator(cl client.Client, logger logr.Logger) webhooks.ValidateFunc {
+	return func(ctx context.Context, obj, oldObj runtime.Object) field.ErrorList {
+		product := obj.(*capabilitiesv1beta1.Product)
+		specChanged := oldObj == nil || !equality.Semantic.DeepEqual(oldObj.(*capabilitiesv1beta1.Product).Spec, product.Spec)
+		product.SetDefaults(logger)
+
+		fieldErrors := product.Validate()
+		if len(fieldErrors) > 0 || len(product.Spec.BackendUsages) == 0 || !specChanged {
+			return fieldErrors
+		}
+
+		backendList := &capabilitiesv1beta1.BackendList{}
+		err := cl.List(ctx, backendList, client.InNamespace(product.Namespace))
+		if err != nil {
+			logger.Error(err, "Skipping product backend usages validation", "product", product.Name)
+			return nil
+		}
+
+		// System names of backends not reconciled yet
+		for idx := range backendList.Items {
+			backendList.Items[idx].SetDefaults(logger)
+		}
+
+		return checkBackendUsages(product, backendList.Items)
+	}
+}
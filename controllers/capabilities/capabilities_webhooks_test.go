@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/webhooks"
+)
+
+func TestProductValidator(t *testing.T) {
+	backend := &capabilitiesv1beta1.Backend{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend1", Namespace: "test"},
+		Spec: capabilitiesv1beta1.BackendSpec{
+			Name:           "Backend 1",
+			PrivateBaseURL: "https://api.example.com",
+		},
+	}
+	baseReconciler := openapiTestReconciler(backend)
+	validate := productValidator(baseReconciler.Client(), logf.Log.WithName("product webhook test"))
+
+	product := &capabilitiesv1beta1.Product{
+		ObjectMeta: metav1.ObjectMeta{Name: "product", Namespace: "test"},
+		Spec: capabilitiesv1beta1.ProductSpec{
+			Name: "Product",
+			BackendUsages: map[string]capabilitiesv1beta1.BackendUsageSpec{
+				"backend1": {Path: "/one"},
+			},
+		},
+	}
+
+	if errs := validate(context.TODO(), product.DeepCopy(), nil); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	product.Spec.BackendUsages["missing"] = capabilitiesv1beta1.BackendUsageSpec{Path: "/two"}
+	errs := validate(context.TODO(), product.DeepCopy(), nil)
+	if len(errs) != 1 || errs[0].Field != "spec.backendUsages[missing]" {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
+
+func productUpdateRequest(t *testing.T, product, oldProduct *capabilitiesv1beta1.Product) admission.Request {
+	raw, err := json.Marshal(product)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldRaw, err := json.Marshal(oldProduct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: capabilitiesv1beta1.GroupVersion.Group, Version: capabilitiesv1beta1.GroupVersion.Version, Kind: capabilitiesv1beta1.ProductKind},
+		Name:      product.Name,
+		Namespace: product.Namespace,
+		Operation: v1beta1.Update,
+		Object:    runtime.RawExtension{Raw: raw},
+		OldObject: runtime.RawExtension{Raw: oldRaw},
+	}}
+}
+
+func TestProductValidatorMissingBackend(t *testing.T) {
+	baseReconciler := openapiTestReconciler()
+	logger := logf.Log.WithName("product webhook test")
+	hook, err := webhooks.NewValidatingWebhook(baseReconciler.Scheme(), func() runtime.Object { return &capabilitiesv1beta1.Product{} },
+		productValidator(baseReconciler.Client(), logger), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The referenced backend CR has been deleted after the product was created
+	product := &capabilitiesv1beta1.Product{
+		TypeMeta:   metav1.TypeMeta{APIVersion: capabilitiesv1beta1.GroupVersion.String(), Kind: capabilitiesv1beta1.ProductKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "product", Namespace: "test", Finalizers: []string{productFinalizer}},
+		Spec: capabilitiesv1beta1.ProductSpec{
+			Name: "Product",
+			BackendUsages: map[string]capabilitiesv1beta1.BackendUsageSpec{
+				"backend1": {Path: "/one"},
+			},
+		},
+	}
+
+	updated := product.DeepCopy()
+	updated.Labels = map[string]string{"app": "product"}
+	if resp := hook.Handle(context.TODO(), productUpdateRequest(t, updated, product)); !resp.Allowed {
+		t.Fatalf("metadata update not allowed: %v", resp.Result)
+	}
+
+	updated = product.DeepCopy()
+	updated.Spec.Description = "changed"
+	if resp := hook.Handle(context.TODO(), productUpdateRequest(t, updated, product)); resp.Allowed {
+		t.Fatal("spec update with missing backend allowed")
+	}
+
+	now := metav1.Now()
+	deleting := product.DeepCopy()
+	deleting.DeletionTimestamp = &now
+	updated = deleting.DeepCopy()
+	updated.Finalizers = nil
+	if resp := hook.Handle(context.TODO(), productUpdateRequest(t, updated, deleting)); !resp.Allowed {
+		t.Fatalf("finalizer removal not allowed: %v", resp.Result)
+	}
+}
@@ -23,9 +23,14 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
 	"github.com/3scale/3scale-operator/pkg/helper"
@@ -34,6 +39,8 @@ import (
 	"github.com/go-logr/logr"
 )
 
+const customPolicyDefinitionFinalizer = "custompolicydefinition.capabilities.3scale.net/finalizer"
+
 // CustomPolicyDefinitionReconciler reconciles a CustomPolicyDefinition object
 type CustomPolicyDefinitionReconciler struct {
 	*reconcilers.BaseReconciler
@@ -44,6 +51,7 @@ var _ reconcile.Reconciler = &CustomPolicyDefinitionReconciler{}
 
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=custompolicydefinitions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=custompolicydefinitions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=custompolicydefinitions/finalizers,verbs=get;list;watch;create;update;patch;delete
 
 func (r *CustomPolicyDefinitionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	_ = context.Background()
@@ -73,12 +81,44 @@ func (r *CustomPolicyDefinitionReconciler) Reconcile(req ctrl.Request) (ctrl.Res
 		reqLogger.V(1).Info(string(jsonData))
 	}
 
+	// CustomPolicyDefinition has been marked for deletion
+	if customPolicyDefinitionCR.GetDeletionTimestamp() != nil && controllerutil.ContainsFinalizer(customPolicyDefinitionCR, customPolicyDefinitionFinalizer) {
+		err = r.removeCustomPolicyDefinitionFrom3scale(customPolicyDefinitionCR)
+		if err != nil {
+			r.EventRecorder().Eventf(customPolicyDefinitionCR, corev1.EventTypeWarning, "Failed to delete custom policy", "%v", err)
+			return ctrl.Result{}, err
+		}
+
+		controllerutil.RemoveFinalizer(customPolicyDefinitionCR, customPolicyDefinitionFinalizer)
+		err = r.UpdateResource(customPolicyDefinitionCR)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
 	// Ignore deleted resource, this can happen when foregroundDeletion is enabled
 	// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#foreground-cascading-deletion
 	if customPolicyDefinitionCR.DeletionTimestamp != nil {
 		return ctrl.Result{}, nil
 	}
 
+	if !controllerutil.ContainsFinalizer(customPolicyDefinitionCR, customPolicyDefinitionFinalizer) {
+		// CustomPolicyDefinitions synchronized before the finalizer was introduced keep
+		// the 3scale custom policy on deletion, as they did before the upgrade
+		if customPolicyDefinitionCR.Status.ID != nil {
+			metav1.SetMetaDataAnnotation(&customPolicyDefinitionCR.ObjectMeta, capabilitiesv1beta1.DefaultDeletionPolicyAnnotation,
+				string(capabilitiesv1beta1.DeletionPolicyOrphan))
+		}
+		controllerutil.AddFinalizer(customPolicyDefinitionCR, customPolicyDefinitionFinalizer)
+		err = r.UpdateResource(customPolicyDefinitionCR)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	statusReconciler, reconcileErr := r.reconcileSpec(customPolicyDefinitionCR, reqLogger)
 	statusResult, statusUpdateErr := statusReconciler.Reconcile()
 	if statusUpdateErr != nil {
@@ -134,3 +174,39 @@ func (r *CustomPolicyDefinitionReconciler) SetupWithManager(mgr ctrl.Manager) er
 		For(&capabilitiesv1beta1.CustomPolicyDefinition{}).
 		Complete(r)
 }
+
+func (r *CustomPolicyDefinitionReconciler) removeCustomPolicyDefinitionFrom3scale(customPolicyDefinitionCR *capabilitiesv1beta1.CustomPolicyDefinition) error {
+	logger := r.Logger().WithValues("custompolicydefinition", client.ObjectKey{Name: customPolicyDefinitionCR.Name, Namespace: customPolicyDefinitionCR.Namespace})
+
+	if capabilitiesv1beta1.OrphanOnDeletion(capabilitiesv1beta1.EffectiveDeletionPolicy(customPolicyDefinitionCR.Spec.DeletionPolicy, customPolicyDefinitionCR.Annotations)) {
+		logger.Info("custom policy not deleted from 3scale, deletion policy is Orphan")
+		return nil
+	}
+
+	// Attempt to remove custom policy only if customPolicyDefinitionCR.Status.ID is present
+	if customPolicyDefinitionCR.Status.ID == nil {
+		logger.Info("could not remove custom policy because ID is missing in status")
+		return nil
+	}
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), customPolicyDefinitionCR.Namespace, customPolicyDefinitionCR.Spec.ProviderAccountRef, logger)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("custom policy not deleted from 3scale, provider account not found")
+			return nil
+		}
+		return err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		return err
+	}
+
+	err = threescaleAPIClient.DeleteAPIcastPolicy(*customPolicyDefinitionCR.Status.ID)
+	if err != nil && !threescaleapi.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
@@ -226,6 +226,11 @@ func (r *DeveloperAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
 func (r *DeveloperAccountReconciler) removeDeveloperAccountFrom3scale(developerAccountCR *capabilitiesv1beta1.DeveloperAccount) error {
 	logger := r.Logger().WithValues("developeraccount", client.ObjectKey{Name: developerAccountCR.Name, Namespace: developerAccountCR.Namespace})
 
+	if capabilitiesv1beta1.OrphanOnDeletion(developerAccountCR.Spec.DeletionPolicy) {
+		logger.Info("developer account not deleted from 3scale, deletion policy is Orphan")
+		return nil
+	}
+
 	// Attempt to remove developer account only if developerAccountCR.Status.ID is present
 	if developerAccountCR.Status.ID == nil {
 		logger.Info("could not remove developer account because ID is missing in status")
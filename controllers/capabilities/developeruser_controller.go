@@ -278,6 +278,11 @@ func (r *DeveloperUserReconciler) findParentAccount(userCR *capabilitiesv1beta1.
 func (r *DeveloperUserReconciler) removeDeveloperUserFrom3scale(developerUser *capabilitiesv1beta1.DeveloperUser) error {
 	logger := r.Logger().WithValues("developerUser", client.ObjectKey{Name: developerUser.Name, Namespace: developerUser.Namespace})
 
+	if capabilitiesv1beta1.OrphanOnDeletion(developerUser.Spec.DeletionPolicy) {
+		logger.Info("developer user not deleted from 3scale, deletion policy is Orphan")
+		return nil
+	}
+
 	// Attempt to remove developerUser only if developerUser.Status.ID is present
 	if developerUser.Status.ID == nil {
 		logger.Info("could not remove developerUser because ID is missing in status")
@@ -326,7 +331,13 @@ func (r *DeveloperUserReconciler) retrieveDevelopAccountCR(developerUser *capabi
 }
 
 func (r *DeveloperUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&capabilitiesv1beta1.DeveloperUser{}).
-		Complete(r)
+	err := indexField(mgr, &capabilitiesv1beta1.DeveloperUser{}, secretRefIndexField, developerUserSecretRefIndexer)
+	if err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&capabilitiesv1beta1.DeveloperUser{})
+
+	return secretRefWatch(r.BaseReconciler, builder, &capabilitiesv1beta1.DeveloperUserList{}).Complete(r)
 }
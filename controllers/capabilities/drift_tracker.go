@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+)
+
+// driftTracker records the sections of a 3scale object modified by the synchronization tasks.
+// When planning, it records the changes the tasks would apply instead.
+type driftTracker struct {
+	changeTracker     *controllerhelper.ChangeTracker
+	changePlanner     *controllerhelper.ChangePlanner
+	sections          []string
+	plannedChanges    []capabilitiesv1beta1.PlannedChange
+	unplannedSections []string
+}
+
+func newDriftTracker(changeTracker *controllerhelper.ChangeTracker, changePlanner *controllerhelper.ChangePlanner) *driftTracker {
+	return &driftTracker{
+		changeTracker:     changeTracker,
+		changePlanner:     changePlanner,
+		sections:          []string{},
+		plannedChanges:    []capabilitiesv1beta1.PlannedChange{},
+		unplannedSections: []string{},
+	}
+}
+
+// track wraps a synchronization task. The section is recorded when the task sends requests modifying 3scale.
+// When planning, a task error is recorded as unplanned section and the next tasks still run.
+func (d *driftTracker) track(section string, task func(interface{}) error) func(interface{}) error {
+	planTask := d.trackRequired(section, task)
+	if d.changePlanner == nil {
+		return planTask
+	}
+
+	return func(ctx interface{}) error {
+		if err := planTask(ctx); err != nil {
+			d.unplannedSections = append(d.unplannedSections, fmt.Sprintf("%s: %v", section, err))
+		}
+		return nil
+	}
+}
+
+// trackRequired wraps a synchronization task that the next tasks depend on.
+// The task error is returned also when planning.
+func (d *driftTracker) trackRequired(section string, task func(interface{}) error) func(interface{}) error {
+	return func(ctx interface{}) error {
+		if d.changePlanner != nil {
+			planned := len(d.changePlanner.Changes())
+			err := task(ctx)
+			taskChanges := d.changePlanner.Changes()[planned:]
+			// tasks iterate over maps, sorted for a stable plan
+			sort.SliceStable(taskChanges, func(i, j int) bool {
+				return plannedChangeKey(taskChanges[i]) < plannedChangeKey(taskChanges[j])
+			})
+			for _, change := range taskChanges {
+				change.Section = section
+				d.plannedChanges = append(d.plannedChanges, change)
+			}
+			return err
+		}
+
+		if d.changeTracker == nil {
+			return task(ctx)
+		}
+
+		changes := d.changeTracker.Changes()
+		err := task(ctx)
+		if d.changeTracker.Changes() > changes {
+			d.addSection(section)
+		}
+		return err
+	}
+}
+
+func plannedChangeKey(change capabilitiesv1beta1.PlannedChange) string {
+	// fmt prints maps sorted by key
+	return fmt.Sprintf("%s %s %v %v", change.Path, change.Action, change.After, change.Before)
+}
+
+func (d *driftTracker) addSection(section string) {
+	for _, existing := range d.sections {
+		if existing == section {
+			return
+		}
+	}
+	d.sections = append(d.sections, section)
+}
+
+// Sections returns the modified sections in synchronization order
+func (d *driftTracker) Sections() []string {
+	return d.sections
+}
+
+// PlannedChanges returns the planned changes in synchronization order
+func (d *driftTracker) PlannedChanges() []capabilitiesv1beta1.PlannedChange {
+	return d.plannedChanges
+}
+
+// UnplannedSections returns the sections that could not be planned, with the reason
+func (d *driftTracker) UnplannedSections() []string {
+	return d.unplannedSections
+}
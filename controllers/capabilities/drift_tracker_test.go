@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+)
+
+func TestDriftTracker(t *testing.T) {
+	changeTracker := &controllerhelper.ChangeTracker{
+		Transport: roundTripFunc(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+				Header:     make(http.Header),
+			}
+		}),
+	}
+	httpClient := &http.Client{Transport: changeTracker}
+
+	request := func(method string) func(interface{}) error {
+		return func(_ interface{}) error {
+			req, err := http.NewRequest(method, "https://www.test.com/admin/api/services.json", nil)
+			if err != nil {
+				return err
+			}
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}
+	}
+
+	tracker := newDriftTracker(changeTracker, nil)
+	tasks := []func(interface{}) error{
+		tracker.track("product", request(http.MethodGet)),
+		tracker.track("methods", request(http.MethodPost)),
+		tracker.track("metrics", request(http.MethodGet)),
+		tracker.track("proxy", request(http.MethodPatch)),
+		tracker.track("proxy", request(http.MethodPut)),
+	}
+	for _, task := range tasks {
+		if err := task(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{"methods", "proxy"}
+	if !reflect.DeepEqual(tracker.Sections(), expected) {
+		t.Errorf("unexpected sections: got %v, want %v", tracker.Sections(), expected)
+	}
+}
+
+func TestDriftTrackerWithoutChangeTracker(t *testing.T) {
+	tracker := newDriftTracker(nil, nil)
+	err := tracker.track("methods", func(_ interface{}) error { return nil })(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracker.Sections()) != 0 {
+		t.Errorf("unexpected sections: %v", tracker.Sections())
+	}
+}
+
+func TestDriftTrackerPlanning(t *testing.T) {
+	changePlanner := &controllerhelper.ChangePlanner{
+		Transport: roundTripFunc(func(req *http.Request) *http.Response {
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: ioutil.NopCloser(bytes.NewBufferString("{}"))}
+		}),
+	}
+	httpClient := &http.Client{Transport: changePlanner}
+
+	create := func(path string) func(interface{}) error {
+		return func(_ interface{}) error {
+			resp, err := httpClient.Post("https://www.test.com"+path, "application/x-www-form-urlencoded", nil)
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}
+	}
+
+	tracker := newDriftTracker(nil, changePlanner)
+	tasks := []func(interface{}) error{
+		tracker.trackRequired("product", create("/admin/api/services.json")),
+		tracker.track("methods", func(_ interface{}) error { return errors.New("hits not found") }),
+		tracker.track("metrics", create("/admin/api/services/0/metrics.json")),
+	}
+	for _, task := range tasks {
+		if err := task(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []capabilitiesv1beta1.PlannedChange{
+		{Section: "product", Action: capabilitiesv1beta1.PlannedChangeActionCreate, Path: "/admin/api/services.json"},
+		{Section: "metrics", Action: capabilitiesv1beta1.PlannedChangeActionCreate, Path: "/admin/api/services/0/metrics.json"},
+	}
+	if !reflect.DeepEqual(tracker.PlannedChanges(), expected) {
+		t.Errorf("unexpected planned changes: got %v, want %v", tracker.PlannedChanges(), expected)
+	}
+	if !reflect.DeepEqual(tracker.UnplannedSections(), []string{"methods: hits not found"}) {
+		t.Errorf("unexpected unplanned sections: %v", tracker.UnplannedSections())
+	}
+	if len(tracker.Sections()) != 0 {
+		t.Errorf("unexpected modified sections: %v", tracker.Sections())
+	}
+
+	err := tracker.trackRequired("product", func(_ interface{}) error { return errors.New("failed") })(nil)
+	if err == nil {
+		t.Error("required task error not returned")
+	}
+}
+
+type roundTripFunc func(req *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/handlers"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+)
+
+const (
+	// secretRefIndexField is the field index with the namespace/name of the secrets
+	// and ProviderAccount CRs referenced by a CR
+	secretRefIndexField = "secretRef"
+
+	// privateServiceRefIndexField is the field index with the namespace/name of the service referenced by a backend
+	privateServiceRefIndexField = "privateServiceRef"
+
+	// backendUsageIndexField is the field index with the namespace/systemName of the backends used by a product
+	backendUsageIndexField = "backendUsage"
+)
+
+func namespacedIndexValue(namespace, name string) string {
+	return types.NamespacedName{Name: name, Namespace: namespace}.String()
+}
+
+func providerAccountSecretIndexValue(namespace string, providerAccountRef *capabilitiesv1beta1.ProviderAccountReference) string {
+	if providerAccountRef != nil && providerAccountRef.Namespace != "" {
+		namespace = providerAccountRef.Namespace
+	}
+	return namespacedIndexValue(namespace, controllerhelper.ProviderAccountSecretName(providerAccountRef))
+}
+
+func productSecretRefIndexer(obj runtime.Object) []string {
+	product := obj.(*capabilitiesv1beta1.Product)
+	return []string{providerAccountSecretIndexValue(product.Namespace, product.Spec.ProviderAccountRef)}
+}
+
+func productBackendUsageIndexer(obj runtime.Object) []string {
+	product := obj.(*capabilitiesv1beta1.Product)
+	values := make([]string, 0, len(product.Spec.BackendUsages))
+	for backendSystemName := range product.Spec.BackendUsages {
+		values = append(values, namespacedIndexValue(product.Namespace, backendSystemName))
+	}
+	return values
+}
+
+func backendSecretRefIndexer(obj runtime.Object) []string {
+	backend := obj.(*capabilitiesv1beta1.Backend)
+	return []string{providerAccountSecretIndexValue(backend.Namespace, backend.Spec.ProviderAccountRef)}
+}
+
+func backendPrivateServiceRefIndexer(obj runtime.Object) []string {
+	backend := obj.(*capabilitiesv1beta1.Backend)
+	if backend.Spec.PrivateServiceRef == nil {
+		return nil
+	}
+	return []string{namespacedIndexValue(controllerhelper.BackendPrivateServiceNamespace(backend), backend.Spec.PrivateServiceRef.Name)}
+}
+
+func openapiSecretRefIndexer(obj runtime.Object) []string {
+	openapiCR := obj.(*capabilitiesv1beta1.OpenAPI)
+	values := []string{providerAccountSecretIndexValue(openapiCR.Namespace, openapiCR.Spec.ProviderAccountRef)}
+	if secretRef := openapiCR.Spec.OpenAPIRef.SecretRef; secretRef != nil {
+		namespace := openapiCR.Namespace
+		if secretRef.Namespace != "" {
+			namespace = secretRef.Namespace
+		}
+		values = append(values, namespacedIndexValue(namespace, secretRef.Name))
+	}
+	if oidc := openapiCR.Spec.OIDC; oidc != nil && oidc.IssuerEndpointRef != nil {
+		values = append(values, namespacedIndexValue(openapiCR.Namespace, oidc.IssuerEndpointRef.Name))
+	}
+	return values
+}
+
+func developerUserSecretRefIndexer(obj runtime.Object) []string {
+	userCR := obj.(*capabilitiesv1beta1.DeveloperUser)
+	namespace := userCR.Namespace
+	if userCR.Spec.PasswordCredentialsRef.Namespace != "" {
+		namespace = userCR.Spec.PasswordCredentialsRef.Namespace
+	}
+	return []string{
+		providerAccountSecretIndexValue(userCR.Namespace, userCR.Spec.ProviderAccountRef),
+		namespacedIndexValue(namespace, userCR.Spec.PasswordCredentialsRef.Name),
+	}
+}
+
+func applicationSecretRefIndexer(obj runtime.Object) []string {
+	applicationCR := obj.(*capabilitiesv1beta1.Application)
+	values := []string{providerAccountSecretIndexValue(applicationCR.Namespace, applicationCR.Spec.ProviderAccountRef)}
+	if applicationCR.Spec.AuthSecretRef != nil {
+		values = append(values, namespacedIndexValue(applicationCR.Namespace, applicationCR.Spec.AuthSecretRef.Name))
+	}
+	return values
+}
+
+func providerAccountSecretRefIndexer(obj runtime.Object) []string {
+	providerAccountCR := obj.(*capabilitiesv1beta1.ProviderAccount)
+	values := []string{namespacedIndexValue(providerAccountCR.Namespace, providerAccountCR.Spec.TokenSecretRef.Name)}
+	if providerAccountCR.Spec.CABundleSecretRef != nil {
+		values = append(values, namespacedIndexValue(providerAccountCR.Namespace, providerAccountCR.Spec.CABundleSecretRef.Name))
+	}
+	return values
+}
+
+func backendUsageIndexValue(mapObject handler.MapObject) string {
+	backend, ok := mapObject.Object.(*capabilitiesv1beta1.Backend)
+	if !ok || backend.Spec.SystemName == "" {
+		return ""
+	}
+	return namespacedIndexValue(backend.Namespace, backend.Spec.SystemName)
+}
+
+// secretRefWatch enqueues the CRs of the list type referencing the changed secret or ProviderAccount.
+// The provider account reference of a CR is indexed once and matches either.
+func secretRefWatch(b *reconcilers.BaseReconciler, builder *ctrl.Builder, list runtime.Object) *ctrl.Builder {
+	return builder.
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: &handlers.IndexedFieldEventMapper{
+				K8sClient:  b.Client(),
+				Logger:     b.Logger().WithName("SecretRefHandler"),
+				List:       list,
+				IndexField: secretRefIndexField,
+				IndexValue: handlers.NamespacedNameIndexValue,
+			},
+		}).
+		Watches(&source.Kind{Type: &capabilitiesv1beta1.ProviderAccount{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: &handlers.IndexedFieldEventMapper{
+				K8sClient:  b.Client(),
+				Logger:     b.Logger().WithName("ProviderAccountRefHandler"),
+				List:       list,
+				IndexField: secretRefIndexField,
+				IndexValue: handlers.NamespacedNameIndexValue,
+			},
+		})
+}
+
+func indexField(mgr ctrl.Manager, obj runtime.Object, field string, indexer func(runtime.Object) []string) error {
+	return mgr.GetFieldIndexer().IndexField(context.Background(), obj, field, indexer)
+}
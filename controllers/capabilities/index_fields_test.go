@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+)
+
+func TestProductIndexers(t *testing.T) {
+	product := &capabilitiesv1beta1.Product{
+		ObjectMeta: metav1.ObjectMeta{Name: "product", Namespace: "ns"},
+		Spec: capabilitiesv1beta1.ProductSpec{
+			BackendUsages: map[string]capabilitiesv1beta1.BackendUsageSpec{
+				"backend1": {Path: "/one"},
+				"backend2": {Path: "/two"},
+			},
+		},
+	}
+
+	values := productSecretRefIndexer(product)
+	if !reflect.DeepEqual(values, []string{"ns/threescale-provider-account"}) {
+		t.Fatalf("unexpected default provider account index values: %v", values)
+	}
+
+	product.Spec.ProviderAccountRef = &capabilitiesv1beta1.ProviderAccountReference{Name: "mytenant"}
+	values = productSecretRefIndexer(product)
+	if !reflect.DeepEqual(values, []string{"ns/mytenant"}) {
+		t.Fatalf("unexpected provider account index values: %v", values)
+	}
+
+	product.Spec.ProviderAccountRef.Namespace = "shared"
+	values = productSecretRefIndexer(product)
+	if !reflect.DeepEqual(values, []string{"shared/mytenant"}) {
+		t.Fatalf("unexpected cross namespace provider account index values: %v", values)
+	}
+
+	values = productBackendUsageIndexer(product)
+	sort.Strings(values)
+	if !reflect.DeepEqual(values, []string{"ns/backend1", "ns/backend2"}) {
+		t.Fatalf("unexpected backend usage index values: %v", values)
+	}
+
+	backend := &capabilitiesv1beta1.Backend{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "ns"},
+		Spec:       capabilitiesv1beta1.BackendSpec{SystemName: "backend1"},
+	}
+	if value := backendUsageIndexValue(handler.MapObject{Meta: backend, Object: backend}); value != "ns/backend1" {
+		t.Fatalf("unexpected backend index value: %s", value)
+	}
+}
+
+func TestSecretRefIndexers(t *testing.T) {
+	openapiCR := &capabilitiesv1beta1.OpenAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "openapi", Namespace: "ns"},
+		Spec: capabilitiesv1beta1.OpenAPISpec{
+			OpenAPIRef: capabilitiesv1beta1.OpenAPIRefSpec{
+				SecretRef: &corev1.ObjectReference{Name: "oas"},
+			},
+			ProviderAccountRef: &capabilitiesv1beta1.ProviderAccountReference{Name: "mytenant"},
+		},
+	}
+
+	values := openapiSecretRefIndexer(openapiCR)
+	if !reflect.DeepEqual(values, []string{"ns/mytenant", "ns/oas"}) {
+		t.Fatalf("unexpected openapi index values: %v", values)
+	}
+
+	openapiCR.Spec.OIDC = &capabilitiesv1beta1.OpenAPIOIDCSpec{
+		IssuerEndpointRef: &corev1.LocalObjectReference{Name: "issuer"},
+	}
+	values = openapiSecretRefIndexer(openapiCR)
+	if !reflect.DeepEqual(values, []string{"ns/mytenant", "ns/oas", "ns/issuer"}) {
+		t.Fatalf("unexpected openapi index values: %v", values)
+	}
+
+	userCR := &capabilitiesv1beta1.DeveloperUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "user", Namespace: "ns"},
+		Spec: capabilitiesv1beta1.DeveloperUserSpec{
+			PasswordCredentialsRef: corev1.SecretReference{Name: "password", Namespace: "other"},
+		},
+	}
+
+	values = developerUserSecretRefIndexer(userCR)
+	if !reflect.DeepEqual(values, []string{"ns/threescale-provider-account", "other/password"}) {
+		t.Fatalf("unexpected developer user index values: %v", values)
+	}
+}
+
+func TestBackendPrivateServiceRefIndexer(t *testing.T) {
+	backend := &capabilitiesv1beta1.Backend{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "ns"},
+		Spec:       capabilitiesv1beta1.BackendSpec{PrivateBaseURL: "https://api.example.com"},
+	}
+
+	if values := backendPrivateServiceRefIndexer(backend); len(values) != 0 {
+		t.Fatalf("unexpected index values without service reference: %v", values)
+	}
+
+	backend.Spec.PrivateBaseURL = ""
+	backend.Spec.PrivateServiceRef = &capabilitiesv1beta1.BackendPrivateServiceReference{Name: "echo-api", Port: intstr.FromString("http")}
+	values := backendPrivateServiceRefIndexer(backend)
+	if !reflect.DeepEqual(values, []string{"ns/echo-api"}) {
+		t.Fatalf("unexpected private service index values: %v", values)
+	}
+
+	backend.Spec.PrivateServiceRef.Namespace = "apis"
+	values = backendPrivateServiceRefIndexer(backend)
+	if !reflect.DeepEqual(values, []string{"apis/echo-api"}) {
+		t.Fatalf("unexpected cross namespace private service index values: %v", values)
+	}
+}
+
+func TestApplicationSecretRefIndexer(t *testing.T) {
+	applicationCR := &capabilitiesv1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application", Namespace: "ns"},
+	}
+
+	values := applicationSecretRefIndexer(applicationCR)
+	if !reflect.DeepEqual(values, []string{"ns/threescale-provider-account"}) {
+		t.Fatalf("unexpected application index values: %v", values)
+	}
+
+	applicationCR.Spec.AuthSecretRef = &corev1.LocalObjectReference{Name: "auth"}
+	values = applicationSecretRefIndexer(applicationCR)
+	if !reflect.DeepEqual(values, []string{"ns/threescale-provider-account", "ns/auth"}) {
+		t.Fatalf("unexpected application auth secret index values: %v", values)
+	}
+}
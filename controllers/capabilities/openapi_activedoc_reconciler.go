@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type OpenAPIActiveDocReconciler struct {
+	*reconcilers.BaseReconciler
+	openapiCR       *capabilitiesv1beta1.OpenAPI
+	openapiObj      *openapi3.T
+	providerAccount *controllerhelper.ProviderAccount
+	logger          logr.Logger
+}
+
+func NewOpenAPIActiveDocReconciler(b *reconcilers.BaseReconciler,
+	openapiCR *capabilitiesv1beta1.OpenAPI,
+	openapiObj *openapi3.T,
+	providerAccount *controllerhelper.ProviderAccount,
+	logger logr.Logger,
+) *OpenAPIActiveDocReconciler {
+	return &OpenAPIActiveDocReconciler{
+		BaseReconciler:  b,
+		openapiCR:       openapiCR,
+		openapiObj:      openapiObj,
+		providerAccount: providerAccount,
+		logger:          logger,
+	}
+}
+
+func (p *OpenAPIActiveDocReconciler) Logger() logr.Logger {
+	return p.logger
+}
+
+func (p *OpenAPIActiveDocReconciler) Reconcile() (*capabilitiesv1beta1.ActiveDoc, error) {
+	desired, err := p.desired()
+	if err != nil {
+		return nil, err
+	}
+
+	// The activedoc is deleted when the activeDoc section is removed from the spec
+	if p.openapiCR.Spec.ActiveDoc == nil {
+		common.TagObjectToDelete(desired)
+	}
+
+	if p.Logger().V(1).Enabled() {
+		jsonData, err := json.MarshalIndent(desired, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		p.Logger().V(1).Info(string(jsonData))
+	}
+
+	return nil, p.ReconcileResource(&capabilitiesv1beta1.ActiveDoc{}, desired, p.activeDocMutator)
+}
+
+func (p *OpenAPIActiveDocReconciler) desired() (*capabilitiesv1beta1.ActiveDoc, error) {
+	fieldErrors := field.ErrorList{}
+	specFldPath := field.NewPath("spec")
+	openapiRefFldPath := specFldPath.Child("openapiRef")
+
+	// obj name
+	objName := p.desiredObjName()
+
+	// DNS Subdomain Names
+	// If the name would be part of some label, validation would be DNS Label Names (validation.IsDNS1123Label)
+	// https://kubernetes.io/docs/concepts/overview/working-with-objects/names/
+	errStrings := validation.IsDNS1123Subdomain(objName)
+	if len(errStrings) > 0 {
+		fieldErrors = append(fieldErrors, field.Invalid(openapiRefFldPath, p.openapiCR.Spec.OpenAPIRef, strings.Join(errStrings, ",")))
+		return nil, &helper.SpecFieldError{
+			ErrorType:      helper.InvalidError,
+			FieldErrorList: fieldErrors,
+		}
+	}
+
+	// activedoc name
+	name := p.openapiObj.Info.Title
+
+	// product system name
+	// Same as product system name
+	productSystemName := helper.SystemNameFromOpenAPITitle(p.openapiObj)
+	if p.openapiCR.Spec.ProductSystemName != nil {
+		productSystemName = *p.openapiCR.Spec.ProductSystemName
+	}
+
+	activeDoc := &capabilitiesv1beta1.ActiveDoc{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       capabilitiesv1beta1.ActiveDocKind,
+			APIVersion: capabilitiesv1beta1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      objName,
+			Namespace: p.openapiCR.Namespace,
+		},
+		Spec: capabilitiesv1beta1.ActiveDocSpec{
+			Name:               name,
+			ProductSystemName:  &productSystemName,
+			ProviderAccountRef: p.openapiCR.Spec.ProviderAccountRef,
+			ActiveDocOpenAPIRef: capabilitiesv1beta1.ActiveDocOpenAPIRefSpec{
+				SecretRef: p.openapiCR.Spec.OpenAPIRef.SecretRef.DeepCopy(),
+				URL:       p.openapiCR.Spec.OpenAPIRef.URL,
+			},
+		},
+	}
+
+	if p.openapiObj.Info.Description != "" {
+		description := p.openapiObj.Info.Description
+		activeDoc.Spec.Description = &description
+	}
+
+	if activeDocSpec := p.openapiCR.Spec.ActiveDoc; activeDocSpec != nil {
+		if activeDocSpec.Name != nil {
+			activeDoc.Spec.Name = *activeDocSpec.Name
+		}
+		activeDoc.Spec.Published = activeDocSpec.Published
+		activeDoc.Spec.SkipSwaggerValidations = activeDocSpec.SkipSwaggerValidations
+	}
+
+	activeDoc.SetDefaults(p.Logger())
+
+	// internal validation
+	validationErrors := activeDoc.Validate()
+	if len(validationErrors) > 0 {
+		return nil, errors.New(validationErrors.ToAggregate().Error())
+	}
+
+	err := p.SetOwnerReference(p.openapiCR, activeDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	return activeDoc, nil
+}
+
+func (p *OpenAPIActiveDocReconciler) activeDocMutator(existingObj, desiredObj common.KubernetesObject) (bool, error) {
+	existing, ok := existingObj.(*capabilitiesv1beta1.ActiveDoc)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *capabilitiesv1beta1.ActiveDoc", existingObj)
+	}
+	desired, ok := desiredObj.(*capabilitiesv1beta1.ActiveDoc)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *capabilitiesv1beta1.ActiveDoc", desiredObj)
+	}
+
+	// Metadata labels and annotations
+	updated := helper.EnsureObjectMeta(existing, desired)
+
+	// OwnerRefenrence
+	updatedTmp, err := p.EnsureOwnerReference(p.openapiCR, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
+	if !reflect.DeepEqual(existing.Spec, desired.Spec) {
+		diff := cmp.Diff(existing.Spec, desired.Spec)
+		p.Logger().Info(fmt.Sprintf("%s spec has changed: %s", common.ObjectInfo(desired), diff))
+		existing.Spec = desired.Spec
+		updated = true
+	}
+
+	return updated, nil
+}
+
+func (p *OpenAPIActiveDocReconciler) desiredObjName() string {
+	// Same as product obj name
+	return fmt.Sprintf("%s-%s", helper.K8sNameFromOpenAPITitle(p.openapiObj), string(p.openapiCR.UID))
+}
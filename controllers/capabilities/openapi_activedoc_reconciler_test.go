@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+)
+
+const activeDocOpenAPI = `
+openapi: "3.0.2"
+info:
+  title: "Pet Store"
+  description: "Pets API"
+  version: "1.0.0"
+paths: {}
+`
+
+func TestOpenAPIActiveDocReconciler(t *testing.T) {
+	published := true
+	activeDocName := "Pets"
+	openapiCR := &capabilitiesv1beta1.OpenAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "openapi", Namespace: "test", UID: "ab12"},
+		Spec: capabilitiesv1beta1.OpenAPISpec{
+			OpenAPIRef: capabilitiesv1beta1.OpenAPIRefSpec{
+				SecretRef: &corev1.ObjectReference{Name: "oas", Namespace: "test"},
+			},
+			ProviderAccountRef: &capabilitiesv1beta1.ProviderAccountReference{Name: "provider"},
+			ActiveDoc: &capabilitiesv1beta1.OpenAPIActiveDocSpec{
+				Name:      &activeDocName,
+				Published: &published,
+			},
+		},
+	}
+	baseReconciler := openapiTestReconciler()
+	openapiObj := loadTestOpenAPI(t, activeDocOpenAPI)
+
+	reconciler := NewOpenAPIActiveDocReconciler(baseReconciler, openapiCR, openapiObj, nil, logf.Log.WithName("openapi test"))
+	if _, err := reconciler.Reconcile(); err != nil {
+		t.Fatal(err)
+	}
+
+	activeDoc := &capabilitiesv1beta1.ActiveDoc{}
+	activeDocKey := types.NamespacedName{Name: "petstore-ab12", Namespace: "test"}
+	if err := baseReconciler.Client().Get(baseReconciler.Context(), activeDocKey, activeDoc); err != nil {
+		t.Fatal(err)
+	}
+
+	if activeDoc.Spec.Name != "Pets" {
+		t.Errorf("unexpected name: %s", activeDoc.Spec.Name)
+	}
+	if activeDoc.Spec.ProductSystemName == nil || *activeDoc.Spec.ProductSystemName != "pet_store" {
+		t.Errorf("unexpected product system name: %v", activeDoc.Spec.ProductSystemName)
+	}
+	if activeDoc.Spec.Description == nil || *activeDoc.Spec.Description != "Pets API" {
+		t.Errorf("unexpected description: %v", activeDoc.Spec.Description)
+	}
+	if activeDoc.Spec.ActiveDocOpenAPIRef.SecretRef == nil || activeDoc.Spec.ActiveDocOpenAPIRef.SecretRef.Name != "oas" {
+		t.Errorf("unexpected openapi ref: %+v", activeDoc.Spec.ActiveDocOpenAPIRef)
+	}
+	if activeDoc.Spec.Published == nil || !*activeDoc.Spec.Published {
+		t.Errorf("activedoc not published")
+	}
+	if activeDoc.Spec.ProviderAccountRef == nil || activeDoc.Spec.ProviderAccountRef.Name != "provider" {
+		t.Errorf("unexpected provider account ref: %v", activeDoc.Spec.ProviderAccountRef)
+	}
+	if len(activeDoc.OwnerReferences) != 1 || activeDoc.OwnerReferences[0].UID != openapiCR.UID {
+		t.Errorf("unexpected owner references: %v", activeDoc.OwnerReferences)
+	}
+
+	// activedoc is deleted when removed from the spec
+	openapiCR.Spec.ActiveDoc = nil
+	reconciler = NewOpenAPIActiveDocReconciler(baseReconciler, openapiCR, openapiObj, nil, logf.Log.WithName("openapi test"))
+	if _, err := reconciler.Reconcile(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := baseReconciler.Client().Get(baseReconciler.Context(), activeDocKey, activeDoc)
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected activedoc to be deleted, got: %v", err)
+	}
+}
@@ -18,12 +18,19 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"net/url"
+	"time"
 
+	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -46,6 +53,9 @@ type OpenAPIReconciler struct {
 // blank assignment to verify that OpenAPIReconciler implements reconcile.Reconciler
 var _ reconcile.Reconciler = &OpenAPIReconciler{}
 
+// openapiHTTPClient fetches OpenAPI documents from URL references
+var openapiHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=openapis,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=openapis/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=openapis/finalizers,verbs=get;list;watch;create;update;patch;delete
@@ -125,9 +135,18 @@ func (r *OpenAPIReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 }
 
 func (r *OpenAPIReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	err := indexField(mgr, &capabilitiesv1beta1.OpenAPI{}, secretRefIndexField, openapiSecretRefIndexer)
+	if err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&capabilitiesv1beta1.OpenAPI{}).
-		Complete(r)
+		Owns(&capabilitiesv1beta1.Product{}).
+		Owns(&capabilitiesv1beta1.Backend{}).
+		Owns(&capabilitiesv1beta1.ActiveDoc{})
+
+	return secretRefWatch(r.BaseReconciler, builder, &capabilitiesv1beta1.OpenAPIList{}).Complete(r)
 }
 
 func (r *OpenAPIReconciler) reconcileSpec(openapiCR *capabilitiesv1beta1.OpenAPI) (*OpenAPIStatusReconciler, ctrl.Result, error) {
@@ -145,29 +164,16 @@ func (r *OpenAPIReconciler) reconcileSpec(openapiCR *capabilitiesv1beta1.OpenAPI
 		return statusReconciler, ctrl.Result{}, err
 	}
 
-	openapiObj, err := r.readOpenAPI(openapiCR)
-	if err != nil {
-		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, false)
-		return statusReconciler, ctrl.Result{}, err
-	}
-
-	err = r.validateOpenAPIAs3scaleProduct(openapiCR, openapiObj)
+	openapiObj, openapiRefStatus, err := r.readOpenAPI(openapiCR)
 	if err != nil {
 		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, false)
 		return statusReconciler, ctrl.Result{}, err
 	}
 
-	backendReconciler := NewOpenAPIBackendReconciler(r.BaseReconciler, openapiCR, openapiObj, providerAccount, logger)
-	_, err = backendReconciler.Reconcile()
-	if err != nil {
-		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, false)
-		return statusReconciler, ctrl.Result{}, err
-	}
-
-	productReconciler := NewOpenAPIProductReconciler(r.BaseReconciler, openapiCR, openapiObj, providerAccount, logger)
-	_, err = productReconciler.Reconcile()
+	err = r.reconcileOpenAPIResources(openapiCR, openapiObj, providerAccount, logger)
 	if err != nil {
 		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, false)
+		statusReconciler.openapiRefStatus = openapiRefStatus
 		return statusReconciler, ctrl.Result{}, err
 	}
 
@@ -178,11 +184,42 @@ func (r *OpenAPIReconciler) reconcileSpec(openapiCR *capabilitiesv1beta1.OpenAPI
 	productSynced, err := r.checkProductSynced(openapiCR)
 	if err != nil {
 		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, false)
+		statusReconciler.openapiRefStatus = openapiRefStatus
 		return statusReconciler, ctrl.Result{}, err
 	}
 
 	statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, productSynced)
-	return statusReconciler, ctrl.Result{Requeue: !productSynced}, err
+	statusReconciler.openapiRefStatus = openapiRefStatus
+
+	if !productSynced {
+		return statusReconciler, ctrl.Result{Requeue: true}, nil
+	}
+
+	return statusReconciler, ctrl.Result{RequeueAfter: openapiRequeueAfter(openapiCR, openapiRefStatus)}, nil
+}
+
+// reconcileOpenAPIResources generates the 3scale resources from the OpenAPI document
+func (r *OpenAPIReconciler) reconcileOpenAPIResources(openapiCR *capabilitiesv1beta1.OpenAPI, openapiObj *openapi3.T, providerAccount *controllerhelper.ProviderAccount, logger logr.Logger) error {
+	err := r.validateOpenAPIAs3scaleProduct(openapiCR, openapiObj)
+	if err != nil {
+		return err
+	}
+
+	backendReconciler := NewOpenAPIBackendReconciler(r.BaseReconciler, openapiCR, openapiObj, providerAccount, logger)
+	_, err = backendReconciler.Reconcile()
+	if err != nil {
+		return err
+	}
+
+	productReconciler := NewOpenAPIProductReconciler(r.BaseReconciler, openapiCR, openapiObj, providerAccount, logger)
+	_, err = productReconciler.Reconcile()
+	if err != nil {
+		return err
+	}
+
+	activeDocReconciler := NewOpenAPIActiveDocReconciler(r.BaseReconciler, openapiCR, openapiObj, providerAccount, logger)
+	_, err = activeDocReconciler.Reconcile()
+	return err
 }
 
 func (r *OpenAPIReconciler) validateSpec(resource *capabilitiesv1beta1.OpenAPI) error {
@@ -220,10 +257,13 @@ func (r *OpenAPIReconciler) checkProductSynced(resource *capabilitiesv1beta1.Ope
 	return product.Status.Conditions.IsTrueFor(capabilitiesv1beta1.ProductSyncedConditionType), nil
 }
 
-func (r *OpenAPIReconciler) readOpenAPI(resource *capabilitiesv1beta1.OpenAPI) (*openapi3.T, error) {
+// readOpenAPI returns the OpenAPI document and, for URL sources, the status of
+// the fetched document
+func (r *OpenAPIReconciler) readOpenAPI(resource *capabilitiesv1beta1.OpenAPI) (*openapi3.T, *capabilitiesv1beta1.OpenAPIRefStatus, error) {
 	// OpenAPIRef is oneOf by CRD openapiV3 validation
 	if resource.Spec.OpenAPIRef.SecretRef != nil {
-		return r.readOpenAPISecret(resource)
+		openapiObj, err := r.readOpenAPISecret(resource)
+		return openapiObj, nil, err
 	}
 
 	// Must be URL
@@ -269,7 +309,7 @@ func (r *OpenAPIReconciler) readOpenAPISecret(resource *capabilitiesv1beta1.Open
 		return nil
 	}(openapiSecretObj)
 
-	openapiObj, err := openapi3.NewLoader().LoadFromData(dataByteArray)
+	openapiObj, err := helper.LoadOpenAPIDocument(dataByteArray, nil)
 	if err != nil {
 		fieldErrors = append(fieldErrors, field.Invalid(secretRefFldPath, resource.Spec.OpenAPIRef.SecretRef, err.Error()))
 		return nil, &helper.SpecFieldError{
@@ -308,8 +348,16 @@ func (r *OpenAPIReconciler) validateOpenAPIAs3scaleProduct(openapiCR *capabiliti
 	if len(globalSecRequirements) == 1 {
 		// Validate supported types
 		switch globalSecRequirements[0].Value.Type {
-		case "apiKey":
+		case "apiKey", "openIdConnect":
 			break
+		case "oauth2":
+			if openapiCR.Spec.OIDC == nil || (openapiCR.Spec.OIDC.IssuerEndpoint == nil && openapiCR.Spec.OIDC.IssuerEndpointRef == nil) {
+				fieldErrors = append(fieldErrors, field.Required(specFldPath.Child("oidc").Child("issuerEndpoint"), "OIDC issuer endpoint required for the oauth2 security scheme"))
+				return &helper.SpecFieldError{
+					ErrorType:      helper.InvalidError,
+					FieldErrorList: fieldErrors,
+				}
+			}
 		default:
 			fieldErrors = append(fieldErrors, field.Invalid(openapiRefFldPath, openapiCR.Spec.OpenAPIRef, fmt.Sprintf("Unexpected security schema type: %s", globalSecRequirements[0].Value.Type)))
 			return &helper.SpecFieldError{
@@ -322,7 +370,7 @@ func (r *OpenAPIReconciler) validateOpenAPIAs3scaleProduct(openapiCR *capabiliti
 	return nil
 }
 
-func (r *OpenAPIReconciler) readOpenAPIFromURL(resource *capabilitiesv1beta1.OpenAPI) (*openapi3.T, error) {
+func (r *OpenAPIReconciler) readOpenAPIFromURL(resource *capabilitiesv1beta1.OpenAPI) (*openapi3.T, *capabilitiesv1beta1.OpenAPIRefStatus, error) {
 	fieldErrors := field.ErrorList{}
 	specFldPath := field.NewPath("spec")
 	openapiRefFldPath := specFldPath.Child("openapiRef")
@@ -331,16 +379,66 @@ func (r *OpenAPIReconciler) readOpenAPIFromURL(resource *capabilitiesv1beta1.Ope
 	openAPIURL, err := url.Parse(*resource.Spec.OpenAPIRef.URL)
 	if err != nil {
 		fieldErrors = append(fieldErrors, field.Invalid(urlRefFldPath, resource.Spec.OpenAPIRef.URL, err.Error()))
-		return nil, &helper.SpecFieldError{
+		return nil, nil, &helper.SpecFieldError{
 			ErrorType:      helper.InvalidError,
 			FieldErrorList: fieldErrors,
 		}
 	}
 
-	openapiObj, err := openapi3.NewLoader().LoadFromURI(openAPIURL)
+	// Periodically fetched documents are cached. Until the refresh is due, or
+	// when the document has not changed, the generated resources are
+	// reconciled with the cached document
+	var previous *capabilitiesv1beta1.OpenAPIRefStatus
+	var cachedData []byte
+	if resource.Spec.OpenAPIRef.RefreshInterval != nil && openapiResourcesGenerated(resource) {
+		previous = resource.Status.OpenAPIRef
+		cachedData, err = r.readCachedOpenAPIDocument(resource, previous.ContentHash)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cachedData == nil {
+			// Without the cached document, the document is fetched unconditionally
+			previous = nil
+		}
+	}
+
+	var openapiData []byte
+	var openapiRefStatus *capabilitiesv1beta1.OpenAPIRefStatus
+	if previous != nil && openapiRefreshAfter(resource, previous) > 0 {
+		openapiData, openapiRefStatus = cachedData, previous
+	} else {
+		openapiData, openapiRefStatus, err = fetchOpenAPIURL(r.Context(), openapiHTTPClient, openAPIURL, previous)
+		if err != nil && cachedData == nil {
+			// Fetch errors may be transient, they are not spec errors and are retried
+			return nil, nil, fmt.Errorf("failed fetching the OpenAPI document: %w", err)
+		}
+
+		if err != nil {
+			// Failed refreshes keep the cached document until the next refresh
+			r.Logger().Info("OpenAPI document refresh failed, using the cached document", "url", openAPIURL.String(), "error", err.Error())
+			r.EventRecorder().Eventf(resource, corev1.EventTypeWarning, "OpenAPIRefreshFailed", "%v", err)
+			openapiData, openapiRefStatus = cachedData, previous
+		} else if openapiData == nil {
+			r.Logger().V(1).Info("OpenAPI document not changed", "url", openAPIURL.String())
+			openapiData = cachedData
+		} else if resource.Spec.OpenAPIRef.RefreshInterval != nil {
+			err = r.cacheOpenAPIDocument(resource, openapiData, openapiRefStatus.ContentHash)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	// Keep the fetch time of the unchanged document when it is not periodically
+	// fetched. Otherwise every reconciliation would update the status
+	if previousStatus := resource.Status.OpenAPIRef; resource.Spec.OpenAPIRef.RefreshInterval == nil && previousStatus != nil && previousStatus.ContentHash == openapiRefStatus.ContentHash {
+		openapiRefStatus = previousStatus
+	}
+
+	openapiObj, err := helper.LoadOpenAPIDocument(openapiData, openAPIURL)
 	if err != nil {
 		fieldErrors = append(fieldErrors, field.Invalid(urlRefFldPath, resource.Spec.OpenAPIRef.URL, err.Error()))
-		return nil, &helper.SpecFieldError{
+		return nil, nil, &helper.SpecFieldError{
 			ErrorType:      helper.InvalidError,
 			FieldErrorList: fieldErrors,
 		}
@@ -349,11 +447,100 @@ func (r *OpenAPIReconciler) readOpenAPIFromURL(resource *capabilitiesv1beta1.Ope
 	err = openapiObj.Validate(r.Context())
 	if err != nil {
 		fieldErrors = append(fieldErrors, field.Invalid(urlRefFldPath, resource.Spec.OpenAPIRef.URL, err.Error()))
-		return nil, &helper.SpecFieldError{
+		return nil, nil, &helper.SpecFieldError{
 			ErrorType:      helper.InvalidError,
 			FieldErrorList: fieldErrors,
 		}
 	}
 
-	return openapiObj, nil
+	return openapiObj, openapiRefStatus, nil
+}
+
+// openapiResourcesGenerated returns true when the product and backend have
+// been generated for the current spec
+func openapiResourcesGenerated(resource *capabilitiesv1beta1.OpenAPI) bool {
+	return resource.Status.OpenAPIRef != nil &&
+		resource.Status.ObservedGeneration == resource.Generation &&
+		!resource.Status.Conditions.IsTrueFor(capabilitiesv1beta1.OpenAPIFailedConditionType)
+}
+
+// openapiRefreshAfter returns the time until the document must be fetched
+// again. Zero when it is not periodically fetched or the refresh is due
+func openapiRefreshAfter(resource *capabilitiesv1beta1.OpenAPI, openapiRefStatus *capabilitiesv1beta1.OpenAPIRefStatus) time.Duration {
+	refreshInterval := resource.Spec.OpenAPIRef.RefreshInterval
+	if refreshInterval == nil || openapiRefStatus == nil || openapiRefStatus.LastFetchTime == nil {
+		return 0
+	}
+
+	refreshAfter := refreshInterval.Duration - time.Since(openapiRefStatus.LastFetchTime.Time)
+	if refreshAfter < 0 {
+		return 0
+	}
+	return refreshAfter
+}
+
+// openapiRequeueAfter returns the time until the next periodic fetch of the document.
+// When the refresh is due, because the last refresh failed, it is retried after the refresh interval
+func openapiRequeueAfter(resource *capabilitiesv1beta1.OpenAPI, openapiRefStatus *capabilitiesv1beta1.OpenAPIRefStatus) time.Duration {
+	refreshAfter := openapiRefreshAfter(resource, openapiRefStatus)
+	if refreshAfter == 0 && resource.Spec.OpenAPIRef.RefreshInterval != nil {
+		return resource.Spec.OpenAPIRef.RefreshInterval.Duration
+	}
+	return refreshAfter
+}
+
+// fetchOpenAPIURL fetches the OpenAPI document with a conditional request when
+// the status of a previous fetch is available. The returned data is nil when
+// the document has not changed since the previous fetch
+func fetchOpenAPIURL(ctx context.Context, httpClient *http.Client, location *url.URL, previous *capabilitiesv1beta1.OpenAPIRefStatus) ([]byte, *capabilitiesv1beta1.OpenAPIRefStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if previous != nil {
+		if previous.ETag != "" {
+			req.Header.Set("If-None-Match", previous.ETag)
+		}
+		if previous.LastModified != "" {
+			req.Header.Set("If-Modified-Since", previous.LastModified)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	now := metav1.Now()
+
+	if resp.StatusCode == http.StatusNotModified && previous != nil {
+		refStatus := previous.DeepCopy()
+		refStatus.LastFetchTime = &now
+		return nil, refStatus, nil
+	}
+
+	if resp.StatusCode > 399 {
+		return nil, nil, fmt.Errorf("error loading %q: request returned status code %d", location.String(), resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	refStatus := &capabilitiesv1beta1.OpenAPIRefStatus{
+		ContentHash:   hex.EncodeToString(hash[:]),
+		ETag:          resp.Header.Get("ETag"),
+		LastModified:  resp.Header.Get("Last-Modified"),
+		LastFetchTime: &now,
+	}
+
+	if previous != nil && previous.ContentHash == refStatus.ContentHash {
+		return nil, refStatus, nil
+	}
+
+	return data, refStatus, nil
 }
@@ -0,0 +1,333 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+)
+
+func TestFetchOpenAPIURL(t *testing.T) {
+	const etag = `"v1"`
+	body := "openapi: 3.0.2"
+	useETag := true
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if useETag {
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	location, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// first fetch
+	data, refStatus, err := fetchOpenAPIURL(context.TODO(), server.Client(), location, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != body {
+		t.Fatalf("unexpected data: %s", data)
+	}
+	if refStatus.ETag != etag || refStatus.ContentHash == "" || refStatus.LastFetchTime == nil {
+		t.Fatalf("unexpected status: %+v", refStatus)
+	}
+
+	// not modified
+	data, notModifiedStatus, err := fetchOpenAPIURL(context.TODO(), server.Client(), location, refStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != nil {
+		t.Fatalf("unexpected data on not modified document: %s", data)
+	}
+	if notModifiedStatus.ContentHash != refStatus.ContentHash {
+		t.Fatalf("content hash changed: %s -> %s", refStatus.ContentHash, notModifiedStatus.ContentHash)
+	}
+
+	// no conditional request support and same content
+	useETag = false
+	data, sameStatus, err := fetchOpenAPIURL(context.TODO(), server.Client(), location, refStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != nil {
+		t.Fatalf("unexpected data on unchanged document: %s", data)
+	}
+	if sameStatus.ContentHash != refStatus.ContentHash {
+		t.Fatalf("content hash changed: %s -> %s", refStatus.ContentHash, sameStatus.ContentHash)
+	}
+
+	// content changed
+	body = "openapi: 3.0.3"
+	data, changedStatus, err := fetchOpenAPIURL(context.TODO(), server.Client(), location, refStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != body {
+		t.Fatalf("unexpected data: %s", data)
+	}
+	if changedStatus.ContentHash == refStatus.ContentHash {
+		t.Fatal("content hash did not change")
+	}
+}
+
+func TestFetchOpenAPIURLErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	location, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = fetchOpenAPIURL(context.TODO(), server.Client(), location, nil)
+	if err == nil {
+		t.Fatal("expected error on not found document")
+	}
+}
+
+func TestOpenAPIRefreshAfter(t *testing.T) {
+	openapiURL := "https://example.com/openapi.yaml"
+	openapiCR := &capabilitiesv1beta1.OpenAPI{
+		Spec: capabilitiesv1beta1.OpenAPISpec{
+			OpenAPIRef: capabilitiesv1beta1.OpenAPIRefSpec{URL: &openapiURL},
+		},
+	}
+	lastFetchTime := metav1.NewTime(time.Now().Add(-time.Minute))
+	refStatus := &capabilitiesv1beta1.OpenAPIRefStatus{LastFetchTime: &lastFetchTime}
+
+	if refreshAfter := openapiRefreshAfter(openapiCR, refStatus); refreshAfter != 0 {
+		t.Fatalf("expected no refresh without interval, got %s", refreshAfter)
+	}
+
+	openapiCR.Spec.OpenAPIRef.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+	if refreshAfter := openapiRefreshAfter(openapiCR, refStatus); refreshAfter <= 0 || refreshAfter > time.Hour-time.Minute {
+		t.Fatalf("unexpected refresh time %s", refreshAfter)
+	}
+
+	openapiCR.Spec.OpenAPIRef.RefreshInterval = &metav1.Duration{Duration: 30 * time.Second}
+	if refreshAfter := openapiRefreshAfter(openapiCR, refStatus); refreshAfter != 0 {
+		t.Fatalf("expected refresh due, got %s", refreshAfter)
+	}
+}
+
+func TestReadOpenAPIFromURLCachedDocument(t *testing.T) {
+	const etag = `"v1"`
+	body := []byte("openapi: 3.0.2\ninfo:\n  title: Petstore\n  version: 1.0.0\npaths: {}\n")
+	hash := sha256.Sum256(body)
+	contentHash := hex.EncodeToString(hash[:])
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	previousHTTPClient := openapiHTTPClient
+	openapiHTTPClient = server.Client()
+	defer func() { openapiHTTPClient = previousHTTPClient }()
+
+	openapiCR := func(lastFetchTime time.Time) *capabilitiesv1beta1.OpenAPI {
+		fetchTime := metav1.NewTime(lastFetchTime)
+		return &capabilitiesv1beta1.OpenAPI{
+			ObjectMeta: metav1.ObjectMeta{Name: "petstore", Namespace: "test", Generation: 1, UID: "openapi-uid"},
+			Spec: capabilitiesv1beta1.OpenAPISpec{
+				OpenAPIRef: capabilitiesv1beta1.OpenAPIRefSpec{
+					URL:             &server.URL,
+					RefreshInterval: &metav1.Duration{Duration: time.Hour},
+				},
+			},
+			Status: capabilitiesv1beta1.OpenAPIStatus{
+				ObservedGeneration: 1,
+				OpenAPIRef: &capabilitiesv1beta1.OpenAPIRefStatus{
+					ContentHash:   contentHash,
+					ETag:          etag,
+					LastFetchTime: &fetchTime,
+				},
+			},
+		}
+	}
+	cache := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "petstore-openapi-document",
+			Namespace:   "test",
+			Annotations: map[string]string{openapiDocumentCacheHashAnnotation: contentHash},
+		},
+		BinaryData: map[string][]byte{openapiDocumentCacheDataKey: body},
+	}
+
+	tests := []struct {
+		name          string
+		lastFetchTime time.Time
+		objs          []runtime.Object
+		wantRequests  int
+	}{
+		{"refresh not due", time.Now().Add(-time.Minute), []runtime.Object{cache.DeepCopy()}, 0},
+		{"refresh due and not modified", time.Now().Add(-2 * time.Hour), []runtime.Object{cache.DeepCopy()}, 1},
+		{"refresh not due without cache", time.Now().Add(-time.Minute), nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(subT *testing.T) {
+			requests = 0
+			r := &OpenAPIReconciler{BaseReconciler: openapiTestReconciler(tt.objs...)}
+			resource := openapiCR(tt.lastFetchTime)
+
+			openapiObj, refStatus, err := r.readOpenAPIFromURL(resource)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if openapiObj == nil || openapiObj.Info.Title != "Petstore" {
+				subT.Fatalf("unexpected document: %v", openapiObj)
+			}
+			if refStatus == nil || refStatus.ContentHash != contentHash {
+				subT.Fatalf("unexpected status: %+v", refStatus)
+			}
+			if requests != tt.wantRequests {
+				subT.Errorf("expected %d requests, got %d", tt.wantRequests, requests)
+			}
+
+			cached := &corev1.ConfigMap{}
+			err = r.Client().Get(context.TODO(), types.NamespacedName{Name: "petstore-openapi-document", Namespace: "test"}, cached)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if string(cached.BinaryData[openapiDocumentCacheDataKey]) != string(body) {
+				subT.Errorf("unexpected cached document: %s", cached.BinaryData[openapiDocumentCacheDataKey])
+			}
+		})
+	}
+}
+
+func TestReadOpenAPIFromURLRefreshFailure(t *testing.T) {
+	oldBody := []byte("openapi: 3.0.2\ninfo:\n  title: Petstore\n  version: 1.0.0\npaths: {}\n")
+	newBody := []byte("openapi: 3.0.2\ninfo:\n  title: Petstore\n  version: 2.0.0\npaths: {}\n")
+	hash := sha256.Sum256(oldBody)
+	contentHash := hex.EncodeToString(hash[:])
+
+	// The server is unavailable once and then recovers
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(newBody)
+	}))
+	defer server.Close()
+
+	previousHTTPClient := openapiHTTPClient
+	openapiHTTPClient = server.Client()
+	defer func() { openapiHTTPClient = previousHTTPClient }()
+
+	lastFetchTime := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	resource := &capabilitiesv1beta1.OpenAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "petstore", Namespace: "test", Generation: 1, UID: "openapi-uid"},
+		Spec: capabilitiesv1beta1.OpenAPISpec{
+			OpenAPIRef: capabilitiesv1beta1.OpenAPIRefSpec{
+				URL:             &server.URL,
+				RefreshInterval: &metav1.Duration{Duration: time.Hour},
+			},
+		},
+		Status: capabilitiesv1beta1.OpenAPIStatus{
+			ObservedGeneration: 1,
+			OpenAPIRef: &capabilitiesv1beta1.OpenAPIRefStatus{
+				ContentHash:   contentHash,
+				LastFetchTime: &lastFetchTime,
+			},
+		},
+	}
+	cache := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "petstore-openapi-document",
+			Namespace:   "test",
+			Annotations: map[string]string{openapiDocumentCacheHashAnnotation: contentHash},
+		},
+		BinaryData: map[string][]byte{openapiDocumentCacheDataKey: oldBody},
+	}
+	r := &OpenAPIReconciler{BaseReconciler: openapiTestReconciler(cache)}
+
+	// The failed refresh keeps the cached document and is retried after the refresh interval
+	openapiObj, refStatus, err := r.readOpenAPIFromURL(resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if openapiObj == nil || openapiObj.Info.Version != "1.0.0" {
+		t.Fatalf("unexpected document: %v", openapiObj)
+	}
+	if refStatus == nil || refStatus.ContentHash != contentHash {
+		t.Fatalf("unexpected status: %+v", refStatus)
+	}
+	if requeueAfter := openapiRequeueAfter(resource, refStatus); requeueAfter != time.Hour {
+		t.Fatalf("unexpected requeue after: %s", requeueAfter)
+	}
+
+	openapiObj, refStatus, err = r.readOpenAPIFromURL(resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if openapiObj == nil || openapiObj.Info.Version != "2.0.0" {
+		t.Fatalf("unexpected refreshed document: %v", openapiObj)
+	}
+	if refStatus == nil || refStatus.ContentHash == contentHash {
+		t.Fatalf("unexpected refreshed status: %+v", refStatus)
+	}
+}
+
+func TestReadOpenAPIFromURLFetchFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	previousHTTPClient := openapiHTTPClient
+	openapiHTTPClient = server.Client()
+	defer func() { openapiHTTPClient = previousHTTPClient }()
+
+	resource := &capabilitiesv1beta1.OpenAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "petstore", Namespace: "test"},
+		Spec: capabilitiesv1beta1.OpenAPISpec{
+			OpenAPIRef: capabilitiesv1beta1.OpenAPIRefSpec{URL: &server.URL},
+		},
+	}
+	r := &OpenAPIReconciler{BaseReconciler: openapiTestReconciler()}
+
+	_, _, err := r.readOpenAPIFromURL(resource)
+	if err == nil {
+		t.Fatal("expected fetch error")
+	}
+	// Fetch errors are retried, they are not spec errors
+	if helper.IsInvalidSpecError(err) {
+		t.Fatalf("fetch error reported as invalid spec: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	"github.com/3scale/3scale-operator/pkg/helper"
+)
+
+const (
+	// openapiDocumentCacheDataKey is the configmap key holding the cached OpenAPI document
+	openapiDocumentCacheDataKey = "openapi"
+
+	// openapiDocumentCacheHashAnnotation is the annotation holding the content hash of the cached OpenAPI document
+	openapiDocumentCacheHashAnnotation = "capabilities.3scale.net/openapi-content-hash"
+)
+
+// openapiDocumentCacheName returns the name of the configmap caching the OpenAPI document fetched from the URL
+func openapiDocumentCacheName(resource *capabilitiesv1beta1.OpenAPI) string {
+	return fmt.Sprintf("%s-openapi-document", resource.Name)
+}
+
+// readCachedOpenAPIDocument returns the cached OpenAPI document.
+// Nil when the document is not cached or the cached document has a different content hash
+func (r *OpenAPIReconciler) readCachedOpenAPIDocument(resource *capabilitiesv1beta1.OpenAPI, contentHash string) ([]byte, error) {
+	cache := &corev1.ConfigMap{}
+	err := r.Client().Get(r.Context(), types.NamespacedName{Name: openapiDocumentCacheName(resource), Namespace: resource.Namespace}, cache)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if cache.Annotations[openapiDocumentCacheHashAnnotation] != contentHash {
+		return nil, nil
+	}
+
+	return cache.BinaryData[openapiDocumentCacheDataKey], nil
+}
+
+// cacheOpenAPIDocument stores the OpenAPI document fetched from the URL in a configmap owned by the OpenAPI resource
+func (r *OpenAPIReconciler) cacheOpenAPIDocument(resource *capabilitiesv1beta1.OpenAPI, data []byte, contentHash string) error {
+	cache := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      openapiDocumentCacheName(resource),
+			Namespace: resource.Namespace,
+			Annotations: map[string]string{
+				openapiDocumentCacheHashAnnotation: contentHash,
+			},
+		},
+		BinaryData: map[string][]byte{
+			openapiDocumentCacheDataKey: data,
+		},
+	}
+
+	err := r.SetOwnerReference(resource, cache)
+	if err != nil {
+		return err
+	}
+
+	return r.ReconcileResource(&corev1.ConfigMap{}, cache, openapiDocumentCacheMutator)
+}
+
+func openapiDocumentCacheMutator(existingObj, desiredObj common.KubernetesObject) (bool, error) {
+	existing, ok := existingObj.(*corev1.ConfigMap)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *corev1.ConfigMap", existingObj)
+	}
+	desired, ok := desiredObj.(*corev1.ConfigMap)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *corev1.ConfigMap", desiredObj)
+	}
+
+	updated := helper.EnsureObjectMeta(existing, desired)
+
+	if !bytes.Equal(existing.BinaryData[openapiDocumentCacheDataKey], desired.BinaryData[openapiDocumentCacheDataKey]) {
+		existing.BinaryData = desired.BinaryData
+		updated = true
+	}
+
+	return updated, nil
+}
@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -17,7 +16,10 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -118,10 +120,25 @@ func (p *OpenAPIProductReconciler) desired() (*capabilitiesv1beta1.Product, erro
 	}
 
 	// Deployment
-	product.Spec.Deployment = p.desiredDeployment()
+	deployment, err := p.desiredDeployment()
+	if err != nil {
+		return nil, err
+	}
+	product.Spec.Deployment = deployment
+
+	// Metrics
+	metrics, err := p.desiredMetrics()
+	if err != nil {
+		return nil, err
+	}
+	product.Spec.Metrics = metrics
 
 	// Methods
-	product.Spec.Methods = p.desiredMethods()
+	methods, err := p.desiredMethods()
+	if err != nil {
+		return nil, err
+	}
+	product.Spec.Methods = methods
 
 	// Mapping rules
 	mappingRules, err := p.desiredMappingRules()
@@ -130,6 +147,13 @@ func (p *OpenAPIProductReconciler) desired() (*capabilitiesv1beta1.Product, erro
 	}
 	product.Spec.MappingRules = mappingRules
 
+	// Application plans
+	applicationPlans, err := p.desiredApplicationPlans()
+	if err != nil {
+		return nil, err
+	}
+	product.Spec.ApplicationPlans = applicationPlans
+
 	// backend usages
 	// current implementation assumes same system name for backend and product
 	backendSystemName := p.desiredSystemName()
@@ -141,10 +165,11 @@ func (p *OpenAPIProductReconciler) desired() (*capabilitiesv1beta1.Product, erro
 
 	product.SetDefaults(p.Logger())
 
-	// internal validation
+	// metrics, plans and mapping rules read from the 3scale extensions
+	// may reference unknown metrics or methods
 	validationErrors := product.Validate()
 	if len(validationErrors) > 0 {
-		return nil, errors.New(validationErrors.ToAggregate().Error())
+		return nil, p.invalidOpenAPIError(validationErrors.ToAggregate().Error())
 	}
 
 	err = p.SetOwnerReference(p.openapiCR, product)
@@ -208,31 +233,36 @@ func (p *OpenAPIProductReconciler) desiredObjName() string {
 	return fmt.Sprintf("%s-%s", helper.K8sNameFromOpenAPITitle(p.openapiObj), string(p.openapiCR.UID))
 }
 
-func (p *OpenAPIProductReconciler) desiredDeployment() *capabilitiesv1beta1.ProductDeploymentSpec {
+func (p *OpenAPIProductReconciler) desiredDeployment() (*capabilitiesv1beta1.ProductDeploymentSpec, error) {
 	deployment := &capabilitiesv1beta1.ProductDeploymentSpec{}
 
+	authentication, err := p.desiredAuthentication()
+	if err != nil {
+		return nil, err
+	}
+
 	if p.openapiCR.Spec.ProductionPublicBaseURL != nil || p.openapiCR.Spec.StagingPublicBaseURL != nil {
 		// Self managed deployment
 		deployment.ApicastSelfManaged = &capabilitiesv1beta1.ApicastSelfManagedSpec{
 			StagingPublicBaseURL:    p.openapiCR.Spec.StagingPublicBaseURL,
 			ProductionPublicBaseURL: p.openapiCR.Spec.ProductionPublicBaseURL,
-			Authentication:          p.desiredAuthentication(),
+			Authentication:          authentication,
 		}
 	} else {
 		// Hosted deployment
 		deployment.ApicastHosted = &capabilitiesv1beta1.ApicastHostedSpec{
-			Authentication: p.desiredAuthentication(),
+			Authentication: authentication,
 		}
 	}
 
-	return deployment
+	return deployment, nil
 }
 
-func (p *OpenAPIProductReconciler) desiredAuthentication() *capabilitiesv1beta1.AuthenticationSpec {
+func (p *OpenAPIProductReconciler) desiredAuthentication() (*capabilitiesv1beta1.AuthenticationSpec, error) {
 	globalSecRequirements := helper.OpenAPIGlobalSecurityRequirements(p.openapiObj)
 	if len(globalSecRequirements) == 0 {
 		// if no security requirements are found, default to UserKey auth
-		return p.desiredUserKeyAuthentication(nil)
+		return p.desiredUserKeyAuthentication(nil), nil
 	}
 
 	// Only the first one is used
@@ -241,12 +271,115 @@ func (p *OpenAPIProductReconciler) desiredAuthentication() *capabilitiesv1beta1.
 	var authenticationSpec *capabilitiesv1beta1.AuthenticationSpec
 
 	switch secRequirementExtended.Value.Type {
-	// TODO types "oauth2", "openIdConnect"
 	case "apiKey":
 		authenticationSpec = p.desiredUserKeyAuthentication(secRequirementExtended)
+	case "oauth2", "openIdConnect":
+		oidcAuthentication, err := p.desiredOIDCAuthentication(secRequirementExtended)
+		if err != nil {
+			return nil, err
+		}
+		authenticationSpec = oidcAuthentication
 	}
 
-	return authenticationSpec
+	return authenticationSpec, nil
+}
+
+func (p *OpenAPIProductReconciler) desiredOIDCAuthentication(secReq *helper.ExtendedSecurityRequirement) (*capabilitiesv1beta1.AuthenticationSpec, error) {
+	issuerEndpoint, err := p.desiredOIDCIssuerEndpoint(secReq)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcSpec := &capabilitiesv1beta1.OIDCSpec{
+		IssuerType:         "keycloak",
+		IssuerEndpoint:     issuerEndpoint,
+		AuthenticationFlow: p.desiredOIDCAuthenticationFlow(secReq),
+		Security:           p.desiredPrivateAPISecurity(),
+	}
+
+	if oidcCRSpec := p.openapiCR.Spec.OIDC; oidcCRSpec != nil {
+		if oidcCRSpec.IssuerType != nil {
+			oidcSpec.IssuerType = *oidcCRSpec.IssuerType
+		}
+
+		if oidcCRSpec.AuthenticationFlow != nil {
+			oidcSpec.AuthenticationFlow = oidcCRSpec.AuthenticationFlow.DeepCopy()
+		}
+
+		oidcSpec.JwtClaimWithClientID = oidcCRSpec.JwtClaimWithClientID
+		oidcSpec.JwtClaimWithClientIDType = oidcCRSpec.JwtClaimWithClientIDType
+	}
+
+	return &capabilitiesv1beta1.AuthenticationSpec{OIDC: oidcSpec}, nil
+}
+
+// desiredOIDCAuthenticationFlow maps the flows of the oauth2 security
+// scheme to 3scale OIDC authorization flows
+func (p *OpenAPIProductReconciler) desiredOIDCAuthenticationFlow(secReq *helper.ExtendedSecurityRequirement) *capabilitiesv1beta1.OIDCAuthenticationFlowSpec {
+	flows := secReq.Value.Flows
+	if flows == nil {
+		return nil
+	}
+
+	return &capabilitiesv1beta1.OIDCAuthenticationFlowSpec{
+		StandardFlowEnabled:       flows.AuthorizationCode != nil,
+		ImplicitFlowEnabled:       flows.Implicit != nil,
+		ServiceAccountsEnabled:    flows.ClientCredentials != nil,
+		DirectAccessGrantsEnabled: flows.Password != nil,
+	}
+}
+
+// desiredOIDCIssuerEndpoint reads the issuer endpoint from the OpenAPI CR.
+// For the openIdConnect security scheme, when not set in the CR, the issuer
+// is derived from the openIdConnectUrl discovery endpoint
+func (p *OpenAPIProductReconciler) desiredOIDCIssuerEndpoint(secReq *helper.ExtendedSecurityRequirement) (string, error) {
+	fieldErrors := field.ErrorList{}
+	oidcFldPath := field.NewPath("spec").Child("oidc")
+
+	oidcCRSpec := p.openapiCR.Spec.OIDC
+	if oidcCRSpec != nil && oidcCRSpec.IssuerEndpoint != nil {
+		return *oidcCRSpec.IssuerEndpoint, nil
+	}
+
+	if oidcCRSpec != nil && oidcCRSpec.IssuerEndpointRef != nil {
+		issuerEndpointRefFldPath := oidcFldPath.Child("issuerEndpointRef")
+		secretKey := types.NamespacedName{Name: oidcCRSpec.IssuerEndpointRef.Name, Namespace: p.openapiCR.Namespace}
+		secret := &corev1.Secret{}
+		err := p.Client().Get(p.Context(), secretKey, secret)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				fieldErrors = append(fieldErrors, field.Invalid(issuerEndpointRefFldPath, oidcCRSpec.IssuerEndpointRef, "Secret not found"))
+				return "", &helper.SpecFieldError{
+					ErrorType:      helper.InvalidError,
+					FieldErrorList: fieldErrors,
+				}
+			}
+			return "", err
+		}
+
+		issuerEndpoint := strings.TrimSpace(string(secret.Data[capabilitiesv1beta1.OpenAPIOIDCIssuerEndpointSecretKey]))
+		if issuerEndpoint == "" {
+			fieldErrors = append(fieldErrors, field.Invalid(issuerEndpointRefFldPath, oidcCRSpec.IssuerEndpointRef,
+				fmt.Sprintf("Secret does not have the '%s' key", capabilitiesv1beta1.OpenAPIOIDCIssuerEndpointSecretKey)))
+			return "", &helper.SpecFieldError{
+				ErrorType:      helper.InvalidError,
+				FieldErrorList: fieldErrors,
+			}
+		}
+
+		return issuerEndpoint, nil
+	}
+
+	if secReq.Value.Type == "openIdConnect" && secReq.Value.OpenIdConnectUrl != "" {
+		return helper.OIDCIssuerFromDiscoveryURL(secReq.Value.OpenIdConnectUrl), nil
+	}
+
+	fieldErrors = append(fieldErrors, field.Required(oidcFldPath.Child("issuerEndpoint"),
+		fmt.Sprintf("OIDC issuer endpoint required for the %s security scheme", secReq.Value.Type)))
+	return "", &helper.SpecFieldError{
+		ErrorType:      helper.InvalidError,
+		FieldErrorList: fieldErrors,
+	}
 }
 
 func (p *OpenAPIProductReconciler) desiredUserKeyAuthentication(secReq *helper.ExtendedSecurityRequirement) *capabilitiesv1beta1.AuthenticationSpec {
@@ -277,10 +410,71 @@ func (p *OpenAPIProductReconciler) parseUserKeyCredentialsLoc(inField string) *s
 	}
 }
 
-func (p *OpenAPIProductReconciler) desiredMethods() map[string]capabilitiesv1beta1.MethodSpec {
+func (p *OpenAPIProductReconciler) desiredMetrics() (map[string]capabilitiesv1beta1.MetricSpec, error) {
+	metrics := make(map[string]capabilitiesv1beta1.MetricSpec)
+	_, err := helper.OpenAPIExtension(p.openapiObj.ExtensionProps, helper.OpenAPIMetricsExtension, &metrics)
+	if err != nil {
+		return nil, p.invalidOpenAPIError(err.Error())
+	}
+	return metrics, nil
+}
+
+func (p *OpenAPIProductReconciler) desiredApplicationPlans() (map[string]capabilitiesv1beta1.ApplicationPlanSpec, error) {
+	var applicationPlans map[string]capabilitiesv1beta1.ApplicationPlanSpec
+	_, err := helper.OpenAPIExtension(p.openapiObj.ExtensionProps, helper.OpenAPIPlansExtension, &applicationPlans)
+	if err != nil {
+		return nil, p.invalidOpenAPIError(err.Error())
+	}
+	return applicationPlans, nil
+}
+
+// openapiOperationExtensions holds the 3scale extensions of an operation
+type openapiOperationExtensions struct {
+	skip      bool
+	metric    *string
+	increment int
+}
+
+func (p *OpenAPIProductReconciler) operationExtensions(path, opVerb string, operation *openapi3.Operation) (*openapiOperationExtensions, error) {
+	opExtensions := &openapiOperationExtensions{increment: 1}
+
+	errorPrefix := fmt.Sprintf("operation %s %s", strings.ToUpper(opVerb), path)
+
+	_, err := helper.OpenAPIExtension(operation.ExtensionProps, helper.OpenAPISkipExtension, &opExtensions.skip)
+	if err != nil {
+		return nil, p.invalidOpenAPIError(fmt.Sprintf("%s: %s", errorPrefix, err.Error()))
+	}
+
+	_, err = helper.OpenAPIExtension(operation.ExtensionProps, helper.OpenAPIMetricExtension, &opExtensions.metric)
+	if err != nil {
+		return nil, p.invalidOpenAPIError(fmt.Sprintf("%s: %s", errorPrefix, err.Error()))
+	}
+
+	found, err := helper.OpenAPIExtension(operation.ExtensionProps, helper.OpenAPIIncrementExtension, &opExtensions.increment)
+	if err != nil {
+		return nil, p.invalidOpenAPIError(fmt.Sprintf("%s: %s", errorPrefix, err.Error()))
+	}
+	if found && opExtensions.increment < 1 {
+		return nil, p.invalidOpenAPIError(fmt.Sprintf("%s: %s extension must be greater than 0", errorPrefix, helper.OpenAPIIncrementExtension))
+	}
+
+	return opExtensions, nil
+}
+
+func (p *OpenAPIProductReconciler) desiredMethods() (map[string]capabilitiesv1beta1.MethodSpec, error) {
 	methods := make(map[string]capabilitiesv1beta1.MethodSpec)
 	for path, pathItem := range p.openapiObj.Paths {
 		for opVerb, operation := range pathItem.Operations() {
+			opExtensions, err := p.operationExtensions(path, opVerb, operation)
+			if err != nil {
+				return nil, err
+			}
+
+			// Operations increasing a metric do not have their own method
+			if opExtensions.skip || opExtensions.metric != nil {
+				continue
+			}
+
 			methodSystemName := helper.MethodSystemNameFromOpenAPIOperation(path, opVerb, operation)
 			methods[methodSystemName] = capabilitiesv1beta1.MethodSpec{
 				Name:        helper.MethodNameFromOpenAPIOperation(path, opVerb, operation),
@@ -288,7 +482,7 @@ func (p *OpenAPIProductReconciler) desiredMethods() map[string]capabilitiesv1bet
 			}
 		}
 	}
-	return methods
+	return methods, nil
 }
 
 func (p *OpenAPIProductReconciler) desiredMappingRules() ([]capabilitiesv1beta1.MappingRuleSpec, error) {
@@ -300,11 +494,25 @@ func (p *OpenAPIProductReconciler) desiredMappingRules() ([]capabilitiesv1beta1.
 		}
 
 		for opVerb, operation := range pathItem.Operations() {
+			opExtensions, err := p.operationExtensions(path, opVerb, operation)
+			if err != nil {
+				return nil, err
+			}
+
+			if opExtensions.skip {
+				continue
+			}
+
+			metricMethodRef := helper.MethodSystemNameFromOpenAPIOperation(path, opVerb, operation)
+			if opExtensions.metric != nil {
+				metricMethodRef = *opExtensions.metric
+			}
+
 			mappingRules = append(mappingRules, capabilitiesv1beta1.MappingRuleSpec{
 				HTTPMethod:      strings.ToUpper(opVerb),
 				Pattern:         desiredPattern,
-				MetricMethodRef: helper.MethodSystemNameFromOpenAPIOperation(path, opVerb, operation),
-				Increment:       1,
+				MetricMethodRef: metricMethodRef,
+				Increment:       opExtensions.increment,
 			})
 		}
 	}
@@ -369,3 +577,13 @@ func (p *OpenAPIProductReconciler) desiredPrivateAPISecurity() *capabilitiesv1be
 
 	return privateAPISec
 }
+
+func (p *OpenAPIProductReconciler) invalidOpenAPIError(message string) error {
+	fieldErrors := field.ErrorList{}
+	openapiRefFldPath := field.NewPath("spec").Child("openapiRef")
+	fieldErrors = append(fieldErrors, field.Invalid(openapiRefFldPath, p.openapiCR.Spec.OpenAPIRef, message))
+	return &helper.SpecFieldError{
+		ErrorType:      helper.InvalidError,
+		FieldErrorList: fieldErrors,
+	}
+}
@@ -21,7 +21,9 @@ type OpenAPIStatusReconciler struct {
 	providerAccountHost string
 	reconcileError      error
 	reconcileReady      bool
-	logger              logr.Logger
+	// openapiRefStatus is the status of the document fetched from the URL reference
+	openapiRefStatus *capabilitiesv1beta1.OpenAPIRefStatus
+	logger           logr.Logger
 }
 
 func NewOpenAPIStatusReconciler(b *reconcilers.BaseReconciler, resource *capabilitiesv1beta1.OpenAPI, providerAccountHost string, reconcileError error, reconcileReady bool) *OpenAPIStatusReconciler {
@@ -91,6 +93,20 @@ func (s *OpenAPIStatusReconciler) calculateStatus() (*capabilitiesv1beta1.OpenAP
 	}
 	newStatus.BackendResourceNames = backendResourceNames
 
+	activeDocResourceName, err := s.getManagedActiveDoc()
+	if err != nil {
+		return nil, err
+	}
+	newStatus.ActiveDocResourceName = activeDocResourceName
+
+	// The last fetched document status is kept when the URL is not fetched
+	if s.resource.Spec.OpenAPIRef.URL != nil {
+		newStatus.OpenAPIRef = s.resource.Status.OpenAPIRef
+		if s.openapiRefStatus != nil {
+			newStatus.OpenAPIRef = s.openapiRefStatus
+		}
+	}
+
 	newStatus.ObservedGeneration = s.resource.Status.ObservedGeneration
 
 	newStatus.Conditions = s.resource.Status.Conditions.Copy()
@@ -189,3 +205,26 @@ func (s *OpenAPIStatusReconciler) getManagedBackends() ([]corev1.LocalObjectRefe
 
 	return managedBackends, nil
 }
+
+func (s *OpenAPIStatusReconciler) getManagedActiveDoc() (*corev1.LocalObjectReference, error) {
+	listOps := []client.ListOption{
+		client.InNamespace(s.resource.Namespace),
+	}
+	list := &capabilitiesv1beta1.ActiveDocList{}
+	err := s.Client().List(s.Context(), list, listOps...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list activedocs: %w", err)
+	}
+
+	for _, activeDoc := range list.Items {
+		for _, ownerRef := range activeDoc.GetOwnerReferences() {
+			if ownerRef.UID == s.resource.UID {
+				return &corev1.LocalObjectReference{
+					Name: activeDoc.Name,
+				}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
 	corev1 "k8s.io/api/core/v1"
@@ -29,10 +30,13 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/handlers"
 	"github.com/3scale/3scale-operator/pkg/helper"
 	"github.com/3scale/3scale-operator/pkg/reconcilers"
 	"github.com/3scale/3scale-operator/version"
@@ -41,6 +45,10 @@ import (
 // ProductReconciler reconciles a Product object
 type ProductReconciler struct {
 	*reconcilers.BaseReconciler
+
+	// ResyncPeriod is the default period to synchronize products again with 3scale.
+	// Zero disables periodic synchronization.
+	ResyncPeriod time.Duration
 }
 
 const productFinalizer = "product.capabilities.3scale.net/finalizer"
@@ -182,7 +190,16 @@ func (r *ProductReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	}
 
 	reqLogger.Info("END", "error", reconcileErr)
-	return ctrl.Result{}, reconcileErr
+	return ctrl.Result{RequeueAfter: r.resyncPeriod(product)}, reconcileErr
+}
+
+// resyncPeriod returns the period to synchronize the product again with 3scale.
+// The product spec takes precedence over the operator wide period.
+func (r *ProductReconciler) resyncPeriod(product *capabilitiesv1beta1.Product) time.Duration {
+	if product.Spec.ResyncPeriod != nil {
+		return product.Spec.ResyncPeriod.Duration
+	}
+	return r.ResyncPeriod
 }
 
 func (r *ProductReconciler) reconcile(productResource *capabilitiesv1beta1.Product) (*ProductStatusReconciler, error) {
@@ -207,7 +224,16 @@ func (r *ProductReconciler) reconcile(productResource *capabilitiesv1beta1.Produ
 		return statusReconciler, err
 	}
 
-	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	var (
+		threescaleAPIClient *threescaleapi.ThreeScaleClient
+		changeTracker       *controllerhelper.ChangeTracker
+		changePlanner       *controllerhelper.ChangePlanner
+	)
+	if productResource.Spec.DryRun {
+		threescaleAPIClient, changePlanner, err = controllerhelper.PortaClientWithChangePlanner(providerAccount)
+	} else {
+		threescaleAPIClient, changeTracker, err = controllerhelper.PortaClientWithChangeTracker(providerAccount)
+	}
 	if err != nil {
 		statusReconciler := NewProductStatusReconciler(r.BaseReconciler, productResource, nil, providerAccount.AdminURLStr, err)
 		return statusReconciler, err
@@ -219,9 +245,12 @@ func (r *ProductReconciler) reconcile(productResource *capabilitiesv1beta1.Produ
 		return statusReconciler, err
 	}
 
-	reconciler := NewProductThreescaleReconciler(r.BaseReconciler, productResource, threescaleAPIClient, backendRemoteIndex)
+	reconciler := NewProductThreescaleReconciler(r.BaseReconciler, productResource, threescaleAPIClient, backendRemoteIndex, changeTracker, changePlanner)
 	productEntity, err := reconciler.Reconcile()
 	statusReconciler := NewProductStatusReconciler(r.BaseReconciler, productResource, productEntity, providerAccount.AdminURLStr, err)
+	statusReconciler.modifiedSections = reconciler.ModifiedSections()
+	statusReconciler.plannedChanges = reconciler.PlannedChanges()
+	statusReconciler.unplannedSections = reconciler.UnplannedSections()
 	return statusReconciler, err
 }
 
@@ -248,7 +277,7 @@ func (r *ProductReconciler) checkExternalRefs(resource *capabilitiesv1beta1.Prod
 		return fmt.Errorf("checking backend usage references: %w", err)
 	}
 
-	backendUsageErrors := r.checkBackendUsages(resource, backendList)
+	backendUsageErrors := checkBackendUsages(resource, backendList)
 	errors = append(errors, backendUsageErrors...)
 
 	backendUsageList := computeBackendUsageList(backendList, resource.Spec.BackendUsages)
@@ -269,7 +298,7 @@ func (r *ProductReconciler) checkExternalRefs(resource *capabilitiesv1beta1.Prod
 	}
 }
 
-func (r *ProductReconciler) checkBackendUsages(resource *capabilitiesv1beta1.Product, backendList []capabilitiesv1beta1.Backend) field.ErrorList {
+func checkBackendUsages(resource *capabilitiesv1beta1.Product, backendList []capabilitiesv1beta1.Backend) field.ErrorList {
 	errors := field.ErrorList{}
 
 	specFldPath := field.NewPath("spec")
@@ -385,6 +414,16 @@ func computeBackendUsageList(list []capabilitiesv1beta1.Backend, backendUsageMap
 func (r *ProductReconciler) removeProductFrom3scale(product *capabilitiesv1beta1.Product) error {
 	logger := r.Logger().WithValues("product", client.ObjectKey{Name: product.Name, Namespace: product.Namespace})
 
+	if capabilitiesv1beta1.OrphanOnDeletion(product.Spec.DeletionPolicy) {
+		logger.Info("product not deleted from 3scale, deletion policy is Orphan")
+		return nil
+	}
+
+	if product.Spec.DryRun {
+		logger.Info("product not deleted from 3scale, dry run enabled")
+		return nil
+	}
+
 	// Attempt to remove product only if product.Status.ID is present
 	if product.Status.ID == nil {
 		logger.Info("could not remove product because ID is missing in status")
@@ -414,7 +453,27 @@ func (r *ProductReconciler) removeProductFrom3scale(product *capabilitiesv1beta1
 }
 
 func (r *ProductReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	err := indexField(mgr, &capabilitiesv1beta1.Product{}, secretRefIndexField, productSecretRefIndexer)
+	if err != nil {
+		return err
+	}
+
+	err = indexField(mgr, &capabilitiesv1beta1.Product{}, backendUsageIndexField, productBackendUsageIndexer)
+	if err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&capabilitiesv1beta1.Product{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &capabilitiesv1beta1.Backend{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: &handlers.IndexedFieldEventMapper{
+				K8sClient:  r.Client(),
+				Logger:     r.Logger().WithName("BackendUsageHandler"),
+				List:       &capabilitiesv1beta1.ProductList{},
+				IndexField: backendUsageIndexField,
+				IndexValue: backendUsageIndexValue,
+			},
+		})
+
+	return secretRefWatch(r.BaseReconciler, builder, &capabilitiesv1beta1.ProductList{}).Complete(r)
 }
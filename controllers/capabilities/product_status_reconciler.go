@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"fmt"
+	"strings"
 
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	"github.com/3scale/3scale-operator/pkg/common"
@@ -21,6 +22,9 @@ type ProductStatusReconciler struct {
 	entity              *controllerhelper.ProductEntity
 	providerAccountHost string
 	syncError           error
+	modifiedSections    []string
+	plannedChanges      []capabilitiesv1beta1.PlannedChange
+	unplannedSections   []string
 	logger              logr.Logger
 }
 
@@ -73,20 +77,40 @@ func (s *ProductStatusReconciler) Reconcile() (reconcile.Result, error) {
 
 func (s *ProductStatusReconciler) calculateStatus() *capabilitiesv1beta1.ProductStatus {
 	newStatus := &capabilitiesv1beta1.ProductStatus{}
-	if s.entity != nil {
+	// products planned for creation have no ID
+	if s.entity != nil && s.entity.ID() != 0 {
 		tmpID := s.entity.ID()
 		newStatus.ID = &tmpID
 	}
 
 	newStatus.ProviderAccountHost = s.providerAccountHost
 
+	// Keep the last known public base URLs when the proxy is not available
+	newStatus.StagingPublicBaseURL = s.resource.Status.StagingPublicBaseURL
+	newStatus.ProductionPublicBaseURL = s.resource.Status.ProductionPublicBaseURL
+	if newStatus.ID != nil {
+		proxy, err := s.entity.Proxy()
+		if err != nil {
+			s.logger.Info("Failed to read product proxy public base URLs", "error", err.Error())
+		} else {
+			newStatus.StagingPublicBaseURL = proxy.Element.SandboxEndpoint
+			newStatus.ProductionPublicBaseURL = proxy.Element.Endpoint
+		}
+	}
+
 	newStatus.ObservedGeneration = s.resource.Status.ObservedGeneration
 
+	if len(s.plannedChanges) > 0 {
+		newStatus.PlannedChanges = s.plannedChanges
+	}
+
 	newStatus.Conditions = s.resource.Status.Conditions.Copy()
 	newStatus.Conditions.SetCondition(s.syncCondition())
 	newStatus.Conditions.SetCondition(s.orphanCondition())
 	newStatus.Conditions.SetCondition(s.invalidCondition())
 	newStatus.Conditions.SetCondition(s.failedCondition())
+	newStatus.Conditions.SetCondition(s.driftedCondition())
+	newStatus.Conditions.SetCondition(s.plannedCondition())
 
 	return newStatus
 }
@@ -97,7 +121,8 @@ func (s *ProductStatusReconciler) syncCondition() common.Condition {
 		Status: corev1.ConditionFalse,
 	}
 
-	if s.syncError == nil {
+	// nothing is synchronized when planning
+	if s.syncError == nil && !s.resource.Spec.DryRun {
 		condition.Status = corev1.ConditionTrue
 	}
 
@@ -146,3 +171,44 @@ func (s *ProductStatusReconciler) failedCondition() common.Condition {
 
 	return condition
 }
+
+func (s *ProductStatusReconciler) driftedCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.ProductDriftedConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	// Modifications are expected when the spec changed or the previous synchronization did not complete.
+	// Otherwise, the product was changed in 3scale outside the operator.
+	resyncOfSyncedSpec := s.resource.Generation == s.resource.Status.ObservedGeneration &&
+		s.resource.Status.Conditions.IsTrueFor(capabilitiesv1beta1.ProductSyncedConditionType)
+
+	if s.syncError == nil && resyncOfSyncedSpec && len(s.modifiedSections) > 0 {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = fmt.Sprintf("3scale product differed from the spec and was corrected. Sections: %s",
+			strings.Join(s.modifiedSections, ", "))
+	}
+
+	return condition
+}
+
+func (s *ProductStatusReconciler) plannedCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.ProductPlannedConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if !s.resource.Spec.DryRun || s.syncError != nil {
+		return condition
+	}
+
+	if len(s.unplannedSections) > 0 {
+		condition.Message = fmt.Sprintf("Planned changes: %d. Sections not planned: %s",
+			len(s.plannedChanges), strings.Join(s.unplannedSections, "; "))
+		return condition
+	}
+
+	condition.Status = corev1.ConditionTrue
+	condition.Message = fmt.Sprintf("Planned changes: %d", len(s.plannedChanges))
+	return condition
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestProductStatusReconcilerDriftedCondition(t *testing.T) {
+	tests := []struct {
+		name             string
+		generation       int64
+		syncedCondition  v1.ConditionStatus
+		modifiedSections []string
+		syncError        error
+		want             v1.ConditionStatus
+	}{
+		{"resync with modifications", 1, v1.ConditionTrue, []string{"methods", "policies"}, nil, v1.ConditionTrue},
+		{"resync without modifications", 1, v1.ConditionTrue, []string{}, nil, v1.ConditionFalse},
+		{"spec changed", 2, v1.ConditionTrue, []string{"methods"}, nil, v1.ConditionFalse},
+		{"previous sync failed", 1, v1.ConditionFalse, []string{"methods"}, nil, v1.ConditionFalse},
+		{"sync error", 1, v1.ConditionTrue, []string{"methods"}, errors.New("some error"), v1.ConditionFalse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(subT *testing.T) {
+			product := getProductCR()
+			product.Generation = tt.generation
+			product.Status.Conditions = common.Conditions{
+				{Type: capabilitiesv1beta1.ProductSyncedConditionType, Status: tt.syncedCondition},
+			}
+
+			s := NewProductStatusReconciler(getBaseReconciler(), product, nil, "", tt.syncError)
+			s.modifiedSections = tt.modifiedSections
+
+			condition := s.calculateStatus().Conditions.GetCondition(capabilitiesv1beta1.ProductDriftedConditionType)
+			if condition == nil {
+				subT.Fatal("drifted condition not found")
+			}
+			if condition.Status != tt.want {
+				subT.Errorf("unexpected drifted condition status: got %s, want %s", condition.Status, tt.want)
+			}
+			if tt.want == v1.ConditionTrue && condition.Message != "3scale product differed from the spec and was corrected. Sections: methods, policies" {
+				subT.Errorf("unexpected drifted condition message: %s", condition.Message)
+			}
+		})
+	}
+}
+
+func TestProductStatusReconcilerPlannedCondition(t *testing.T) {
+	plannedChanges := []capabilitiesv1beta1.PlannedChange{
+		{Section: "methods", Action: capabilitiesv1beta1.PlannedChangeActionDelete, Path: "/admin/api/services/1/metrics/2/methods/3.json"},
+	}
+
+	tests := []struct {
+		name              string
+		dryRun            bool
+		unplannedSections []string
+		syncError         error
+		wantPlanned       v1.ConditionStatus
+		wantSynced        v1.ConditionStatus
+		wantMessage       string
+	}{
+		{"dry run", true, nil, nil, v1.ConditionTrue, v1.ConditionFalse, "Planned changes: 1"},
+		{"dry run with unplanned sections", true, []string{"mappingRules: metric not found"}, nil, v1.ConditionFalse, v1.ConditionFalse, "Planned changes: 1. Sections not planned: mappingRules: metric not found"},
+		{"dry run error", true, nil, errors.New("some error"), v1.ConditionFalse, v1.ConditionFalse, ""},
+		{"no dry run", false, nil, nil, v1.ConditionFalse, v1.ConditionTrue, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(subT *testing.T) {
+			product := getProductCR()
+			product.Spec.DryRun = tt.dryRun
+
+			s := NewProductStatusReconciler(getBaseReconciler(), product, nil, "", tt.syncError)
+			s.plannedChanges = plannedChanges
+			s.unplannedSections = tt.unplannedSections
+
+			status := s.calculateStatus()
+			condition := status.Conditions.GetCondition(capabilitiesv1beta1.ProductPlannedConditionType)
+			if condition == nil {
+				subT.Fatal("planned condition not found")
+			}
+			if condition.Status != tt.wantPlanned || condition.Message != tt.wantMessage {
+				subT.Errorf("unexpected planned condition: got %s %q, want %s %q", condition.Status, condition.Message, tt.wantPlanned, tt.wantMessage)
+			}
+			if synced := status.Conditions.GetCondition(capabilitiesv1beta1.ProductSyncedConditionType); synced.Status != tt.wantSynced {
+				subT.Errorf("unexpected synced condition status: got %s, want %s", synced.Status, tt.wantSynced)
+			}
+		})
+	}
+}
@@ -18,42 +18,44 @@ type ProductThreescaleReconciler struct {
 	productEntity       *controllerhelper.ProductEntity
 	backendRemoteIndex  *controllerhelper.BackendAPIRemoteIndex
 	threescaleAPIClient *threescaleapi.ThreeScaleClient
+	driftTracker        *driftTracker
 	logger              logr.Logger
 }
 
-func NewProductThreescaleReconciler(b *reconcilers.BaseReconciler, resource *capabilitiesv1beta1.Product, threescaleAPIClient *threescaleapi.ThreeScaleClient, backendRemoteIndex *controllerhelper.BackendAPIRemoteIndex) *ProductThreescaleReconciler {
+// NewProductThreescaleReconciler returns a ProductThreescaleReconciler.
+// The changeTracker, when not nil, is expected to track the requests of threescaleAPIClient
+// and it is used to find the product sections that had to be modified.
+// The changePlanner, when not nil, is expected to be the transport of threescaleAPIClient
+// and it is used to plan the product changes without applying them.
+func NewProductThreescaleReconciler(b *reconcilers.BaseReconciler, resource *capabilitiesv1beta1.Product, threescaleAPIClient *threescaleapi.ThreeScaleClient, backendRemoteIndex *controllerhelper.BackendAPIRemoteIndex, changeTracker *controllerhelper.ChangeTracker, changePlanner *controllerhelper.ChangePlanner) *ProductThreescaleReconciler {
 	return &ProductThreescaleReconciler{
 		BaseReconciler:      b,
 		resource:            resource,
 		threescaleAPIClient: threescaleAPIClient,
 		backendRemoteIndex:  backendRemoteIndex,
+		driftTracker:        newDriftTracker(changeTracker, changePlanner),
 		logger:              b.Logger().WithValues("3scale Reconciler", resource.Name),
 	}
 }
 
 func (t *ProductThreescaleReconciler) Reconcile() (*controllerhelper.ProductEntity, error) {
-	productEntity, err := t.reconcile3scaleProduct()
-	if err != nil {
-		return nil, err
-	}
-	t.productEntity = productEntity
-
 	taskRunner := helper.NewTaskRunner(nil, t.logger)
-	taskRunner.AddTask("SyncProduct", t.syncProduct)
-	taskRunner.AddTask("SyncBackendUsage", t.syncBackendUsage)
-	taskRunner.AddTask("SyncProxy", t.syncProxy)
+	taskRunner.AddTask("Reconcile3scaleProduct", t.driftTracker.trackRequired("product", t.reconcile3scaleProduct))
+	taskRunner.AddTask("SyncProduct", t.driftTracker.track("product", t.syncProduct))
+	taskRunner.AddTask("SyncBackendUsage", t.driftTracker.track("backendUsages", t.syncBackendUsage))
+	taskRunner.AddTask("SyncProxy", t.driftTracker.track("proxy", t.syncProxy))
 	// First methods and metrics, then mapping rules.
 	// Mapping rules reference methods and metrics.
 	// When a method/metric is deleted,
 	// any orphan mapping rule will be deleted automatically by 3scale
-	taskRunner.AddTask("SyncMethods", t.syncMethods)
-	taskRunner.AddTask("SyncMetrics", t.syncMetrics)
-	taskRunner.AddTask("SyncMappingRules", t.syncMappingRules)
-	taskRunner.AddTask("SyncApplicationPlans", t.syncApplicationPlans)
-	taskRunner.AddTask("SyncPolicies", t.syncPolicies)
-	taskRunner.AddTask("SyncOIDCConfiguration", t.syncOIDCConfiguration)
-
-	err = taskRunner.Run()
+	taskRunner.AddTask("SyncMethods", t.driftTracker.track("methods", t.syncMethods))
+	taskRunner.AddTask("SyncMetrics", t.driftTracker.track("metrics", t.syncMetrics))
+	taskRunner.AddTask("SyncMappingRules", t.driftTracker.track("mappingRules", t.syncMappingRules))
+	taskRunner.AddTask("SyncApplicationPlans", t.driftTracker.track("applicationPlans", t.syncApplicationPlans))
+	taskRunner.AddTask("SyncPolicies", t.driftTracker.track("policies", t.syncPolicies))
+	taskRunner.AddTask("SyncOIDCConfiguration", t.driftTracker.track("proxy", t.syncOIDCConfiguration))
+
+	err := taskRunner.Run()
 	if err != nil {
 		return nil, err
 	}
@@ -61,10 +63,25 @@ func (t *ProductThreescaleReconciler) Reconcile() (*controllerhelper.ProductEnti
 	return t.productEntity, nil
 }
 
-func (t *ProductThreescaleReconciler) reconcile3scaleProduct() (*controllerhelper.ProductEntity, error) {
+// ModifiedSections returns the product sections modified in 3scale by the last Reconcile call
+func (t *ProductThreescaleReconciler) ModifiedSections() []string {
+	return t.driftTracker.Sections()
+}
+
+// PlannedChanges returns the product changes planned by the last Reconcile call
+func (t *ProductThreescaleReconciler) PlannedChanges() []capabilitiesv1beta1.PlannedChange {
+	return t.driftTracker.PlannedChanges()
+}
+
+// UnplannedSections returns the product sections that could not be planned by the last Reconcile call
+func (t *ProductThreescaleReconciler) UnplannedSections() []string {
+	return t.driftTracker.UnplannedSections()
+}
+
+func (t *ProductThreescaleReconciler) reconcile3scaleProduct(_ interface{}) error {
 	productList, err := t.threescaleAPIClient.ListProducts()
 	if err != nil {
-		return nil, fmt.Errorf("reconcile3scaleProduct product [%s]: %w", t.resource.Spec.SystemName, err)
+		return fmt.Errorf("reconcile3scaleProduct product [%s]: %w", t.resource.Spec.SystemName, err)
 	}
 
 	// Find product in the list by system name
@@ -88,11 +105,12 @@ func (t *ProductThreescaleReconciler) reconcile3scaleProduct() (*controllerhelpe
 		}
 		product, err := t.threescaleAPIClient.CreateProduct(t.resource.Spec.Name, params)
 		if err != nil {
-			return nil, fmt.Errorf("reconcile3scaleProduct product [%s]: %w", t.resource.Spec.SystemName, err)
+			return fmt.Errorf("reconcile3scaleProduct product [%s]: %w", t.resource.Spec.SystemName, err)
 		}
 
 		productObj = product
 	}
 
-	return controllerhelper.NewProductEntity(productObj, t.threescaleAPIClient, t.logger), nil
+	t.productEntity = controllerhelper.NewProductEntity(productObj, t.threescaleAPIClient, t.logger)
+	return nil
 }
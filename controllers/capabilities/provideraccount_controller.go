@@ -0,0 +1,171 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/handlers"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+	"github.com/3scale/3scale-operator/version"
+)
+
+// providerAccountDefaultCheckPeriod is the period to check the provider accounts again when not set in the CR
+const providerAccountDefaultCheckPeriod = 10 * time.Minute
+
+// ProviderAccountReconciler reconciles a ProviderAccount object
+type ProviderAccountReconciler struct {
+	*reconcilers.BaseReconciler
+}
+
+// blank assignment to verify that ProviderAccountReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ProviderAccountReconciler{}
+
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=provideraccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=provideraccounts/status,verbs=get;update;patch
+
+func (r *ProviderAccountReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	_ = context.Background()
+	reqLogger := r.Logger().WithValues("provideraccount", req.NamespacedName)
+	reqLogger.Info("Reconcile ProviderAccount", "Operator version", version.Version)
+
+	// Fetch the instance
+	providerAccountCR := &capabilitiesv1beta1.ProviderAccount{}
+	err := r.Client().Get(context.TODO(), req.NamespacedName, providerAccountCR)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("resource not found. Ignoring since object must have been deleted")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	if reqLogger.V(1).Enabled() {
+		jsonData, err := json.MarshalIndent(providerAccountCR, "", "  ")
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		reqLogger.V(1).Info(string(jsonData))
+	}
+
+	// Nothing to clean up in 3scale
+	if providerAccountCR.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	statusReconciler, reconcileErr := r.reconcileSpec(providerAccountCR, reqLogger)
+	statusResult, statusUpdateErr := statusReconciler.Reconcile()
+	if statusUpdateErr != nil {
+		if reconcileErr != nil {
+			return ctrl.Result{}, fmt.Errorf("Failed to check provider account: %v. Failed to update provider account status: %w", reconcileErr, statusUpdateErr)
+		}
+
+		return ctrl.Result{}, fmt.Errorf("Failed to update provider account status: %w", statusUpdateErr)
+	}
+
+	if statusResult.Requeue {
+		return statusResult, nil
+	}
+
+	if reconcileErr != nil {
+		if helper.IsInvalidSpecError(reconcileErr) {
+			// On Validation error, no need to retry as spec is not valid and needs to be changed
+			reqLogger.Info("ERROR", "spec validation error", reconcileErr)
+			r.EventRecorder().Eventf(providerAccountCR, corev1.EventTypeWarning, "Invalid ProviderAccount Spec", "%v", reconcileErr)
+			return ctrl.Result{}, nil
+		}
+
+		if controllerhelper.IsProviderAccountTokenError(reconcileErr) {
+			// The token is checked again on the next period, it can be granted permissions meanwhile
+			reqLogger.Info("ERROR", "token error", reconcileErr)
+			r.EventRecorder().Eventf(providerAccountCR, corev1.EventTypeWarning, "Invalid ProviderAccount Token", "%v", reconcileErr)
+			return ctrl.Result{RequeueAfter: r.checkPeriod(providerAccountCR)}, nil
+		}
+
+		reqLogger.Error(reconcileErr, "Failed to reconcile")
+		r.EventRecorder().Eventf(providerAccountCR, corev1.EventTypeWarning, "ReconcileError", "%v", reconcileErr)
+		return ctrl.Result{}, reconcileErr
+	}
+
+	return ctrl.Result{RequeueAfter: r.checkPeriod(providerAccountCR)}, nil
+}
+
+func (r *ProviderAccountReconciler) checkPeriod(providerAccountCR *capabilitiesv1beta1.ProviderAccount) time.Duration {
+	if providerAccountCR.Spec.CheckPeriod != nil {
+		return providerAccountCR.Spec.CheckPeriod.Duration
+	}
+	return providerAccountDefaultCheckPeriod
+}
+
+func (r *ProviderAccountReconciler) reconcileSpec(providerAccountCR *capabilitiesv1beta1.ProviderAccount, logger logr.Logger) (*ProviderAccountStatusReconciler, error) {
+	if fieldErrors := providerAccountCR.Validate(); len(fieldErrors) > 0 {
+		err := &helper.SpecFieldError{
+			ErrorType:      helper.InvalidError,
+			FieldErrorList: fieldErrors,
+		}
+		return NewProviderAccountStatusReconciler(r.BaseReconciler, providerAccountCR, "", err), err
+	}
+
+	providerAccount, err := controllerhelper.ProviderAccountFromCR(r.Client(), providerAccountCR)
+	if err != nil {
+		return NewProviderAccountStatusReconciler(r.BaseReconciler, providerAccountCR, "", err), err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		return NewProviderAccountStatusReconciler(r.BaseReconciler, providerAccountCR, providerAccount.AdminURLStr, err), err
+	}
+
+	logger.V(1).Info("checking provider account", "adminURL", providerAccount.AdminURLStr)
+	err = controllerhelper.CheckProviderAccount(threescaleAPIClient)
+	return NewProviderAccountStatusReconciler(r.BaseReconciler, providerAccountCR, providerAccount.AdminURLStr, err), err
+}
+
+func (r *ProviderAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := indexField(mgr, &capabilitiesv1beta1.ProviderAccount{}, secretRefIndexField, providerAccountSecretRefIndexer)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&capabilitiesv1beta1.ProviderAccount{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: &handlers.IndexedFieldEventMapper{
+				K8sClient:  r.Client(),
+				Logger:     r.Logger().WithName("SecretRefHandler"),
+				List:       &capabilitiesv1beta1.ProviderAccountList{},
+				IndexField: secretRefIndexField,
+				IndexValue: handlers.NamespacedNameIndexValue,
+			},
+		}).
+		Complete(r)
+}
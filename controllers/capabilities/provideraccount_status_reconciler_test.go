@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestProviderAccountStatusReconcilerConditions(t *testing.T) {
+	tests := []struct {
+		name        string
+		checkError  error
+		wantReady   v1.ConditionStatus
+		wantInvalid v1.ConditionStatus
+	}{
+		{"check succeeded", nil, v1.ConditionTrue, v1.ConditionFalse},
+		{"admin portal unreachable", errors.New("connection refused"), v1.ConditionFalse, v1.ConditionFalse},
+		{"token rejected", &controllerhelper.ProviderAccountTokenError{Err: errors.New("forbidden")}, v1.ConditionFalse, v1.ConditionTrue},
+		{"invalid spec", &helper.SpecFieldError{
+			ErrorType:      helper.InvalidError,
+			FieldErrorList: field.ErrorList{field.Required(field.NewPath("spec", "tokenSecretRef", "name"), "token secret name is required.")},
+		}, v1.ConditionFalse, v1.ConditionTrue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(subT *testing.T) {
+			providerAccountCR := &capabilitiesv1beta1.ProviderAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: "provideraccount", Namespace: "test"},
+			}
+
+			s := NewProviderAccountStatusReconciler(getBaseReconciler(), providerAccountCR, "https://3scale-admin.example.com", tt.checkError)
+			status := s.calculateStatus()
+
+			expected := map[common.ConditionType]v1.ConditionStatus{
+				capabilitiesv1beta1.ProviderAccountReadyConditionType:   tt.wantReady,
+				capabilitiesv1beta1.ProviderAccountInvalidConditionType: tt.wantInvalid,
+			}
+			for conditionType, want := range expected {
+				condition := status.Conditions.GetCondition(conditionType)
+				if condition == nil {
+					subT.Fatalf("%s condition not found", conditionType)
+				}
+				if condition.Status != want {
+					subT.Errorf("unexpected %s condition status: got %s, want %s", conditionType, condition.Status, want)
+				}
+			}
+
+			if status.ProviderAccountHost != "https://3scale-admin.example.com" {
+				subT.Errorf("unexpected provider account host: %s", status.ProviderAccountHost)
+			}
+		})
+	}
+}
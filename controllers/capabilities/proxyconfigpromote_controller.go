@@ -22,10 +22,12 @@ import (
 	"fmt"
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
 	"github.com/3scale/3scale-operator/pkg/reconcilers"
 	"github.com/3scale/3scale-operator/version"
 	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -66,6 +68,18 @@ func (r *ProxyConfigPromoteReconciler) Reconcile(req ctrl.Request) (ctrl.Result,
 		}
 		reqLogger.V(1).Info(string(jsonData))
 	}
+	if validationErr := r.validateSpec(proxyConfigPromote); validationErr != nil {
+		// On Validation error, no need to retry as spec is not valid and needs to be changed
+		reqLogger.Info("ERROR", "spec validation error", validationErr)
+		r.EventRecorder().Eventf(proxyConfigPromote, corev1.EventTypeWarning, "Invalid ProxyConfigPromote Spec", "%v", validationErr)
+		statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromote, "Failed", proxyConfigPromote.Status.ProductId, 0, 0, validationErr)
+		statusResult, statusUpdateErr := statusReconciler.Reconcile()
+		if statusUpdateErr != nil {
+			return ctrl.Result{}, fmt.Errorf("Failed to update proxyConfigPromote status: %w", statusUpdateErr)
+		}
+		return statusResult, nil
+	}
+
 	// get product
 	product := &capabilitiesv1beta1.Product{}
 	projectMeta := types.NamespacedName{
@@ -124,6 +138,7 @@ func (r *ProxyConfigPromoteReconciler) proxyConfigPromoteReconciler(proxyConfigP
 
 	var latestStagingVersion int
 	var latestProductionVersion int
+	var previousProductionVersion int
 	//get product
 
 	if product.Status.Conditions.IsTrueFor(capabilitiesv1beta1.ProductSyncedConditionType) {
@@ -131,6 +146,10 @@ func (r *ProxyConfigPromoteReconciler) proxyConfigPromoteReconciler(proxyConfigP
 		productIDInt64 := *productID
 		productIDStr := strconv.Itoa(int(productIDInt64))
 
+		if proxyConfigPromote.TargetsVersion() {
+			return r.promoteVersionToProduction(proxyConfigPromote, reqLogger, threescaleAPIClient, productIDStr)
+		}
+
 		if proxyConfigPromote.Spec.Production == nil {
 			// check the existing config to get the lastUpdate time
 			_, err := threescaleAPIClient.DeployProductProxy(*product.Status.ID)
@@ -184,6 +203,7 @@ func (r *ProxyConfigPromoteReconciler) proxyConfigPromoteReconciler(proxyConfigP
 				}
 			}
 			latestProductionVersion = productionElement.ProxyConfig.Version
+			previousProductionVersion = latestProductionVersion
 
 			_, err = threescaleAPIClient.PromoteProxyConfig(productIDStr, "sandbox", strconv.Itoa(stageElement.ProxyConfig.Version), "production")
 			if err != nil {
@@ -195,6 +215,7 @@ func (r *ProxyConfigPromoteReconciler) proxyConfigPromoteReconciler(proxyConfigP
 
 		}
 		statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromote, "Completed", productIDStr, latestProductionVersion, latestStagingVersion, nil)
+		statusReconciler.previousProductionVersion = previousProductionVersion
 		return statusReconciler, nil
 	} else {
 		err := fmt.Errorf("Proudct CR is not ready")
@@ -203,6 +224,118 @@ func (r *ProxyConfigPromoteReconciler) proxyConfigPromoteReconciler(proxyConfigP
 	}
 }
 
+// promoteVersionToProduction promotes a specific staging version to production.
+// The current product configuration is not deployed to staging, so older
+// versions can be promoted to roll back production
+func (r *ProxyConfigPromoteReconciler) promoteVersionToProduction(proxyConfigPromote *capabilitiesv1beta1.ProxyConfigPromote, reqLogger logr.Logger, threescaleAPIClient *threescaleapi.ThreeScaleClient, productIDStr string) (*ProxyConfigPromoteStatusReconciler, error) {
+	stageElement, err := threescaleAPIClient.GetLatestProxyConfig(productIDStr, "sandbox")
+	if err != nil {
+		statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromote, "Failed", productIDStr, 0, 0, err)
+		return statusReconciler, err
+	}
+	latestStagingVersion := stageElement.ProxyConfig.Version
+
+	currentProductionVersion := 0
+	productionElement, err := threescaleAPIClient.GetLatestProxyConfig(productIDStr, "production")
+	if err != nil {
+		if !threescaleapi.IsNotFound(err) {
+			statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromote, "Failed", productIDStr, 0, latestStagingVersion, err)
+			return statusReconciler, err
+		}
+	} else {
+		currentProductionVersion = productionElement.ProxyConfig.Version
+	}
+
+	// The target version and the production version it replaces are recorded
+	// before promoting. A retry after a failed status update promotes the
+	// recorded version instead of computing the target again from the already
+	// promoted production version
+	if !targetProductionVersionRecorded(proxyConfigPromote) {
+		targetVersion, err := r.targetProductionVersion(proxyConfigPromote, threescaleAPIClient, productIDStr, currentProductionVersion)
+		if err != nil {
+			statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromote, "Failed", productIDStr, currentProductionVersion, latestStagingVersion, err)
+			return statusReconciler, err
+		}
+
+		proxyConfigPromote.Status.TargetProductionVersion = targetVersion
+		proxyConfigPromote.Status.PreviousProductionVersion = currentProductionVersion
+		err = r.UpdateResourceStatus(proxyConfigPromote)
+		if err != nil {
+			statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromote, "Failed", productIDStr, currentProductionVersion, latestStagingVersion, err)
+			statusReconciler.previousProductionVersion = currentProductionVersion
+			return statusReconciler, err
+		}
+	}
+	targetVersion := proxyConfigPromote.Status.TargetProductionVersion
+	previousProductionVersion := proxyConfigPromote.Status.PreviousProductionVersion
+
+	if targetVersion != currentProductionVersion {
+		reqLogger.Info("Promoting proxy config version to production", "version", targetVersion, "current production version", currentProductionVersion)
+		_, err = threescaleAPIClient.PromoteProxyConfig(productIDStr, "sandbox", strconv.Itoa(targetVersion), "production")
+		if err != nil {
+			statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromote, "Failed", productIDStr, currentProductionVersion, latestStagingVersion, err)
+			statusReconciler.previousProductionVersion = previousProductionVersion
+			return statusReconciler, err
+		}
+	}
+
+	statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromote, "Completed", productIDStr, targetVersion, latestStagingVersion, nil)
+	statusReconciler.previousProductionVersion = previousProductionVersion
+	return statusReconciler, nil
+}
+
+// targetProductionVersionRecorded returns true when the status holds the version to be promoted
+// to production. A recorded version not matching the requested staging version is outdated
+func targetProductionVersionRecorded(proxyConfigPromote *capabilitiesv1beta1.ProxyConfigPromote) bool {
+	recorded := proxyConfigPromote.Status.TargetProductionVersion
+	if recorded == 0 {
+		return false
+	}
+	return proxyConfigPromote.Spec.StagingVersion == nil || *proxyConfigPromote.Spec.StagingVersion == recorded
+}
+
+// targetProductionVersion returns the proxy configuration version to be promoted to production
+func (r *ProxyConfigPromoteReconciler) targetProductionVersion(proxyConfigPromote *capabilitiesv1beta1.ProxyConfigPromote, threescaleAPIClient *threescaleapi.ThreeScaleClient, productIDStr string, currentProductionVersion int) (int, error) {
+	if proxyConfigPromote.Spec.StagingVersion != nil {
+		return *proxyConfigPromote.Spec.StagingVersion, nil
+	}
+
+	if currentProductionVersion == 0 {
+		return 0, fmt.Errorf("product %s has no proxy configuration in production", productIDStr)
+	}
+
+	productionConfigs, err := threescaleAPIClient.ListProxyConfig(productIDStr, "production")
+	if err != nil {
+		return 0, err
+	}
+
+	previousVersion := 0
+	for _, element := range productionConfigs.ProxyConfigs {
+		version := element.ProxyConfig.Version
+		if version < currentProductionVersion && version > previousVersion {
+			previousVersion = version
+		}
+	}
+
+	if previousVersion == 0 {
+		return 0, fmt.Errorf("product %s has no production proxy configuration previous to version %d", productIDStr, currentProductionVersion)
+	}
+
+	return previousVersion, nil
+}
+
+func (r *ProxyConfigPromoteReconciler) validateSpec(resource *capabilitiesv1beta1.ProxyConfigPromote) error {
+	errors := resource.Validate()
+	if len(errors) == 0 {
+		return nil
+	}
+
+	return &helper.SpecFieldError{
+		ErrorType:      helper.InvalidError,
+		FieldErrorList: errors,
+	}
+}
+
 func (r *ProxyConfigPromoteReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&capabilitiesv1beta1.ProxyConfigPromote{}).
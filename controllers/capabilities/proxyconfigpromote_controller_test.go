@@ -15,6 +15,7 @@ import (
 	"net/http"
 	"reflect"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
 	"testing"
 	"time"
 )
@@ -398,3 +399,210 @@ func TestProxyConfigPromoteReconciler_proxyConfigPromoteReconciler(t *testing.T)
 		})
 	}
 }
+
+func proxyConfigElementVersion(version int) client.ProxyConfigElement {
+	return client.ProxyConfigElement{
+		ProxyConfig: client.ProxyConfig{
+			ID:          3,
+			Version:     version,
+			Environment: "production",
+		},
+	}
+}
+
+func mockVersionsHttpClient(stagingVersion int, productionVersions []int, promoted *[]string) *http.Client {
+	productionList := &client.ProxyConfigList{ProxyConfigs: []client.ProxyConfigElement{}}
+	for _, version := range productionVersions {
+		productionList.ProxyConfigs = append(productionList.ProxyConfigs, proxyConfigElementVersion(version))
+	}
+
+	return NewTestClient(func(req *http.Request) *http.Response {
+		// GetLatestProxyConfig sandbox
+		if req.Method == "GET" && req.URL.Path == "/admin/api/services/3/proxy/configs/sandbox/latest.json" {
+			element := proxyConfigElementVersion(stagingVersion)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       ioutil.NopCloser(bytes.NewBuffer(responseBody(&element))),
+			}
+		}
+		// GetLatestProxyConfig production
+		if req.Method == "GET" && req.URL.Path == "/admin/api/services/3/proxy/configs/production/latest.json" {
+			if len(productionVersions) == 0 {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Header:     make(http.Header),
+					Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+				}
+			}
+			element := proxyConfigElementVersion(productionVersions[len(productionVersions)-1])
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       ioutil.NopCloser(bytes.NewBuffer(responseBody(&element))),
+			}
+		}
+		// ListProxyConfig production
+		if req.Method == "GET" && req.URL.Path == "/admin/api/services/3/proxy/configs/production.json" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       ioutil.NopCloser(bytes.NewBuffer(responseBody(productionList))),
+			}
+		}
+		// PromoteProxyConfig production
+		if req.Method == "POST" && strings.HasPrefix(req.URL.Path, "/admin/api/services/3/proxy/configs/sandbox/") && strings.HasSuffix(req.URL.Path, "/promote.json") {
+			*promoted = append(*promoted, req.URL.Path)
+			element := proxyConfigElementVersion(0)
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Header:     make(http.Header),
+				Body:       ioutil.NopCloser(bytes.NewBuffer(responseBody(&element))),
+			}
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(bytes.NewBufferString(fmt.Sprintf("unexpected request %s %s", req.Method, req.URL.Path))),
+		}
+	})
+}
+
+func TestProxyConfigPromoteReconciler_promoteVersionToProduction(t *testing.T) {
+	ap, _ := client.NewAdminPortalFromStr("https://3scale-admin.test.3scale.net")
+
+	stagingVersion := func(version int) *capabilitiesv1beta1.ProxyConfigPromote {
+		cr := getProxyConfigPromoteCRProduction()
+		cr.Spec.StagingVersion = &version
+		return cr
+	}
+	previousProductionVersion := func() *capabilitiesv1beta1.ProxyConfigPromote {
+		cr := getProxyConfigPromoteCRProduction()
+		cr.Spec.PreviousProductionVersion = newTrue()
+		return cr
+	}
+	recorded := func(cr *capabilitiesv1beta1.ProxyConfigPromote, targetVersion, previousVersion int) *capabilitiesv1beta1.ProxyConfigPromote {
+		cr.Status.TargetProductionVersion = targetVersion
+		cr.Status.PreviousProductionVersion = previousVersion
+		return cr
+	}
+
+	tests := []struct {
+		name                          string
+		proxyConfigPromote            *capabilitiesv1beta1.ProxyConfigPromote
+		stagingVersion                int
+		productionVersions            []int
+		wantErr                       bool
+		wantPromoted                  []string
+		wantLatestProductionVersion   int
+		wantPreviousProductionVersion int
+	}{
+		{
+			name:                          "Promote older staging version",
+			proxyConfigPromote:            stagingVersion(2),
+			stagingVersion:                5,
+			productionVersions:            []int{2, 4},
+			wantPromoted:                  []string{"/admin/api/services/3/proxy/configs/sandbox/2/promote.json"},
+			wantLatestProductionVersion:   2,
+			wantPreviousProductionVersion: 4,
+		},
+		{
+			name:                          "Staging version already in production",
+			proxyConfigPromote:            stagingVersion(4),
+			stagingVersion:                5,
+			productionVersions:            []int{2, 4},
+			wantPromoted:                  nil,
+			wantLatestProductionVersion:   4,
+			wantPreviousProductionVersion: 4,
+		},
+		{
+			name:                          "Staging version with nothing in production",
+			proxyConfigPromote:            stagingVersion(1),
+			stagingVersion:                1,
+			productionVersions:            nil,
+			wantPromoted:                  []string{"/admin/api/services/3/proxy/configs/sandbox/1/promote.json"},
+			wantLatestProductionVersion:   1,
+			wantPreviousProductionVersion: 0,
+		},
+		{
+			name:                          "Roll back to previous production version",
+			proxyConfigPromote:            previousProductionVersion(),
+			stagingVersion:                5,
+			productionVersions:            []int{1, 3, 4},
+			wantPromoted:                  []string{"/admin/api/services/3/proxy/configs/sandbox/3/promote.json"},
+			wantLatestProductionVersion:   3,
+			wantPreviousProductionVersion: 4,
+		},
+		{
+			// A failed status update after the roll back must not roll back further
+			name:                          "Retry recorded roll back already promoted",
+			proxyConfigPromote:            recorded(previousProductionVersion(), 3, 4),
+			stagingVersion:                5,
+			productionVersions:            []int{1, 3, 4, 3},
+			wantPromoted:                  nil,
+			wantLatestProductionVersion:   3,
+			wantPreviousProductionVersion: 4,
+		},
+		{
+			name:                          "Recorded version outdated by the staging version",
+			proxyConfigPromote:            recorded(stagingVersion(2), 3, 4),
+			stagingVersion:                5,
+			productionVersions:            []int{2, 4},
+			wantPromoted:                  []string{"/admin/api/services/3/proxy/configs/sandbox/2/promote.json"},
+			wantLatestProductionVersion:   2,
+			wantPreviousProductionVersion: 4,
+		},
+		{
+			name:               "Roll back without previous production version",
+			proxyConfigPromote: previousProductionVersion(),
+			stagingVersion:     5,
+			productionVersions: []int{4},
+			wantErr:            true,
+		},
+		{
+			name:               "Roll back with nothing in production",
+			proxyConfigPromote: previousProductionVersion(),
+			stagingVersion:     5,
+			productionVersions: nil,
+			wantErr:            true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ProxyConfigPromoteReconciler{
+				BaseReconciler: getBaseReconciler(),
+			}
+			var promoted []string
+			threescaleAPIClient := client.NewThreeScale(ap, "test", mockVersionsHttpClient(tt.stagingVersion, tt.productionVersions, &promoted))
+			got, err := r.proxyConfigPromoteReconciler(tt.proxyConfigPromote, logf.Log.WithName("test reqlogger"), threescaleAPIClient, getProductCR())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("proxyConfigPromoteReconciler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if got.state != "Failed" {
+					t.Errorf("proxyConfigPromoteReconciler() got.state = %v, want Failed", got.state)
+				}
+				if len(promoted) != 0 {
+					t.Errorf("proxyConfigPromoteReconciler() promoted = %v, want none", promoted)
+				}
+				return
+			}
+			if got.state != "Completed" {
+				t.Errorf("proxyConfigPromoteReconciler() got.state = %v, want Completed", got.state)
+			}
+			if !reflect.DeepEqual(promoted, tt.wantPromoted) {
+				t.Errorf("proxyConfigPromoteReconciler() promoted = %v, want %v", promoted, tt.wantPromoted)
+			}
+			if got.latestProductionVersion != tt.wantLatestProductionVersion {
+				t.Errorf("proxyConfigPromoteReconciler() got.latestProductionVersion = %v, want %v", got.latestProductionVersion, tt.wantLatestProductionVersion)
+			}
+			if got.latestStagingVersion != tt.stagingVersion {
+				t.Errorf("proxyConfigPromoteReconciler() got.latestStagingVersion = %v, want %v", got.latestStagingVersion, tt.stagingVersion)
+			}
+			if got.previousProductionVersion != tt.wantPreviousProductionVersion {
+				t.Errorf("proxyConfigPromoteReconciler() got.previousProductionVersion = %v, want %v", got.previousProductionVersion, tt.wantPreviousProductionVersion)
+			}
+		})
+	}
+}
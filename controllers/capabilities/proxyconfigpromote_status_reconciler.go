@@ -21,6 +21,9 @@ type ProxyConfigPromoteStatusReconciler struct {
 	latestStagingVersion    int
 	reconcileError          error
 	logger                  logr.Logger
+
+	// previousProductionVersion is the production version replaced by the promotion
+	previousProductionVersion int
 }
 
 func NewProxyConfigPromoteStatusReconciler(b *reconcilers.BaseReconciler, resource *capabilitiesv1beta1.ProxyConfigPromote, state string, productID string, latestProductionVersion int, latestStagingVersion int, reconcileError error) *ProxyConfigPromoteStatusReconciler {
@@ -72,6 +75,8 @@ func (s *ProxyConfigPromoteStatusReconciler) calculateStatus() (*capabilitiesv1b
 	newStatus.ProductId = s.productID
 	newStatus.LatestProductionVersion = s.latestProductionVersion
 	newStatus.LatestStagingVersion = s.latestStagingVersion
+	newStatus.PreviousProductionVersion = s.previousProductionVersion
+	newStatus.TargetProductionVersion = s.resource.Status.TargetProductionVersion
 
 	newStatus.Conditions = s.resource.Status.Conditions.Copy()
 	newStatus.Conditions.SetCondition(s.readyCondition(s.state))
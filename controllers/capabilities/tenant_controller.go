@@ -91,14 +91,14 @@ func (r *TenantReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		reqLogger.V(1).Info(string(jsonData))
 	}
 
-	masterAccessToken, err := r.fetchMasterCredentials(tenantR)
+	masterProviderAccount, err := r.fetchMasterCredentials(tenantR)
 	if err != nil {
 		reqLogger.Error(err, "Error fetching master credentials secret")
 		// Error reading the object - requeue the request.
 		return ctrl.Result{}, err
 	}
 
-	portaClient, err := controllerhelper.PortaClientFromURLString(tenantR.Spec.SystemMasterUrl, masterAccessToken)
+	portaClient, err := controllerhelper.PortaClient(masterProviderAccount)
 	if err != nil {
 		reqLogger.Error(err, "Error creating porta client object")
 		// Error reading the object - requeue the request.
@@ -183,21 +183,33 @@ func (r *TenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
-func (r *TenantReconciler) fetchMasterCredentials(tenantR *capabilitiesv1alpha1.Tenant) (string, error) {
+// fetchMasterCredentials returns the master provider account with the access token
+// and the optional TLS settings of the master credentials secret
+func (r *TenantReconciler) fetchMasterCredentials(tenantR *capabilitiesv1alpha1.Tenant) (*controllerhelper.ProviderAccount, error) {
 	masterCredentialsSecret := &v1.Secret{}
 
 	err := r.Client().Get(context.TODO(), tenantR.MasterSecretKey(), masterCredentialsSecret)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	masterAccessTokenByteArray, ok := masterCredentialsSecret.Data[component.SystemSecretSystemSeedMasterAccessTokenFieldName]
 	if !ok {
-		return "", fmt.Errorf("Key not found in master secret (%s) key: %s",
+		return nil, fmt.Errorf("Key not found in master secret (%s) key: %s",
 			tenantR.MasterSecretKey(),
 			component.SystemSecretSystemSeedMasterAccessTokenFieldName)
 	}
 
-	return bytes.NewBuffer(masterAccessTokenByteArray).String(), nil
+	masterProviderAccount := &controllerhelper.ProviderAccount{
+		AdminURLStr: tenantR.Spec.SystemMasterUrl,
+		Token:       bytes.NewBuffer(masterAccessTokenByteArray).String(),
+	}
+
+	err = controllerhelper.ReadProviderAccountTLSConfig(masterProviderAccount, masterCredentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return masterProviderAccount, nil
 }
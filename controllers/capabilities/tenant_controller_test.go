@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"testing"
+
+	capabilitiesv1alpha1 "github.com/3scale/3scale-operator/apis/capabilities/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTenantReconcilerFetchMasterCredentials(t *testing.T) {
+	tests := []struct {
+		name                   string
+		data                   map[string][]byte
+		wantErr                bool
+		wantCABundle           string
+		wantInsecureSkipVerify bool
+	}{
+		{"token only", map[string][]byte{
+			component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("mastertoken"),
+		}, false, "", false},
+		{"tls settings", map[string][]byte{
+			component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("mastertoken"),
+			"caBundle":           []byte("-----BEGIN CERTIFICATE-----"),
+			"insecureSkipVerify": []byte("true"),
+		}, false, "-----BEGIN CERTIFICATE-----", true},
+		{"invalid insecureSkipVerify", map[string][]byte{
+			component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("mastertoken"),
+			"insecureSkipVerify": []byte("maybe"),
+		}, true, "", false},
+		{"missing token", map[string][]byte{}, true, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(subT *testing.T) {
+			masterSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "system-seed", Namespace: "test"},
+				Data:       tt.data,
+			}
+			tenantR := &capabilitiesv1alpha1.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "test"},
+				Spec: capabilitiesv1alpha1.TenantSpec{
+					SystemMasterUrl:      "https://master.example.com",
+					MasterCredentialsRef: corev1.SecretReference{Name: "system-seed"},
+				},
+			}
+
+			r := &TenantReconciler{BaseReconciler: openapiTestReconciler(masterSecret)}
+			providerAccount, err := r.fetchMasterCredentials(tenantR)
+			if tt.wantErr {
+				if err == nil {
+					subT.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if providerAccount.AdminURLStr != "https://master.example.com" || providerAccount.Token != "mastertoken" {
+				subT.Errorf("unexpected provider account: %s %s", providerAccount.AdminURLStr, providerAccount.Token)
+			}
+			if string(providerAccount.CABundle) != tt.wantCABundle {
+				subT.Errorf("CABundle = %q, want %q", providerAccount.CABundle, tt.wantCABundle)
+			}
+			if providerAccount.InsecureSkipVerify != tt.wantInsecureSkipVerify {
+				subT.Errorf("InsecureSkipVerify = %v, want %v", providerAccount.InsecureSkipVerify, tt.wantInsecureSkipVerify)
+			}
+		})
+	}
+}
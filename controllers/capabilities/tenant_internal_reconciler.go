@@ -92,8 +92,10 @@ func (r *TenantInternalReconciler) reconcileTenant() (ctrl.Result, error) {
 		// Early update status with the new tenantID
 		newStatus := &apiv1alpha1.TenantStatus{
 			// reset adminID. It could keep old stale value
-			AdminId:  0,
-			TenantId: tenantDef.Signup.Account.ID,
+			AdminId:         0,
+			TenantId:        tenantDef.Signup.Account.ID,
+			AdminDomain:     tenantDef.Signup.Account.AdminDomain,
+			DeveloperDomain: tenantDef.Signup.Account.Domain,
 		}
 
 		updated, err := r.reconcileStatus(newStatus)
@@ -105,6 +107,17 @@ func (r *TenantInternalReconciler) reconcileTenant() (ctrl.Result, error) {
 			// requeue to have a new run with the updated tenant resource
 			return ctrl.Result{Requeue: true}, nil
 		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// Portal domains can be changed in 3scale
+	newStatus := r.tenantR.Status.DeepCopy()
+	newStatus.AdminDomain = tenantDef.Signup.Account.AdminDomain
+	newStatus.DeveloperDomain = tenantDef.Signup.Account.Domain
+	_, err = r.reconcileStatus(newStatus)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
@@ -143,10 +156,8 @@ func (r *TenantInternalReconciler) reconcileAdminUser() (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
-	newStatus := &apiv1alpha1.TenantStatus{
-		AdminId:  *adminUser.Element.ID,
-		TenantId: r.tenantR.Status.TenantId,
-	}
+	newStatus := r.tenantR.Status.DeepCopy()
+	newStatus.AdminId = *adminUser.Element.ID
 
 	updated, err := r.reconcileStatus(newStatus)
 	if err != nil {
@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strconv"
+	"time"
 
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -83,6 +85,7 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var resyncPeriod time.Duration
 
 	// https://v1-2-x.sdk.operatorframework.io/docs/building-operators/golang/references/logging/#a-simple-example
 	// Add the zap logger flag set to the CLI. The flag set must
@@ -94,6 +97,9 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&resyncPeriod, "resync-period", 0,
+		"Period to synchronize Product and Backend resources again with 3scale, correcting changes made outside the operator. "+
+			"Can be overridden by each resource. Zero disables periodic synchronization.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&loggerOpts)))
@@ -151,6 +157,22 @@ func main() {
 		os.Exit(1)
 	}
 
+	discoveryClientAPIManagerBackupSchedule, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+	if err = (&appscontroller.APIManagerBackupScheduleReconciler{
+		BaseReconciler: reconcilers.NewBaseReconciler(
+			context.Background(), mgr.GetClient(), mgr.GetScheme(), mgr.GetAPIReader(),
+			ctrl.Log.WithName("controllers").WithName("APIManagerBackupSchedule"),
+			discoveryClientAPIManagerBackupSchedule,
+			mgr.GetEventRecorderFor("APIManagerBackupSchedule")),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "APIManagerBackupSchedule")
+		os.Exit(1)
+	}
+
 	discoveryClientAPIManagerRestore, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
 	if err != nil {
 		setupLog.Error(err, "unable to create discovery client")
@@ -194,6 +216,7 @@ func main() {
 			ctrl.Log.WithName("controllers").WithName("Backend"),
 			discoveryClientBackend,
 			mgr.GetEventRecorderFor("Backend")),
+		ResyncPeriod: resyncPeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Backend")
 		os.Exit(1)
@@ -210,6 +233,7 @@ func main() {
 			ctrl.Log.WithName("controllers").WithName("Product"),
 			discoveryClientProduct,
 			mgr.GetEventRecorderFor("Product")),
+		ResyncPeriod: resyncPeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Product")
 		os.Exit(1)
@@ -314,6 +338,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	discoveryClientApplication, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+
+	if err = (&capabilitiescontroller.ApplicationReconciler{
+		BaseReconciler: reconcilers.NewBaseReconciler(
+			context.Background(), mgr.GetClient(), mgr.GetScheme(), mgr.GetAPIReader(),
+			ctrl.Log.WithName("controllers").WithName("Application"),
+			discoveryClientApplication,
+			mgr.GetEventRecorderFor("Application")),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Application")
+		os.Exit(1)
+	}
+
 	registerThreescaleMetricsIntoControllerRuntimeMetricsRegistry()
 
 	discoveryProxyConfigPromote, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
@@ -332,8 +373,37 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "ProxyConfigPromote")
 		os.Exit(1)
 	}
+
+	discoveryClientProviderAccount, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+
+	if err = (&capabilitiescontroller.ProviderAccountReconciler{
+		BaseReconciler: reconcilers.NewBaseReconciler(
+			context.Background(), mgr.GetClient(), mgr.GetScheme(), mgr.GetAPIReader(),
+			ctrl.Log.WithName("controllers").WithName("ProviderAccount"),
+			discoveryClientProviderAccount,
+			mgr.GetEventRecorderFor("ProviderAccount")),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ProviderAccount")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
+	if webhooksEnabled() {
+		if err = appscontroller.SetupAPIManagerWebhookWithManager(mgr, ctrl.Log.WithName("webhooks").WithName("APIManager")); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "APIManager")
+			os.Exit(1)
+		}
+
+		if err = capabilitiescontroller.SetupWebhooksWithManager(mgr, ctrl.Log.WithName("webhooks").WithName("Capabilities")); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Capabilities")
+			os.Exit(1)
+		}
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -355,6 +425,13 @@ func getWatchNamespace() (string, error) {
 	return ns, nil
 }
 
+// webhooksEnabled returns true when the validating admission webhooks are served.
+// The serving certificates must be provisioned, for instance, by OLM or cert-manager
+func webhooksEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("ENABLE_WEBHOOKS"))
+	return err == nil && enabled
+}
+
 func printVersion() {
 	setupLog.Info(fmt.Sprintf("Operator Version: %s", version.Version))
 	setupLog.Info(fmt.Sprintf("Go Version: %s", runtime.Version()))
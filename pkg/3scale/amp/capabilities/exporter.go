@@ -0,0 +1,558 @@
+package capabilities
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+)
+
+// Exporter reads 3scale products and backends and builds the equivalent
+// Product and Backend custom resources
+type Exporter struct {
+	client             *threescaleapi.ThreeScaleClient
+	namespace          string
+	providerAccountRef *capabilitiesv1beta1.ProviderAccountReference
+	logger             logr.Logger
+
+	backends map[int64]*controllerhelper.BackendAPIEntity
+}
+
+// NewExporter returns an Exporter of the 3scale account of the client.
+// The custom resources are built in the namespace, when not empty, referencing the provider account secret, when not nil.
+func NewExporter(client *threescaleapi.ThreeScaleClient, namespace string, providerAccountRef *capabilitiesv1beta1.ProviderAccountReference, logger logr.Logger) *Exporter {
+	return &Exporter{
+		client:             client,
+		namespace:          namespace,
+		providerAccountRef: providerAccountRef,
+		logger:             logger,
+	}
+}
+
+// Export returns the Backend and Product custom resources of the 3scale account.
+// When productSystemNames is not empty, only those products and the backends they use are exported.
+func (e *Exporter) Export(productSystemNames []string) ([]capabilitiesv1beta1.Backend, []capabilitiesv1beta1.Product, error) {
+	backendList, err := e.client.ListBackendApis()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error listing backends: %w", err)
+	}
+
+	e.backends = map[int64]*controllerhelper.BackendAPIEntity{}
+	for idx := range backendList.Backends {
+		backendEntity := controllerhelper.NewBackendAPIEntity(&backendList.Backends[idx], e.client, e.logger)
+		e.backends[backendEntity.ID()] = backendEntity
+	}
+
+	productList, err := e.client.ListProducts()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error listing products: %w", err)
+	}
+
+	exportedBackendIDs := map[int64]bool{}
+	products := make([]capabilitiesv1beta1.Product, 0)
+	for idx := range productList.Products {
+		productObj := &productList.Products[idx]
+		if len(productSystemNames) > 0 && !helper.ArrayContains(productSystemNames, productObj.Element.SystemName) {
+			continue
+		}
+
+		productEntity := controllerhelper.NewProductEntity(productObj, e.client, e.logger)
+		product, backendIDs, err := e.product(productObj, productEntity)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		products = append(products, *product)
+		for _, backendID := range backendIDs {
+			exportedBackendIDs[backendID] = true
+		}
+	}
+
+	backends := make([]capabilitiesv1beta1.Backend, 0)
+	for idx := range backendList.Backends {
+		backendID := backendList.Backends[idx].Element.ID
+		if len(productSystemNames) > 0 && !exportedBackendIDs[backendID] {
+			continue
+		}
+
+		backend, err := e.backend(e.backends[backendID])
+		if err != nil {
+			return nil, nil, err
+		}
+		backends = append(backends, *backend)
+	}
+
+	return backends, products, nil
+}
+
+func (e *Exporter) objectMeta(systemName string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      ObjectName(systemName),
+		Namespace: e.namespace,
+	}
+}
+
+func (e *Exporter) backend(backendEntity *controllerhelper.BackendAPIEntity) (*capabilitiesv1beta1.Backend, error) {
+	methods, err := backendEntity.Methods()
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := backendEntity.Metrics()
+	if err != nil {
+		return nil, err
+	}
+
+	metricsAndMethods, err := backendEntity.MetricsAndMethods()
+	if err != nil {
+		return nil, err
+	}
+
+	mappingRules, err := backendEntity.MappingRules()
+	if err != nil {
+		return nil, err
+	}
+
+	return &capabilitiesv1beta1.Backend{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       capabilitiesv1beta1.BackendKind,
+			APIVersion: capabilitiesv1beta1.GroupVersion.String(),
+		},
+		ObjectMeta: e.objectMeta(backendEntity.SystemName()),
+		Spec: capabilitiesv1beta1.BackendSpec{
+			Name:               backendEntity.Name(),
+			SystemName:         backendEntity.SystemName(),
+			PrivateBaseURL:     backendEntity.PrivateEndpoint(),
+			Description:        backendEntity.Description(),
+			Methods:            methodSpecs(methods),
+			Metrics:            metricSpecs(metrics),
+			MappingRules:       mappingRuleSpecs(mappingRules, metricsAndMethods),
+			ProviderAccountRef: e.providerAccountRef,
+		},
+	}, nil
+}
+
+// product returns the product custom resource and the IDs of the backends used by the product
+func (e *Exporter) product(productObj *threescaleapi.Product, productEntity *controllerhelper.ProductEntity) (*capabilitiesv1beta1.Product, []int64, error) {
+	systemName := productObj.Element.SystemName
+
+	methods, err := productEntity.Methods()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metrics, err := productEntity.Metrics()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metricsAndMethods, err := productEntity.MetricsAndMethods()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mappingRules, err := productEntity.MappingRules()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	backendUsageList, err := productEntity.BackendUsages()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// metric and method references of the plan limits and pricing rules
+	metricRefs := map[int64]capabilitiesv1beta1.MetricMethodRefSpec{}
+	for _, metric := range metricsAndMethods.Metrics {
+		metricRefs[metric.Element.ID] = capabilitiesv1beta1.MetricMethodRefSpec{SystemName: metric.Element.SystemName}
+	}
+
+	backendUsages := map[string]capabilitiesv1beta1.BackendUsageSpec{}
+	backendIDs := make([]int64, 0, len(backendUsageList))
+	for _, backendUsage := range backendUsageList {
+		backendEntity, ok := e.backends[backendUsage.Element.BackendAPIID]
+		if !ok {
+			return nil, nil, fmt.Errorf("product [%s] backend usage: backend %d not found", systemName, backendUsage.Element.BackendAPIID)
+		}
+
+		backendUsages[backendEntity.SystemName()] = capabilitiesv1beta1.BackendUsageSpec{Path: backendUsage.Element.Path}
+		backendIDs = append(backendIDs, backendEntity.ID())
+
+		backendMetricsAndMethods, err := backendEntity.MetricsAndMethods()
+		if err != nil {
+			return nil, nil, err
+		}
+		backendSystemName := backendEntity.SystemName()
+		for _, metric := range backendMetricsAndMethods.Metrics {
+			metricRefs[metric.Element.ID] = capabilitiesv1beta1.MetricMethodRefSpec{
+				SystemName:        metric.Element.SystemName,
+				BackendSystemName: &backendSystemName,
+			}
+		}
+	}
+
+	deployment, err := e.deployment(productObj, productEntity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applicationPlans, err := e.applicationPlans(productObj, productEntity, metricRefs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policies, err := e.policies(productEntity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	product := &capabilitiesv1beta1.Product{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       capabilitiesv1beta1.ProductKind,
+			APIVersion: capabilitiesv1beta1.GroupVersion.String(),
+		},
+		ObjectMeta: e.objectMeta(systemName),
+		Spec: capabilitiesv1beta1.ProductSpec{
+			Name:               productObj.Element.Name,
+			SystemName:         systemName,
+			Description:        productObj.Element.Description,
+			Deployment:         deployment,
+			Methods:            methodSpecs(methods),
+			Metrics:            metricSpecs(metrics),
+			MappingRules:       mappingRuleSpecs(mappingRules, metricsAndMethods),
+			ApplicationPlans:   applicationPlans,
+			Policies:           policies,
+			ProviderAccountRef: e.providerAccountRef,
+		},
+	}
+
+	if len(backendUsages) > 0 {
+		product.Spec.BackendUsages = backendUsages
+	}
+
+	return product, backendIDs, nil
+}
+
+func (e *Exporter) deployment(productObj *threescaleapi.Product, productEntity *controllerhelper.ProductEntity) (*capabilitiesv1beta1.ProductDeploymentSpec, error) {
+	deploymentOption := productObj.Element.DeploymentOption
+	if deploymentOption != "hosted" && deploymentOption != "self_managed" {
+		e.logger.Info("deployment option not supported, deployment not exported", "product", productObj.Element.SystemName, "deploymentOption", deploymentOption)
+		return nil, nil
+	}
+
+	proxy, err := productEntity.Proxy()
+	if err != nil {
+		return nil, err
+	}
+
+	authentication, err := e.authentication(productObj, productEntity, &proxy.Element)
+	if err != nil {
+		return nil, err
+	}
+
+	if deploymentOption == "hosted" {
+		return &capabilitiesv1beta1.ProductDeploymentSpec{
+			ApicastHosted: &capabilitiesv1beta1.ApicastHostedSpec{Authentication: authentication},
+		}, nil
+	}
+
+	return &capabilitiesv1beta1.ProductDeploymentSpec{
+		ApicastSelfManaged: &capabilitiesv1beta1.ApicastSelfManagedSpec{
+			Authentication:          authentication,
+			StagingPublicBaseURL:    stringPtrOrNil(proxy.Element.SandboxEndpoint),
+			ProductionPublicBaseURL: stringPtrOrNil(proxy.Element.Endpoint),
+		},
+	}, nil
+}
+
+func (e *Exporter) authentication(productObj *threescaleapi.Product, productEntity *controllerhelper.ProductEntity, proxy *threescaleapi.ProxyItem) (*capabilitiesv1beta1.AuthenticationSpec, error) {
+	security := securitySpec(proxy)
+	gatewayResponse := gatewayResponseSpec(proxy)
+	credentialsLoc := stringPtrOrNil(proxy.CredentialsLocation)
+
+	switch productObj.Element.BackendVersion {
+	case "1":
+		return &capabilitiesv1beta1.AuthenticationSpec{
+			UserKeyAuthentication: &capabilitiesv1beta1.UserKeyAuthenticationSpec{
+				Key:             stringPtrOrNil(proxy.AuthUserKey),
+				CredentialsLoc:  credentialsLoc,
+				Security:        security,
+				GatewayResponse: gatewayResponse,
+			},
+		}, nil
+	case "2":
+		return &capabilitiesv1beta1.AuthenticationSpec{
+			AppKeyAppIDAuthentication: &capabilitiesv1beta1.AppKeyAppIDAuthenticationSpec{
+				AppID:           stringPtrOrNil(proxy.AuthAppID),
+				AppKey:          stringPtrOrNil(proxy.AuthAppKey),
+				CredentialsLoc:  credentialsLoc,
+				Security:        security,
+				GatewayResponse: gatewayResponse,
+			},
+		}, nil
+	case "oidc":
+		oidcConf, err := productEntity.OIDCConfiguration()
+		if err != nil {
+			return nil, err
+		}
+
+		return &capabilitiesv1beta1.AuthenticationSpec{
+			OIDC: &capabilitiesv1beta1.OIDCSpec{
+				IssuerType:     proxy.OidcIssuerType,
+				IssuerEndpoint: proxy.OidcIssuerEndpoint,
+				AuthenticationFlow: &capabilitiesv1beta1.OIDCAuthenticationFlowSpec{
+					StandardFlowEnabled:       oidcConf.Element.StandardFlowEnabled,
+					ImplicitFlowEnabled:       oidcConf.Element.ImplicitFlowEnabled,
+					ServiceAccountsEnabled:    oidcConf.Element.ServiceAccountsEnabled,
+					DirectAccessGrantsEnabled: oidcConf.Element.DirectAccessGrantsEnabled,
+				},
+				JwtClaimWithClientID:     stringPtrOrNil(proxy.JwtClaimWithClientID),
+				JwtClaimWithClientIDType: stringPtrOrNil(proxy.JwtClaimWithClientIDType),
+				CredentialsLoc:           credentialsLoc,
+				Security:                 security,
+				GatewayResponse:          gatewayResponse,
+			},
+		}, nil
+	}
+
+	e.logger.Info("authentication mode not supported, authentication not exported", "product", productObj.Element.SystemName, "backendVersion", productObj.Element.BackendVersion)
+	return nil, nil
+}
+
+func (e *Exporter) applicationPlans(productObj *threescaleapi.Product, productEntity *controllerhelper.ProductEntity, metricRefs map[int64]capabilitiesv1beta1.MetricMethodRefSpec) (map[string]capabilitiesv1beta1.ApplicationPlanSpec, error) {
+	planList, err := productEntity.ApplicationPlans()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(planList.Plans) == 0 {
+		return nil, nil
+	}
+
+	plans := map[string]capabilitiesv1beta1.ApplicationPlanSpec{}
+	for _, plan := range planList.Plans {
+		planEntity := controllerhelper.NewApplicationPlanEntity(productObj.Element.ID, plan.Element, e.client, e.logger)
+
+		limits, err := planEntity.Limits()
+		if err != nil {
+			return nil, err
+		}
+
+		pricingRules, err := planEntity.PricingRules()
+		if err != nil {
+			return nil, err
+		}
+
+		name := plan.Element.Name
+		approvalRequired := plan.Element.ApprovalRequired
+		trialPeriod := plan.Element.TrialPeriodDays
+		setupFee := priceString(plan.Element.SetupFee)
+		costMonth := priceString(plan.Element.CostPerMonth)
+		published := plan.Element.State == "published"
+
+		planSpec := capabilitiesv1beta1.ApplicationPlanSpec{
+			Name:                &name,
+			AppsRequireApproval: &approvalRequired,
+			TrialPeriod:         &trialPeriod,
+			SetupFee:            &setupFee,
+			CostMonth:           &costMonth,
+			Published:           &published,
+		}
+
+		for _, limit := range limits.Limits {
+			metricRef, ok := metricRefs[limit.Element.MetricID]
+			if !ok {
+				return nil, fmt.Errorf("product [%s] plan [%s] limit: metric %d not found", productObj.Element.SystemName, plan.Element.SystemName, limit.Element.MetricID)
+			}
+			planSpec.Limits = append(planSpec.Limits, capabilitiesv1beta1.LimitSpec{
+				Period:          limit.Element.Period,
+				Value:           limit.Element.Value,
+				MetricMethodRef: metricRef,
+			})
+		}
+
+		for _, pricingRule := range pricingRules.Rules {
+			metricRef, ok := metricRefs[pricingRule.Element.MetricID]
+			if !ok {
+				return nil, fmt.Errorf("product [%s] plan [%s] pricing rule: metric %d not found", productObj.Element.SystemName, plan.Element.SystemName, pricingRule.Element.MetricID)
+			}
+
+			pricePerUnit, err := strconv.ParseFloat(pricingRule.Element.CostPerUnit, 64)
+			if err != nil {
+				return nil, fmt.Errorf("product [%s] plan [%s] pricing rule: %w", productObj.Element.SystemName, plan.Element.SystemName, err)
+			}
+
+			planSpec.PricingRules = append(planSpec.PricingRules, capabilitiesv1beta1.PricingRuleSpec{
+				From:            pricingRule.Element.Min,
+				To:              pricingRule.Element.Max,
+				PricePerUnit:    priceString(pricePerUnit),
+				MetricMethodRef: metricRef,
+			})
+		}
+
+		plans[plan.Element.SystemName] = planSpec
+	}
+
+	return plans, nil
+}
+
+func (e *Exporter) policies(productEntity *controllerhelper.ProductEntity) ([]capabilitiesv1beta1.PolicyConfig, error) {
+	policyList, err := productEntity.Policies()
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []capabilitiesv1beta1.PolicyConfig
+	for _, policy := range policyList.Policies {
+		configuration := policy.Configuration
+		if configuration == nil {
+			configuration = map[string]interface{}{}
+		}
+
+		raw, err := json.Marshal(configuration)
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, capabilitiesv1beta1.PolicyConfig{
+			Name:          policy.Name,
+			Version:       policy.Version,
+			Enabled:       policy.Enabled,
+			Configuration: runtime.RawExtension{Raw: raw},
+		})
+	}
+
+	return policies, nil
+}
+
+func methodSpecs(list *threescaleapi.MethodList) map[string]capabilitiesv1beta1.MethodSpec {
+	if len(list.Methods) == 0 {
+		return nil
+	}
+
+	methods := map[string]capabilitiesv1beta1.MethodSpec{}
+	for _, method := range list.Methods {
+		methods[method.Element.SystemName] = capabilitiesv1beta1.MethodSpec{
+			Name:        method.Element.Name,
+			Description: method.Element.Description,
+		}
+	}
+	return methods
+}
+
+func metricSpecs(list *threescaleapi.MetricJSONList) map[string]capabilitiesv1beta1.MetricSpec {
+	if len(list.Metrics) == 0 {
+		return nil
+	}
+
+	metrics := map[string]capabilitiesv1beta1.MetricSpec{}
+	for _, metric := range list.Metrics {
+		metrics[metric.Element.SystemName] = capabilitiesv1beta1.MetricSpec{
+			Name:        metric.Element.Name,
+			Unit:        metric.Element.Unit,
+			Description: metric.Element.Description,
+		}
+	}
+	return metrics
+}
+
+// mappingRuleSpecs returns the mapping rules sorted by position
+func mappingRuleSpecs(list *threescaleapi.MappingRuleJSONList, metricsAndMethods *threescaleapi.MetricJSONList) []capabilitiesv1beta1.MappingRuleSpec {
+	metricSystemNames := map[int64]string{}
+	for _, metric := range metricsAndMethods.Metrics {
+		metricSystemNames[metric.Element.ID] = metric.Element.SystemName
+	}
+
+	items := make([]threescaleapi.MappingRuleItem, 0, len(list.MappingRules))
+	for _, mappingRule := range list.MappingRules {
+		items = append(items, mappingRule.Element)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Position < items[j].Position })
+
+	var mappingRules []capabilitiesv1beta1.MappingRuleSpec
+	for _, item := range items {
+		mappingRule := capabilitiesv1beta1.MappingRuleSpec{
+			HTTPMethod:      item.HTTPMethod,
+			Pattern:         item.Pattern,
+			MetricMethodRef: metricSystemNames[item.MetricID],
+			Increment:       item.Delta,
+		}
+		if item.Last {
+			last := true
+			mappingRule.Last = &last
+		}
+		mappingRules = append(mappingRules, mappingRule)
+	}
+	return mappingRules
+}
+
+func securitySpec(proxy *threescaleapi.ProxyItem) *capabilitiesv1beta1.SecuritySpec {
+	if proxy.HostnameRewrite == "" && proxy.SecretToken == "" {
+		return nil
+	}
+
+	return &capabilitiesv1beta1.SecuritySpec{
+		HostHeader:  stringPtrOrNil(proxy.HostnameRewrite),
+		SecretToken: stringPtrOrNil(proxy.SecretToken),
+	}
+}
+
+func gatewayResponseSpec(proxy *threescaleapi.ProxyItem) *capabilitiesv1beta1.GatewayResponseSpec {
+	gatewayResponse := &capabilitiesv1beta1.GatewayResponseSpec{
+		ErrorStatusAuthFailed:      int32PtrOrNil(proxy.ErrorStatusAuthFailed),
+		ErrorHeadersAuthFailed:     stringPtrOrNil(proxy.ErrorHeadersAuthFailed),
+		ErrorAuthFailed:            stringPtrOrNil(proxy.ErrorAuthFailed),
+		ErrorStatusAuthMissing:     int32PtrOrNil(proxy.ErrorStatusAuthMissing),
+		ErrorHeadersAuthMissing:    stringPtrOrNil(proxy.ErrorHeadersAuthMissing),
+		ErrorAuthMissing:           stringPtrOrNil(proxy.ErrorAuthMissing),
+		ErrorStatusNoMatch:         int32PtrOrNil(proxy.ErrorStatusNoMatch),
+		ErrorHeadersNoMatch:        stringPtrOrNil(proxy.ErrorHeadersNoMatch),
+		ErrorNoMatch:               stringPtrOrNil(proxy.ErrorNoMatch),
+		ErrorStatusLimitsExceeded:  int32PtrOrNil(proxy.ErrorStatusLimitsExceeded),
+		ErrorHeadersLimitsExceeded: stringPtrOrNil(proxy.ErrorHeadersLimitsExceeded),
+		ErrorLimitsExceeded:        stringPtrOrNil(proxy.ErrorLimitsExceeded),
+	}
+
+	if *gatewayResponse == (capabilitiesv1beta1.GatewayResponseSpec{}) {
+		return nil
+	}
+	return gatewayResponse
+}
+
+// ObjectName returns a valid custom resource name from the 3scale system name
+func ObjectName(systemName string) string {
+	return helper.DNS1123Name(strings.Replace(systemName, "_", "-", -1))
+}
+
+// priceString formats prices with the precision accepted by the custom resources
+func priceString(price float64) string {
+	if price == float64(int64(price)) {
+		return strconv.FormatInt(int64(price), 10)
+	}
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
+
+func stringPtrOrNil(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func int32PtrOrNil(value int) *int32 {
+	if value == 0 {
+		return nil
+	}
+	tmp := int32(value)
+	return &tmp
+}
@@ -0,0 +1,180 @@
+package capabilities
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	logrtesting "github.com/go-logr/logr/testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+)
+
+// exportTestResponses are the 3scale responses of an account with one product using one backend
+var exportTestResponses = map[string]string{
+	"/admin/api/backend_apis.json": `{"backend_apis":[
+		{"backend_api":{"id":1,"name":"Backend One","system_name":"backend_one","description":"first","private_endpoint":"https://api.example.com"}},
+		{"backend_api":{"id":4,"name":"Unused","system_name":"unused","private_endpoint":"https://unused.example.com"}}]}`,
+	"/admin/api/backend_apis/1/metrics.json": `{"metrics":[
+		{"metric":{"id":10,"friendly_name":"Hits","system_name":"hits.1","unit":"hit"}},
+		{"metric":{"id":11,"friendly_name":"Get Pets","system_name":"get_pets.1","unit":"hit"}}]}`,
+	"/admin/api/backend_apis/1/metrics/10/methods.json": `{"methods":[
+		{"method":{"id":11,"friendly_name":"Get Pets","system_name":"get_pets.1"}}]}`,
+	"/admin/api/backend_apis/1/mapping_rules.json": `{"mapping_rules":[
+		{"mapping_rule":{"id":2,"metric_id":10,"pattern":"/","http_method":"GET","delta":1,"position":2}},
+		{"mapping_rule":{"id":1,"metric_id":11,"pattern":"/pets$","http_method":"GET","delta":1,"position":1,"last":true}}]}`,
+	"/admin/api/backend_apis/4/metrics.json": `{"metrics":[
+		{"metric":{"id":40,"friendly_name":"Hits","system_name":"hits.4","unit":"hit"}}]}`,
+	"/admin/api/backend_apis/4/metrics/40/methods.json": `{"methods":[]}`,
+	"/admin/api/backend_apis/4/mapping_rules.json":      `{"mapping_rules":[]}`,
+	"/admin/api/services.json": `{"services":[
+		{"service":{"id":2,"name":"Product One","system_name":"product_one","description":"product","deployment_option":"self_managed","backend_version":"1"}}]}`,
+	"/admin/api/services/2/metrics.json": `{"metrics":[
+		{"metric":{"id":20,"friendly_name":"Hits","system_name":"hits","unit":"hit"}},
+		{"metric":{"id":21,"friendly_name":"Bytes","system_name":"bytes","unit":"byte"}}]}`,
+	"/admin/api/services/2/metrics/20/methods.json": `{"methods":[]}`,
+	"/admin/api/services/2/proxy/mapping_rules.json": `{"mapping_rules":[
+		{"mapping_rule":{"id":3,"metric_id":21,"pattern":"/upload","http_method":"POST","delta":5,"position":1}}]}`,
+	"/admin/api/services/2/backend_usages.json": `[{"backend_usage":{"id":5,"path":"/v1","service_id":2,"backend_id":1}}]`,
+	"/admin/api/services/2/proxy.json": `{"proxy":{"service_id":2,"endpoint":"https://prod.example.com:443","sandbox_endpoint":"https://staging.example.com:443",
+		"auth_user_key":"api-key","credentials_location":"headers","secret_token":"secret","error_status_auth_failed":403}}`,
+	"/admin/api/services/2/application_plans.json": `{"plans":[
+		{"application_plan":{"id":3,"name":"Basic","system_name":"basic","state":"published","setup_fee":1.5,"cost_per_month":10,"trial_period_days":7}}]}`,
+	"/admin/api/application_plans/3/limits.json": `{"limits":[
+		{"limit":{"id":6,"period":"day","value":100,"metric_id":11}},
+		{"limit":{"id":7,"period":"month","value":10,"metric_id":21}}]}`,
+	"/admin/api/application_plans/3/pricing_rules.json": `{"pricing_rules":[
+		{"pricing_rule":{"id":8,"metric_id":10,"cost_per_unit":"0.1","min":1,"max":100}}]}`,
+	"/admin/api/services/2/proxy/policies.json": `{"policies_config":[
+		{"name":"apicast","version":"builtin","configuration":{},"enabled":true}]}`,
+}
+
+func exportTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := exportTestResponses[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestExporterExport(t *testing.T) {
+	server := exportTestServer(t)
+	defer server.Close()
+
+	threescaleAPIClient, err := controllerhelper.PortaClientFromURLString(server.URL, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	providerAccountRef := &capabilitiesv1beta1.ProviderAccountReference{Name: "mytenant"}
+	exporter := NewExporter(threescaleAPIClient, "ns", providerAccountRef, logrtesting.NullLogger{})
+
+	backends, products, err := exporter.Export([]string{"product_one"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(backends) != 1 || len(products) != 1 {
+		t.Fatalf("unexpected number of backends %d and products %d", len(backends), len(products))
+	}
+
+	backend := backends[0]
+	if backend.Name != "backend-one" || backend.Namespace != "ns" || backend.Kind != capabilitiesv1beta1.BackendKind {
+		t.Fatalf("unexpected backend metadata: %v %v", backend.TypeMeta, backend.ObjectMeta)
+	}
+
+	last := true
+	expectedBackendSpec := capabilitiesv1beta1.BackendSpec{
+		Name:           "Backend One",
+		SystemName:     "backend_one",
+		PrivateBaseURL: "https://api.example.com",
+		Description:    "first",
+		Methods: map[string]capabilitiesv1beta1.MethodSpec{
+			"get_pets": {Name: "Get Pets"},
+		},
+		Metrics: map[string]capabilitiesv1beta1.MetricSpec{
+			"hits": {Name: "Hits", Unit: "hit"},
+		},
+		MappingRules: []capabilitiesv1beta1.MappingRuleSpec{
+			{HTTPMethod: "GET", Pattern: "/pets$", MetricMethodRef: "get_pets", Increment: 1, Last: &last},
+			{HTTPMethod: "GET", Pattern: "/", MetricMethodRef: "hits", Increment: 1},
+		},
+		ProviderAccountRef: providerAccountRef,
+	}
+	if !reflect.DeepEqual(backend.Spec, expectedBackendSpec) {
+		t.Fatalf("unexpected backend spec: got %+v, expected %+v", backend.Spec, expectedBackendSpec)
+	}
+
+	product := products[0]
+	if product.Name != "product-one" || product.Spec.SystemName != "product_one" {
+		t.Fatalf("unexpected product: %v", product.ObjectMeta)
+	}
+
+	if !reflect.DeepEqual(product.Spec.BackendUsages, map[string]capabilitiesv1beta1.BackendUsageSpec{"backend_one": {Path: "/v1"}}) {
+		t.Fatalf("unexpected backend usages: %v", product.Spec.BackendUsages)
+	}
+
+	selfManaged := product.Spec.Deployment.ApicastSelfManaged
+	if selfManaged == nil || *selfManaged.ProductionPublicBaseURL != "https://prod.example.com:443" || *selfManaged.StagingPublicBaseURL != "https://staging.example.com:443" {
+		t.Fatalf("unexpected deployment: %+v", product.Spec.Deployment)
+	}
+	userKey := selfManaged.Authentication.UserKeyAuthentication
+	if userKey == nil || *userKey.Key != "api-key" || *userKey.CredentialsLoc != "headers" || *userKey.Security.SecretToken != "secret" || *userKey.GatewayResponse.ErrorStatusAuthFailed != 403 {
+		t.Fatalf("unexpected authentication: %+v", selfManaged.Authentication)
+	}
+
+	if len(product.Spec.MappingRules) != 1 || product.Spec.MappingRules[0].MetricMethodRef != "bytes" || product.Spec.MappingRules[0].Increment != 5 {
+		t.Fatalf("unexpected product mapping rules: %+v", product.Spec.MappingRules)
+	}
+
+	plan, ok := product.Spec.ApplicationPlans["basic"]
+	if !ok {
+		t.Fatalf("plan not exported: %v", product.Spec.ApplicationPlans)
+	}
+	if *plan.Name != "Basic" || *plan.SetupFee != "1.50" || *plan.CostMonth != "10" || *plan.TrialPeriod != 7 || !plan.IsPublished() {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+
+	backendSystemName := "backend_one"
+	expectedLimits := []capabilitiesv1beta1.LimitSpec{
+		{Period: "day", Value: 100, MetricMethodRef: capabilitiesv1beta1.MetricMethodRefSpec{SystemName: "get_pets", BackendSystemName: &backendSystemName}},
+		{Period: "month", Value: 10, MetricMethodRef: capabilitiesv1beta1.MetricMethodRefSpec{SystemName: "bytes"}},
+	}
+	if !reflect.DeepEqual(plan.Limits, expectedLimits) {
+		t.Fatalf("unexpected limits: %+v", plan.Limits)
+	}
+
+	expectedPricingRules := []capabilitiesv1beta1.PricingRuleSpec{
+		{From: 1, To: 100, PricePerUnit: "0.10", MetricMethodRef: capabilitiesv1beta1.MetricMethodRefSpec{SystemName: "hits", BackendSystemName: &backendSystemName}},
+	}
+	if !reflect.DeepEqual(plan.PricingRules, expectedPricingRules) {
+		t.Fatalf("unexpected pricing rules: %+v", plan.PricingRules)
+	}
+
+	if len(product.Spec.Policies) != 1 || product.Spec.Policies[0].Name != "apicast" || string(product.Spec.Policies[0].Configuration.Raw) != "{}" {
+		t.Fatalf("unexpected policies: %+v", product.Spec.Policies)
+	}
+
+	if fieldErrors := product.Validate(); len(fieldErrors) > 0 {
+		t.Fatalf("exported product not valid: %v", fieldErrors)
+	}
+	if fieldErrors := backend.Validate(); len(fieldErrors) > 0 {
+		t.Fatalf("exported backend not valid: %v", fieldErrors)
+	}
+
+	// all backends are exported without product filter
+	backends, _, err = exporter.Export(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backends) != 2 {
+		t.Fatalf("unexpected number of backends %d", len(backends))
+	}
+}
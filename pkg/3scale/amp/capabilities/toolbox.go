@@ -0,0 +1,561 @@
+package capabilities
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+)
+
+const (
+	toolboxListKind       = "List"
+	toolboxListAPIVersion = "v1"
+
+	toolboxPlanStatePublished = "published"
+	toolboxPlanStateHidden    = "hidden"
+)
+
+// ToolboxList is the document written by the 3scale toolbox "product export" command
+// and read by the "product import" command
+type ToolboxList struct {
+	APIVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Products   []ToolboxProduct `json:"-"`
+	Backends   []ToolboxBackend `json:"-"`
+}
+
+// ToolboxMetadata is the metadata of the toolbox documents
+type ToolboxMetadata struct {
+	Name        string            `json:"name"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ToolboxProduct is the product of the toolbox documents
+type ToolboxProduct struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Metadata   ToolboxMetadata    `json:"metadata"`
+	Spec       ToolboxProductSpec `json:"spec"`
+}
+
+// ToolboxProductSpec is the product spec of the toolbox documents
+type ToolboxProductSpec struct {
+	Name             string                                          `json:"name"`
+	SystemName       string                                          `json:"systemName"`
+	Description      string                                          `json:"description,omitempty"`
+	MappingRules     []capabilitiesv1beta1.MappingRuleSpec           `json:"mappingRules,omitempty"`
+	Metrics          map[string]capabilitiesv1beta1.MetricSpec       `json:"metrics,omitempty"`
+	Methods          map[string]capabilitiesv1beta1.MethodSpec       `json:"methods,omitempty"`
+	Policies         []capabilitiesv1beta1.PolicyConfig              `json:"policies,omitempty"`
+	ApplicationPlans map[string]ToolboxApplicationPlan               `json:"applicationPlans,omitempty"`
+	BackendUsages    map[string]capabilitiesv1beta1.BackendUsageSpec `json:"backendUsages,omitempty"`
+	Deployment       *capabilitiesv1beta1.ProductDeploymentSpec      `json:"deployment,omitempty"`
+}
+
+// ToolboxApplicationPlan is the application plan of the toolbox documents.
+// Prices are numbers and the plan state is "published" or "hidden"
+type ToolboxApplicationPlan struct {
+	Name                string                          `json:"name"`
+	AppsRequireApproval bool                            `json:"appsRequireApproval"`
+	TrialPeriod         int                             `json:"trialPeriod"`
+	SetupFee            float64                         `json:"setupFee"`
+	Custom              bool                            `json:"custom"`
+	State               string                          `json:"state"`
+	CostMonth           float64                         `json:"costMonth"`
+	PricingRules        []ToolboxPricingRule            `json:"pricingRules,omitempty"`
+	Limits              []capabilitiesv1beta1.LimitSpec `json:"limits,omitempty"`
+}
+
+// ToolboxPricingRule is the application plan pricing rule of the toolbox documents
+type ToolboxPricingRule struct {
+	From            int                                     `json:"from"`
+	To              int                                     `json:"to"`
+	PricePerUnit    float64                                 `json:"pricePerUnit"`
+	MetricMethodRef capabilitiesv1beta1.MetricMethodRefSpec `json:"metricMethodRef"`
+}
+
+// ToolboxBackend is the backend of the toolbox documents
+type ToolboxBackend struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Metadata   ToolboxMetadata    `json:"metadata"`
+	Spec       ToolboxBackendSpec `json:"spec"`
+}
+
+// ToolboxBackendSpec is the backend spec of the toolbox documents
+type ToolboxBackendSpec struct {
+	Name           string                                    `json:"name"`
+	SystemName     string                                    `json:"systemName"`
+	Description    string                                    `json:"description,omitempty"`
+	PrivateBaseURL string                                    `json:"privateBaseURL"`
+	MappingRules   []capabilitiesv1beta1.MappingRuleSpec     `json:"mappingRules,omitempty"`
+	Metrics        map[string]capabilitiesv1beta1.MetricSpec `json:"metrics,omitempty"`
+	Methods        map[string]capabilitiesv1beta1.MethodSpec `json:"methods,omitempty"`
+}
+
+// toolboxItem decodes the kind of the list items
+type toolboxItem struct {
+	Kind string `json:"kind"`
+}
+
+// toolboxListItems is the serialized ToolboxList with the products followed by the backends
+type toolboxListItems struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Items      []interface{} `json:"items"`
+}
+
+// MarshalJSON serializes the products and backends as the list items
+func (l ToolboxList) MarshalJSON() ([]byte, error) {
+	items := make([]interface{}, 0, len(l.Products)+len(l.Backends))
+	for idx := range l.Products {
+		items = append(items, l.Products[idx])
+	}
+	for idx := range l.Backends {
+		items = append(items, l.Backends[idx])
+	}
+
+	return json.Marshal(toolboxListItems{APIVersion: l.APIVersion, Kind: l.Kind, Items: items})
+}
+
+// ReadToolboxList reads the products and backends of the toolbox documents.
+// The documents are lists or single products and backends.
+func ReadToolboxList(r io.Reader) (*ToolboxList, error) {
+	list := &ToolboxList{APIVersion: toolboxListAPIVersion, Kind: toolboxListKind}
+
+	var addItem func(raw []byte) error
+	addItem = func(raw []byte) error {
+		item := &toolboxItem{}
+		err := json.Unmarshal(raw, item)
+		if err != nil {
+			return err
+		}
+
+		switch item.Kind {
+		case toolboxListKind:
+			items := &struct {
+				Items []json.RawMessage `json:"items"`
+			}{}
+			err = json.Unmarshal(raw, items)
+			if err != nil {
+				return err
+			}
+			for _, itemRaw := range items.Items {
+				err = addItem(itemRaw)
+				if err != nil {
+					return err
+				}
+			}
+		case capabilitiesv1beta1.ProductKind:
+			product := ToolboxProduct{}
+			err = json.Unmarshal(raw, &product)
+			if err != nil {
+				return fmt.Errorf("toolbox product: %w", err)
+			}
+			list.Products = append(list.Products, product)
+		case capabilitiesv1beta1.BackendKind:
+			backend := ToolboxBackend{}
+			err = json.Unmarshal(raw, &backend)
+			if err != nil {
+				return fmt.Errorf("toolbox backend: %w", err)
+			}
+			list.Backends = append(list.Backends, backend)
+		default:
+			return fmt.Errorf("toolbox document kind %q not supported", item.Kind)
+		}
+
+		return nil
+	}
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		raw := json.RawMessage{}
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// empty documents
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		err = addItem(raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+// ToolboxToCRs converts the toolbox products and backends into Product and Backend custom resources.
+// Backend references of backend usages and metric references are resolved by the backend system name
+// or document name, and must be defined in the list. Custom application plans are not converted.
+func ToolboxToCRs(list *ToolboxList, namespace string, providerAccountRef *capabilitiesv1beta1.ProviderAccountReference) ([]capabilitiesv1beta1.Backend, []capabilitiesv1beta1.Product, error) {
+	backendIndex := map[string]*ToolboxBackend{}
+	backends := make([]capabilitiesv1beta1.Backend, 0, len(list.Backends))
+	for idx := range list.Backends {
+		toolboxBackend := &list.Backends[idx]
+		backendIndex[toolboxBackend.Spec.SystemName] = toolboxBackend
+		if toolboxBackend.Metadata.Name != "" {
+			backendIndex[toolboxBackend.Metadata.Name] = toolboxBackend
+		}
+
+		backends = append(backends, capabilitiesv1beta1.Backend{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       capabilitiesv1beta1.BackendKind,
+				APIVersion: capabilitiesv1beta1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: ObjectName(toolboxBackend.Spec.SystemName), Namespace: namespace},
+			Spec: capabilitiesv1beta1.BackendSpec{
+				Name:               toolboxBackend.Spec.Name,
+				SystemName:         toolboxBackend.Spec.SystemName,
+				Description:        toolboxBackend.Spec.Description,
+				PrivateBaseURL:     toolboxBackend.Spec.PrivateBaseURL,
+				MappingRules:       toolboxBackend.Spec.MappingRules,
+				Metrics:            toolboxBackend.Spec.Metrics,
+				Methods:            toolboxBackend.Spec.Methods,
+				ProviderAccountRef: providerAccountRef,
+			},
+		})
+	}
+
+	products := make([]capabilitiesv1beta1.Product, 0, len(list.Products))
+	for idx := range list.Products {
+		product, err := toolboxProductToCR(&list.Products[idx], backendIndex)
+		if err != nil {
+			return nil, nil, err
+		}
+		product.Namespace = namespace
+		product.Spec.ProviderAccountRef = providerAccountRef
+		products = append(products, *product)
+	}
+
+	return backends, products, nil
+}
+
+func toolboxProductToCR(toolboxProduct *ToolboxProduct, backendIndex map[string]*ToolboxBackend) (*capabilitiesv1beta1.Product, error) {
+	systemName := toolboxProduct.Spec.SystemName
+
+	var backendUsages map[string]capabilitiesv1beta1.BackendUsageSpec
+	// backend system names of the backend references
+	usedBackends := map[string]*ToolboxBackend{}
+	for backendRef, backendUsage := range toolboxProduct.Spec.BackendUsages {
+		toolboxBackend, ok := backendIndex[backendRef]
+		if !ok {
+			return nil, fmt.Errorf("product [%s] backend usage: backend %s not found", systemName, backendRef)
+		}
+
+		if backendUsages == nil {
+			backendUsages = map[string]capabilitiesv1beta1.BackendUsageSpec{}
+		}
+		backendUsages[toolboxBackend.Spec.SystemName] = backendUsage
+		usedBackends[backendRef] = toolboxBackend
+		usedBackends[toolboxBackend.Spec.SystemName] = toolboxBackend
+	}
+
+	resolveMetricRef := func(ref capabilitiesv1beta1.MetricMethodRefSpec) (capabilitiesv1beta1.MetricMethodRefSpec, error) {
+		if ref.BackendSystemName == nil {
+			if !toolboxMetricOrMethodExists(ref.SystemName, toolboxProduct.Spec.Metrics, toolboxProduct.Spec.Methods) {
+				return ref, fmt.Errorf("product [%s] metric or method %s not found", systemName, ref.SystemName)
+			}
+			return ref, nil
+		}
+
+		toolboxBackend, ok := usedBackends[*ref.BackendSystemName]
+		if !ok {
+			return ref, fmt.Errorf("product [%s] metric %s: backend %s not used by the product", systemName, ref.SystemName, *ref.BackendSystemName)
+		}
+		if !toolboxMetricOrMethodExists(ref.SystemName, toolboxBackend.Spec.Metrics, toolboxBackend.Spec.Methods) {
+			return ref, fmt.Errorf("product [%s] metric or method %s not found in backend %s", systemName, ref.SystemName, toolboxBackend.Spec.SystemName)
+		}
+
+		backendSystemName := toolboxBackend.Spec.SystemName
+		return capabilitiesv1beta1.MetricMethodRefSpec{SystemName: ref.SystemName, BackendSystemName: &backendSystemName}, nil
+	}
+
+	var applicationPlans map[string]capabilitiesv1beta1.ApplicationPlanSpec
+	for planSystemName, toolboxPlan := range toolboxProduct.Spec.ApplicationPlans {
+		if toolboxPlan.Custom {
+			continue
+		}
+
+		name := toolboxPlan.Name
+		appsRequireApproval := toolboxPlan.AppsRequireApproval
+		trialPeriod := toolboxPlan.TrialPeriod
+		setupFee := priceString(toolboxPlan.SetupFee)
+		costMonth := priceString(toolboxPlan.CostMonth)
+		published := toolboxPlan.State == toolboxPlanStatePublished
+
+		planSpec := capabilitiesv1beta1.ApplicationPlanSpec{
+			Name:                &name,
+			AppsRequireApproval: &appsRequireApproval,
+			TrialPeriod:         &trialPeriod,
+			SetupFee:            &setupFee,
+			CostMonth:           &costMonth,
+			Published:           &published,
+		}
+
+		for _, limit := range toolboxPlan.Limits {
+			metricRef, err := resolveMetricRef(limit.MetricMethodRef)
+			if err != nil {
+				return nil, fmt.Errorf("plan [%s] limit: %w", planSystemName, err)
+			}
+			limit.MetricMethodRef = metricRef
+			planSpec.Limits = append(planSpec.Limits, limit)
+		}
+
+		for _, pricingRule := range toolboxPlan.PricingRules {
+			metricRef, err := resolveMetricRef(pricingRule.MetricMethodRef)
+			if err != nil {
+				return nil, fmt.Errorf("plan [%s] pricing rule: %w", planSystemName, err)
+			}
+			planSpec.PricingRules = append(planSpec.PricingRules, capabilitiesv1beta1.PricingRuleSpec{
+				From:            pricingRule.From,
+				To:              pricingRule.To,
+				PricePerUnit:    priceString(pricingRule.PricePerUnit),
+				MetricMethodRef: metricRef,
+			})
+		}
+
+		if applicationPlans == nil {
+			applicationPlans = map[string]capabilitiesv1beta1.ApplicationPlanSpec{}
+		}
+		applicationPlans[planSystemName] = planSpec
+	}
+
+	return &capabilitiesv1beta1.Product{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       capabilitiesv1beta1.ProductKind,
+			APIVersion: capabilitiesv1beta1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: ObjectName(systemName)},
+		Spec: capabilitiesv1beta1.ProductSpec{
+			Name:             toolboxProduct.Spec.Name,
+			SystemName:       systemName,
+			Description:      toolboxProduct.Spec.Description,
+			Deployment:       toolboxProduct.Spec.Deployment,
+			MappingRules:     toolboxProduct.Spec.MappingRules,
+			BackendUsages:    backendUsages,
+			Metrics:          toolboxProduct.Spec.Metrics,
+			Methods:          toolboxProduct.Spec.Methods,
+			ApplicationPlans: applicationPlans,
+			Policies:         toolboxProduct.Spec.Policies,
+		},
+	}, nil
+}
+
+// CRsToToolbox converts the Product and Backend custom resources into a toolbox list.
+// The backends used by the products must be in the backends list.
+func CRsToToolbox(backends []capabilitiesv1beta1.Backend, products []capabilitiesv1beta1.Product) (*ToolboxList, error) {
+	backendIndex := map[string]bool{}
+	list := &ToolboxList{APIVersion: toolboxListAPIVersion, Kind: toolboxListKind}
+	for idx := range backends {
+		backend := &backends[idx]
+		backendIndex[backend.Spec.SystemName] = true
+		// backends referencing a service have the private base URL resolved in the status
+		privateBaseURL := backend.Spec.PrivateBaseURL
+		if backend.Spec.PrivateServiceRef != nil {
+			if backend.Status.PrivateBaseURL == "" {
+				return nil, fmt.Errorf("backend '%s' private service reference not resolved yet", backend.Name)
+			}
+			privateBaseURL = backend.Status.PrivateBaseURL
+		}
+		list.Backends = append(list.Backends, ToolboxBackend{
+			APIVersion: capabilitiesv1beta1.GroupVersion.String(),
+			Kind:       capabilitiesv1beta1.BackendKind,
+			Metadata:   ToolboxMetadata{Name: backend.Name},
+			Spec: ToolboxBackendSpec{
+				Name:           backend.Spec.Name,
+				SystemName:     backend.Spec.SystemName,
+				Description:    backend.Spec.Description,
+				PrivateBaseURL: privateBaseURL,
+				MappingRules:   backend.Spec.MappingRules,
+				Metrics:        backend.Spec.Metrics,
+				Methods:        backend.Spec.Methods,
+			},
+		})
+	}
+
+	for idx := range products {
+		product := &products[idx]
+		for backendSystemName := range product.Spec.BackendUsages {
+			if !backendIndex[backendSystemName] {
+				return nil, fmt.Errorf("product [%s] backend usage: backend %s not found", product.Spec.SystemName, backendSystemName)
+			}
+		}
+
+		applicationPlans, err := toolboxApplicationPlans(product)
+		if err != nil {
+			return nil, err
+		}
+
+		list.Products = append(list.Products, ToolboxProduct{
+			APIVersion: capabilitiesv1beta1.GroupVersion.String(),
+			Kind:       capabilitiesv1beta1.ProductKind,
+			Metadata:   ToolboxMetadata{Name: product.Name},
+			Spec: ToolboxProductSpec{
+				Name:             product.Spec.Name,
+				SystemName:       product.Spec.SystemName,
+				Description:      product.Spec.Description,
+				MappingRules:     product.Spec.MappingRules,
+				Metrics:          product.Spec.Metrics,
+				Methods:          product.Spec.Methods,
+				Policies:         product.Spec.Policies,
+				ApplicationPlans: applicationPlans,
+				BackendUsages:    product.Spec.BackendUsages,
+				Deployment:       product.Spec.Deployment,
+			},
+		})
+	}
+
+	return list, nil
+}
+
+func toolboxApplicationPlans(product *capabilitiesv1beta1.Product) (map[string]ToolboxApplicationPlan, error) {
+	if len(product.Spec.ApplicationPlans) == 0 {
+		return nil, nil
+	}
+
+	parsePrice := func(planSystemName string, price *string) (float64, error) {
+		if price == nil {
+			return 0, nil
+		}
+		value, err := strconv.ParseFloat(*price, 64)
+		if err != nil {
+			return 0, fmt.Errorf("product [%s] plan [%s]: %w", product.Spec.SystemName, planSystemName, err)
+		}
+		return value, nil
+	}
+
+	// sorted for deterministic errors
+	planSystemNames := make([]string, 0, len(product.Spec.ApplicationPlans))
+	for planSystemName := range product.Spec.ApplicationPlans {
+		planSystemNames = append(planSystemNames, planSystemName)
+	}
+	sort.Strings(planSystemNames)
+
+	plans := map[string]ToolboxApplicationPlan{}
+	for _, planSystemName := range planSystemNames {
+		planSpec := product.Spec.ApplicationPlans[planSystemName]
+
+		setupFee, err := parsePrice(planSystemName, planSpec.SetupFee)
+		if err != nil {
+			return nil, err
+		}
+
+		costMonth, err := parsePrice(planSystemName, planSpec.CostMonth)
+		if err != nil {
+			return nil, err
+		}
+
+		plan := ToolboxApplicationPlan{
+			Name:      planSystemName,
+			SetupFee:  setupFee,
+			CostMonth: costMonth,
+			State:     toolboxPlanStateHidden,
+			Limits:    planSpec.Limits,
+		}
+		if planSpec.Name != nil {
+			plan.Name = *planSpec.Name
+		}
+		if planSpec.AppsRequireApproval != nil {
+			plan.AppsRequireApproval = *planSpec.AppsRequireApproval
+		}
+		if planSpec.TrialPeriod != nil {
+			plan.TrialPeriod = *planSpec.TrialPeriod
+		}
+		if planSpec.IsPublished() {
+			plan.State = toolboxPlanStatePublished
+		}
+
+		for _, pricingRule := range planSpec.PricingRules {
+			pricePerUnit, err := parsePrice(planSystemName, &pricingRule.PricePerUnit)
+			if err != nil {
+				return nil, err
+			}
+			plan.PricingRules = append(plan.PricingRules, ToolboxPricingRule{
+				From:            pricingRule.From,
+				To:              pricingRule.To,
+				PricePerUnit:    pricePerUnit,
+				MetricMethodRef: pricingRule.MetricMethodRef,
+			})
+		}
+
+		plans[planSystemName] = plan
+	}
+
+	return plans, nil
+}
+
+// ReadCRs reads the Product and Backend custom resources of the YAML or JSON documents.
+// Other kinds are ignored.
+func ReadCRs(r io.Reader) ([]capabilitiesv1beta1.Backend, []capabilitiesv1beta1.Product, error) {
+	backends := make([]capabilitiesv1beta1.Backend, 0)
+	products := make([]capabilitiesv1beta1.Product, 0)
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		raw := json.RawMessage{}
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		item := &toolboxItem{}
+		err = json.Unmarshal(raw, item)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch item.Kind {
+		case capabilitiesv1beta1.ProductKind:
+			product := capabilitiesv1beta1.Product{}
+			err = json.Unmarshal(raw, &product)
+			if err != nil {
+				return nil, nil, err
+			}
+			products = append(products, product)
+		case capabilitiesv1beta1.BackendKind:
+			backend := capabilitiesv1beta1.Backend{}
+			err = json.Unmarshal(raw, &backend)
+			if err != nil {
+				return nil, nil, err
+			}
+			backends = append(backends, backend)
+		}
+	}
+
+	return backends, products, nil
+}
+
+func toolboxMetricOrMethodExists(systemName string, metrics map[string]capabilitiesv1beta1.MetricSpec, methods map[string]capabilitiesv1beta1.MethodSpec) bool {
+	// hits metric is created by 3scale
+	if systemName == "hits" {
+		return true
+	}
+
+	if _, ok := metrics[systemName]; ok {
+		return true
+	}
+
+	_, ok := methods[systemName]
+	return ok
+}
@@ -0,0 +1,278 @@
+package capabilities
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+)
+
+// toolboxTestDocument is a toolbox product export with backends referenced by the document name
+const toolboxTestDocument = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: capabilities.3scale.net/v1beta1
+  kind: Product
+  metadata:
+    name: api.yzxizxuy
+  spec:
+    name: Petstore
+    systemName: petstore
+    description: pets
+    mappingRules:
+    - httpMethod: GET
+      pattern: "/"
+      metricMethodRef: hits
+      increment: 1
+      last: false
+    metrics:
+      hits:
+        friendlyName: Hits
+        unit: hit
+        description: Number of API hits
+    methods:
+      list_pets:
+        friendlyName: List pets
+    policies:
+    - name: apicast
+      version: builtin
+      configuration: {}
+      enabled: true
+    applicationPlans:
+      basic:
+        name: Basic
+        appsRequireApproval: false
+        trialPeriod: 0
+        setupFee: 1.5
+        custom: false
+        state: published
+        costMonth: 10.0
+        pricingRules:
+        - from: 1
+          to: 100
+          pricePerUnit: 0.1
+          metricMethodRef:
+            systemName: get_pet
+            backend: backend.abcdef
+        limits:
+        - period: day
+          value: 100
+          metricMethodRef:
+            systemName: list_pets
+      custom_plan:
+        name: Custom
+        custom: true
+        state: hidden
+    backendUsages:
+      backend.abcdef:
+        path: "/v1"
+    deployment:
+      apicastHosted:
+        authentication:
+          userkey:
+            authUserKey: user_key
+            credentials: query
+---
+apiVersion: capabilities.3scale.net/v1beta1
+kind: Backend
+metadata:
+  name: backend.abcdef
+spec:
+  name: Pets Backend
+  systemName: pets_backend
+  privateBaseURL: https://pets.example.com
+  mappingRules:
+  - httpMethod: GET
+    pattern: "/pet/{id}"
+    metricMethodRef: get_pet
+    increment: 1
+    last: true
+  metrics:
+    hits:
+      friendlyName: Hits
+      unit: hit
+  methods:
+    get_pet:
+      friendlyName: Get pet
+`
+
+func TestToolboxToCRs(t *testing.T) {
+	list, err := ReadToolboxList(strings.NewReader(toolboxTestDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Products) != 1 || len(list.Backends) != 1 {
+		t.Fatalf("unexpected number of products %d and backends %d", len(list.Products), len(list.Backends))
+	}
+
+	providerAccountRef := &capabilitiesv1beta1.ProviderAccountReference{Name: "mytenant"}
+	backends, products, err := ToolboxToCRs(list, "ns", providerAccountRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend := backends[0]
+	if backend.Name != "pets-backend" || backend.Namespace != "ns" || backend.Spec.ProviderAccountRef != providerAccountRef {
+		t.Fatalf("unexpected backend: %v %+v", backend.ObjectMeta, backend.Spec)
+	}
+
+	product := products[0]
+	if product.Name != "petstore" || product.Namespace != "ns" || product.Spec.ProviderAccountRef != providerAccountRef {
+		t.Fatalf("unexpected product: %v %+v", product.ObjectMeta, product.Spec)
+	}
+
+	if !reflect.DeepEqual(product.Spec.BackendUsages, map[string]capabilitiesv1beta1.BackendUsageSpec{"pets_backend": {Path: "/v1"}}) {
+		t.Fatalf("backend usage not resolved: %v", product.Spec.BackendUsages)
+	}
+
+	if _, ok := product.Spec.ApplicationPlans["custom_plan"]; ok || len(product.Spec.ApplicationPlans) != 1 {
+		t.Fatalf("unexpected plans: %v", product.Spec.ApplicationPlans)
+	}
+
+	plan := product.Spec.ApplicationPlans["basic"]
+	if *plan.Name != "Basic" || *plan.SetupFee != "1.50" || *plan.CostMonth != "10" || !plan.IsPublished() {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+
+	backendSystemName := "pets_backend"
+	expectedPricingRules := []capabilitiesv1beta1.PricingRuleSpec{
+		{From: 1, To: 100, PricePerUnit: "0.10", MetricMethodRef: capabilitiesv1beta1.MetricMethodRefSpec{SystemName: "get_pet", BackendSystemName: &backendSystemName}},
+	}
+	if !reflect.DeepEqual(plan.PricingRules, expectedPricingRules) {
+		t.Fatalf("unexpected pricing rules: %+v", plan.PricingRules)
+	}
+
+	if fieldErrors := product.Validate(); len(fieldErrors) > 0 {
+		t.Fatalf("product not valid: %v", fieldErrors)
+	}
+	if fieldErrors := backend.Validate(); len(fieldErrors) > 0 {
+		t.Fatalf("backend not valid: %v", fieldErrors)
+	}
+}
+
+func TestToolboxToCRsUnknownReferences(t *testing.T) {
+	cases := []struct {
+		name     string
+		document string
+	}{
+		{"unknown backend usage", strings.Replace(toolboxTestDocument, "backend.abcdef:\n        path", "other:\n        path", 1)},
+		{"unknown backend metric", strings.Replace(toolboxTestDocument, "systemName: get_pet\n", "systemName: delete_pet\n", 1)},
+		{"unknown product metric", strings.Replace(toolboxTestDocument, "systemName: list_pets", "systemName: update_pets", 1)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			list, err := ReadToolboxList(strings.NewReader(tc.document))
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			_, _, err = ToolboxToCRs(list, "ns", nil)
+			if err == nil {
+				subT.Fatal("expected reference error")
+			}
+		})
+	}
+}
+
+func TestToolboxRoundTrip(t *testing.T) {
+	list, err := ReadToolboxList(strings.NewReader(toolboxTestDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backends, products, err := ToolboxToCRs(list, "ns", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// toolbox -> CR -> toolbox
+	exported, err := CRsToToolbox(backends, products)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := yaml.Marshal(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reimported, err := ReadToolboxList(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// CR -> toolbox -> CR
+	roundTripBackends, roundTripProducts, err := ToolboxToCRs(reimported, "ns", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(roundTripBackends, backends) {
+		t.Fatalf("backends changed: got %+v, expected %+v", roundTripBackends, backends)
+	}
+	if !reflect.DeepEqual(roundTripProducts, products) {
+		t.Fatalf("products changed: got %+v, expected %+v", roundTripProducts, products)
+	}
+
+	// the toolbox documents only differ in the document names, the backend references
+	// and the custom plans not converted into custom resources
+	expected := list.Products[0].Spec
+	expected.BackendUsages = map[string]capabilitiesv1beta1.BackendUsageSpec{"pets_backend": {Path: "/v1"}}
+	delete(expected.ApplicationPlans, "custom_plan")
+	basic := expected.ApplicationPlans["basic"]
+	basic.PricingRules[0].MetricMethodRef.BackendSystemName = &reimported.Backends[0].Spec.SystemName
+	expected.ApplicationPlans["basic"] = basic
+	if !reflect.DeepEqual(reimported.Products[0].Spec, expected) {
+		t.Fatalf("product changed: got %+v, expected %+v", reimported.Products[0].Spec, expected)
+	}
+	if !reflect.DeepEqual(reimported.Backends[0].Spec, list.Backends[0].Spec) {
+		t.Fatalf("backend changed: got %+v, expected %+v", reimported.Backends[0].Spec, list.Backends[0].Spec)
+	}
+}
+
+func TestCRsToToolboxMissingBackend(t *testing.T) {
+	products := []capabilitiesv1beta1.Product{
+		{
+			Spec: capabilitiesv1beta1.ProductSpec{
+				SystemName:    "product",
+				BackendUsages: map[string]capabilitiesv1beta1.BackendUsageSpec{"missing": {Path: "/"}},
+			},
+		},
+	}
+
+	_, err := CRsToToolbox(nil, products)
+	if err == nil {
+		t.Fatal("expected missing backend error")
+	}
+}
+
+func TestCRsToToolboxUnresolvedPrivateService(t *testing.T) {
+	backends := []capabilitiesv1beta1.Backend{
+		{
+			Spec: capabilitiesv1beta1.BackendSpec{
+				SystemName:        "backend",
+				PrivateServiceRef: &capabilitiesv1beta1.BackendPrivateServiceReference{Name: "echo-api", Port: intstr.FromString("http")},
+			},
+		},
+	}
+
+	_, err := CRsToToolbox(backends, nil)
+	if err == nil {
+		t.Fatal("expected unresolved private service error")
+	}
+
+	backends[0].Status.PrivateBaseURL = "http://echo-api.ns.svc.cluster.local:8080"
+	exported, err := CRsToToolbox(backends, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.Backends[0].Spec.PrivateBaseURL != backends[0].Status.PrivateBaseURL {
+		t.Fatalf("unexpected private base URL: %s", exported.Backends[0].Spec.PrivateBaseURL)
+	}
+}
@@ -0,0 +1,122 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/capabilities"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+)
+
+var (
+	capabilitiesAdminURL           string
+	capabilitiesToken              string
+	capabilitiesNamespace          string
+	capabilitiesProviderAccountRef string
+	capabilitiesInsecureSkipVerify bool
+)
+
+var capabilitiesCmd = &cobra.Command{
+	Use:   getCapabilitiesUsage(),
+	Short: getCapabilitiesShortDescription(),
+	Long:  getCapabilitiesLongDescription(),
+	RunE:  runCapabilitiesCommand,
+}
+
+func getCapabilitiesUsage() string {
+	return "capabilities [product-system-name...]"
+}
+
+func getCapabilitiesShortDescription() string {
+	return "generate Product and Backend custom resources from 3scale"
+}
+
+func getCapabilitiesLongDescription() string {
+	return `generate Product and Backend custom resources from the products and backends of a 3scale account.
+When product system names are given, only those products and the backends they use are generated.`
+}
+
+func runCapabilitiesCommand(cmd *cobra.Command, args []string) error {
+	logger := zap.New(zap.WriteTo(os.Stderr))
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(&controllerhelper.ProviderAccount{
+		AdminURLStr:        capabilitiesAdminURL,
+		Token:              capabilitiesToken,
+		InsecureSkipVerify: capabilitiesInsecureSkipVerify,
+	})
+	if err != nil {
+		return err
+	}
+
+	var providerAccountRef *capabilitiesv1beta1.ProviderAccountReference
+	if capabilitiesProviderAccountRef != "" {
+		providerAccountRef = &capabilitiesv1beta1.ProviderAccountReference{Name: capabilitiesProviderAccountRef}
+	}
+
+	exporter := capabilities.NewExporter(threescaleAPIClient, capabilitiesNamespace, providerAccountRef, logger)
+	backends, products, err := exporter.Export(args)
+	if err != nil {
+		return err
+	}
+
+	objects := make([]runtime.Object, 0, len(backends)+len(products))
+	for idx := range backends {
+		objects = append(objects, &backends[idx])
+	}
+	for idx := range products {
+		objects = append(objects, &products[idx])
+	}
+
+	return encodeObjects(objects, os.Stdout)
+}
+
+// encodeObjects writes the objects as a multi document YAML stream
+func encodeObjects(objects []runtime.Object, w io.Writer) error {
+	serializer := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil,
+		json.SerializerOptions{Yaml: true, Pretty: true, Strict: true})
+
+	for idx, obj := range objects {
+		if idx > 0 {
+			if _, err := fmt.Fprintln(w, "---"); err != nil {
+				return err
+			}
+		}
+
+		err := serializer.Encode(obj, w)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	capabilitiesCmd.PersistentFlags().StringVar(&capabilitiesAdminURL, "admin-url", "", "3scale admin portal URL")
+	capabilitiesCmd.PersistentFlags().StringVar(&capabilitiesToken, "token", "", "3scale access token")
+	capabilitiesCmd.PersistentFlags().StringVar(&capabilitiesNamespace, "namespace", "", "Namespace of the generated custom resources")
+	capabilitiesCmd.PersistentFlags().StringVar(&capabilitiesProviderAccountRef, "provider-account-ref", "", "Provider account secret referenced by the generated custom resources")
+	capabilitiesCmd.PersistentFlags().BoolVar(&capabilitiesInsecureSkipVerify, "insecure-skip-verify", false, "Skip the verification of the admin portal certificate")
+	capabilitiesCmd.MarkPersistentFlagRequired("admin-url")
+	capabilitiesCmd.MarkPersistentFlagRequired("token")
+	rootCmd.AddCommand(capabilitiesCmd)
+}
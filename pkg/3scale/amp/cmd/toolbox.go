@@ -0,0 +1,132 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/capabilities"
+)
+
+var (
+	toolboxFile               string
+	toolboxNamespace          string
+	toolboxProviderAccountRef string
+)
+
+var toolboxCmd = &cobra.Command{
+	Use:   "toolbox",
+	Short: "convert 3scale toolbox product exports to and from custom resources",
+	Long:  "convert 3scale toolbox product exports to and from Product and Backend custom resources",
+}
+
+var toolboxImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "generate Product and Backend custom resources from a toolbox product export",
+	Long: `generate Product and Backend custom resources from the output of the 3scale toolbox "product export" command.
+Backend references are resolved by the backend system name or document name. Custom application plans are not generated.`,
+	Args: cobra.NoArgs,
+	RunE: runToolboxImportCommand,
+}
+
+var toolboxExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "generate a toolbox product export from Product and Backend custom resources",
+	Long: `generate the input of the 3scale toolbox "product import" command from Product and Backend custom resources.
+The backends used by the products must be included in the input.`,
+	Args: cobra.NoArgs,
+	RunE: runToolboxExportCommand,
+}
+
+func runToolboxImportCommand(cmd *cobra.Command, args []string) error {
+	input, err := openToolboxInput()
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	list, err := capabilities.ReadToolboxList(input)
+	if err != nil {
+		return err
+	}
+
+	var providerAccountRef *capabilitiesv1beta1.ProviderAccountReference
+	if toolboxProviderAccountRef != "" {
+		providerAccountRef = &capabilitiesv1beta1.ProviderAccountReference{Name: toolboxProviderAccountRef}
+	}
+
+	backends, products, err := capabilities.ToolboxToCRs(list, toolboxNamespace, providerAccountRef)
+	if err != nil {
+		return err
+	}
+
+	objects := make([]runtime.Object, 0, len(backends)+len(products))
+	for idx := range backends {
+		objects = append(objects, &backends[idx])
+	}
+	for idx := range products {
+		objects = append(objects, &products[idx])
+	}
+
+	return encodeObjects(objects, os.Stdout)
+}
+
+func runToolboxExportCommand(cmd *cobra.Command, args []string) error {
+	input, err := openToolboxInput()
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	backends, products, err := capabilities.ReadCRs(input)
+	if err != nil {
+		return err
+	}
+
+	list, err := capabilities.CRsToToolbox(backends, products)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// openToolboxInput opens the input file, or stdin when the file is "-"
+func openToolboxInput() (io.ReadCloser, error) {
+	if toolboxFile == "-" {
+		return os.Stdin, nil
+	}
+
+	return os.Open(toolboxFile)
+}
+
+func init() {
+	toolboxCmd.PersistentFlags().StringVarP(&toolboxFile, "file", "f", "-", "Input file, - for stdin")
+	toolboxImportCmd.Flags().StringVar(&toolboxNamespace, "namespace", "", "Namespace of the generated custom resources")
+	toolboxImportCmd.Flags().StringVar(&toolboxProviderAccountRef, "provider-account-ref", "", "Provider account secret referenced by the generated custom resources")
+	toolboxCmd.AddCommand(toolboxImportCmd)
+	toolboxCmd.AddCommand(toolboxExportCmd)
+	rootCmd.AddCommand(toolboxCmd)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/3scale/3scale-operator/pkg/helper"
 
 	appsv1 "github.com/openshift/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -477,6 +478,10 @@ func (apicast *Apicast) StagingPodDisruptionBudget() *v1beta1.PodDisruptionBudge
 	}
 }
 
+func (apicast *Apicast) ProductionHorizontalPodAutoscaler() *autoscalingv2beta2.HorizontalPodAutoscaler {
+	return horizontalPodAutoscaler(ApicastProductionName, apicast.Options.CommonProductionLabels, apicast.Options.ProductionHPA)
+}
+
 func (apicast *Apicast) ProductionPodDisruptionBudget() *v1beta1.PodDisruptionBudget {
 	return &v1beta1.PodDisruptionBudget{
 		TypeMeta: metav1.TypeMeta{
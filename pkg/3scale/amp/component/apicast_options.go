@@ -94,6 +94,8 @@ type ApicastOptions struct {
 	ProductionTracingConfig *APIcastTracingConfig `validate:"required"`
 	StagingTracingConfig    *APIcastTracingConfig `validate:"required"`
 
+	ProductionHPA *HorizontalPodAutoscalerOptions `validate:"-"`
+
 	ProductionCustomEnvironments []*v1.Secret `validate:"-"`
 	StagingCustomEnvironments    []*v1.Secret `validate:"-"`
 
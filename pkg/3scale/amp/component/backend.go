@@ -8,6 +8,7 @@ import (
 
 	appsv1 "github.com/openshift/api/apps/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -458,6 +459,14 @@ func (backend *Backend) ListenerSecret() *v1.Secret {
 	}
 }
 
+func (backend *Backend) ListenerHorizontalPodAutoscaler() *autoscalingv2beta2.HorizontalPodAutoscaler {
+	return horizontalPodAutoscaler(BackendListenerName, backend.Options.CommonListenerLabels, backend.Options.ListenerHPA)
+}
+
+func (backend *Backend) WorkerHorizontalPodAutoscaler() *autoscalingv2beta2.HorizontalPodAutoscaler {
+	return horizontalPodAutoscaler(BackendWorkerName, backend.Options.CommonWorkerLabels, backend.Options.WorkerHPA)
+}
+
 func (backend *Backend) WorkerPodDisruptionBudget() *v1beta1.PodDisruptionBudget {
 	return &v1beta1.PodDisruptionBudget{
 		TypeMeta: metav1.TypeMeta{
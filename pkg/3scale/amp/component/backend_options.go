@@ -37,6 +37,9 @@ type BackendOptions struct {
 	WorkerMetrics                bool
 	ListenerMetrics              bool
 
+	ListenerHPA *HorizontalPodAutoscalerOptions `validate:"-"`
+	WorkerHPA   *HorizontalPodAutoscalerOptions `validate:"-"`
+
 	// Used for monitoring objects
 	// Those objects are namespaced. However, objects includes labels, rules and expressions
 	// that need namespace filtering because they are "global" once imported
@@ -0,0 +1,245 @@
+package component
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeploymentHookType is the DeploymentConfig lifecycle hook run as a Job for the equivalent Deployment
+type DeploymentHookType string
+
+const (
+	// DeploymentPreHook runs before the pod template is rolled out
+	DeploymentPreHook DeploymentHookType = "pre"
+	// DeploymentPostHook runs once the pod template has been rolled out
+	DeploymentPostHook DeploymentHookType = "post"
+
+	// DeploymentHookLabelKey labels the hook jobs of a Deployment. The value is "<deployment>-<hook type>"
+	DeploymentHookLabelKey = "apps.3scale.net/deployment-hook"
+	// DeploymentHookHashAnnotation records on the Deployment the pod template hash its hooks ran for
+	DeploymentHookHashAnnotation = "apps.3scale.net/deployment-hook-hash"
+)
+
+// DeploymentFromDeploymentConfig renders the apps/v1 Deployment equivalent to
+// the given DeploymentConfig.
+//
+// Container images are resolved from the ImageStreamTag each image change
+// trigger points to. The images map is indexed by "<imagestream>:<tag>".
+//
+// Lifecycle hooks are not rendered, they are run as Jobs, see DeploymentHookJob.
+func DeploymentFromDeploymentConfig(dc *appsv1.DeploymentConfig, images map[string]string) (*k8sappsv1.Deployment, error) {
+	if dc.Spec.Template == nil {
+		return nil, fmt.Errorf("deploymentconfig %s has no pod template", dc.Name)
+	}
+
+	template := dc.Spec.Template.DeepCopy()
+
+	for _, trigger := range dc.Spec.Triggers {
+		if trigger.Type != appsv1.DeploymentTriggerOnImageChange || trigger.ImageChangeParams == nil {
+			continue
+		}
+
+		image, ok := images[trigger.ImageChangeParams.From.Name]
+		if !ok {
+			return nil, fmt.Errorf("deploymentconfig %s: no image found for %s '%s'",
+				dc.Name, trigger.ImageChangeParams.From.Kind, trigger.ImageChangeParams.From.Name)
+		}
+
+		for _, containerName := range trigger.ImageChangeParams.ContainerNames {
+			found := setContainerImage(template.Spec.InitContainers, containerName, image)
+			found = setContainerImage(template.Spec.Containers, containerName, image) || found
+			if !found {
+				return nil, fmt.Errorf("deploymentconfig %s: image change trigger container '%s' not found", dc.Name, containerName)
+			}
+		}
+	}
+
+	strategy, err := deploymentStrategy(dc)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := dc.Spec.Replicas
+
+	return &k8sappsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        dc.Name,
+			Namespace:   dc.Namespace,
+			Labels:      dc.Labels,
+			Annotations: dc.Annotations,
+		},
+		Spec: k8sappsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: dc.Spec.Selector,
+			},
+			Strategy:        strategy,
+			MinReadySeconds: dc.Spec.MinReadySeconds,
+			Template:        *template,
+		},
+	}, nil
+}
+
+func deploymentStrategy(dc *appsv1.DeploymentConfig) (k8sappsv1.DeploymentStrategy, error) {
+	strategy := k8sappsv1.DeploymentStrategy{}
+	switch dc.Spec.Strategy.Type {
+	case appsv1.DeploymentStrategyTypeRecreate:
+		strategy.Type = k8sappsv1.RecreateDeploymentStrategyType
+	case appsv1.DeploymentStrategyTypeRolling:
+		strategy.Type = k8sappsv1.RollingUpdateDeploymentStrategyType
+		strategy.RollingUpdate = &k8sappsv1.RollingUpdateDeployment{}
+		if dc.Spec.Strategy.RollingParams != nil {
+			strategy.RollingUpdate.MaxSurge = dc.Spec.Strategy.RollingParams.MaxSurge
+			strategy.RollingUpdate.MaxUnavailable = dc.Spec.Strategy.RollingParams.MaxUnavailable
+		}
+	default:
+		return strategy, fmt.Errorf("deploymentconfig %s: unsupported strategy type '%s'", dc.Name, dc.Spec.Strategy.Type)
+	}
+
+	return strategy, nil
+}
+
+// DeploymentConfigHook returns the ExecNewPod lifecycle hook of the DeploymentConfig. Nil when not set
+func DeploymentConfigHook(dc *appsv1.DeploymentConfig, hookType DeploymentHookType) *appsv1.LifecycleHook {
+	var pre, post *appsv1.LifecycleHook
+	switch {
+	case dc.Spec.Strategy.RecreateParams != nil:
+		pre, post = dc.Spec.Strategy.RecreateParams.Pre, dc.Spec.Strategy.RecreateParams.Post
+	case dc.Spec.Strategy.RollingParams != nil:
+		pre, post = dc.Spec.Strategy.RollingParams.Pre, dc.Spec.Strategy.RollingParams.Post
+	}
+
+	hook := pre
+	if hookType == DeploymentPostHook {
+		hook = post
+	}
+	if hook == nil || hook.ExecNewPod == nil {
+		return nil
+	}
+	return hook
+}
+
+// DeploymentHookJob renders the Job running the ExecNewPod lifecycle hook of the DeploymentConfig
+// with the pod template of the equivalent Deployment. The job name includes the hash of the pod template,
+// so the hook runs once for every change of the template instead of on every pod start.
+// Nil when the DeploymentConfig does not have the hook.
+func DeploymentHookJob(dc *appsv1.DeploymentConfig, deployment *k8sappsv1.Deployment, hookType DeploymentHookType) (*batchv1.Job, error) {
+	hook := DeploymentConfigHook(dc, hookType)
+	if hook == nil {
+		return nil, nil
+	}
+
+	hookContainer, err := deploymentHookContainer(dc.Name, hookType, hook.ExecNewPod, &deployment.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	templateHash, err := DeploymentHookHash(deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	// The hook pods do not get the deployment pod labels, so services do not select them
+	labels := map[string]string{DeploymentHookLabelKey: fmt.Sprintf("%s-%s", dc.Name, hookType)}
+	podSpec := deployment.Spec.Template.Spec.DeepCopy()
+	podSpec.InitContainers = nil
+	podSpec.Containers = []v1.Container{*hookContainer}
+	podSpec.RestartPolicy = v1.RestartPolicyNever
+
+	// Jobs with the Retry failure policy are retried with the job backoff
+	var backoffLimit *int32
+	if hook.FailurePolicy != appsv1.LifecycleHookFailurePolicyRetry {
+		backoffLimit = &[]int32{0}[0]
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s-hook-%s", dc.Name, hookType, templateHash),
+			Namespace: dc.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: backoffLimit,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       *podSpec,
+			},
+		},
+	}, nil
+}
+
+// DeploymentHookHash returns a short hash of the pod template of the Deployment
+func DeploymentHookHash(deployment *k8sappsv1.Deployment) (string, error) {
+	data, err := json.Marshal(deployment.Spec.Template)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])[:10], nil
+}
+
+// deploymentHookContainer builds the container running the hook command
+// with the same image, env and volume mounts as the container the hook refers to
+func deploymentHookContainer(dcName string, hookType DeploymentHookType, hook *appsv1.ExecNewPodHook, template *v1.PodTemplateSpec) (*v1.Container, error) {
+	var container *v1.Container
+	for idx := range template.Spec.Containers {
+		if template.Spec.Containers[idx].Name == hook.ContainerName {
+			container = template.Spec.Containers[idx].DeepCopy()
+			break
+		}
+	}
+
+	if container == nil {
+		return nil, fmt.Errorf("deploymentconfig %s: %s hook container '%s' not found", dcName, hookType, hook.ContainerName)
+	}
+
+	return &v1.Container{
+		Name:            fmt.Sprintf("%s-%s-hook", dcName, hookType),
+		Image:           container.Image,
+		ImagePullPolicy: container.ImagePullPolicy,
+		Command:         hook.Command,
+		Env:             mergeEnvVars(container.Env, hook.Env),
+		EnvFrom:         container.EnvFrom,
+		VolumeMounts:    container.VolumeMounts,
+		Resources:       container.Resources,
+	}, nil
+}
+
+// mergeEnvVars returns base env vars overridden by the ones in overrides
+func mergeEnvVars(base, overrides []v1.EnvVar) []v1.EnvVar {
+	result := append([]v1.EnvVar{}, base...)
+	for _, override := range overrides {
+		found := false
+		for idx := range result {
+			if result[idx].Name == override.Name {
+				result[idx] = override
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, override)
+		}
+	}
+	return result
+}
+
+func setContainerImage(containers []v1.Container, name, image string) bool {
+	for idx := range containers {
+		if containers[idx].Name == name {
+			containers[idx].Image = image
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package component
+
+import (
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HorizontalPodAutoscalerOptions holds the autoscaling settings of a DeploymentConfig.
+type HorizontalPodAutoscalerOptions struct {
+	MinReplicas int32
+	MaxReplicas int32
+	Metrics     []autoscalingv2beta2.MetricSpec
+}
+
+// horizontalPodAutoscaler renders the HorizontalPodAutoscaler targeting the named DeploymentConfig.
+// When opts is nil, only the object metadata is set.
+func horizontalPodAutoscaler(dcName string, labels map[string]string, opts *HorizontalPodAutoscalerOptions) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "HorizontalPodAutoscaler",
+			APIVersion: "autoscaling/v2beta2",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   dcName,
+			Labels: labels,
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: "apps.openshift.io/v1",
+				Kind:       "DeploymentConfig",
+				Name:       dcName,
+			},
+		},
+	}
+
+	if opts != nil {
+		hpa.Spec.MinReplicas = &opts.MinReplicas
+		hpa.Spec.MaxReplicas = opts.MaxReplicas
+		hpa.Spec.Metrics = opts.Metrics
+	}
+
+	return hpa
+}
@@ -43,3 +43,7 @@ func ZyncPostgreSQLImageURL() string {
 func OCCLIImageURL() string {
 	return "quay.io/openshift/origin-cli:4.7"
 }
+
+func AWSCLIImageURL() string {
+	return "amazon/aws-cli:2.2.5"
+}
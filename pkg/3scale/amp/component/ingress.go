@@ -0,0 +1,193 @@
+package component
+
+import (
+	"fmt"
+
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	BackendListenerIngressName   = "backend"
+	SystemMasterIngressName      = "system-master"
+	SystemProviderIngressName    = "system-provider"
+	SystemDeveloperIngressName   = "system-developer"
+	ApicastStagingIngressName    = ApicastStagingName
+	ApicastProductionIngressName = ApicastProductionName
+)
+
+const (
+	// IngressTenantLabelKey labels the Ingress objects of additional tenants with the tenant name
+	IngressTenantLabelKey = "apps.3scale.net/ingress-tenant"
+	// IngressProductLabelKey labels the Ingress objects of products with the product name
+	IngressProductLabelKey = "apps.3scale.net/ingress-product"
+)
+
+type Ingress struct {
+	Options *IngressOptions
+}
+
+func NewIngress(options *IngressOptions) *Ingress {
+	return &Ingress{Options: options}
+}
+
+func (ingress *Ingress) BackendListenerIngress() *networkingv1beta1.Ingress {
+	host := fmt.Sprintf("backend-%s.%s", ingress.Options.TenantName, ingress.Options.WildcardDomain)
+	return ingress.ingress(BackendListenerIngressName, host, BackendListenerName, "http",
+		ingress.labels("backend", "listener"), ingress.Options.TLSSecretName)
+}
+
+func (ingress *Ingress) SystemMasterIngress() *networkingv1beta1.Ingress {
+	host := fmt.Sprintf("%s.%s", ingress.Options.MasterName, ingress.Options.WildcardDomain)
+	return ingress.ingress(SystemMasterIngressName, host, "system-master", "http",
+		ingress.labels("system", "master-ui"), ingress.Options.TLSSecretName)
+}
+
+func (ingress *Ingress) SystemProviderIngress() *networkingv1beta1.Ingress {
+	host := fmt.Sprintf("%s-admin.%s", ingress.Options.TenantName, ingress.Options.WildcardDomain)
+	return ingress.ingress(SystemProviderIngressName, host, "system-provider", "http",
+		ingress.labels("system", "provider-ui"), ingress.Options.TLSSecretName)
+}
+
+func (ingress *Ingress) SystemDeveloperIngress() *networkingv1beta1.Ingress {
+	host := fmt.Sprintf("%s.%s", ingress.Options.TenantName, ingress.Options.WildcardDomain)
+	return ingress.ingress(SystemDeveloperIngressName, host, "system-developer", "http",
+		ingress.labels("system", "developer-ui"), ingress.Options.TLSSecretName)
+}
+
+func (ingress *Ingress) ApicastStagingIngress() *networkingv1beta1.Ingress {
+	host := fmt.Sprintf("api-%s-apicast-staging.%s", ingress.Options.TenantName, ingress.Options.WildcardDomain)
+	return ingress.ingress(ApicastStagingIngressName, host, ApicastStagingName, "gateway",
+		ingress.labels("apicast", "staging"), ingress.Options.TLSSecretName)
+}
+
+func (ingress *Ingress) ApicastProductionIngress() *networkingv1beta1.Ingress {
+	host := fmt.Sprintf("api-%s-apicast-production.%s", ingress.Options.TenantName, ingress.Options.WildcardDomain)
+	return ingress.ingress(ApicastProductionIngressName, host, ApicastProductionName, "gateway",
+		ingress.labels("apicast", "production"), ingress.Options.TLSSecretName)
+}
+
+// TenantIngresses returns the admin and developer portal Ingress objects of the additional tenants
+func (ingress *Ingress) TenantIngresses() []*networkingv1beta1.Ingress {
+	result := []*networkingv1beta1.Ingress{}
+
+	for _, tenant := range ingress.Options.Tenants {
+		tlsSecretName := ingress.Options.TLSSecretName
+		if tenant.TLSSecretName != nil {
+			tlsSecretName = tenant.TLSSecretName
+		}
+
+		labels := ingress.labels("system", "provider-ui")
+		labels[IngressTenantLabelKey] = tenant.Name
+		result = append(result, ingress.ingress(fmt.Sprintf("%s-%s", SystemProviderIngressName, tenant.Name),
+			tenant.AdminHost, "system-provider", "http", labels, tlsSecretName))
+
+		if tenant.DeveloperHost != nil && *tenant.DeveloperHost != "" {
+			labels := ingress.labels("system", "developer-ui")
+			labels[IngressTenantLabelKey] = tenant.Name
+			result = append(result, ingress.ingress(fmt.Sprintf("%s-%s", SystemDeveloperIngressName, tenant.Name),
+				*tenant.DeveloperHost, "system-developer", "http", labels, tlsSecretName))
+		}
+	}
+
+	return result
+}
+
+// ProductIngresses returns the APIcast staging and production Ingress objects of the product hosts.
+// Hosts already routed to APIcast by a previous product or by the default APIcast ingresses are skipped
+func (ingress *Ingress) ProductIngresses() []*networkingv1beta1.Ingress {
+	result := []*networkingv1beta1.Ingress{}
+
+	routedHosts := map[string]bool{
+		ingress.ApicastStagingIngress().Spec.Rules[0].Host:    true,
+		ingress.ApicastProductionIngress().Spec.Rules[0].Host: true,
+	}
+
+	for _, product := range ingress.Options.Products {
+		hosts := []struct {
+			host, ingressName, serviceName, element string
+		}{
+			{product.StagingHost, ApicastStagingIngressName, ApicastStagingName, "staging"},
+			{product.ProductionHost, ApicastProductionIngressName, ApicastProductionName, "production"},
+		}
+
+		for _, h := range hosts {
+			if h.host == "" || routedHosts[h.host] {
+				continue
+			}
+			routedHosts[h.host] = true
+
+			labels := ingress.labels("apicast", h.element)
+			labels[IngressProductLabelKey] = product.Name
+			result = append(result, ingress.ingress(fmt.Sprintf("%s-%s", h.ingressName, product.Name),
+				h.host, h.serviceName, "gateway", labels, ingress.Options.TLSSecretName))
+		}
+	}
+
+	return result
+}
+
+func (ingress *Ingress) labels(component, element string) map[string]string {
+	labels := map[string]string{}
+	for k, v := range ingress.Options.CommonLabels {
+		labels[k] = v
+	}
+	labels["threescale_component"] = component
+	labels["threescale_component_element"] = element
+	return labels
+}
+
+func (ingress *Ingress) ingress(name, host, serviceName, servicePort string, labels map[string]string, tlsSecretName *string) *networkingv1beta1.Ingress {
+	pathType := networkingv1beta1.PathTypePrefix
+
+	annotations := map[string]string{}
+	for k, v := range ingress.Options.Annotations {
+		annotations[k] = v
+	}
+
+	obj := &networkingv1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "networking.k8s.io/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			IngressClassName: ingress.Options.IngressClassName,
+			Rules: []networkingv1beta1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: networkingv1beta1.IngressRuleValue{
+						HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+							Paths: []networkingv1beta1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1beta1.IngressBackend{
+										ServiceName: serviceName,
+										ServicePort: intstr.FromString(servicePort),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if tlsSecretName != nil {
+		obj.Spec.TLS = []networkingv1beta1.IngressTLS{
+			{
+				Hosts:      []string{host},
+				SecretName: *tlsSecretName,
+			},
+		}
+	}
+
+	return obj
+}
@@ -0,0 +1,44 @@
+package component
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+type IngressOptions struct {
+	TenantName     string `validate:"required"`
+	WildcardDomain string `validate:"required"`
+	MasterName     string `validate:"required"`
+
+	IngressClassName *string           `validate:"-"`
+	Annotations      map[string]string `validate:"-"`
+	TLSSecretName    *string           `validate:"-"`
+
+	Tenants  []IngressTenantOptions  `validate:"-"`
+	Products []IngressProductOptions `validate:"-"`
+
+	CommonLabels map[string]string `validate:"required"`
+}
+
+// IngressTenantOptions holds the portal hosts of an additional tenant
+type IngressTenantOptions struct {
+	Name          string
+	AdminHost     string
+	DeveloperHost *string
+	TLSSecretName *string
+}
+
+// IngressProductOptions holds the APIcast hosts of a product
+type IngressProductOptions struct {
+	Name           string
+	StagingHost    string
+	ProductionHost string
+}
+
+func NewIngressOptions() *IngressOptions {
+	return &IngressOptions{}
+}
+
+func (i *IngressOptions) Validate() error {
+	validate := validator.New()
+	return validate.Struct(i)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/3scale/3scale-operator/pkg/helper"
 	appsv1 "github.com/openshift/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -1240,6 +1241,10 @@ func (system *System) SphinxDeploymentConfig() *appsv1.DeploymentConfig {
 	}
 }
 
+func (system *System) AppHorizontalPodAutoscaler() *autoscalingv2beta2.HorizontalPodAutoscaler {
+	return horizontalPodAutoscaler(SystemAppDeploymentName, system.Options.CommonAppLabels, system.Options.AppHPA)
+}
+
 func (system *System) AppPodDisruptionBudget() *v1beta1.PodDisruptionBudget {
 	return &v1beta1.PodDisruptionBudget{
 		TypeMeta: metav1.TypeMeta{
@@ -75,6 +75,8 @@ type SystemOptions struct {
 	SphinxAffinity     *v1.Affinity    `validate:"-"`
 	SphinxTolerations  []v1.Toleration `validate:"-"`
 
+	AppHPA *HorizontalPodAutoscalerOptions `validate:"-"`
+
 	CommonLabels             map[string]string `validate:"required"`
 	CommonAppLabels          map[string]string `validate:"required"`
 	AppPodTemplateLabels     map[string]string `validate:"required"`
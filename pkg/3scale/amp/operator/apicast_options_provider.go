@@ -82,6 +82,7 @@ func (a *ApicastOptionsProvider) GetApicastOptions() (*component.ApicastOptions,
 	a.setResourceRequirementsOptions()
 	a.setNodeAffinityAndTolerationsOptions()
 	a.setReplicas()
+	a.setHorizontalPodAutoscalerOptions()
 
 	err := a.setCustomPolicies()
 	if err != nil {
@@ -139,6 +140,10 @@ func (a *ApicastOptionsProvider) setNodeAffinityAndTolerationsOptions() {
 	a.apicastOptions.ProductionTolerations = a.apimanager.Spec.Apicast.ProductionSpec.Tolerations
 }
 
+func (a *ApicastOptionsProvider) setHorizontalPodAutoscalerOptions() {
+	a.apicastOptions.ProductionHPA = horizontalPodAutoscalerOptions(a.apimanager.Spec.Apicast.ProductionSpec.HPA)
+}
+
 func (a *ApicastOptionsProvider) setReplicas() {
 	a.apicastOptions.ProductionReplicas = int32(*a.apimanager.Spec.Apicast.ProductionSpec.Replicas)
 	a.apicastOptions.StagingReplicas = int32(*a.apimanager.Spec.Apicast.StagingSpec.Replicas)
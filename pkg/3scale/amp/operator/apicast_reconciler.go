@@ -113,7 +113,8 @@ func (r *ApicastReconciler) Reconcile() (reconcile.Result, error) {
 		apicastPodTemplateEnvConfigMapAnnotationsMutator,
 	}
 
-	if value, found := r.apiManager.ObjectMeta.Annotations[disableApicastProductionReplicaReconciler]; !found || value != "true" {
+	// Replicas are managed by the HPA when enabled
+	if value, found := r.apiManager.ObjectMeta.Annotations[disableApicastProductionReplicaReconciler]; (!found || value != "true") && !r.apiManager.IsAPIcastProductionHPAEnabled() {
 		productionMutators = append(productionMutators, reconcilers.DeploymentConfigReplicasMutator)
 	}
 
@@ -157,6 +158,16 @@ func (r *ApicastReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
+	// Production HPA
+	productionHPA := apicast.ProductionHorizontalPodAutoscaler()
+	if !r.apiManager.IsAPIcastProductionHPAEnabled() {
+		common.TagObjectToDelete(productionHPA)
+	}
+	err = r.ReconcileHorizontalPodAutoscaler(productionHPA, reconcilers.GenericHPAMutator)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	sumRate, err := helper.SumRateForOpenshiftVersion(r.Context(), r.Client())
 	if err != nil {
 		return reconcile.Result{}, err
@@ -43,6 +43,7 @@ func (o *OperatorBackendOptionsProvider) GetBackendOptions() (*component.Backend
 	o.setResourceRequirementsOptions()
 	o.setNodeAffinityAndTolerationsOptions()
 	o.setReplicas()
+	o.setHorizontalPodAutoscalerOptions()
 
 	o.backendOptions.CommonLabels = o.commonLabels()
 	o.backendOptions.CommonListenerLabels = o.commonListenerLabels()
@@ -141,6 +142,11 @@ func (o *OperatorBackendOptionsProvider) setNodeAffinityAndTolerationsOptions()
 	o.backendOptions.CronTolerations = o.apimanager.Spec.Backend.CronSpec.Tolerations
 }
 
+func (o *OperatorBackendOptionsProvider) setHorizontalPodAutoscalerOptions() {
+	o.backendOptions.ListenerHPA = horizontalPodAutoscalerOptions(o.apimanager.Spec.Backend.ListenerSpec.HPA)
+	o.backendOptions.WorkerHPA = horizontalPodAutoscalerOptions(o.apimanager.Spec.Backend.WorkerSpec.HPA)
+}
+
 func (o *OperatorBackendOptionsProvider) setReplicas() {
 	o.backendOptions.ListenerReplicas = int32(*o.apimanager.Spec.Backend.ListenerSpec.Replicas)
 	o.backendOptions.WorkerReplicas = int32(*o.apimanager.Spec.Backend.WorkerSpec.Replicas)
@@ -3,6 +3,7 @@ package operator
 import (
 	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/common"
 	"github.com/3scale/3scale-operator/pkg/helper"
 	"github.com/3scale/3scale-operator/pkg/reconcilers"
 
@@ -47,7 +48,8 @@ func (r *BackendReconciler) Reconcile() (reconcile.Result, error) {
 	// Listener DC
 	listenerConfigMutator := reconcilers.GenericBackendMutators()
 
-	if value, found := r.apiManager.ObjectMeta.Annotations[disableBackendListenerReplicasReconciler]; !found || value != "true" {
+	// Replicas are managed by the HPA when enabled
+	if value, found := r.apiManager.ObjectMeta.Annotations[disableBackendListenerReplicasReconciler]; (!found || value != "true") && !r.apiManager.IsBackendListenerHPAEnabled() {
 		listenerConfigMutator = append(listenerConfigMutator, reconcilers.DeploymentConfigReplicasMutator)
 	}
 
@@ -63,15 +65,28 @@ func (r *BackendReconciler) Reconcile() (reconcile.Result, error) {
 	}
 
 	// Listener Route
-	err = r.ReconcileRoute(backend.ListenerRoute(), reconcilers.CreateOnlyMutator)
+	// When ingress is enabled, the listener is exposed by the ingress reconciler.
+	// Clusters without the Route API, like Deployment workloads outside OpenShift, have no route to reconcile
+	routesAvailable, err := r.HasRoutes()
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	listenerRoute := backend.ListenerRoute()
+	if r.apiManager.IsIngressEnabled() {
+		common.TagObjectToDelete(listenerRoute)
+	}
+	if routesAvailable {
+		err = r.ReconcileRoute(listenerRoute, reconcilers.CreateOnlyMutator)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
 
 	// Worker DC
 	workerConfigMutator := reconcilers.GenericBackendMutators()
 
-	if value, found := r.apiManager.ObjectMeta.Annotations[disableBackendWorkerReplicasReconciler]; !found || value != "true" {
+	// Replicas are managed by the HPA when enabled
+	if value, found := r.apiManager.ObjectMeta.Annotations[disableBackendWorkerReplicasReconciler]; (!found || value != "true") && !r.apiManager.IsBackendWorkerHPAEnabled() {
 		workerConfigMutator = append(workerConfigMutator, reconcilers.DeploymentConfigReplicasMutator)
 	}
 
@@ -116,6 +131,26 @@ func (r *BackendReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
+	// Listener HPA
+	listenerHPA := backend.ListenerHorizontalPodAutoscaler()
+	if !r.apiManager.IsBackendListenerHPAEnabled() {
+		common.TagObjectToDelete(listenerHPA)
+	}
+	err = r.ReconcileHorizontalPodAutoscaler(listenerHPA, reconcilers.GenericHPAMutator)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Worker HPA
+	workerHPA := backend.WorkerHorizontalPodAutoscaler()
+	if !r.apiManager.IsBackendWorkerHPAEnabled() {
+		common.TagObjectToDelete(workerHPA)
+	}
+	err = r.ReconcileHorizontalPodAutoscaler(workerHPA, reconcilers.GenericHPAMutator)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	err = r.ReconcilePodMonitor(backend.BackendWorkerPodMonitor(), reconcilers.CreateOnlyMutator)
 	if err != nil {
 		return reconcile.Result{}, err
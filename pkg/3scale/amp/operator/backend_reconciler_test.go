@@ -12,8 +12,10 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -80,6 +82,7 @@ func TestNewBackendReconciler(t *testing.T) {
 	cl := fake.NewFakeClient(objs...)
 	clientAPIReader := fake.NewFakeClient(objs...)
 	clientset := fakeclientset.NewSimpleClientset()
+	clientset.Resources = routeAPIResources()
 	recorder := record.NewFakeRecorder(10000)
 
 	baseReconciler := reconcilers.NewBaseReconciler(ctx, cl, s, clientAPIReader, log, clientset.Discovery(), recorder)
@@ -283,3 +286,163 @@ func confirmReplicasWhenAnnotationPresent(apiManager *appsv1alpha1.APIManager, d
 
 	return true
 }
+
+func TestBackendReconcilerHPA(t *testing.T) {
+	var (
+		namespace         = "operator-unittest"
+		log               = logf.Log.WithName("operator_test")
+		maxReplicas int32 = 5
+		threeValue  int32 = 3
+	)
+	ctx := context.TODO()
+	s := scheme.Scheme
+
+	err := appsv1alpha1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = appsv1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := configv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	err = routev1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apimanager := backendApiManagerCreator("someAnnotation", "false")
+	apimanager.Spec.Backend.ListenerSpec.HPA = &appsv1alpha1.HorizontalPodAutoscalerSpec{MaxReplicas: maxReplicas}
+
+	objs := []runtime.Object{apimanager}
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+	clientset := fakeclientset.NewSimpleClientset()
+	recorder := record.NewFakeRecorder(10000)
+	baseReconciler := reconcilers.NewBaseReconciler(ctx, cl, s, clientAPIReader, log, clientset.Discovery(), recorder)
+
+	_, err = NewBackendReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listenerKey := types.NamespacedName{Name: component.BackendListenerName, Namespace: namespace}
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	err = cl.Get(ctx, listenerKey, hpa)
+	if err != nil {
+		t.Fatalf("error fetching listener hpa: %v", err)
+	}
+	if hpa.Spec.MinReplicas == nil || *hpa.Spec.MinReplicas != HorizontalPodAutoscalerDefaultMinReplicas {
+		t.Errorf("unexpected minReplicas: %v", hpa.Spec.MinReplicas)
+	}
+	if hpa.Spec.MaxReplicas != maxReplicas {
+		t.Errorf("unexpected maxReplicas: %d", hpa.Spec.MaxReplicas)
+	}
+	if len(hpa.Spec.Metrics) != 1 || hpa.Spec.Metrics[0].Resource == nil ||
+		hpa.Spec.Metrics[0].Resource.Name != v1.ResourceCPU ||
+		*hpa.Spec.Metrics[0].Resource.Target.AverageUtilization != HorizontalPodAutoscalerDefaultCPUAverageUtilization {
+		t.Errorf("unexpected default metrics: %v", hpa.Spec.Metrics)
+	}
+	if hpa.Spec.ScaleTargetRef.Kind != "DeploymentConfig" || hpa.Spec.ScaleTargetRef.Name != component.BackendListenerName {
+		t.Errorf("unexpected scale target: %v", hpa.Spec.ScaleTargetRef)
+	}
+
+	err = cl.Get(ctx, types.NamespacedName{Name: component.BackendWorkerName, Namespace: namespace}, &autoscalingv2beta2.HorizontalPodAutoscaler{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("worker hpa should not exist: %v", err)
+	}
+
+	// replicas set by the autoscaler are not reverted
+	dc := &appsv1.DeploymentConfig{}
+	err = cl.Get(ctx, listenerKey, dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc.Spec.Replicas = threeValue
+	err = cl.Update(ctx, dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewBackendReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cl.Get(ctx, listenerKey, dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dc.Spec.Replicas != threeValue {
+		t.Errorf("listener replicas reconciled while hpa is enabled: %d", dc.Spec.Replicas)
+	}
+
+	// disabling the autoscaler removes it
+	apimanager.Spec.Backend.ListenerSpec.HPA = nil
+	_, err = NewBackendReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cl.Get(ctx, listenerKey, &autoscalingv2beta2.HorizontalPodAutoscaler{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("listener hpa should have been deleted: %v", err)
+	}
+}
+
+func TestBackendReconcilerWithoutRoutes(t *testing.T) {
+	var (
+		log = logf.Log.WithName("operator_test")
+	)
+	ctx := context.TODO()
+	s := scheme.Scheme
+
+	err := appsv1alpha1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = appsv1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = routev1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apimanager := basicApimanager()
+	deploymentWorkloadType := appsv1alpha1.DeploymentWorkloadType
+	apimanager.Spec.WorkloadType = &deploymentWorkloadType
+
+	objs := []runtime.Object{apimanager}
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+	// the fake discovery does not serve the Route API
+	clientset := fakeclientset.NewSimpleClientset()
+	recorder := record.NewFakeRecorder(10000)
+	baseReconciler := reconcilers.NewBaseReconciler(ctx, cl, s, clientAPIReader, log, clientset.Discovery(), recorder)
+
+	_, err = NewBackendReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cl.Get(ctx, types.NamespacedName{Name: "backend", Namespace: namespace}, &routev1.Route{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("listener route should not exist without the Route API: %v", err)
+	}
+}
+
+// routeAPIResources returns the discovery resources of an OpenShift cluster serving the Route API
+func routeAPIResources() []*metav1.APIResourceList {
+	return []*metav1.APIResourceList{
+		{
+			GroupVersion: routev1.GroupVersion.String(),
+			APIResources: []metav1.APIResource{
+				{Name: "routes", Namespaced: true, Kind: "Route"},
+			},
+		},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	"github.com/3scale/3scale-operator/pkg/common"
 	"github.com/3scale/3scale-operator/pkg/helper"
 	"github.com/3scale/3scale-operator/pkg/reconcilers"
@@ -14,11 +15,17 @@ import (
 	appsv1 "github.com/openshift/api/apps/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type BaseAPIManagerLogicReconciler struct {
@@ -26,6 +33,7 @@ type BaseAPIManagerLogicReconciler struct {
 	apiManager           *appsv1alpha1.APIManager
 	logger               logr.Logger
 	crdAvailabilityCache *baseAPIManagerLogicReconcilerCRDAvailabilityCache
+	workloadImages       map[string]string
 }
 
 type baseAPIManagerLogicReconcilerCRDAvailabilityCache struct {
@@ -33,6 +41,8 @@ type baseAPIManagerLogicReconcilerCRDAvailabilityCache struct {
 	prometheusRuleCRDAvailable   *bool
 	podMonitorCRDAvailable       *bool
 	serviceMonitorCRDAvailable   *bool
+	routeAvailable               *bool
+	ingressAvailable             *bool
 }
 
 func NewBaseAPIManagerLogicReconciler(b *reconcilers.BaseReconciler, apiManager *appsv1alpha1.APIManager) *BaseAPIManagerLogicReconciler {
@@ -56,13 +66,200 @@ func (r *BaseAPIManagerLogicReconciler) ReconcilePodDisruptionBudget(desired *v1
 }
 
 func (r *BaseAPIManagerLogicReconciler) ReconcileImagestream(desired *imagev1.ImageStream, mutatefn reconcilers.MutateFn) error {
+	if r.apiManager.IsDeploymentWorkloadEnabled() {
+		// Deployments reference images directly
+		return nil
+	}
 	return r.ReconcileResource(&imagev1.ImageStream{}, desired, mutatefn)
 }
 
+// ReconcileDeploymentConfig reconciles the desired DeploymentConfig or,
+// when the APIManager workload type is Deployment, the equivalent Deployment.
+// DeploymentConfig mutators are applied to the Deployment as well.
 func (r *BaseAPIManagerLogicReconciler) ReconcileDeploymentConfig(desired *appsv1.DeploymentConfig, mutatefn reconcilers.MutateFn) error {
+	if r.apiManager.IsDeploymentWorkloadEnabled() {
+		images, err := r.getWorkloadImages()
+		if err != nil {
+			return err
+		}
+
+		desiredDeployment, err := component.DeploymentFromDeploymentConfig(desired, images)
+		if err != nil {
+			return err
+		}
+
+		return r.reconcileDeploymentWithHooks(desired, desiredDeployment, mutatefn)
+	}
+
 	return r.ReconcileResource(&appsv1.DeploymentConfig{}, desired, mutatefn)
 }
 
+// reconcileDeploymentWithHooks runs the DeploymentConfig lifecycle hooks as Jobs around the Deployment rollout.
+// The pre hook job has to complete before the Deployment gets the new pod template,
+// the post hook job is created once the new pod template has been rolled out.
+// Hook jobs run once for every pod template, the hash of the template the hooks ran for
+// is recorded in the Deployment annotations.
+func (r *BaseAPIManagerLogicReconciler) reconcileDeploymentWithHooks(dc *appsv1.DeploymentConfig, desired *k8sappsv1.Deployment, mutatefn reconcilers.MutateFn) error {
+	deploymentMutator := reconcilers.DeploymentFromDeploymentConfigMutator(mutatefn,
+		reconcilers.DeploymentImageMutator,
+		reconcilers.DeploymentPodTemplateLabelsMutator,
+		deploymentHookHashMutator,
+	)
+
+	if component.DeploymentConfigHook(dc, component.DeploymentPreHook) == nil &&
+		component.DeploymentConfigHook(dc, component.DeploymentPostHook) == nil {
+		return r.ReconcileDeployment(desired, deploymentMutator)
+	}
+
+	hookHash, err := component.DeploymentHookHash(desired)
+	if err != nil {
+		return err
+	}
+
+	existing := &k8sappsv1.Deployment{}
+	err = r.Client().Get(r.Context(), types.NamespacedName{Name: desired.Name, Namespace: r.apiManager.GetNamespace()}, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	deploymentExists := err == nil
+
+	if !deploymentExists || existing.Annotations[component.DeploymentHookHashAnnotation] != hookHash {
+		completed, err := r.reconcileDeploymentHookJob(dc, desired, component.DeploymentPreHook)
+		if err != nil || !completed {
+			return err
+		}
+	}
+
+	annotations := map[string]string{}
+	for key, value := range desired.Annotations {
+		annotations[key] = value
+	}
+	annotations[component.DeploymentHookHashAnnotation] = hookHash
+	desired.Annotations = annotations
+
+	err = r.ReconcileDeployment(desired, deploymentMutator)
+	if err != nil {
+		return err
+	}
+
+	// The post hook runs for the pod template the existing Deployment has rolled out.
+	// A Deployment updated in this reconciliation gets its post hook in a later one
+	if !deploymentExists ||
+		existing.Annotations[component.DeploymentHookHashAnnotation] != hookHash ||
+		!helper.IsDeploymentRolledOut(existing) {
+		return nil
+	}
+
+	_, err = r.reconcileDeploymentHookJob(dc, desired, component.DeploymentPostHook)
+	return err
+}
+
+// reconcileDeploymentHookJob ensures the hook job of the Deployment pod template exists and returns
+// whether it completed. Failed jobs of hooks with the Retry failure policy are recreated,
+// failures of hooks with the Ignore failure policy count as completed.
+func (r *BaseAPIManagerLogicReconciler) reconcileDeploymentHookJob(dc *appsv1.DeploymentConfig, deployment *k8sappsv1.Deployment, hookType component.DeploymentHookType) (bool, error) {
+	desired, err := component.DeploymentHookJob(dc, deployment, hookType)
+	if err != nil || desired == nil {
+		return desired == nil, err
+	}
+
+	err = r.ReconcileResource(&batchv1.Job{}, desired, reconcilers.CreateOnlyMutator)
+	if err != nil {
+		return false, err
+	}
+
+	job := &batchv1.Job{}
+	err = r.Client().Get(r.Context(), types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, job)
+	if err != nil {
+		// Not in the cache yet. The job events trigger a new reconciliation
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if jobConditionTrue(job, batchv1.JobComplete) {
+		return true, r.deleteOutdatedDeploymentHookJobs(job)
+	}
+
+	if !jobConditionTrue(job, batchv1.JobFailed) {
+		return false, nil
+	}
+
+	hook := component.DeploymentConfigHook(dc, hookType)
+	switch hook.FailurePolicy {
+	case appsv1.LifecycleHookFailurePolicyIgnore:
+		return true, r.deleteOutdatedDeploymentHookJobs(job)
+	case appsv1.LifecycleHookFailurePolicyRetry:
+		r.logger.Info("Deployment hook job failed, retrying", "job", job.Name)
+		return false, r.DeleteResource(job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	default:
+		// Failed jobs are kept so the cause of the failure can be inspected.
+		// A new pod template gets a new hook job
+		r.EventRecorder().Eventf(r.apiManager, v1.EventTypeWarning, "DeploymentHookFailed",
+			"%s hook job %s of deployment %s failed", hookType, job.Name, deployment.Name)
+		return false, nil
+	}
+}
+
+// deleteOutdatedDeploymentHookJobs deletes the hook jobs of previous pod templates
+func (r *BaseAPIManagerLogicReconciler) deleteOutdatedDeploymentHookJobs(current *batchv1.Job) error {
+	jobList := &batchv1.JobList{}
+	err := r.Client().List(r.Context(), jobList, client.InNamespace(current.Namespace),
+		client.MatchingLabels{component.DeploymentHookLabelKey: current.Labels[component.DeploymentHookLabelKey]})
+	if err != nil {
+		return err
+	}
+
+	for idx := range jobList.Items {
+		job := &jobList.Items[idx]
+		if job.Name == current.Name || job.DeletionTimestamp != nil {
+			continue
+		}
+		err = r.DeleteResource(job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func jobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func deploymentHookHashMutator(desired, existing *k8sappsv1.Deployment) bool {
+	desiredHash := desired.Annotations[component.DeploymentHookHashAnnotation]
+	if existing.Annotations[component.DeploymentHookHashAnnotation] == desiredHash {
+		return false
+	}
+	if existing.Annotations == nil {
+		existing.Annotations = map[string]string{}
+	}
+	existing.Annotations[component.DeploymentHookHashAnnotation] = desiredHash
+	return true
+}
+
+func (r *BaseAPIManagerLogicReconciler) ReconcileDeployment(desired *k8sappsv1.Deployment, mutatefn reconcilers.MutateFn) error {
+	return r.ReconcileResource(&k8sappsv1.Deployment{}, desired, mutatefn)
+}
+
+// ReconcileHorizontalPodAutoscaler reconciles the desired HorizontalPodAutoscaler.
+// When the APIManager workload type is Deployment, the autoscaler targets the Deployment.
+func (r *BaseAPIManagerLogicReconciler) ReconcileHorizontalPodAutoscaler(desired *autoscalingv2beta2.HorizontalPodAutoscaler, mutateFn reconcilers.MutateFn) error {
+	if r.apiManager.IsDeploymentWorkloadEnabled() {
+		desired.Spec.ScaleTargetRef.APIVersion = "apps/v1"
+		desired.Spec.ScaleTargetRef.Kind = "Deployment"
+	}
+	return r.ReconcileResource(&autoscalingv2beta2.HorizontalPodAutoscaler{}, desired, mutateFn)
+}
+
 func (r *BaseAPIManagerLogicReconciler) ReconcileService(desired *v1.Service, mutateFn reconcilers.MutateFn) error {
 	return r.ReconcileResource(&v1.Service{}, desired, mutateFn)
 }
@@ -79,6 +276,10 @@ func (r *BaseAPIManagerLogicReconciler) ReconcileRoute(desired *routev1.Route, m
 	return r.ReconcileResource(&routev1.Route{}, desired, mutateFn)
 }
 
+func (r *BaseAPIManagerLogicReconciler) ReconcileIngress(desired *networkingv1beta1.Ingress, mutateFn reconcilers.MutateFn) error {
+	return r.ReconcileResource(&networkingv1beta1.Ingress{}, desired, mutateFn)
+}
+
 func (r *BaseAPIManagerLogicReconciler) ReconcileSecret(desired *v1.Secret, mutateFn reconcilers.MutateFn) error {
 	return r.ReconcileResource(&v1.Secret{}, desired, mutateFn)
 }
@@ -282,3 +483,38 @@ func (b *BaseAPIManagerLogicReconciler) HasPodMonitors() (bool, error) {
 	}
 	return *b.crdAvailabilityCache.podMonitorCRDAvailable, nil
 }
+
+func (b *BaseAPIManagerLogicReconciler) HasRoutes() (bool, error) {
+	if b.crdAvailabilityCache.routeAvailable == nil {
+		res, err := b.BaseReconciler.HasRoutes()
+		if err != nil {
+			return res, err
+		}
+		b.crdAvailabilityCache.routeAvailable = &res
+		return res, err
+	}
+	return *b.crdAvailabilityCache.routeAvailable, nil
+}
+
+func (b *BaseAPIManagerLogicReconciler) HasIngresses() (bool, error) {
+	if b.crdAvailabilityCache.ingressAvailable == nil {
+		res, err := b.BaseReconciler.HasIngresses()
+		if err != nil {
+			return res, err
+		}
+		b.crdAvailabilityCache.ingressAvailable = &res
+		return res, err
+	}
+	return *b.crdAvailabilityCache.ingressAvailable, nil
+}
+
+func (b *BaseAPIManagerLogicReconciler) getWorkloadImages() (map[string]string, error) {
+	if b.workloadImages == nil {
+		images, err := WorkloadImages(b.apiManager, b.Client())
+		if err != nil {
+			return nil, err
+		}
+		b.workloadImages = images
+	}
+	return b.workloadImages, nil
+}
@@ -0,0 +1,59 @@
+package operator
+
+import (
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	HorizontalPodAutoscalerDefaultMinReplicas           int32 = 1
+	HorizontalPodAutoscalerDefaultCPUAverageUtilization int32 = 80
+)
+
+// horizontalPodAutoscalerOptions returns the autoscaling options of the given spec.
+// Defaults match the ones set by the API server, to avoid reconciling them back and forth.
+func horizontalPodAutoscalerOptions(spec *appsv1alpha1.HorizontalPodAutoscalerSpec) *component.HorizontalPodAutoscalerOptions {
+	if spec == nil {
+		return nil
+	}
+
+	minReplicas := HorizontalPodAutoscalerDefaultMinReplicas
+	if spec.MinReplicas != nil {
+		minReplicas = *spec.MinReplicas
+	}
+
+	metrics := []autoscalingv2beta2.MetricSpec{}
+	if spec.CPUAverageUtilization != nil {
+		metrics = append(metrics, resourceUtilizationMetric(v1.ResourceCPU, *spec.CPUAverageUtilization))
+	}
+	if spec.MemoryAverageUtilization != nil {
+		metrics = append(metrics, resourceUtilizationMetric(v1.ResourceMemory, *spec.MemoryAverageUtilization))
+	}
+	metrics = append(metrics, spec.Metrics...)
+
+	if len(metrics) == 0 {
+		metrics = append(metrics, resourceUtilizationMetric(v1.ResourceCPU, HorizontalPodAutoscalerDefaultCPUAverageUtilization))
+	}
+
+	return &component.HorizontalPodAutoscalerOptions{
+		MinReplicas: minReplicas,
+		MaxReplicas: spec.MaxReplicas,
+		Metrics:     metrics,
+	}
+}
+
+func resourceUtilizationMetric(resourceName v1.ResourceName, averageUtilization int32) autoscalingv2beta2.MetricSpec {
+	return autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{
+			Name: resourceName,
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:               autoscalingv2beta2.UtilizationMetricType,
+				AverageUtilization: &averageUtilization,
+			},
+		},
+	}
+}
@@ -0,0 +1,176 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	capabilitiesv1alpha1 "github.com/3scale/3scale-operator/apis/capabilities/v1alpha1"
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type IngressOptionsProvider struct {
+	apimanager   *appsv1alpha1.APIManager
+	namespace    string
+	client       client.Client
+	options      *component.IngressOptions
+	secretSource *helper.SecretSource
+}
+
+func NewIngressOptionsProvider(apimanager *appsv1alpha1.APIManager, namespace string, client client.Client) *IngressOptionsProvider {
+	return &IngressOptionsProvider{
+		apimanager:   apimanager,
+		namespace:    namespace,
+		client:       client,
+		options:      component.NewIngressOptions(),
+		secretSource: helper.NewSecretSource(client, namespace),
+	}
+}
+
+func (i *IngressOptionsProvider) GetIngressOptions() (*component.IngressOptions, error) {
+	i.options.TenantName = *i.apimanager.Spec.TenantName
+	i.options.WildcardDomain = i.apimanager.Spec.WildcardDomain
+	i.options.CommonLabels = i.commonLabels()
+
+	// Master portal host is built from the system seed secret, the same one used by system
+	masterName, err := i.secretSource.FieldValue(
+		component.SystemSecretSystemSeedSecretName,
+		component.SystemSecretSystemSeedMasterDomainFieldName,
+		component.DefaultSystemMasterName())
+	if err != nil {
+		return nil, err
+	}
+	i.options.MasterName = masterName
+
+	if i.apimanager.Spec.Ingress != nil {
+		i.setIngressSpecOptions(i.apimanager.Spec.Ingress)
+	}
+
+	if i.apimanager.IsIngressEnabled() {
+		err = i.setTenantResourceOptions()
+		if err != nil {
+			return nil, err
+		}
+
+		err = i.setProductOptions()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = i.options.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("GetIngressOptions validating: %w", err)
+	}
+	return i.options, nil
+}
+
+func (i *IngressOptionsProvider) setIngressSpecOptions(spec *appsv1alpha1.IngressSpec) {
+	i.options.IngressClassName = spec.IngressClassName
+	i.options.Annotations = spec.Annotations
+	if spec.TLSSecretRef != nil {
+		i.options.TLSSecretName = &spec.TLSSecretRef.Name
+	}
+
+	for idx := range spec.Tenants {
+		tenant := &spec.Tenants[idx]
+		tenantOpts := component.IngressTenantOptions{
+			Name:          tenant.Name,
+			AdminHost:     tenant.AdminHost,
+			DeveloperHost: tenant.DeveloperHost,
+		}
+		if tenant.TLSSecretRef != nil {
+			tenantOpts.TLSSecretName = &tenant.TLSSecretRef.Name
+		}
+		i.options.Tenants = append(i.options.Tenants, tenantOpts)
+	}
+}
+
+// setTenantResourceOptions adds the portal hosts of the Tenant custom resources of the namespace.
+// Tenants already in the ingress spec, by name or by admin host, are skipped
+func (i *IngressOptionsProvider) setTenantResourceOptions() error {
+	tenantList := &capabilitiesv1alpha1.TenantList{}
+	err := i.client.List(context.TODO(), tenantList, client.InNamespace(i.namespace))
+	if err != nil {
+		return fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	knownNames := map[string]bool{}
+	knownHosts := map[string]bool{}
+	for _, tenant := range i.options.Tenants {
+		knownNames[tenant.Name] = true
+		knownHosts[tenant.AdminHost] = true
+	}
+
+	for idx := range tenantList.Items {
+		tenant := &tenantList.Items[idx]
+		adminHost := tenant.Status.AdminDomain
+		if adminHost == "" || knownNames[tenant.Name] || knownHosts[adminHost] {
+			continue
+		}
+
+		tenantOpts := component.IngressTenantOptions{
+			Name:      tenant.Name,
+			AdminHost: adminHost,
+		}
+		if tenant.Status.DeveloperDomain != "" {
+			developerHost := tenant.Status.DeveloperDomain
+			tenantOpts.DeveloperHost = &developerHost
+		}
+		i.options.Tenants = append(i.options.Tenants, tenantOpts)
+	}
+
+	return nil
+}
+
+// setProductOptions adds the APIcast hosts of the Product custom resources of the namespace
+// that belong to one of the tenants of the APIManager
+func (i *IngressOptionsProvider) setProductOptions() error {
+	productList := &capabilitiesv1beta1.ProductList{}
+	err := i.client.List(context.TODO(), productList, client.InNamespace(i.namespace))
+	if err != nil {
+		return fmt.Errorf("failed to list products: %w", err)
+	}
+
+	tenantAdminHosts := map[string]bool{
+		fmt.Sprintf("%s-admin.%s", i.options.TenantName, i.options.WildcardDomain): true,
+	}
+	for _, tenant := range i.options.Tenants {
+		tenantAdminHosts[tenant.AdminHost] = true
+	}
+
+	for idx := range productList.Items {
+		product := &productList.Items[idx]
+		if !tenantAdminHosts[urlHostname(product.Status.ProviderAccountHost)] {
+			continue
+		}
+
+		i.options.Products = append(i.options.Products, component.IngressProductOptions{
+			Name:           product.Name,
+			StagingHost:    urlHostname(product.Status.StagingPublicBaseURL),
+			ProductionHost: urlHostname(product.Status.ProductionPublicBaseURL),
+		})
+	}
+
+	return nil
+}
+
+// urlHostname returns the hostname of the URL. Empty when it cannot be parsed
+func urlHostname(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsedURL.Hostname()
+}
+
+func (i *IngressOptionsProvider) commonLabels() map[string]string {
+	return map[string]string{
+		"app": *i.apimanager.Spec.AppLabel,
+	}
+}
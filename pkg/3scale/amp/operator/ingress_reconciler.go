@@ -0,0 +1,115 @@
+package operator
+
+import (
+	"fmt"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/common"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type IngressReconciler struct {
+	*BaseAPIManagerLogicReconciler
+}
+
+func NewIngressReconciler(baseAPIManagerLogicReconciler *BaseAPIManagerLogicReconciler) *IngressReconciler {
+	return &IngressReconciler{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+	}
+}
+
+func (r *IngressReconciler) Reconcile() (reconcile.Result, error) {
+	ingressesAvailable, err := r.HasIngresses()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !ingressesAvailable {
+		if r.apiManager.IsIngressEnabled() {
+			return reconcile.Result{}, fmt.Errorf("ingress is enabled but %s Ingress is not available in the cluster",
+				networkingv1beta1.SchemeGroupVersion.String())
+		}
+		// Nothing to clean up
+		return reconcile.Result{}, nil
+	}
+
+	ingress, err := Ingress(r.apiManager, r.Client())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	desiredIngresses := []*networkingv1beta1.Ingress{
+		ingress.BackendListenerIngress(),
+		ingress.SystemMasterIngress(),
+		ingress.SystemProviderIngress(),
+		ingress.SystemDeveloperIngress(),
+		ingress.ApicastStagingIngress(),
+		ingress.ApicastProductionIngress(),
+	}
+	desiredIngresses = append(desiredIngresses, ingress.TenantIngresses()...)
+	desiredIngresses = append(desiredIngresses, ingress.ProductIngresses()...)
+
+	for _, desired := range desiredIngresses {
+		if !r.apiManager.IsIngressEnabled() {
+			common.TagObjectToDelete(desired)
+		}
+		err = r.ReconcileIngress(desired, reconcilers.GenericIngressMutator)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	for _, labelKey := range []string{component.IngressTenantLabelKey, component.IngressProductLabelKey} {
+		err = r.deleteRemovedIngresses(desiredIngresses, labelKey)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// deleteRemovedIngresses deletes the ingresses, with the given label, of the tenants and products no longer exposed
+func (r *IngressReconciler) deleteRemovedIngresses(desiredIngresses []*networkingv1beta1.Ingress, labelKey string) error {
+	desiredNames := map[string]bool{}
+	for _, desired := range desiredIngresses {
+		desiredNames[desired.Name] = !common.IsObjectTaggedToDelete(desired)
+	}
+
+	ingressList := &networkingv1beta1.IngressList{}
+	err := r.Client().List(r.Context(), ingressList,
+		client.InNamespace(r.apiManager.Namespace),
+		client.HasLabels{labelKey})
+	if err != nil {
+		return fmt.Errorf("failed to list %s ingresses: %w", labelKey, err)
+	}
+
+	for idx := range ingressList.Items {
+		existing := &ingressList.Items[idx]
+		if desiredNames[existing.Name] || !metav1.IsControlledBy(existing, r.apiManager) {
+			continue
+		}
+
+		err = r.DeleteResource(existing)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func Ingress(apimanager *appsv1alpha1.APIManager, client client.Client) (*component.Ingress, error) {
+	optsProvider := NewIngressOptionsProvider(apimanager, apimanager.Namespace, client)
+	opts, err := optsProvider.GetIngressOptions()
+	if err != nil {
+		return nil, err
+	}
+	return component.NewIngress(opts), nil
+}
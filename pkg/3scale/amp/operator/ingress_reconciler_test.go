@@ -0,0 +1,289 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	capabilitiesv1alpha1 "github.com/3scale/3scale-operator/apis/capabilities/v1alpha1"
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	configv1 "github.com/openshift/api/config/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestIngressReconciler(t *testing.T) {
+	var (
+		namespace      = "operator-unittest"
+		log            = logf.Log.WithName("operator_test")
+		ingressClass   = "nginx"
+		developerHost  = "tenant2.example.com"
+		tlsSecretName  = "wildcard-tls"
+		tenantTLSName  = "tenant2-tls"
+		expectedLabels = map[string]string{"app": "someLabel"}
+	)
+	ctx := context.TODO()
+	s := scheme.Scheme
+
+	err := appsv1alpha1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = appsv1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := configv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	err = routev1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := capabilitiesv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := capabilitiesv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	apimanager := backendApiManagerCreator("someAnnotation", "false")
+	apimanager.Spec.Ingress = &appsv1alpha1.IngressSpec{
+		Enabled:          true,
+		IngressClassName: &ingressClass,
+		Annotations:      map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "0"},
+		TLSSecretRef:     &v1.LocalObjectReference{Name: tlsSecretName},
+		Tenants: []appsv1alpha1.IngressTenantSpec{
+			{Name: "tenant2", AdminHost: "tenant2-admin.example.com", DeveloperHost: &developerHost,
+				TLSSecretRef: &v1.LocalObjectReference{Name: tenantTLSName}},
+			{Name: "tenant3", AdminHost: "tenant3-admin.example.com"},
+		},
+	}
+
+	// Tenant custom resources, tenant3 is already exposed by the ingress spec
+	tenant4 := &capabilitiesv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant4", Namespace: namespace},
+		Status:     capabilitiesv1alpha1.TenantStatus{AdminDomain: "tenant4-admin.example.com", DeveloperDomain: "tenant4.example.com"},
+	}
+	specTenant := &capabilitiesv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant3-cr", Namespace: namespace},
+		Status:     capabilitiesv1alpha1.TenantStatus{AdminDomain: "tenant3-admin.example.com"},
+	}
+	product1 := &capabilitiesv1beta1.Product{
+		ObjectMeta: metav1.ObjectMeta{Name: "product1", Namespace: namespace},
+		Status: capabilitiesv1beta1.ProductStatus{
+			ProviderAccountHost:     "https://someTenant-admin.test.3scale.net",
+			StagingPublicBaseURL:    "https://product1-staging.example.com:443",
+			ProductionPublicBaseURL: "https://product1.example.com",
+		},
+	}
+	// product2 staging host is the default APIcast staging host
+	product2 := &capabilitiesv1beta1.Product{
+		ObjectMeta: metav1.ObjectMeta{Name: "product2", Namespace: namespace},
+		Status: capabilitiesv1beta1.ProductStatus{
+			ProviderAccountHost:     "https://tenant4-admin.example.com",
+			StagingPublicBaseURL:    "https://api-someTenant-apicast-staging.test.3scale.net",
+			ProductionPublicBaseURL: "https://product2.example.com",
+		},
+	}
+	// products of other 3scale instances are not exposed
+	externalProduct := &capabilitiesv1beta1.Product{
+		ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: namespace},
+		Status: capabilitiesv1beta1.ProductStatus{
+			ProviderAccountHost:     "https://other-admin.example.org",
+			ProductionPublicBaseURL: "https://external.example.org",
+		},
+	}
+
+	objs := []runtime.Object{apimanager, tenant4, specTenant, product1, product2, externalProduct}
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+	clientset := fakeclientset.NewSimpleClientset()
+	clientset.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: networkingv1beta1.SchemeGroupVersion.String(),
+			APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress", Namespaced: true}},
+		},
+	}
+	recorder := record.NewFakeRecorder(10000)
+	baseReconciler := reconcilers.NewBaseReconciler(ctx, cl, s, clientAPIReader, log, clientset.Discovery(), recorder)
+
+	_, err = NewIngressReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name          string
+		host          string
+		serviceName   string
+		tlsSecretName string
+	}{
+		{component.BackendListenerIngressName, "backend-someTenant.test.3scale.net", component.BackendListenerName, tlsSecretName},
+		{component.SystemMasterIngressName, "master.test.3scale.net", "system-master", tlsSecretName},
+		{component.SystemProviderIngressName, "someTenant-admin.test.3scale.net", "system-provider", tlsSecretName},
+		{component.SystemDeveloperIngressName, "someTenant.test.3scale.net", "system-developer", tlsSecretName},
+		{component.ApicastStagingIngressName, "api-someTenant-apicast-staging.test.3scale.net", component.ApicastStagingName, tlsSecretName},
+		{component.ApicastProductionIngressName, "api-someTenant-apicast-production.test.3scale.net", component.ApicastProductionName, tlsSecretName},
+		{"system-provider-tenant2", "tenant2-admin.example.com", "system-provider", tenantTLSName},
+		{"system-developer-tenant2", "tenant2.example.com", "system-developer", tenantTLSName},
+		{"system-provider-tenant3", "tenant3-admin.example.com", "system-provider", tlsSecretName},
+		{"system-provider-tenant4", "tenant4-admin.example.com", "system-provider", tlsSecretName},
+		{"system-developer-tenant4", "tenant4.example.com", "system-developer", tlsSecretName},
+		{"apicast-staging-product1", "product1-staging.example.com", component.ApicastStagingName, tlsSecretName},
+		{"apicast-production-product1", "product1.example.com", component.ApicastProductionName, tlsSecretName},
+		{"apicast-production-product2", "product2.example.com", component.ApicastProductionName, tlsSecretName},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			ingress := &networkingv1beta1.Ingress{}
+			err := cl.Get(ctx, types.NamespacedName{Name: tc.name, Namespace: namespace}, ingress)
+			if err != nil {
+				subT.Fatalf("error fetching ingress %s: %v", tc.name, err)
+			}
+			if ingress.Spec.IngressClassName == nil || *ingress.Spec.IngressClassName != ingressClass {
+				subT.Errorf("unexpected ingress class: %v", ingress.Spec.IngressClassName)
+			}
+			if len(ingress.Spec.Rules) != 1 || ingress.Spec.Rules[0].Host != tc.host {
+				subT.Fatalf("unexpected rules: %v", ingress.Spec.Rules)
+			}
+			if ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName != tc.serviceName {
+				subT.Errorf("unexpected backend: %v", ingress.Spec.Rules[0].HTTP.Paths[0].Backend)
+			}
+			if len(ingress.Spec.TLS) != 1 || ingress.Spec.TLS[0].SecretName != tc.tlsSecretName {
+				subT.Errorf("unexpected tls: %v", ingress.Spec.TLS)
+			}
+			if ingress.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"] != "0" {
+				subT.Errorf("missing annotations: %v", ingress.Annotations)
+			}
+			for k, v := range expectedLabels {
+				if ingress.Labels[k] != v {
+					subT.Errorf("missing label %s: %v", k, ingress.Labels)
+				}
+			}
+		})
+	}
+
+	// developer portal ingress is only created when the developer host is set
+	err = cl.Get(ctx, types.NamespacedName{Name: "system-developer-tenant3", Namespace: namespace}, &networkingv1beta1.Ingress{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("tenant3 developer ingress should not exist: %v", err)
+	}
+
+	// hosts already routed and products of other 3scale instances are not exposed
+	for _, name := range []string{"apicast-staging-product2", "apicast-production-external", "system-provider-tenant3-cr"} {
+		err = cl.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &networkingv1beta1.Ingress{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("ingress %s should not exist: %v", name, err)
+		}
+	}
+
+	// removed products get their ingresses deleted
+	err = cl.Delete(ctx, product1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewIngressReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"apicast-staging-product1", "apicast-production-product1"} {
+		err = cl.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &networkingv1beta1.Ingress{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("ingress %s should have been deleted: %v", name, err)
+		}
+	}
+
+	// removed tenants get their ingresses deleted
+	apimanager.Spec.Ingress.Tenants = apimanager.Spec.Ingress.Tenants[1:]
+	_, err = NewIngressReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"system-provider-tenant2", "system-developer-tenant2"} {
+		err = cl.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &networkingv1beta1.Ingress{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("ingress %s should have been deleted: %v", name, err)
+		}
+	}
+	err = cl.Get(ctx, types.NamespacedName{Name: "system-provider-tenant3", Namespace: namespace}, &networkingv1beta1.Ingress{})
+	if err != nil {
+		t.Errorf("error fetching tenant3 ingress: %v", err)
+	}
+
+	// backend listener is exposed by the ingress, not by a route
+	_, err = NewBackendReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cl.Get(ctx, types.NamespacedName{Name: "backend", Namespace: namespace}, &routev1.Route{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("backend route should not exist: %v", err)
+	}
+
+	// disabling ingress removes all of them
+	apimanager.Spec.Ingress.Enabled = false
+	_, err = NewIngressReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ingressList := &networkingv1beta1.IngressList{}
+	err = cl.List(ctx, ingressList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ingressList.Items) != 0 {
+		t.Errorf("ingresses should have been deleted, found %d", len(ingressList.Items))
+	}
+}
+
+func TestIngressReconcilerIngressNotAvailable(t *testing.T) {
+	var (
+		log = logf.Log.WithName("operator_test")
+	)
+	ctx := context.TODO()
+	s := scheme.Scheme
+
+	err := appsv1alpha1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apimanager := backendApiManagerCreator("someAnnotation", "false")
+
+	objs := []runtime.Object{apimanager}
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+	clientset := fakeclientset.NewSimpleClientset()
+	recorder := record.NewFakeRecorder(10000)
+	baseReconciler := reconcilers.NewBaseReconciler(ctx, cl, s, clientAPIReader, log, clientset.Discovery(), recorder)
+
+	// disabled ingress does not require the Ingress API
+	_, err = NewIngressReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apimanager.Spec.Ingress = &appsv1alpha1.IngressSpec{Enabled: true}
+	_, err = NewIngressReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)).Reconcile()
+	if err == nil {
+		t.Error("expected error when the Ingress API is not available")
+	}
+}
@@ -59,6 +59,7 @@ func (s *SystemOptionsProvider) GetSystemOptions() (*component.SystemOptions, er
 	s.setNodeAffinityAndTolerationsOptions()
 	s.setFileStorageOptions()
 	s.setReplicas()
+	s.setHorizontalPodAutoscalerOptions()
 
 	s.options.SideKiqMetrics = true
 	s.options.AppMetrics = true
@@ -427,6 +428,10 @@ func (s *SystemOptionsProvider) setNodeAffinityAndTolerationsOptions() {
 	s.options.SphinxTolerations = s.apimanager.Spec.System.SphinxSpec.Tolerations
 }
 
+func (s *SystemOptionsProvider) setHorizontalPodAutoscalerOptions() {
+	s.options.AppHPA = horizontalPodAutoscalerOptions(s.apimanager.Spec.System.AppSpec.HPA)
+}
+
 func (s *SystemOptionsProvider) setFileStorageOptions() {
 	if s.apimanager.Spec.System != nil &&
 		s.apimanager.Spec.System.FileStorageSpec != nil &&
@@ -82,12 +82,16 @@ func (r *SystemReconciler) Reconcile() (reconcile.Result, error) {
 	}
 
 	// SystemApp DC
-	systemAppDCMutator := reconcilers.DeploymentConfigMutator(
-		reconcilers.DeploymentConfigReplicasMutator,
+	systemAppDCMutators := []reconcilers.DCMutateFn{
 		reconcilers.DeploymentConfigAffinityMutator,
 		reconcilers.DeploymentConfigTolerationsMutator,
 		r.systemAppDCResourceMutator,
-	)
+	}
+	// Replicas are managed by the HPA when enabled
+	if !r.apiManager.IsSystemAppHPAEnabled() {
+		systemAppDCMutators = append(systemAppDCMutators, reconcilers.DeploymentConfigReplicasMutator)
+	}
+	systemAppDCMutator := reconcilers.DeploymentConfigMutator(systemAppDCMutators...)
 
 	err = r.ReconcileDeploymentConfig(system.AppDeploymentConfig(), systemAppDCMutator)
 	if err != nil {
@@ -177,6 +181,16 @@ func (r *SystemReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
+	// SystemApp HPA
+	appHPA := system.AppHorizontalPodAutoscaler()
+	if !r.apiManager.IsSystemAppHPAEnabled() {
+		common.TagObjectToDelete(appHPA)
+	}
+	err = r.ReconcileHorizontalPodAutoscaler(appHPA, reconcilers.GenericHPAMutator)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	err = r.ReconcilePodMonitor(system.SystemSidekiqPodMonitor(), reconcilers.CreateOnlyMutator)
 	if err != nil {
 		return reconcile.Result{}, err
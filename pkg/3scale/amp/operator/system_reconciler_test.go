@@ -2,6 +2,8 @@ package operator
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
@@ -14,13 +16,17 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	fakeclientset "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -117,3 +123,233 @@ func TestSystemReconcilerCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestSystemReconcilerDeploymentWorkloadType(t *testing.T) {
+	var (
+		log = logf.Log.WithName("operator_test")
+	)
+
+	ctx := context.TODO()
+
+	apimanager := basicApimanagerSpecTestSystemOptions()
+	workloadType := appsv1alpha1.DeploymentWorkloadType
+	apimanager.Spec.WorkloadType = &workloadType
+	// Objects to track in the fake client.
+	objs := []runtime.Object{apimanager}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.GroupVersion, apimanager)
+	err := appsv1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = imagev1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = routev1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := monitoringv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := grafanav1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := configv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+	clientset := fakeclientset.NewSimpleClientset()
+	recorder := record.NewFakeRecorder(10000)
+
+	baseReconciler := reconcilers.NewBaseReconciler(ctx, cl, s, clientAPIReader, log, clientset.Discovery(), recorder)
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager)
+
+	reconciler := NewSystemReconciler(baseAPIManagerLogicReconciler)
+	_, err = reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	systemAppKey := types.NamespacedName{Name: "system-app", Namespace: namespace}
+
+	// system-app is not deployed until the pre hook job completes
+	err = cl.Get(context.TODO(), systemAppKey, &k8sappsv1.Deployment{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("system-app deployment should not exist before the pre hook completes: %v", err)
+	}
+
+	preHookJob := systemAppHookJob(t, cl, component.DeploymentPreHook)
+	if len(preHookJob.Spec.Template.Spec.InitContainers) != 0 || len(preHookJob.Spec.Template.Spec.Containers) != 1 {
+		t.Fatalf("unexpected pre hook job containers: %v", preHookJob.Spec.Template.Spec.Containers)
+	}
+	hookContainer := preHookJob.Spec.Template.Spec.Containers[0]
+	if hookContainer.Name != "system-app-pre-hook" || hookContainer.Image != SystemImageURL() {
+		t.Errorf("unexpected pre hook container name %s and image %s", hookContainer.Name, hookContainer.Image)
+	}
+	if _, ok := preHookJob.Spec.Template.Labels["deploymentConfig"]; ok {
+		t.Errorf("pre hook job pods should not get the deployment pod labels: %v", preHookJob.Spec.Template.Labels)
+	}
+
+	setJobCondition(t, cl, preHookJob, batchv1.JobComplete)
+	_, err = reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"system-app", "system-sidekiq", "system-sphinx"} {
+		t.Run(name, func(subT *testing.T) {
+			namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
+
+			err := cl.Get(context.TODO(), namespacedName, &appsv1.DeploymentConfig{})
+			if !errors.IsNotFound(err) {
+				subT.Errorf("deploymentconfig %s should not exist: %v", name, err)
+			}
+
+			deployment := &k8sappsv1.Deployment{}
+			err = cl.Get(context.TODO(), namespacedName, deployment)
+			if err != nil {
+				subT.Fatalf("error fetching deployment %s: %v", name, err)
+			}
+
+			if len(deployment.Spec.Template.Spec.InitContainers) != 0 && name == "system-app" {
+				subT.Errorf("deployment %s should not run the hooks as init containers", name)
+			}
+
+			for _, container := range deployment.Spec.Template.Spec.Containers {
+				if container.Image != SystemImageURL() {
+					subT.Errorf("deployment %s container %s image, expected: %s, got: %s",
+						name, container.Name, SystemImageURL(), container.Image)
+				}
+			}
+		})
+	}
+
+	systemApp := &k8sappsv1.Deployment{}
+	err = cl.Get(context.TODO(), systemAppKey, systemApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hookHash := systemApp.Annotations[component.DeploymentHookHashAnnotation]
+	if hookHash == "" || !strings.HasSuffix(preHookJob.Name, hookHash) {
+		t.Errorf("system-app hook hash annotation '%s' does not match the pre hook job %s", hookHash, preHookJob.Name)
+	}
+
+	// The post hook runs once the pod template is rolled out
+	if jobs := systemAppHookJobs(t, cl, component.DeploymentPostHook); len(jobs) != 0 {
+		t.Fatalf("post hook job should not exist before the rollout: %v", jobs)
+	}
+
+	systemApp.Status.ObservedGeneration = systemApp.Generation
+	systemApp.Status.Replicas = *systemApp.Spec.Replicas
+	systemApp.Status.UpdatedReplicas = *systemApp.Spec.Replicas
+	systemApp.Status.AvailableReplicas = *systemApp.Spec.Replicas
+	if err := cl.Status().Update(context.TODO(), systemApp); err != nil {
+		t.Fatal(err)
+	}
+	_, err = reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	postHookJob := systemAppHookJob(t, cl, component.DeploymentPostHook)
+	if !strings.HasSuffix(postHookJob.Name, hookHash) {
+		t.Errorf("post hook job %s does not match the hook hash %s", postHookJob.Name, hookHash)
+	}
+	if postHookJob.Spec.Template.Spec.Containers[0].Image != SystemImageURL() {
+		t.Errorf("unexpected post hook image %s", postHookJob.Spec.Template.Spec.Containers[0].Image)
+	}
+}
+
+func TestSystemReconcilerDeploymentPreHookRetry(t *testing.T) {
+	var (
+		log = logf.Log.WithName("operator_test")
+	)
+
+	ctx := context.TODO()
+
+	apimanager := basicApimanagerSpecTestSystemOptions()
+	workloadType := appsv1alpha1.DeploymentWorkloadType
+	apimanager.Spec.WorkloadType = &workloadType
+	objs := []runtime.Object{apimanager}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.GroupVersion, apimanager)
+	if err := appsv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := imagev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := routev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitoringv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := grafanav1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := configv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+	clientset := fakeclientset.NewSimpleClientset()
+	recorder := record.NewFakeRecorder(10000)
+
+	baseReconciler := reconcilers.NewBaseReconciler(ctx, cl, s, clientAPIReader, log, clientset.Discovery(), recorder)
+	reconciler := NewSystemReconciler(NewBaseAPIManagerLogicReconciler(baseReconciler, apimanager))
+	if _, err := reconciler.Reconcile(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The system-app pre hook has the Retry failure policy
+	preHookJob := systemAppHookJob(t, cl, component.DeploymentPreHook)
+	setJobCondition(t, cl, preHookJob, batchv1.JobFailed)
+	if _, err := reconciler.Reconcile(); err != nil {
+		t.Fatal(err)
+	}
+
+	if jobs := systemAppHookJobs(t, cl, component.DeploymentPreHook); len(jobs) != 0 {
+		t.Fatalf("failed pre hook job should be deleted: %v", jobs)
+	}
+	err := cl.Get(context.TODO(), types.NamespacedName{Name: "system-app", Namespace: namespace}, &k8sappsv1.Deployment{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("system-app deployment should not exist after a pre hook failure: %v", err)
+	}
+
+	if _, err := reconciler.Reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	systemAppHookJob(t, cl, component.DeploymentPreHook)
+}
+
+func systemAppHookJobs(t *testing.T, cl client.Client, hookType component.DeploymentHookType) []batchv1.Job {
+	jobList := &batchv1.JobList{}
+	err := cl.List(context.TODO(), jobList, client.InNamespace(namespace),
+		client.MatchingLabels{component.DeploymentHookLabelKey: fmt.Sprintf("system-app-%s", hookType)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jobList.Items
+}
+
+func systemAppHookJob(t *testing.T, cl client.Client, hookType component.DeploymentHookType) *batchv1.Job {
+	jobs := systemAppHookJobs(t, cl, hookType)
+	if len(jobs) != 1 {
+		t.Fatalf("expected one system-app %s hook job, got %d", hookType, len(jobs))
+	}
+	return &jobs[0]
+}
+
+func setJobCondition(t *testing.T, cl client.Client, job *batchv1.Job, conditionType batchv1.JobConditionType) {
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{Type: conditionType, Status: v1.ConditionTrue})
+	if err := cl.Status().Update(context.TODO(), job); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -34,6 +34,12 @@ func NewUpgradeApiManager(b *reconcilers.BaseReconciler, apiManager *appsv1alpha
 }
 
 func (u *UpgradeApiManager) Upgrade() (reconcile.Result, error) {
+	if u.apiManager.IsDeploymentWorkloadEnabled() {
+		// Deployment images and pod template labels are reconciled by the
+		// APIManager logic. Nothing to upgrade.
+		return reconcile.Result{}, nil
+	}
+
 	res, err := u.upgradeImages()
 	if err != nil {
 		return res, fmt.Errorf("Upgrading images: %w", err)
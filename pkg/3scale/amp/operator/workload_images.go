@@ -0,0 +1,59 @@
+package operator
+
+import (
+	"fmt"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WorkloadImages returns the container image of each 3scale component indexed
+// by the "<imagestream>:<tag>" reference used in the DeploymentConfig image change triggers.
+// Used to render Deployments when ImageStreams are not available.
+func WorkloadImages(apimanager *appsv1alpha1.APIManager, client client.Client) (map[string]string, error) {
+	ampImages, err := AmpImages(apimanager)
+	if err != nil {
+		return nil, err
+	}
+
+	redis, err := Redis(apimanager, client)
+	if err != nil {
+		return nil, err
+	}
+
+	systemMySQLImage, err := SystemMySQLImage(apimanager)
+	if err != nil {
+		return nil, err
+	}
+
+	systemPostgreSQLImage, err := SystemPostgreSQLImage(apimanager)
+	if err != nil {
+		return nil, err
+	}
+
+	imageStreams := []*imagev1.ImageStream{
+		ampImages.BackendImageStream(),
+		ampImages.ZyncImageStream(),
+		ampImages.APICastImageStream(),
+		ampImages.SystemImageStream(),
+		ampImages.ZyncDatabasePostgreSQLImageStream(),
+		ampImages.SystemMemcachedImageStream(),
+		redis.BackendImageStream(),
+		redis.SystemImageStream(),
+		systemMySQLImage.ImageStream(),
+		systemPostgreSQLImage.ImageStream(),
+	}
+
+	images := map[string]string{}
+	for _, imageStream := range imageStreams {
+		for _, tag := range imageStream.Spec.Tags {
+			if tag.From != nil {
+				images[fmt.Sprintf("%s:%s", imageStream.Name, tag.Name)] = tag.From.Name
+			}
+		}
+	}
+
+	return images, nil
+}
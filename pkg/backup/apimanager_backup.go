@@ -410,6 +410,25 @@ func (b *APIManagerBackup) Role() *rbacv1.Role {
 					"list",
 				},
 			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{""},
+				Resources: []string{
+					"pods",
+				},
+				Verbs: []string{
+					"get",
+					"list",
+				},
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{""},
+				Resources: []string{
+					"pods/exec",
+				},
+				Verbs: []string{
+					"create",
+				},
+			},
 			rbacv1.PolicyRule{
 				APIGroups: []string{appsv1alpha1.GroupVersion.Group},
 				Resources: []string{
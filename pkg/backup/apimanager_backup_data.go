@@ -0,0 +1,240 @@
+package backup
+
+import (
+	"fmt"
+	"path"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/helper"
+)
+
+// Database data artifacts are stored under the DataArtifactsSubdir directory
+// of the backup data
+const (
+	DataArtifactsSubdir = "data"
+
+	SystemMySQLDataArtifactFileName      = "system-mysql.sql"
+	SystemPostgreSQLDataArtifactFileName = "system-postgresql.dump"
+	ZyncDatabaseDataArtifactFileName     = "zync-database.dump"
+	BackendRedisDataArtifactFileName     = "backend-redis.rdb"
+	SystemRedisDataArtifactFileName      = "system-redis.rdb"
+)
+
+// DataArtifactPath returns the path of a data artifact relative to
+// the root of the backup data
+func DataArtifactPath(fileName string) string {
+	return path.Join(DataArtifactsSubdir, fileName)
+}
+
+// PodLookupScript returns a shell snippet that sets the PODNAME variable
+// to the name of a running pod of the given deployment. It works for both
+// DeploymentConfig and Deployment workloads
+func PodLookupScript(deploymentName string) string {
+	return fmt.Sprintf(`
+PODNAME=$(oc get pods -l deploymentConfig=%s --field-selector=status.phase=Running --no-headers=true -o custom-columns=:metadata.name | head -n 1);
+if [ -z "${PODNAME}" ]; then
+  echo "No running pods found for %s";
+  exit 1;
+fi
+`, deploymentName, deploymentName)
+}
+
+func (b *APIManagerBackup) dataOptions() *APIManagerBackupDataOptions {
+	if b.options.APIManagerBackupPVCOptions == nil || b.options.APIManagerBackupDataOptions == nil {
+		return nil
+	}
+	return b.options.APIManagerBackupDataOptions
+}
+
+// SystemDatabaseDataArtifact returns the path of the system database dump.
+// Empty when the system database is not backed up
+func (b *APIManagerBackup) SystemDatabaseDataArtifact() string {
+	dataOptions := b.dataOptions()
+	if dataOptions == nil {
+		return ""
+	}
+	if dataOptions.SystemMySQL {
+		return DataArtifactPath(SystemMySQLDataArtifactFileName)
+	}
+	if dataOptions.SystemPostgreSQL {
+		return DataArtifactPath(SystemPostgreSQLDataArtifactFileName)
+	}
+	return ""
+}
+
+func (b *APIManagerBackup) BackupSystemDatabaseToPVCJob() *batchv1.Job {
+	dataOptions := b.dataOptions()
+	if dataOptions == nil {
+		return nil
+	}
+
+	// Credentials are read from the database container environment
+	var deploymentName, dumpCommand string
+	switch {
+	case dataOptions.SystemMySQL:
+		deploymentName = component.SystemMySQLDeploymentName
+		dumpCommand = `MYSQL_PWD="${MYSQL_ROOT_PASSWORD}" mysqldump -h 127.0.0.1 -u root --single-transaction --routines --triggers "${MYSQL_DATABASE}"`
+	case dataOptions.SystemPostgreSQL:
+		deploymentName = component.SystemPostgreSQLDeploymentName
+		dumpCommand = `PGPASSWORD="${POSTGRESQL_PASSWORD}" pg_dump -h 127.0.0.1 -U "${POSTGRESQL_USER}" -Fc "${POSTGRESQL_DATABASE}"`
+	default:
+		return nil
+	}
+
+	return b.dataBackupJob("backup-system-db", deploymentName, dumpCommand, b.SystemDatabaseDataArtifact())
+}
+
+func (b *APIManagerBackup) BackupZyncDatabaseToPVCJob() *batchv1.Job {
+	dataOptions := b.dataOptions()
+	if dataOptions == nil || !dataOptions.ZyncDatabase {
+		return nil
+	}
+
+	dumpCommand := `PGPASSWORD="${POSTGRESQL_PASSWORD}" pg_dump -h 127.0.0.1 -U "${POSTGRESQL_USER}" -Fc "${POSTGRESQL_DATABASE}"`
+	return b.dataBackupJob("backup-zync-db", component.ZyncDatabaseDeploymentName, dumpCommand,
+		DataArtifactPath(ZyncDatabaseDataArtifactFileName))
+}
+
+func (b *APIManagerBackup) BackupBackendRedisToPVCJob() *batchv1.Job {
+	dataOptions := b.dataOptions()
+	if dataOptions == nil || !dataOptions.BackendRedis {
+		return nil
+	}
+
+	return b.dataBackupJob("backup-backend-redis", component.BackendRedisDeploymentName, redisSnapshotCommand(),
+		DataArtifactPath(BackendRedisDataArtifactFileName))
+}
+
+func (b *APIManagerBackup) BackupSystemRedisToPVCJob() *batchv1.Job {
+	dataOptions := b.dataOptions()
+	if dataOptions == nil || !dataOptions.SystemRedis {
+		return nil
+	}
+
+	return b.dataBackupJob("backup-system-redis", component.SystemRedisDeploymentName, redisSnapshotCommand(),
+		DataArtifactPath(SystemRedisDataArtifactFileName))
+}
+
+// DataJobs returns the data backup jobs to be run
+func (b *APIManagerBackup) DataJobs() []*batchv1.Job {
+	jobs := []*batchv1.Job{}
+	for _, job := range []*batchv1.Job{
+		b.BackupSystemDatabaseToPVCJob(),
+		b.BackupZyncDatabaseToPVCJob(),
+		b.BackupBackendRedisToPVCJob(),
+		b.BackupSystemRedisToPVCJob(),
+	} {
+		if job != nil {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// redisSnapshotCommand takes an RDB snapshot through the replication
+// protocol, so the running instance is neither blocked nor its own
+// dump file overwritten
+func redisSnapshotCommand() string {
+	return `redis-cli --rdb /tmp/apimanager-backup.rdb > /dev/null && cat /tmp/apimanager-backup.rdb && rm -f /tmp/apimanager-backup.rdb`
+}
+
+// dataBackupJob creates a Job that runs the dump command in a running pod
+// of the deployment and stores its standard output in the artifact path
+func (b *APIManagerBackup) dataBackupJob(name, deploymentName, dumpCommand, artifactPath string) *batchv1.Job {
+	jobName, err := helper.UIDBasedJobName(name, b.options.APIManagerBackupUID)
+	if err != nil {
+		panic(err)
+	}
+
+	var completions int32 = 1
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: b.options.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			Completions: &completions,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Volumes: []v1.Volume{
+						b.pvcBackupDestinationPodVolume(),
+					},
+					Containers: []v1.Container{
+						v1.Container{
+							Name:  name,
+							Image: b.options.OCCLIImageURL,
+							Command: []string{
+								"/bin/bash",
+							},
+							Args: []string{
+								"-c",
+								"-e",
+								b.dataBackupContainerArgs(deploymentName, dumpCommand, artifactPath),
+							},
+							VolumeMounts: []v1.VolumeMount{
+								b.pvcBackupDestinationContainerVolumeMount(),
+							},
+						},
+					},
+					RestartPolicy:      v1.RestartPolicyNever, // Only "Never" or "OnFailure" are accepted in Kubernetes Jobs
+					ServiceAccountName: ServiceAccountName,
+				},
+			},
+		},
+	}
+}
+
+func (b *APIManagerBackup) dataBackupContainerArgs(deploymentName, dumpCommand, artifactPath string) string {
+	// The dump is written to a temporary file first so a failed
+	// job never leaves a truncated artifact behind
+	return fmt.Sprintf(`
+BASEPATH='%s';
+ARTIFACT="${BASEPATH}/%s";
+%s
+mkdir -p $(dirname ${ARTIFACT});
+oc exec ${PODNAME} -- bash -c '%s' > ${ARTIFACT}.tmp;
+mv ${ARTIFACT}.tmp ${ARTIFACT};
+`,
+		BackupPVCMountPath,
+		artifactPath,
+		PodLookupScript(deploymentName),
+		dumpCommand,
+	)
+}
+
+// DataArtifacts returns the data artifacts produced by the data backup
+// jobs. Nil when no database data is backed up
+func (b *APIManagerBackup) DataArtifacts() *appsv1alpha1.APIManagerDataArtifacts {
+	dataOptions := b.dataOptions()
+	if dataOptions == nil {
+		return nil
+	}
+
+	res := &appsv1alpha1.APIManagerDataArtifacts{}
+	if artifact := b.SystemDatabaseDataArtifact(); artifact != "" {
+		res.SystemDatabase = &artifact
+	}
+	if dataOptions.ZyncDatabase {
+		artifact := DataArtifactPath(ZyncDatabaseDataArtifactFileName)
+		res.ZyncDatabase = &artifact
+	}
+	if dataOptions.BackendRedis {
+		artifact := DataArtifactPath(BackendRedisDataArtifactFileName)
+		res.BackendRedis = &artifact
+	}
+	if dataOptions.SystemRedis {
+		artifact := DataArtifactPath(SystemRedisDataArtifactFileName)
+		res.SystemRedis = &artifact
+	}
+
+	return res
+}
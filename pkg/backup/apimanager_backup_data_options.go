@@ -0,0 +1,20 @@
+package backup
+
+// APIManagerBackupDataOptions holds which operator-managed databases
+// are backed up. Databases not deployed by the operator are never set
+type APIManagerBackupDataOptions struct {
+	SystemMySQL      bool
+	SystemPostgreSQL bool
+	ZyncDatabase     bool
+	BackendRedis     bool
+	SystemRedis      bool
+}
+
+func NewAPIManagerBackupDataOptions() *APIManagerBackupDataOptions {
+	return &APIManagerBackupDataOptions{}
+}
+
+// Enabled returns true when at least one database is backed up
+func (a *APIManagerBackupDataOptions) Enabled() bool {
+	return a.SystemMySQL || a.SystemPostgreSQL || a.ZyncDatabase || a.BackendRedis || a.SystemRedis
+}
@@ -0,0 +1,135 @@
+package backup
+
+import (
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testNamespace = "backup-unittest"
+
+func testAPIManager() *appsv1alpha1.APIManager {
+	return &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: testNamespace},
+		Spec: appsv1alpha1.APIManagerSpec{
+			System: &appsv1alpha1.SystemSpec{},
+		},
+	}
+}
+
+func testAPIManagerBackupCR(data *appsv1alpha1.APIManagerBackupDataSpec) *appsv1alpha1.APIManagerBackup {
+	return &appsv1alpha1.APIManagerBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-backup",
+			Namespace: testNamespace,
+			UID:       types.UID("f4a0ab5a-2a4b-4f3c-a6e2-8a1f8b4f3c21"),
+		},
+		Spec: appsv1alpha1.APIManagerBackupSpec{
+			BackupDestination: appsv1alpha1.APIManagerBackupDestination{
+				PersistentVolumeClaim: &appsv1alpha1.PersistentVolumeClaimBackupDestination{},
+			},
+			Data: data,
+		},
+	}
+}
+
+func testBackup(t *testing.T, apimanager *appsv1alpha1.APIManager, data *appsv1alpha1.APIManagerBackupDataSpec) *APIManagerBackup {
+	s := runtime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := appsv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	cl := fake.NewFakeClientWithScheme(s, apimanager)
+
+	options, err := NewAPIManagerBackupOptionsProvider(testAPIManagerBackupCR(data), cl).Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAPIManagerBackup(options)
+}
+
+func TestAPIManagerBackupWithoutData(t *testing.T) {
+	b := testBackup(t, testAPIManager(), nil)
+	if len(b.DataJobs()) != 0 {
+		t.Errorf("unexpected data jobs: %d", len(b.DataJobs()))
+	}
+	if b.DataArtifacts() != nil {
+		t.Errorf("unexpected data artifacts: %v", b.DataArtifacts())
+	}
+}
+
+func TestAPIManagerBackupData(t *testing.T) {
+	trueVal := true
+	data := &appsv1alpha1.APIManagerBackupDataSpec{
+		SystemDatabase: &trueVal,
+		ZyncDatabase:   &trueVal,
+		BackendRedis:   &trueVal,
+		SystemRedis:    &trueVal,
+	}
+
+	b := testBackup(t, testAPIManager(), data)
+
+	if len(b.DataJobs()) != 4 {
+		t.Fatalf("expected 4 data jobs, got %d", len(b.DataJobs()))
+	}
+
+	args := b.BackupSystemDatabaseToPVCJob().Spec.Template.Spec.Containers[0].Args[2]
+	if !strings.Contains(args, "mysqldump") || !strings.Contains(args, "deploymentConfig=system-mysql") {
+		t.Errorf("unexpected system database backup script: %s", args)
+	}
+	args = b.BackupBackendRedisToPVCJob().Spec.Template.Spec.Containers[0].Args[2]
+	if !strings.Contains(args, "redis-cli --rdb") || !strings.Contains(args, "data/backend-redis.rdb") {
+		t.Errorf("unexpected backend redis backup script: %s", args)
+	}
+
+	artifacts := b.DataArtifacts()
+	if artifacts == nil || artifacts.SystemDatabase == nil || *artifacts.SystemDatabase != "data/system-mysql.sql" {
+		t.Fatalf("unexpected system database artifact: %v", artifacts)
+	}
+	if artifacts.ZyncDatabase == nil || artifacts.BackendRedis == nil || artifacts.SystemRedis == nil {
+		t.Errorf("missing data artifacts: %v", artifacts)
+	}
+}
+
+func TestAPIManagerBackupDataPostgreSQL(t *testing.T) {
+	trueVal := true
+	apimanager := testAPIManager()
+	apimanager.Spec.System.DatabaseSpec = &appsv1alpha1.SystemDatabaseSpec{
+		PostgreSQL: &appsv1alpha1.SystemPostgreSQLSpec{},
+	}
+
+	b := testBackup(t, apimanager, &appsv1alpha1.APIManagerBackupDataSpec{SystemDatabase: &trueVal})
+
+	args := b.BackupSystemDatabaseToPVCJob().Spec.Template.Spec.Containers[0].Args[2]
+	if !strings.Contains(args, "pg_dump") || !strings.Contains(args, "data/system-postgresql.dump") {
+		t.Errorf("unexpected system database backup script: %s", args)
+	}
+}
+
+func TestAPIManagerBackupDataExternalDatabases(t *testing.T) {
+	trueVal := true
+	apimanager := testAPIManager()
+	apimanager.Spec.ExternalComponents = appsv1alpha1.AllComponentsExternal()
+
+	b := testBackup(t, apimanager, &appsv1alpha1.APIManagerBackupDataSpec{
+		SystemDatabase: &trueVal,
+		ZyncDatabase:   &trueVal,
+		BackendRedis:   &trueVal,
+		SystemRedis:    &trueVal,
+	})
+
+	if len(b.DataJobs()) != 0 {
+		t.Errorf("external databases should not be backed up, got %d jobs", len(b.DataJobs()))
+	}
+	if b.DataArtifacts() != nil {
+		t.Errorf("unexpected data artifacts: %v", b.DataArtifacts())
+	}
+}
@@ -14,6 +14,9 @@ type APIManagerBackupOptions struct {
 	APIManager                 *appsv1alpha1.APIManager    `validate:"required"`
 	APIManagerBackupPVCOptions *APIManagerBackupPVCOptions `validate:"required"`
 	OCCLIImageURL              string                      `validate:"required"`
+
+	APIManagerBackupDataOptions *APIManagerBackupDataOptions // Optional. Databases whose data is included in the backup
+	APIManagerBackupS3Options   *S3Options                   // Optional. Set when the backup data is uploaded to S3. APIManagerBackupPVCOptions then describes the work PVC
 }
 
 func NewAPIManagerBackupOptions() *APIManagerBackupOptions {
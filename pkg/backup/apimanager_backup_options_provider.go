@@ -40,34 +40,95 @@ func (a *APIManagerBackupOptionsProvider) Options() (*APIManagerBackupOptions, e
 	res.APIManagerName = apiManager.Name
 	res.OCCLIImageURL = a.ocCLIImageURL()
 
+	destination := a.APIManagerBackupCR.Spec.BackupDestination
+	if destination.PersistentVolumeClaim != nil && destination.S3 != nil {
+		return nil, fmt.Errorf("Only one backup destination can be specified")
+	}
+
 	pvcOptions, err := a.pvcBackupOptions()
 	if err != nil {
 		return nil, err
 	}
 
+	s3Options, err := a.s3BackupOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	// TODO can this checks be omitted and just rely on the validator package in the APIManagerBackup struct?
 	if pvcOptions == nil {
 		return nil, fmt.Errorf("At least one backup destination has to be specified")
 	}
 
 	res.APIManagerBackupPVCOptions = pvcOptions
+	res.APIManagerBackupDataOptions = a.dataBackupOptions(apiManager)
+	res.APIManagerBackupS3Options = s3Options
 
 	return res, res.Validate()
 }
 
+// dataBackupOptions returns the databases to be backed up. Only databases
+// deployed by the operator are considered. Requested databases configured
+// as external components are skipped
+func (a *APIManagerBackupOptionsProvider) dataBackupOptions(apiManager *appsv1alpha1.APIManager) *APIManagerBackupDataOptions {
+	dataSpec := a.APIManagerBackupCR.Spec.Data
+	if dataSpec == nil {
+		return nil
+	}
+
+	res := NewAPIManagerBackupDataOptions()
+	if isTrue(dataSpec.SystemDatabase) {
+		res.SystemMySQL = apiManager.IsSystemMysqlEnabled()
+		res.SystemPostgreSQL = apiManager.IsSystemPostgreSQLEnabled()
+	}
+	res.ZyncDatabase = isTrue(dataSpec.ZyncDatabase) && !apiManager.IsExternal(appsv1alpha1.ZyncDatabase)
+	res.BackendRedis = isTrue(dataSpec.BackendRedis) && !apiManager.IsExternal(appsv1alpha1.BackendRedis)
+	res.SystemRedis = isTrue(dataSpec.SystemRedis) && !apiManager.IsExternal(appsv1alpha1.SystemRedis)
+
+	if !res.Enabled() {
+		return nil
+	}
+
+	return res
+}
+
 func (a *APIManagerBackupOptionsProvider) pvcBackupOptions() (*APIManagerBackupPVCOptions, error) {
-	if a.APIManagerBackupCR.Spec.BackupDestination.PersistentVolumeClaim == nil {
+	pvcDestination := a.APIManagerBackupCR.Spec.BackupDestination.PersistentVolumeClaim
+
+	// With S3 destinations the backup data is gathered in a work
+	// PVC before being uploaded
+	if s3Destination := a.APIManagerBackupCR.Spec.BackupDestination.S3; s3Destination != nil {
+		pvcDestination = s3Destination.WorkVolume
+		if pvcDestination == nil {
+			pvcDestination = &appsv1alpha1.PersistentVolumeClaimBackupDestination{}
+		}
+		if pvcDestination.Resources == nil && pvcDestination.VolumeName == nil {
+			pvcDestination = pvcDestination.DeepCopy()
+			pvcDestination.Resources = &appsv1alpha1.PersistentVolumeClaimResources{Requests: DefaultS3WorkVolumeSize}
+		}
+	}
+
+	if pvcDestination == nil {
 		return nil, nil
 	}
 
 	res := NewAPIManagerBackupPVCOptions()
 	res.BackupDestinationPVC.Name = fmt.Sprintf("apimanager-backup-%s", a.APIManagerBackupCR.Name)
-	res.BackupDestinationPVC.StorageClass = a.APIManagerBackupCR.Spec.BackupDestination.PersistentVolumeClaim.StorageClass
-	res.BackupDestinationPVC.VolumeName = a.APIManagerBackupCR.Spec.BackupDestination.PersistentVolumeClaim.VolumeName
-	if a.APIManagerBackupCR.Spec.BackupDestination.PersistentVolumeClaim.Resources != nil {
-		res.BackupDestinationPVC.StorageRequests = &a.APIManagerBackupCR.Spec.BackupDestination.PersistentVolumeClaim.Resources.Requests
+	res.BackupDestinationPVC.StorageClass = pvcDestination.StorageClass
+	res.BackupDestinationPVC.VolumeName = pvcDestination.VolumeName
+	if pvcDestination.Resources != nil {
+		res.BackupDestinationPVC.StorageRequests = &pvcDestination.Resources.Requests
+	}
+
+	return res, res.Validate()
+}
+
+func (a *APIManagerBackupOptionsProvider) s3BackupOptions() (*S3Options, error) {
+	if a.APIManagerBackupCR.Spec.BackupDestination.S3 == nil {
+		return nil, nil
 	}
 
+	res := NewS3OptionsFromLocation(&a.APIManagerBackupCR.Spec.BackupDestination.S3.S3Location)
 	return res, res.Validate()
 }
 
@@ -95,6 +156,10 @@ func (a *APIManagerBackupOptionsProvider) autodiscoveredAPIManager() (*appsv1alp
 
 }
 
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
 func (a *APIManagerBackupOptionsProvider) ocCLIImageURL() string {
 	return helper.GetEnvVar("OSE_CLI_IMAGE", component.OCCLIImageURL())
 }
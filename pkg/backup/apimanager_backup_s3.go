@@ -0,0 +1,100 @@
+package backup
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/3scale/3scale-operator/pkg/helper"
+)
+
+// BackupDataS3ObjectURL returns the URL of the object the backup data is
+// uploaded to. Empty when the backup destination is not S3
+func (b *APIManagerBackup) BackupDataS3ObjectURL() string {
+	if b.options.APIManagerBackupS3Options == nil {
+		return ""
+	}
+	return b.options.APIManagerBackupS3Options.ArchiveURL(b.options.APIManagerBackupName)
+}
+
+// BackupWorkPVC returns the PVC where the backup data is gathered before
+// being uploaded to S3. Nil when the backup destination is not S3
+func (b *APIManagerBackup) BackupWorkPVC() *v1.PersistentVolumeClaim {
+	if b.options.APIManagerBackupS3Options == nil {
+		return nil
+	}
+	return b.BackupDestinationPVC()
+}
+
+// UploadBackupToS3Job returns the Job archiving the backup data gathered in
+// the work PVC and uploading it to S3. Nil when the backup destination is
+// not S3
+func (b *APIManagerBackup) UploadBackupToS3Job() *batchv1.Job {
+	s3Options := b.options.APIManagerBackupS3Options
+	if s3Options == nil || b.options.APIManagerBackupPVCOptions == nil {
+		return nil
+	}
+
+	jobName, err := helper.UIDBasedJobName("backup-s3-upload", b.options.APIManagerBackupUID)
+	if err != nil {
+		panic(err)
+	}
+
+	volumes := append([]v1.Volume{b.pvcBackupDestinationPodVolume()}, s3Options.PodVolumes()...)
+
+	var completions int32 = 1
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: b.options.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			Completions: &completions,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Volumes: volumes,
+					// The archive is created before the upload
+					// container starts
+					InitContainers: []v1.Container{
+						v1.Container{
+							Name:  "backup-s3-archive",
+							Image: b.options.OCCLIImageURL,
+							Command: []string{
+								"/bin/bash",
+							},
+							Args: []string{
+								"-c",
+								"-e",
+								b.archiveBackupContainerArgs(),
+							},
+							VolumeMounts: []v1.VolumeMount{
+								b.pvcBackupDestinationContainerVolumeMount(),
+								ArchiveContainerVolumeMount(),
+							},
+						},
+					},
+					Containers: []v1.Container{
+						s3Options.AWSCLIContainer("backup-s3-upload", "cp", "--only-show-errors", ArchiveFilePath(), b.BackupDataS3ObjectURL()),
+					},
+					RestartPolicy:      v1.RestartPolicyNever, // Only "Never" or "OnFailure" are accepted in Kubernetes Jobs
+					ServiceAccountName: ServiceAccountName,
+				},
+			},
+		},
+	}
+}
+
+func (b *APIManagerBackup) archiveBackupContainerArgs() string {
+	return fmt.Sprintf(`
+tar -czf '%s' --exclude='*.tmp' -C '%s' .;
+`,
+		ArchiveFilePath(),
+		BackupPVCMountPath,
+	)
+}
@@ -0,0 +1,164 @@
+package backup
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	validator "github.com/go-playground/validator/v10"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/helper"
+)
+
+const (
+	DefaultS3Region       = "us-east-1"
+	S3CABundleFieldName   = "ca-bundle.crt"
+	S3CABundleMountPath   = "/etc/pki/s3"
+	S3ArchiveMountPath    = "/archive"
+	S3ArchiveFileName     = "backup.tar.gz"
+	s3CABundleVolumeName  = "s3-ca-bundle"
+	s3ArchiveVolumeName   = "archive"
+	s3ArchiveKeyExtension = ".tar.gz"
+)
+
+// DefaultS3WorkVolumeSize is the size of the temporary PersistentVolumeClaim
+// holding the backup data when no work volume is configured
+var DefaultS3WorkVolumeSize = resource.MustParse("10Gi")
+
+// S3Options holds the S3 API-compatible object storage location
+// of backup data archives
+type S3Options struct {
+	Bucket                string `validate:"required"`
+	Prefix                string
+	Region                string `validate:"required"`
+	Endpoint              *string
+	CredentialsSecretName string `validate:"required"`
+	CABundleSecretName    *string
+	AWSCLIImageURL        string `validate:"required"`
+}
+
+func NewS3Options() *S3Options {
+	return &S3Options{}
+}
+
+// NewS3OptionsFromLocation returns the S3Options of the given location
+func NewS3OptionsFromLocation(location *appsv1alpha1.S3Location) *S3Options {
+	res := NewS3Options()
+	res.Bucket = location.Bucket
+	if location.Prefix != nil {
+		res.Prefix = strings.Trim(*location.Prefix, "/")
+	}
+	res.Region = DefaultS3Region
+	if location.Region != nil && *location.Region != "" {
+		res.Region = *location.Region
+	}
+	res.Endpoint = location.Endpoint
+	res.CredentialsSecretName = location.CredentialsSecretRef.Name
+	if location.CABundleSecretRef != nil {
+		res.CABundleSecretName = &location.CABundleSecretRef.Name
+	}
+	res.AWSCLIImageURL = AWSCLIImageURL()
+	return res
+}
+
+func (s *S3Options) Validate() error {
+	validate := validator.New()
+	return validate.Struct(s)
+}
+
+// ArchiveKey returns the object key of the backup data archive of the
+// given APIManagerBackup
+func (s *S3Options) ArchiveKey(backupName string) string {
+	return path.Join(s.Prefix, backupName+s3ArchiveKeyExtension)
+}
+
+// ArchiveURL returns the s3://<bucket>/<key> URL of the backup data
+// archive of the given APIManagerBackup
+func (s *S3Options) ArchiveURL(backupName string) string {
+	return fmt.Sprintf("s3://%s/%s", s.Bucket, s.ArchiveKey(backupName))
+}
+
+// AWSCLIContainer returns a container running the aws CLI with the given
+// s3 subcommand arguments. Credentials, region and CA bundle are configured
+// from the options
+func (s *S3Options) AWSCLIContainer(name string, s3Args ...string) v1.Container {
+	args := []string{"--region", s.Region}
+	if s.Endpoint != nil && *s.Endpoint != "" {
+		args = append(args, "--endpoint-url", *s.Endpoint)
+	}
+	args = append(args, "s3")
+	args = append(args, s3Args...)
+
+	env := []v1.EnvVar{
+		helper.EnvVarFromSecret(component.AwsAccessKeyID, s.CredentialsSecretName, component.AwsAccessKeyID),
+		helper.EnvVarFromSecret(component.AwsSecretAccessKey, s.CredentialsSecretName, component.AwsSecretAccessKey),
+	}
+	volumeMounts := []v1.VolumeMount{
+		ArchiveContainerVolumeMount(),
+	}
+	if s.CABundleSecretName != nil {
+		env = append(env, helper.EnvVarFromValue("AWS_CA_BUNDLE", path.Join(S3CABundleMountPath, S3CABundleFieldName)))
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      s3CABundleVolumeName,
+			MountPath: S3CABundleMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	return v1.Container{
+		Name:         name,
+		Image:        s.AWSCLIImageURL,
+		Command:      []string{"aws"},
+		Args:         args,
+		Env:          env,
+		VolumeMounts: volumeMounts,
+	}
+}
+
+// PodVolumes returns the volumes required by the archive transfer pods
+func (s *S3Options) PodVolumes() []v1.Volume {
+	volumes := []v1.Volume{
+		v1.Volume{
+			Name: s3ArchiveVolumeName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	if s.CABundleSecretName != nil {
+		volumes = append(volumes, v1.Volume{
+			Name: s3CABundleVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: *s.CABundleSecretName,
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// ArchiveContainerVolumeMount returns the mount of the volume holding the
+// backup data archive while it is transferred
+func ArchiveContainerVolumeMount() v1.VolumeMount {
+	return v1.VolumeMount{
+		Name:      s3ArchiveVolumeName,
+		MountPath: S3ArchiveMountPath,
+	}
+}
+
+// ArchiveFilePath returns the path of the backup data archive in the
+// archive volume
+func ArchiveFilePath() string {
+	return path.Join(S3ArchiveMountPath, S3ArchiveFileName)
+}
+
+// AWSCLIImageURL returns the image used to transfer archives to and
+// from S3 API-compatible object storages
+func AWSCLIImageURL() string {
+	return helper.GetEnvVar("RELATED_IMAGE_AWS_CLI", component.AWSCLIImageURL())
+}
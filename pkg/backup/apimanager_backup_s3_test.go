@@ -0,0 +1,146 @@
+package backup
+
+import (
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testS3Location() appsv1alpha1.S3Location {
+	prefix := "/backups/3scale/"
+	endpoint := "http://minio.minio.svc:9000"
+	return appsv1alpha1.S3Location{
+		Bucket:               "mybucket",
+		Prefix:               &prefix,
+		Endpoint:             &endpoint,
+		CredentialsSecretRef: v1.LocalObjectReference{Name: "s3-credentials"},
+	}
+}
+
+func testS3Backup(t *testing.T, destination appsv1alpha1.APIManagerBackupDestination) (*APIManagerBackup, error) {
+	s := runtime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := appsv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	cl := fake.NewFakeClientWithScheme(s, testAPIManager())
+
+	cr := testAPIManagerBackupCR(nil)
+	cr.Spec.BackupDestination = destination
+	options, err := NewAPIManagerBackupOptionsProvider(cr, cl).Options()
+	if err != nil {
+		return nil, err
+	}
+	return NewAPIManagerBackup(options), nil
+}
+
+func TestS3OptionsFromLocation(t *testing.T) {
+	location := testS3Location()
+	options := NewS3OptionsFromLocation(&location)
+
+	if options.Region != DefaultS3Region {
+		t.Errorf("unexpected region: %s", options.Region)
+	}
+	if options.ArchiveKey("example-backup") != "backups/3scale/example-backup.tar.gz" {
+		t.Errorf("unexpected archive key: %s", options.ArchiveKey("example-backup"))
+	}
+	if options.ArchiveURL("example-backup") != "s3://mybucket/backups/3scale/example-backup.tar.gz" {
+		t.Errorf("unexpected archive url: %s", options.ArchiveURL("example-backup"))
+	}
+
+	container := options.AWSCLIContainer("upload", "cp", "a", "b")
+	expectedArgs := "--region us-east-1 --endpoint-url http://minio.minio.svc:9000 s3 cp a b"
+	if strings.Join(container.Args, " ") != expectedArgs {
+		t.Errorf("unexpected args: %v", container.Args)
+	}
+	if len(options.PodVolumes()) != 1 {
+		t.Errorf("unexpected volumes: %v", options.PodVolumes())
+	}
+
+	caSecret := v1.LocalObjectReference{Name: "s3-ca"}
+	location.CABundleSecretRef = &caSecret
+	options = NewS3OptionsFromLocation(&location)
+	container = options.AWSCLIContainer("upload", "cp", "a", "b")
+	caEnvFound := false
+	for _, env := range container.Env {
+		if env.Name == "AWS_CA_BUNDLE" {
+			caEnvFound = true
+		}
+	}
+	if !caEnvFound {
+		t.Error("AWS_CA_BUNDLE env var not found")
+	}
+	if len(options.PodVolumes()) != 2 {
+		t.Errorf("unexpected volumes: %v", options.PodVolumes())
+	}
+}
+
+func TestAPIManagerBackupS3Destination(t *testing.T) {
+	b, err := testS3Backup(t, appsv1alpha1.APIManagerBackupDestination{
+		S3: &appsv1alpha1.S3BackupDestination{S3Location: testS3Location()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workPVC := b.BackupWorkPVC()
+	if workPVC == nil {
+		t.Fatal("work PVC expected")
+	}
+	storage := workPVC.Spec.Resources.Requests[v1.ResourceStorage]
+	if storage.Cmp(DefaultS3WorkVolumeSize) != 0 {
+		t.Errorf("unexpected work PVC size: %s", storage.String())
+	}
+
+	if b.BackupDataS3ObjectURL() != "s3://mybucket/backups/3scale/example-backup.tar.gz" {
+		t.Errorf("unexpected object url: %s", b.BackupDataS3ObjectURL())
+	}
+
+	job := b.UploadBackupToS3Job()
+	if job == nil {
+		t.Fatal("upload job expected")
+	}
+	if len(job.Spec.Template.Spec.InitContainers) != 1 || len(job.Spec.Template.Spec.Containers) != 1 {
+		t.Fatalf("unexpected containers: %v", job.Spec.Template.Spec)
+	}
+	uploadArgs := strings.Join(job.Spec.Template.Spec.Containers[0].Args, " ")
+	if !strings.HasSuffix(uploadArgs, ArchiveFilePath()+" "+b.BackupDataS3ObjectURL()) {
+		t.Errorf("unexpected upload args: %s", uploadArgs)
+	}
+}
+
+func TestAPIManagerBackupPVCDestinationHasNoS3Resources(t *testing.T) {
+	b, err := testS3Backup(t, appsv1alpha1.APIManagerBackupDestination{
+		PersistentVolumeClaim: &appsv1alpha1.PersistentVolumeClaimBackupDestination{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.BackupWorkPVC() != nil {
+		t.Error("unexpected work PVC")
+	}
+	if b.UploadBackupToS3Job() != nil {
+		t.Error("unexpected upload job")
+	}
+	if b.BackupDataS3ObjectURL() != "" {
+		t.Errorf("unexpected object url: %s", b.BackupDataS3ObjectURL())
+	}
+}
+
+func TestAPIManagerBackupMultipleDestinations(t *testing.T) {
+	_, err := testS3Backup(t, appsv1alpha1.APIManagerBackupDestination{
+		PersistentVolumeClaim: &appsv1alpha1.PersistentVolumeClaimBackupDestination{},
+		S3:                    &appsv1alpha1.S3BackupDestination{S3Location: testS3Location()},
+	})
+	if err == nil {
+		t.Error("error expected with multiple backup destinations")
+	}
+}
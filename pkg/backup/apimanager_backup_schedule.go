@@ -0,0 +1,208 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robfig/cron"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+)
+
+// maxMissedScheduleTimes bounds the schedule times walked through when
+// looking for the most recent missed one
+const maxMissedScheduleTimes = 10000
+
+// ParseSchedule parses a standard five fields cron expression
+func ParseSchedule(schedule string) (cron.Schedule, error) {
+	return cron.ParseStandard(schedule)
+}
+
+// MostRecentScheduleTime returns the most recent schedule time after
+// earliest and not after now. Nil when there is none
+func MostRecentScheduleTime(schedule cron.Schedule, earliest, now time.Time) (*time.Time, error) {
+	var res *time.Time
+	for idx, t := 0, schedule.Next(earliest); !t.IsZero() && !t.After(now); idx, t = idx+1, schedule.Next(t) {
+		if idx >= maxMissedScheduleTimes {
+			return nil, fmt.Errorf("Too many missed schedule times since %s", earliest)
+		}
+		scheduledTime := t
+		res = &scheduledTime
+	}
+	return res, nil
+}
+
+// ScheduledBackupName returns the name of the APIManagerBackup created by
+// the schedule for the given time
+func ScheduledBackupName(scheduleName string, scheduledTime time.Time) string {
+	return fmt.Sprintf("%s-%s", scheduleName, scheduledTime.UTC().Format("20060102150405"))
+}
+
+// ScheduledBackup returns the APIManagerBackup created by the schedule for
+// the given time
+func ScheduledBackup(schedule *appsv1alpha1.APIManagerBackupSchedule, scheduledTime time.Time) *appsv1alpha1.APIManagerBackup {
+	labels := map[string]string{}
+	for key, value := range schedule.Spec.BackupTemplate.Labels {
+		labels[key] = value
+	}
+	labels[appsv1alpha1.APIManagerBackupScheduleLabelKey] = schedule.Name
+
+	return &appsv1alpha1.APIManagerBackup{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1alpha1.GroupVersion.String(),
+			Kind:       "APIManagerBackup",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ScheduledBackupName(schedule.Name, scheduledTime),
+			Namespace: schedule.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				appsv1alpha1.APIManagerBackupScheduledTimeAnnotationKey: scheduledTime.UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: *schedule.Spec.BackupTemplate.Spec.DeepCopy(),
+	}
+}
+
+// BackupScheduledTime returns the time the backup was scheduled for.
+// Backups without the scheduled time annotation use their creation time
+func BackupScheduledTime(backupCR *appsv1alpha1.APIManagerBackup) time.Time {
+	if value, ok := backupCR.Annotations[appsv1alpha1.APIManagerBackupScheduledTimeAnnotationKey]; ok {
+		if scheduledTime, err := time.Parse(time.RFC3339, value); err == nil {
+			return scheduledTime
+		}
+	}
+	return backupCR.CreationTimestamp.Time
+}
+
+// BackupStalled returns true when the backup has not finished within the
+// deadline counted from its scheduled time
+func BackupStalled(backupCR *appsv1alpha1.APIManagerBackup, deadline time.Duration, now time.Time) bool {
+	if backupCR.BackupCompleted() || backupCR.BackupFailed() {
+		return false
+	}
+	return now.Sub(BackupScheduledTime(backupCR)) > deadline
+}
+
+// SortBackupsByScheduledTime sorts the backups, most recent first
+func SortBackupsByScheduledTime(backups []appsv1alpha1.APIManagerBackup) {
+	sort.SliceStable(backups, func(i, j int) bool {
+		return BackupScheduledTime(&backups[i]).After(BackupScheduledTime(&backups[j]))
+	})
+}
+
+// BackupsToPrune returns the backups not selected by the retention rules.
+// Only completed backups are evaluated by the rules. Failed backups are
+// pruned once a more recent backup has completed. Backups in progress are
+// never pruned
+func BackupsToPrune(backups []appsv1alpha1.APIManagerBackup, retention *appsv1alpha1.APIManagerBackupRetention) []appsv1alpha1.APIManagerBackup {
+	if retention == nil {
+		return nil
+	}
+
+	sorted := append([]appsv1alpha1.APIManagerBackup{}, backups...)
+	SortBackupsByScheduledTime(sorted)
+
+	keep := map[string]bool{}
+	keepLast := int32Value(retention.KeepLast)
+	keepDaily := int32Value(retention.KeepDaily)
+	keepWeekly := int32Value(retention.KeepWeekly)
+	days := map[string]bool{}
+	weeks := map[string]bool{}
+	var completed int32
+	for idx := range sorted {
+		backupCR := &sorted[idx]
+		if !backupCR.BackupCompleted() {
+			continue
+		}
+
+		if completed < keepLast {
+			keep[backupCR.Name] = true
+		}
+		completed++
+
+		scheduledTime := BackupScheduledTime(backupCR).UTC()
+		day := scheduledTime.Format("2006-01-02")
+		if !days[day] && int32(len(days)) < keepDaily {
+			days[day] = true
+			keep[backupCR.Name] = true
+		}
+		year, week := scheduledTime.ISOWeek()
+		weekKey := fmt.Sprintf("%d-%d", year, week)
+		if !weeks[weekKey] && int32(len(weeks)) < keepWeekly {
+			weeks[weekKey] = true
+			keep[backupCR.Name] = true
+		}
+	}
+
+	res := []appsv1alpha1.APIManagerBackup{}
+	completedFound := false
+	for idx := range sorted {
+		backupCR := sorted[idx]
+		switch {
+		case backupCR.BackupCompleted():
+			if !keep[backupCR.Name] {
+				res = append(res, backupCR)
+			}
+			completedFound = true
+		case backupCR.BackupFailed():
+			if completedFound {
+				res = append(res, backupCR)
+			}
+		}
+	}
+	return res
+}
+
+func int32Value(value *int32) int32 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+// DeleteS3BackupDataJob returns the Job deleting the backup data archive
+// uploaded to S3 by the given backup. Nil when the backup has not uploaded
+// any archive
+func DeleteS3BackupDataJob(backupCR *appsv1alpha1.APIManagerBackup) *batchv1.Job {
+	s3Destination := backupCR.Spec.BackupDestination.S3
+	if s3Destination == nil || backupCR.Status.BackupS3ObjectURL == nil {
+		return nil
+	}
+
+	jobName, err := helper.UIDBasedJobName("backup-s3-delete", backupCR.UID)
+	if err != nil {
+		panic(err)
+	}
+
+	s3Options := NewS3OptionsFromLocation(&s3Destination.S3Location)
+
+	var completions int32 = 1
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: backupCR.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			Completions: &completions,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Volumes: s3Options.PodVolumes(),
+					Containers: []v1.Container{
+						s3Options.AWSCLIContainer("backup-s3-delete", "rm", "--only-show-errors", *backupCR.Status.BackupS3ObjectURL),
+					},
+					RestartPolicy: v1.RestartPolicyNever, // Only "Never" or "OnFailure" are accepted in Kubernetes Jobs
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,183 @@
+package backup
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testScheduledBackup(name string, scheduledTime time.Time, completed, failed bool) appsv1alpha1.APIManagerBackup {
+	return appsv1alpha1.APIManagerBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				appsv1alpha1.APIManagerBackupScheduledTimeAnnotationKey: scheduledTime.Format(time.RFC3339),
+			},
+		},
+		Status: appsv1alpha1.APIManagerBackupStatus{
+			Completed: &completed,
+			Failed:    &failed,
+		},
+	}
+}
+
+func backupNames(backups []appsv1alpha1.APIManagerBackup) []string {
+	names := []string{}
+	for _, b := range backups {
+		names = append(names, b.Name)
+	}
+	return names
+}
+
+func TestMostRecentScheduleTime(t *testing.T) {
+	schedule, err := ParseSchedule("0 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	earliest := time.Date(2021, 3, 1, 3, 0, 0, 0, time.UTC)
+	mostRecent, err := MostRecentScheduleTime(schedule, earliest, time.Date(2021, 3, 2, 1, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mostRecent != nil {
+		t.Errorf("unexpected schedule time: %s", mostRecent)
+	}
+
+	mostRecent, err = MostRecentScheduleTime(schedule, earliest, time.Date(2021, 3, 4, 1, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := time.Date(2021, 3, 3, 2, 0, 0, 0, time.UTC)
+	if mostRecent == nil || !mostRecent.Equal(expected) {
+		t.Errorf("expected %s, got %v", expected, mostRecent)
+	}
+
+	if _, err := ParseSchedule("not a schedule"); err == nil {
+		t.Error("error expected with invalid schedule")
+	}
+}
+
+func TestScheduledBackup(t *testing.T) {
+	schedule := &appsv1alpha1.APIManagerBackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: testNamespace},
+		Spec: appsv1alpha1.APIManagerBackupScheduleSpec{
+			BackupTemplate: appsv1alpha1.APIManagerBackupTemplate{
+				Labels: map[string]string{"team": "api"},
+				Spec: appsv1alpha1.APIManagerBackupSpec{
+					BackupDestination: appsv1alpha1.APIManagerBackupDestination{
+						PersistentVolumeClaim: &appsv1alpha1.PersistentVolumeClaimBackupDestination{},
+					},
+				},
+			},
+		},
+	}
+
+	scheduledTime := time.Date(2021, 3, 3, 2, 0, 0, 0, time.UTC)
+	b := ScheduledBackup(schedule, scheduledTime)
+	if b.Name != "nightly-20210303020000" {
+		t.Errorf("unexpected name: %s", b.Name)
+	}
+	if b.Labels[appsv1alpha1.APIManagerBackupScheduleLabelKey] != "nightly" || b.Labels["team"] != "api" {
+		t.Errorf("unexpected labels: %v", b.Labels)
+	}
+	if !BackupScheduledTime(b).Equal(scheduledTime) {
+		t.Errorf("unexpected scheduled time: %s", BackupScheduledTime(b))
+	}
+	if b.Spec.BackupDestination.PersistentVolumeClaim == nil {
+		t.Error("backup destination not copied from template")
+	}
+}
+
+func TestBackupsToPrune(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2021, 3, d, h, 0, 0, 0, time.UTC) }
+	backups := []appsv1alpha1.APIManagerBackup{
+		testScheduledBackup("b-0301", day(1, 2), true, false),   // monday, week 9
+		testScheduledBackup("b-0308", day(8, 2), true, false),   // monday, week 10
+		testScheduledBackup("b-0309", day(9, 2), true, false),   // week 10
+		testScheduledBackup("b-0310a", day(10, 2), true, false), // week 10
+		testScheduledBackup("b-0310b", day(10, 14), false, true),
+		testScheduledBackup("b-0310c", day(10, 20), true, false),
+		testScheduledBackup("b-0311", day(11, 2), false, true),
+		testScheduledBackup("b-0312", day(12, 2), false, false),
+	}
+
+	if res := BackupsToPrune(backups, nil); len(res) != 0 {
+		t.Errorf("nothing expected to be pruned without retention: %v", backupNames(res))
+	}
+
+	one, two := int32(1), int32(2)
+
+	res := BackupsToPrune(backups, &appsv1alpha1.APIManagerBackupRetention{KeepLast: &one})
+	expected := "b-0310b,b-0310a,b-0309,b-0308,b-0301"
+	if strings.Join(backupNames(res), ",") != expected {
+		t.Errorf("keepLast: expected %s, got %v", expected, backupNames(res))
+	}
+
+	res = BackupsToPrune(backups, &appsv1alpha1.APIManagerBackupRetention{KeepDaily: &two})
+	expected = "b-0310b,b-0310a,b-0308,b-0301"
+	if strings.Join(backupNames(res), ",") != expected {
+		t.Errorf("keepDaily: expected %s, got %v", expected, backupNames(res))
+	}
+
+	res = BackupsToPrune(backups, &appsv1alpha1.APIManagerBackupRetention{KeepLast: &one, KeepWeekly: &two})
+	expected = "b-0310b,b-0310a,b-0309,b-0308"
+	if strings.Join(backupNames(res), ",") != expected {
+		t.Errorf("keepWeekly: expected %s, got %v", expected, backupNames(res))
+	}
+}
+
+func TestBackupStalled(t *testing.T) {
+	scheduledTime := time.Date(2020, 11, 10, 3, 0, 0, 0, time.UTC)
+	deadline := time.Hour
+
+	cases := []struct {
+		name      string
+		completed bool
+		failed    bool
+		now       time.Time
+		expected  bool
+	}{
+		{"in progress within the deadline", false, false, scheduledTime.Add(30 * time.Minute), false},
+		{"in progress after the deadline", false, false, scheduledTime.Add(2 * time.Hour), true},
+		{"completed after the deadline", true, false, scheduledTime.Add(2 * time.Hour), false},
+		{"failed after the deadline", false, true, scheduledTime.Add(2 * time.Hour), false},
+	}
+
+	for _, tc := range cases {
+		backupCR := testScheduledBackup("backup", scheduledTime, tc.completed, tc.failed)
+		if res := BackupStalled(&backupCR, deadline, tc.now); res != tc.expected {
+			t.Errorf("%s: expected %t, got %t", tc.name, tc.expected, res)
+		}
+	}
+}
+
+func TestDeleteS3BackupDataJob(t *testing.T) {
+	backupCR := testAPIManagerBackupCR(nil)
+	if DeleteS3BackupDataJob(backupCR) != nil {
+		t.Error("unexpected job for PVC backup destinations")
+	}
+
+	backupCR.UID = types.UID("f4a0ab5a-2a4b-4f3c-a6e2-8a1f8b4f3c21")
+	backupCR.Spec.BackupDestination = appsv1alpha1.APIManagerBackupDestination{
+		S3: &appsv1alpha1.S3BackupDestination{S3Location: testS3Location()},
+	}
+	if DeleteS3BackupDataJob(backupCR) != nil {
+		t.Error("unexpected job when nothing has been uploaded")
+	}
+
+	objectURL := "s3://mybucket/backups/3scale/example-backup.tar.gz"
+	backupCR.Status.BackupS3ObjectURL = &objectURL
+	job := DeleteS3BackupDataJob(backupCR)
+	if job == nil {
+		t.Fatal("delete job expected")
+	}
+	args := strings.Join(job.Spec.Template.Spec.Containers[0].Args, " ")
+	if !strings.HasSuffix(args, "s3 rm --only-show-errors "+objectURL) {
+		t.Errorf("unexpected args: %s", args)
+	}
+}
@@ -0,0 +1,259 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	applicationListEndpoint       = "/admin/api/accounts/%d/applications.json"
+	applicationEndpoint           = "/admin/api/accounts/%d/applications/%d.json"
+	applicationChangePlanEndpoint = "/admin/api/accounts/%d/applications/%d/change_plan.json"
+	applicationSuspendEndpoint    = "/admin/api/accounts/%d/applications/%d/suspend.json"
+	applicationResumeEndpoint     = "/admin/api/accounts/%d/applications/%d/resume.json"
+	applicationKeysEndpoint       = "/admin/api/accounts/%d/applications/%d/keys.json"
+)
+
+// Application holds the 3scale application fields managed by the operator
+type Application struct {
+	ID            int64  `json:"id"`
+	State         string `json:"state"`
+	ServiceID     int64  `json:"service_id"`
+	PlanID        int64  `json:"plan_id"`
+	UserKey       string `json:"user_key,omitempty"`
+	ApplicationID string `json:"application_id,omitempty"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+}
+
+// IsSuspended returns true when the application is suspended
+func (a *Application) IsSuspended() bool {
+	return a.State == "suspended"
+}
+
+type applicationElem struct {
+	Application Application `json:"application"`
+}
+
+type applicationList struct {
+	Applications []applicationElem `json:"applications"`
+}
+
+type applicationKeyList struct {
+	Keys []struct {
+		Key struct {
+			Value string `json:"value"`
+		} `json:"key"`
+	} `json:"keys"`
+}
+
+// ApplicationAPIError is returned when 3scale responds with an unexpected status code
+type ApplicationAPIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ApplicationAPIError) Error() string {
+	return fmt.Sprintf("error calling 3scale system - reason: %s - code: %d", e.Message, e.StatusCode)
+}
+
+// IsApplicationNotFound returns true when the error is a 3scale not found response
+func IsApplicationNotFound(err error) bool {
+	apiErr, ok := err.(*ApplicationAPIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// ApplicationAPIClient implements the 3scale application endpoints
+// not provided by the porta client: read, update, plan change, suspension, deletion and keys
+type ApplicationAPIClient struct {
+	adminURL   *url.URL
+	token      string
+	httpClient *http.Client
+}
+
+func NewApplicationAPIClient(adminURL *url.URL, token string, httpClient *http.Client) *ApplicationAPIClient {
+	return &ApplicationAPIClient{
+		adminURL:   adminURL,
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+// ApplicationAPIClientFromProviderAccount instantiates ApplicationAPIClient from ProviderAccount object
+func ApplicationAPIClientFromProviderAccount(providerAccount *ProviderAccount) (*ApplicationAPIClient, error) {
+	adminURL, err := url.Parse(providerAccount.AdminURLStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if adminURL.Scheme == "" || adminURL.Host == "" {
+		return nil, fmt.Errorf("invalid provider account admin URL: %s", providerAccount.AdminURLStr)
+	}
+
+	transport, err := portaTransport(providerAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewApplicationAPIClient(adminURL, providerAccount.Token, &http.Client{Transport: transport}), nil
+}
+
+// Application reads the application
+func (c *ApplicationAPIClient) Application(accountID, applicationID int64) (*Application, error) {
+	elem := &applicationElem{}
+	err := c.do(http.MethodGet, fmt.Sprintf(applicationEndpoint, accountID, applicationID), nil, http.StatusOK, elem)
+	if err != nil {
+		return nil, err
+	}
+	return &elem.Application, nil
+}
+
+// ListApplications returns the applications of the account
+func (c *ApplicationAPIClient) ListApplications(accountID int64) ([]Application, error) {
+	list := &applicationList{}
+	err := c.do(http.MethodGet, fmt.Sprintf(applicationListEndpoint, accountID), nil, http.StatusOK, list)
+	if err != nil {
+		return nil, err
+	}
+
+	applications := make([]Application, 0, len(list.Applications))
+	for idx := range list.Applications {
+		applications = append(applications, list.Applications[idx].Application)
+	}
+	return applications, nil
+}
+
+// CreateApplication creates an application subscribed to the plan.
+// Supported params: name, description, user_key, application_id and application_key.
+// Credentials not provided are generated by 3scale.
+func (c *ApplicationAPIClient) CreateApplication(accountID, planID int64, params url.Values) (*Application, error) {
+	values := url.Values{}
+	for k, v := range params {
+		values[k] = v
+	}
+	values.Set("plan_id", strconv.FormatInt(planID, 10))
+
+	elem := &applicationElem{}
+	err := c.do(http.MethodPost, fmt.Sprintf(applicationListEndpoint, accountID), values, http.StatusCreated, elem)
+	if err != nil {
+		return nil, err
+	}
+	return &elem.Application, nil
+}
+
+// UpdateApplication updates application name and description
+func (c *ApplicationAPIClient) UpdateApplication(accountID, applicationID int64, params url.Values) (*Application, error) {
+	elem := &applicationElem{}
+	err := c.do(http.MethodPut, fmt.Sprintf(applicationEndpoint, accountID, applicationID), params, http.StatusOK, elem)
+	if err != nil {
+		return nil, err
+	}
+	return &elem.Application, nil
+}
+
+// ChangeApplicationPlan subscribes the application to another plan of the same product
+func (c *ApplicationAPIClient) ChangeApplicationPlan(accountID, applicationID, planID int64) (*Application, error) {
+	values := url.Values{}
+	values.Set("plan_id", strconv.FormatInt(planID, 10))
+
+	elem := &applicationElem{}
+	err := c.do(http.MethodPut, fmt.Sprintf(applicationChangePlanEndpoint, accountID, applicationID), values, http.StatusOK, elem)
+	if err != nil {
+		return nil, err
+	}
+	return &elem.Application, nil
+}
+
+// SuspendApplication changes the state of the application to suspended
+func (c *ApplicationAPIClient) SuspendApplication(accountID, applicationID int64) (*Application, error) {
+	elem := &applicationElem{}
+	err := c.do(http.MethodPut, fmt.Sprintf(applicationSuspendEndpoint, accountID, applicationID), url.Values{}, http.StatusOK, elem)
+	if err != nil {
+		return nil, err
+	}
+	return &elem.Application, nil
+}
+
+// ResumeApplication changes the state of a suspended application to live
+func (c *ApplicationAPIClient) ResumeApplication(accountID, applicationID int64) (*Application, error) {
+	elem := &applicationElem{}
+	err := c.do(http.MethodPut, fmt.Sprintf(applicationResumeEndpoint, accountID, applicationID), url.Values{}, http.StatusOK, elem)
+	if err != nil {
+		return nil, err
+	}
+	return &elem.Application, nil
+}
+
+// DeleteApplication deletes the application
+func (c *ApplicationAPIClient) DeleteApplication(accountID, applicationID int64) error {
+	return c.do(http.MethodDelete, fmt.Sprintf(applicationEndpoint, accountID, applicationID), nil, http.StatusOK, nil)
+}
+
+// ApplicationKeys returns the application keys of an app_id/app_key application
+func (c *ApplicationAPIClient) ApplicationKeys(accountID, applicationID int64) ([]string, error) {
+	list := &applicationKeyList{}
+	err := c.do(http.MethodGet, fmt.Sprintf(applicationKeysEndpoint, accountID, applicationID), nil, http.StatusOK, list)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(list.Keys))
+	for idx := range list.Keys {
+		keys = append(keys, list.Keys[idx].Key.Value)
+	}
+	return keys, nil
+}
+
+// CreateApplicationKey adds a key to an app_id/app_key application
+func (c *ApplicationAPIClient) CreateApplicationKey(accountID, applicationID int64, key string) error {
+	values := url.Values{}
+	values.Set("key", key)
+	return c.do(http.MethodPost, fmt.Sprintf(applicationKeysEndpoint, accountID, applicationID), values, http.StatusCreated, nil)
+}
+
+func (c *ApplicationAPIClient) do(method, endpoint string, params url.Values, expectedCode int, decodeInto interface{}) error {
+	var body io.Reader
+	if params != nil {
+		body = strings.NewReader(params.Encode())
+	}
+
+	reqURL := *c.adminURL
+	reqURL.Path = endpoint
+
+	req, err := http.NewRequest(method, reqURL.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.SetBasicAuth("", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedCode {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return &ApplicationAPIError{StatusCode: resp.StatusCode, Message: string(respBody)}
+	}
+
+	if decodeInto == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(decodeInto); err != nil {
+		return fmt.Errorf("decoding error - %w", err)
+	}
+
+	return nil
+}
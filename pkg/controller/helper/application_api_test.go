@@ -0,0 +1,127 @@
+package helper
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func newTestApplicationAPIClient(t *testing.T, fn RoundTripFunc) *ApplicationAPIClient {
+	adminURL, err := url.Parse("https://www.example.com")
+	ok(t, err)
+	return NewApplicationAPIClient(adminURL, "12345", NewTestClient(fn))
+}
+
+func jsonResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+func TestApplicationAPIClientFromProviderAccountInvalidURL(t *testing.T) {
+	_, err := ApplicationAPIClientFromProviderAccount(&ProviderAccount{AdminURLStr: "foo", Token: "some token"})
+	assert(t, err != nil, "error should not be nil")
+}
+
+func TestApplicationAPIClientApplication(t *testing.T) {
+	client := newTestApplicationAPIClient(t, func(req *http.Request) *http.Response {
+		equals(t, http.MethodGet, req.Method)
+		equals(t, "/admin/api/accounts/3/applications/5.json", req.URL.Path)
+		_, password, _ := req.BasicAuth()
+		equals(t, "12345", password)
+		return jsonResponse(http.StatusOK,
+			`{"application":{"id":5,"state":"live","service_id":7,"plan_id":9,"user_key":"abc","name":"app","description":"descr"}}`)
+	})
+
+	application, err := client.Application(3, 5)
+	ok(t, err)
+	equals(t, &Application{ID: 5, State: "live", ServiceID: 7, PlanID: 9, UserKey: "abc", Name: "app", Description: "descr"}, application)
+	assert(t, !application.IsSuspended(), "application should not be suspended")
+}
+
+func TestApplicationAPIClientApplicationNotFound(t *testing.T) {
+	client := newTestApplicationAPIClient(t, func(req *http.Request) *http.Response {
+		return jsonResponse(http.StatusNotFound, `{"status":"Not found"}`)
+	})
+
+	_, err := client.Application(3, 5)
+	assert(t, IsApplicationNotFound(err), "expected not found error, got %v", err)
+}
+
+func TestApplicationAPIClientListApplications(t *testing.T) {
+	client := newTestApplicationAPIClient(t, func(req *http.Request) *http.Response {
+		equals(t, "/admin/api/accounts/3/applications.json", req.URL.Path)
+		return jsonResponse(http.StatusOK,
+			`{"applications":[{"application":{"id":5,"name":"app1"}},{"application":{"id":6,"name":"app2"}}]}`)
+	})
+
+	applications, err := client.ListApplications(3)
+	ok(t, err)
+	equals(t, 2, len(applications))
+	equals(t, "app2", applications[1].Name)
+}
+
+func TestApplicationAPIClientCreateApplication(t *testing.T) {
+	client := newTestApplicationAPIClient(t, func(req *http.Request) *http.Response {
+		equals(t, http.MethodPost, req.Method)
+		ok(t, req.ParseForm())
+		equals(t, "9", req.PostForm.Get("plan_id"))
+		equals(t, "app", req.PostForm.Get("name"))
+		equals(t, "myid", req.PostForm.Get("application_id"))
+		return jsonResponse(http.StatusCreated,
+			`{"application":{"id":5,"state":"live","plan_id":9,"application_id":"myid","name":"app"}}`)
+	})
+
+	params := url.Values{}
+	params.Set("name", "app")
+	params.Set("application_id", "myid")
+	application, err := client.CreateApplication(3, 9, params)
+	ok(t, err)
+	equals(t, "myid", application.ApplicationID)
+	// params are not modified
+	equals(t, "", params.Get("plan_id"))
+}
+
+func TestApplicationAPIClientSuspendApplication(t *testing.T) {
+	client := newTestApplicationAPIClient(t, func(req *http.Request) *http.Response {
+		equals(t, http.MethodPut, req.Method)
+		equals(t, "/admin/api/accounts/3/applications/5/suspend.json", req.URL.Path)
+		return jsonResponse(http.StatusOK, `{"application":{"id":5,"state":"suspended"}}`)
+	})
+
+	application, err := client.SuspendApplication(3, 5)
+	ok(t, err)
+	assert(t, application.IsSuspended(), "application should be suspended")
+}
+
+func TestApplicationAPIClientDeleteApplicationError(t *testing.T) {
+	client := newTestApplicationAPIClient(t, func(req *http.Request) *http.Response {
+		equals(t, http.MethodDelete, req.Method)
+		return jsonResponse(http.StatusForbidden, `{"error":"forbidden"}`)
+	})
+
+	err := client.DeleteApplication(3, 5)
+	assert(t, err != nil, "error should not be nil")
+	assert(t, !IsApplicationNotFound(err), "error should not be not found")
+}
+
+func TestApplicationAPIClientApplicationKeys(t *testing.T) {
+	client := newTestApplicationAPIClient(t, func(req *http.Request) *http.Response {
+		if req.Method == http.MethodPost {
+			ok(t, req.ParseForm())
+			equals(t, "key2", req.PostForm.Get("key"))
+			return jsonResponse(http.StatusCreated, `{"application":{"id":5}}`)
+		}
+		return jsonResponse(http.StatusOK, `{"keys":[{"key":{"value":"key1"}}]}`)
+	})
+
+	keys, err := client.ApplicationKeys(3, 5)
+	ok(t, err)
+	equals(t, []string{"key1"}, keys)
+
+	ok(t, client.CreateApplicationKey(3, 5, "key2"))
+}
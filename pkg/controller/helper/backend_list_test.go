@@ -79,7 +79,7 @@ func TestBackendList(t *testing.T) {
 			&capabilitiesv1beta1.Backend{
 				ObjectMeta: metav1.ObjectMeta{Name: "somename", Namespace: ns},
 				Spec: capabilitiesv1beta1.BackendSpec{
-					ProviderAccountRef: &corev1.LocalObjectReference{
+					ProviderAccountRef: &capabilitiesv1beta1.ProviderAccountReference{
 						Name: anotherProviderSecretName,
 					},
 				},
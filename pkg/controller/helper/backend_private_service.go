@@ -0,0 +1,115 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// backendPrivateServiceDefaultScheme is the scheme of the private base URL when the service reference does not set it
+	backendPrivateServiceDefaultScheme = "http"
+
+	// backendPrivateServiceDomain is the cluster-local domain of the services
+	backendPrivateServiceDomain = "svc.cluster.local"
+)
+
+const (
+	// CROSS_NAMESPACE_PRIVATE_SERVICES_ENVVAR lists, comma separated, the namespaces with Services
+	// the backends in other namespaces are allowed to reference. "*" allows every namespace.
+	// When not set, private service references to other namespaces are rejected.
+	CROSS_NAMESPACE_PRIVATE_SERVICES_ENVVAR = "THREESCALE_CROSS_NAMESPACE_PRIVATE_SERVICES"
+)
+
+// BackendPrivateServiceNamespace returns the namespace of the service referenced by the backend.
+// Defaults to the namespace of the backend.
+func BackendPrivateServiceNamespace(backend *capabilitiesv1beta1.Backend) string {
+	if ref := backend.Spec.PrivateServiceRef; ref != nil && ref.Namespace != "" {
+		return ref.Namespace
+	}
+	return backend.Namespace
+}
+
+// BackendPrivateBaseURL returns the private base URL of the backend API.
+// When the backend references a service, the URL is resolved into the cluster-local URL of the service port.
+// References to other namespaces not allowed by the operator, a missing service
+// or a port missing in the service are reported as invalid spec errors.
+func BackendPrivateBaseURL(cl client.Client, backend *capabilitiesv1beta1.Backend) (string, error) {
+	ref := backend.Spec.PrivateServiceRef
+	if ref == nil {
+		return backend.Spec.PrivateBaseURL, nil
+	}
+
+	namespace := BackendPrivateServiceNamespace(backend)
+	if namespace != backend.Namespace && !namespaceListedInEnvVar(CROSS_NAMESPACE_PRIVATE_SERVICES_ENVVAR, namespace) {
+		return "", &helper.SpecFieldError{
+			ErrorType: helper.InvalidError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "privateServiceRef", "namespace"), namespace,
+					"private service references to the namespace are not allowed by the operator."),
+			},
+		}
+	}
+
+	service := &corev1.Service{}
+	err := cl.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: namespace}, service)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", &helper.SpecFieldError{
+				ErrorType: helper.InvalidError,
+				FieldErrorList: field.ErrorList{
+					field.NotFound(field.NewPath("spec", "privateServiceRef", "name"), fmt.Sprintf("%s/%s", namespace, ref.Name)),
+				},
+			}
+		}
+		return "", err
+	}
+
+	servicePort := findServicePort(service, ref.Port)
+	if servicePort == nil {
+		return "", &helper.SpecFieldError{
+			ErrorType: helper.InvalidError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "privateServiceRef", "port"), ref.Port.String(),
+					fmt.Sprintf("port not found in service '%s/%s'.", namespace, ref.Name)),
+			},
+		}
+	}
+
+	scheme := ref.Scheme
+	if scheme == "" {
+		scheme = backendPrivateServiceDefaultScheme
+	}
+
+	privateBaseURL := url.URL{
+		Scheme: scheme,
+		Host:   fmt.Sprintf("%s.%s.%s:%d", service.Name, service.Namespace, backendPrivateServiceDomain, servicePort.Port),
+		Path:   ref.Path,
+	}
+
+	return privateBaseURL.String(), nil
+}
+
+// findServicePort returns the service port matching the port name or number. Nil when not found
+func findServicePort(service *corev1.Service, port intstr.IntOrString) *corev1.ServicePort {
+	for idx := range service.Spec.Ports {
+		servicePort := &service.Spec.Ports[idx]
+		if port.Type == intstr.String && servicePort.Name == port.StrVal {
+			return servicePort
+		}
+		if port.Type == intstr.Int && servicePort.Port == port.IntVal {
+			return servicePort
+		}
+	}
+	return nil
+}
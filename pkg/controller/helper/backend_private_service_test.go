@@ -0,0 +1,106 @@
+package helper
+
+import (
+	"os"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBackendPrivateBaseURL(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "echo-api", Namespace: "apis"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 8080},
+				{Name: "https", Port: 8443},
+			},
+		},
+	}
+	sharedService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "echo-api", Namespace: "shared"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http", Port: 8080}},
+		},
+	}
+
+	cases := []struct {
+		testName           string
+		ref                *capabilitiesv1beta1.BackendPrivateServiceReference
+		operatorNamespaces string
+		expectedURL        string
+		expectedErr        bool
+		expectedInvalid    bool
+	}{
+		{"No service reference", nil, "", "https://api.example.com", false, false},
+		{
+			"Port name", &capabilitiesv1beta1.BackendPrivateServiceReference{Name: "echo-api", Port: intstr.FromString("http")},
+			"", "http://echo-api.apis.svc.cluster.local:8080", false, false,
+		},
+		{
+			"Port number with scheme and path",
+			&capabilitiesv1beta1.BackendPrivateServiceReference{Name: "echo-api", Port: intstr.FromInt(8443), Scheme: "https", Path: "/v1"},
+			"", "https://echo-api.apis.svc.cluster.local:8443/v1", false, false,
+		},
+		{
+			"Port not found", &capabilitiesv1beta1.BackendPrivateServiceReference{Name: "echo-api", Port: intstr.FromString("grpc")},
+			"", "", true, true,
+		},
+		{
+			"Service not found", &capabilitiesv1beta1.BackendPrivateServiceReference{Name: "missing", Port: intstr.FromInt(8080)},
+			"", "", true, true,
+		},
+		{
+			"Service in another namespace not enabled",
+			&capabilitiesv1beta1.BackendPrivateServiceReference{Name: "echo-api", Namespace: "shared", Port: intstr.FromInt(8080)},
+			"", "", true, true,
+		},
+		{
+			"Service in another namespace not allowed",
+			&capabilitiesv1beta1.BackendPrivateServiceReference{Name: "echo-api", Namespace: "shared", Port: intstr.FromInt(8080)},
+			"other", "", true, true,
+		},
+		{
+			"Service in another namespace allowed",
+			&capabilitiesv1beta1.BackendPrivateServiceReference{Name: "echo-api", Namespace: "shared", Port: intstr.FromInt(8080)},
+			"other, shared", "http://echo-api.shared.svc.cluster.local:8080", false, false,
+		},
+		{
+			"Service in another namespace not found",
+			&capabilitiesv1beta1.BackendPrivateServiceReference{Name: "echo-api", Namespace: "other", Port: intstr.FromInt(8080)},
+			"*", "", true, true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			os.Setenv(CROSS_NAMESPACE_PRIVATE_SERVICES_ENVVAR, tc.operatorNamespaces)
+			defer os.Unsetenv(CROSS_NAMESPACE_PRIVATE_SERVICES_ENVVAR)
+
+			backend := &capabilitiesv1beta1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "apis"},
+				Spec: capabilitiesv1beta1.BackendSpec{
+					PrivateServiceRef: tc.ref,
+				},
+			}
+			if tc.ref == nil {
+				backend.Spec.PrivateBaseURL = "https://api.example.com"
+			}
+
+			privateBaseURL, err := BackendPrivateBaseURL(fake.NewFakeClient(service, sharedService), backend)
+			if tc.expectedErr {
+				assert(subT, err != nil, "error should not be nil")
+				equals(subT, tc.expectedInvalid, helper.IsInvalidSpecError(err))
+				return
+			}
+			ok(subT, err)
+			equals(subT, tc.expectedURL, privateBaseURL)
+		})
+	}
+}
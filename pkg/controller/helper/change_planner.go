@@ -0,0 +1,233 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+)
+
+// ChangePlanner implements http.RoundTripper. When set as Transport of http.Client,
+// it records the requests that would modify remote objects, i.e. any method other than GET and HEAD,
+// instead of sending them.
+//
+// Modifying requests are answered with the current remote object, so the client keeps
+// working on the remote state. Created objects have ID 0 and no children.
+type ChangePlanner struct {
+	Transport http.RoundTripper
+
+	mutex   sync.Mutex
+	changes []capabilitiesv1beta1.PlannedChange
+}
+
+// RoundTrip implements http.RoundTripper
+func (c *ChangePlanner) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		if isPlannedObjectPath(req.URL.Path) {
+			return plannedResponse(req, http.StatusOK, emptyPlannedList(req.URL.Path)), nil
+		}
+
+		return c.transport().RoundTrip(req)
+	}
+
+	after, err := requestParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	change := capabilitiesv1beta1.PlannedChange{Path: req.URL.Path}
+	var resp *http.Response
+
+	switch req.Method {
+	case http.MethodPost:
+		change.Action = capabilitiesv1beta1.PlannedChangeActionCreate
+		change.After = after
+		resp = plannedResponse(req, http.StatusCreated, []byte("{}"))
+	default:
+		if req.Method == http.MethodDelete {
+			change.Action = capabilitiesv1beta1.PlannedChangeActionDelete
+		} else {
+			change.Action = capabilitiesv1beta1.PlannedChangeActionUpdate
+			change.After = after
+		}
+
+		current := []byte("{}")
+		if !isPlannedObjectPath(req.URL.Path) {
+			current, err = c.currentObject(req)
+			if err != nil {
+				return nil, err
+			}
+			change.Before = objectFields(current, change.After)
+		}
+		resp = plannedResponse(req, http.StatusOK, current)
+	}
+
+	c.mutex.Lock()
+	c.changes = append(c.changes, change)
+	c.mutex.Unlock()
+
+	return resp, nil
+}
+
+// Changes returns the changes planned so far
+func (c *ChangePlanner) Changes() []capabilitiesv1beta1.PlannedChange {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]capabilitiesv1beta1.PlannedChange{}, c.changes...)
+}
+
+func (c *ChangePlanner) transport() http.RoundTripper {
+	if c.Transport == nil {
+		return http.DefaultTransport
+	}
+	return c.Transport
+}
+
+// currentObject reads the remote object of the modifying request
+func (c *ChangePlanner) currentObject(req *http.Request) ([]byte, error) {
+	getReq, err := http.NewRequest(http.MethodGet, req.URL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	getReq = getReq.WithContext(req.Context())
+	getReq.Header.Set("Accept", "application/json")
+	getReq.Header.Set("Authorization", req.Header.Get("Authorization"))
+
+	resp, err := c.transport().RoundTrip(getReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("planning %s %s: reading current object: status %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+
+	return body, nil
+}
+
+// requestParams returns the form or JSON params of the request body
+func requestParams(req *http.Request) (map[string]string, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	params := map[string]string{}
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		fields := map[string]interface{}{}
+		err = json.Unmarshal(body, &fields)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range fields {
+			params[key] = fieldString(value)
+		}
+		return params, nil
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	for key := range values {
+		params[key] = values.Get(key)
+	}
+
+	return params, nil
+}
+
+// objectFields returns the fields of the JSON object named by keys.
+// All the fields are returned when keys is empty.
+// Objects wrapped in a single key, like {"service": {...}}, are unwrapped.
+func objectFields(body []byte, keys map[string]string) map[string]string {
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil
+	}
+
+	if len(object) == 1 {
+		for _, value := range object {
+			if wrapped, ok := value.(map[string]interface{}); ok {
+				object = wrapped
+			}
+		}
+	}
+
+	fields := map[string]string{}
+	for key, value := range object {
+		if len(keys) > 0 {
+			if _, ok := keys[key]; !ok {
+				continue
+			}
+		} else if _, isObject := value.(map[string]interface{}); isObject {
+			continue
+		}
+		fields[key] = fieldString(value)
+	}
+
+	return fields
+}
+
+func fieldString(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// isPlannedObjectPath returns true when the path refers to an object planned for creation,
+// i.e. an object with ID 0
+func isPlannedObjectPath(path string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "0" || segment == "0.json" {
+			return true
+		}
+	}
+	return false
+}
+
+// emptyPlannedList returns the empty list of children of an object planned for creation
+func emptyPlannedList(path string) []byte {
+	// backend usages are listed as JSON array
+	if strings.HasSuffix(path, "/backend_usages.json") {
+		return []byte("[]")
+	}
+	return []byte("{}")
+}
+
+func plannedResponse(req *http.Request, statusCode int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}
+}
@@ -0,0 +1,78 @@
+package helper
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+)
+
+func TestChangePlanner(t *testing.T) {
+	sent := []string{}
+	changePlanner := &ChangePlanner{
+		Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+			sent = append(sent, req.Method+" "+req.URL.Path)
+			body := `{"metrics":[]}`
+			if req.URL.Path == "/admin/api/services/2/metrics/3.json" {
+				body = `{"metric":{"id":3,"friendly_name":"Old","system_name":"old","unit":"hit"}}`
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+				Header:     make(http.Header),
+			}
+		}),
+	}
+	httpClient := &http.Client{Transport: changePlanner}
+
+	do := func(method, path string, params url.Values) *http.Response {
+		req, err := http.NewRequest(method, "https://www.test.com"+path, strings.NewReader(params.Encode()))
+		ok(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := httpClient.Do(req)
+		ok(t, err)
+		defer resp.Body.Close()
+		return resp
+	}
+
+	equals(t, http.StatusOK, do(http.MethodGet, "/admin/api/services/2/metrics.json", nil).StatusCode)
+	equals(t, http.StatusCreated, do(http.MethodPost, "/admin/api/services/2/metrics.json", url.Values{"system_name": {"new"}}).StatusCode)
+	equals(t, http.StatusOK, do(http.MethodPut, "/admin/api/services/2/metrics/3.json", url.Values{"friendly_name": {"New"}}).StatusCode)
+	equals(t, http.StatusOK, do(http.MethodDelete, "/admin/api/services/2/metrics/3.json", nil).StatusCode)
+	// children of objects planned for creation are not requested
+	equals(t, http.StatusOK, do(http.MethodGet, "/admin/api/application_plans/0/limits.json", nil).StatusCode)
+
+	// modifying requests are only read to find the current values
+	equals(t, []string{
+		"GET /admin/api/services/2/metrics.json",
+		"GET /admin/api/services/2/metrics/3.json",
+		"GET /admin/api/services/2/metrics/3.json",
+	}, sent)
+
+	expected := []capabilitiesv1beta1.PlannedChange{
+		{
+			Action: capabilitiesv1beta1.PlannedChangeActionCreate,
+			Path:   "/admin/api/services/2/metrics.json",
+			After:  map[string]string{"system_name": "new"},
+		},
+		{
+			Action: capabilitiesv1beta1.PlannedChangeActionUpdate,
+			Path:   "/admin/api/services/2/metrics/3.json",
+			Before: map[string]string{"friendly_name": "Old"},
+			After:  map[string]string{"friendly_name": "New"},
+		},
+		{
+			Action: capabilitiesv1beta1.PlannedChangeActionDelete,
+			Path:   "/admin/api/services/2/metrics/3.json",
+			Before: map[string]string{"id": "3", "friendly_name": "Old", "system_name": "old", "unit": "hit"},
+		},
+	}
+	if !reflect.DeepEqual(changePlanner.Changes(), expected) {
+		t.Fatalf("unexpected planned changes: got %+v, expected %+v", changePlanner.Changes(), expected)
+	}
+}
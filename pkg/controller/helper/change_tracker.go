@@ -0,0 +1,38 @@
+package helper
+
+import (
+	"net/http"
+	"sync"
+)
+
+// ChangeTracker implements http.RoundTripper. When set as Transport of http.Client,
+// it counts the requests that modify remote objects, i.e. any method other than GET and HEAD.
+type ChangeTracker struct {
+	Transport http.RoundTripper
+
+	mutex   sync.Mutex
+	changes int
+}
+
+// RoundTrip implements http.RoundTripper
+func (c *ChangeTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		c.mutex.Lock()
+		c.changes++
+		c.mutex.Unlock()
+	}
+
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return transport.RoundTrip(req)
+}
+
+// Changes returns the number of modifying requests sent so far
+func (c *ChangeTracker) Changes() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.changes
+}
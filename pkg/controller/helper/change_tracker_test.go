@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestChangeTracker(t *testing.T) {
+	changeTracker := &ChangeTracker{
+		Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+				Header:     make(http.Header),
+			}
+		}),
+	}
+	httpClient := &http.Client{Transport: changeTracker}
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodDelete} {
+		req, err := http.NewRequest(method, "https://www.test.com/admin/api/services.json", nil)
+		ok(t, err)
+		resp, err := httpClient.Do(req)
+		ok(t, err)
+		resp.Body.Close()
+	}
+
+	equals(t, 3, changeTracker.Changes())
+}
@@ -6,7 +6,6 @@ import (
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 
 	logrtesting "github.com/go-logr/logr/testing"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -49,7 +48,7 @@ func TestFindDeveloperUserList(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "devUser3", Namespace: ns},
 		Spec: capabilitiesv1beta1.DeveloperUserSpec{
 			Username: "devUser3", Email: "devUser3@example.com", Role: &adminRole,
-			ProviderAccountRef: &corev1.LocalObjectReference{Name: anotherProviderSecretName},
+			ProviderAccountRef: &capabilitiesv1beta1.ProviderAccountReference{Name: anotherProviderSecretName},
 		},
 	}
 
@@ -4,14 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	"github.com/3scale/3scale-operator/pkg/helper"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -24,18 +30,60 @@ var (
 
 	// providerAccountSecretTokenFieldName is the field name of the provider account secret where token can be found
 	providerAccountSecretTokenFieldName = "token"
+
+	// providerAccountSecretCABundleFieldName is the field name of the provider account secret where
+	// the PEM encoded CA certificates trusted to verify the admin portal can be found
+	providerAccountSecretCABundleFieldName = "caBundle"
+
+	// providerAccountSecretInsecureSkipVerifyFieldName is the field name of the provider account secret
+	// disabling the verification of the admin portal certificate
+	providerAccountSecretInsecureSkipVerifyFieldName = "insecureSkipVerify"
+
+	// providerAccountLocal3scaleTLSSecretName is the name of the optional secret with the TLS settings
+	// to connect to the 3scale deployment in the current namespace
+	providerAccountLocal3scaleTLSSecretName = "threescale-provider-account-tls"
+
+	// ProviderAccountTokenSecretTokenFieldName is the field name of the ProviderAccount token secret where token can be found
+	ProviderAccountTokenSecretTokenFieldName = "token"
+
+	// ProviderAccountCABundleSecretCABundleFieldName is the field name of the ProviderAccount CA bundle secret
+	// where the PEM encoded CA certificates can be found
+	ProviderAccountCABundleSecretCABundleFieldName = "caBundle"
 )
 
-type providerAccountSource func(cl client.Client, ns string, providerAccountRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error)
+const (
+	// CROSS_NAMESPACE_PROVIDER_ACCOUNTS_ENVVAR lists, comma separated, the namespaces with ProviderAccount CRs
+	// the CRs in other namespaces are allowed to reference. "*" allows every namespace.
+	// When not set, provider account references to other namespaces are rejected.
+	CROSS_NAMESPACE_PROVIDER_ACCOUNTS_ENVVAR = "THREESCALE_CROSS_NAMESPACE_PROVIDER_ACCOUNTS"
+)
+
+// ProviderAccountSecretName returns the name of the secret the provider account
+// is read from, the default provider account secret when no reference is provided
+func ProviderAccountSecretName(providerAccountRef *capabilitiesv1beta1.ProviderAccountReference) string {
+	if providerAccountRef != nil {
+		return providerAccountRef.Name
+	}
+	return providerAccountDefaultSecretName
+}
+
+type providerAccountSource func(cl client.Client, ns string, providerAccountRef *capabilitiesv1beta1.ProviderAccountReference, logger logr.Logger) (*ProviderAccount, error)
 
 // LookupProviderAccount looks up for account provider url and credentials
-// If provider_account_reference is provided, it must exist and required fields must exists
+// If provider_account_reference is provided, it must reference either a ProviderAccount CR or a secret,
+// it must exist and required fields must exists. A ProviderAccount CR takes precedence over a secret with the same name.
 // If no provider_account_reference is provided, defaul provider account secret with hardcoded name will be looked up in the namespace.
 // If no provider_account_reference is provided AND default provider account secret is not found either, then,
 // 3scale default provider account (3scale-admin) will be looked up using system-seed secret in the current namespace.
+// If provider_account_reference has a namespace other than the current one, only a ProviderAccount CR is looked up there.
 // If nothing is successfully found, return error
-func LookupProviderAccount(cl client.Client, ns string, providerAccountRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
+func LookupProviderAccount(cl client.Client, ns string, providerAccountRef *capabilitiesv1beta1.ProviderAccountReference, logger logr.Logger) (*ProviderAccount, error) {
+	if providerAccountRef != nil && providerAccountRef.Namespace != "" && providerAccountRef.Namespace != ns {
+		return providerAccountFromCrossNamespaceReference(cl, ns, providerAccountRef, logger)
+	}
+
 	orderedSources := []providerAccountSource{
+		providerAccountFromCustomResourceSource,
 		providerAccountFromSecretReferenceSource,
 		providerAccountFromDefaultSecretSource,
 		providerAccountFromLocal3scaleSource,
@@ -57,7 +105,105 @@ func LookupProviderAccount(cl client.Client, ns string, providerAccountRef *core
 	return nil, errors.New("LookupProviderAccount: no provider account found")
 }
 
-func providerAccountFromSecretReferenceSource(cl client.Client, ns string, providerAccountRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
+// ProviderAccountFromCR reads the provider account url, credentials and TLS settings
+// from the ProviderAccount CR and the secrets it references
+func ProviderAccountFromCR(cl client.Client, providerAccountCR *capabilitiesv1beta1.ProviderAccount) (*ProviderAccount, error) {
+	secretSource := helper.NewSecretSource(cl, providerAccountCR.Namespace)
+	token, err := secretSource.RequiredFieldValueFromRequiredSecret(providerAccountCR.Spec.TokenSecretRef.Name, ProviderAccountTokenSecretTokenFieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	providerAccount := &ProviderAccount{
+		AdminURLStr:        providerAccountCR.Spec.AdminURL,
+		Token:              token,
+		InsecureSkipVerify: providerAccountCR.Spec.InsecureSkipVerify,
+	}
+
+	if providerAccountCR.Spec.CABundleSecretRef != nil {
+		caBundle, err := secretSource.RequiredFieldValueFromRequiredSecret(providerAccountCR.Spec.CABundleSecretRef.Name, ProviderAccountCABundleSecretCABundleFieldName)
+		if err != nil {
+			return nil, err
+		}
+		providerAccount.CABundle = []byte(caBundle)
+	}
+
+	return providerAccount, nil
+}
+
+func providerAccountFromCustomResourceSource(cl client.Client, ns string, providerAccountRef *capabilitiesv1beta1.ProviderAccountReference, logger logr.Logger) (*ProviderAccount, error) {
+	if providerAccountRef == nil {
+		return nil, nil
+	}
+
+	providerAccountCR := &capabilitiesv1beta1.ProviderAccount{}
+	err := cl.Get(context.TODO(), types.NamespacedName{Name: providerAccountRef.Name, Namespace: ns}, providerAccountCR)
+	// The reference is a secret when there is no ProviderAccount CR or the CRD is not installed
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("providerAccountFromCustomResourceSource: %w", err)
+	}
+
+	logger.Info("LookupProviderAccount", "ns", ns, "providerAccount", providerAccountRef.Name)
+	return providerAccountFromValidCR(cl, providerAccountCR)
+}
+
+// providerAccountFromCrossNamespaceReference reads the provider account from the ProviderAccount CR in another namespace.
+// The operator must allow references to the namespace of the CR, and the CR must allow references from the current namespace.
+func providerAccountFromCrossNamespaceReference(cl client.Client, ns string, providerAccountRef *capabilitiesv1beta1.ProviderAccountReference, logger logr.Logger) (*ProviderAccount, error) {
+	if !crossNamespaceProviderAccountsAllowed(providerAccountRef.Namespace) {
+		return nil, fmt.Errorf("provider account references to namespace '%s' are not allowed by the operator", providerAccountRef.Namespace)
+	}
+
+	providerAccountCR := &capabilitiesv1beta1.ProviderAccount{}
+	err := cl.Get(context.TODO(), types.NamespacedName{Name: providerAccountRef.Name, Namespace: providerAccountRef.Namespace}, providerAccountCR)
+	if err != nil {
+		// Not found errors are returned as is to tell them apart
+		return nil, err
+	}
+
+	if !providerAccountCR.AllowsNamespace(ns) {
+		return nil, fmt.Errorf("provider account '%s/%s' does not allow references from namespace '%s'", providerAccountCR.Namespace, providerAccountCR.Name, ns)
+	}
+
+	logger.Info("LookupProviderAccount", "ns", ns, "providerAccount", types.NamespacedName{Name: providerAccountRef.Name, Namespace: providerAccountRef.Namespace})
+	return providerAccountFromValidCR(cl, providerAccountCR)
+}
+
+// crossNamespaceProviderAccountsAllowed returns true when the operator allows
+// the references to the ProviderAccount CRs of the namespace from other namespaces
+func crossNamespaceProviderAccountsAllowed(namespace string) bool {
+	return namespaceListedInEnvVar(CROSS_NAMESPACE_PROVIDER_ACCOUNTS_ENVVAR, namespace)
+}
+
+// namespaceListedInEnvVar returns true when the comma separated namespace list
+// of the environment variable contains the namespace or "*"
+func namespaceListedInEnvVar(envVar, namespace string) bool {
+	for _, allowed := range strings.Split(helper.GetEnvVar(envVar, ""), ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == capabilitiesv1beta1.ProviderAccountAllNamespaces || (allowed != "" && allowed == namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+func providerAccountFromValidCR(cl client.Client, providerAccountCR *capabilitiesv1beta1.ProviderAccount) (*ProviderAccount, error) {
+	if invalidCondition := providerAccountCR.Status.Conditions.GetCondition(capabilitiesv1beta1.ProviderAccountInvalidConditionType); invalidCondition != nil && invalidCondition.IsTrue() {
+		return nil, fmt.Errorf("provider account '%s' is invalid: %s", providerAccountCR.Name, invalidCondition.Message)
+	}
+
+	providerAccount, err := ProviderAccountFromCR(cl, providerAccountCR)
+	if err != nil {
+		return nil, fmt.Errorf("provider account '%s': %w", providerAccountCR.Name, err)
+	}
+
+	return providerAccount, nil
+}
+
+func providerAccountFromSecretReferenceSource(cl client.Client, ns string, providerAccountRef *capabilitiesv1beta1.ProviderAccountReference, logger logr.Logger) (*ProviderAccount, error) {
 	if providerAccountRef != nil {
 		logger.Info("LookupProviderAccount", "ns", ns, "providerAccountRef", providerAccountRef)
 		secretSource := helper.NewSecretSource(cl, ns)
@@ -70,13 +216,24 @@ func providerAccountFromSecretReferenceSource(cl client.Client, ns string, provi
 			return nil, err
 		}
 
-		return &ProviderAccount{AdminURLStr: adminURLStr, Token: token}, nil
+		secret, err := secretSource.CachedSecret(providerAccountRef.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		providerAccount := &ProviderAccount{AdminURLStr: adminURLStr, Token: token}
+		err = ReadProviderAccountTLSConfig(providerAccount, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		return providerAccount, nil
 	}
 
 	return nil, nil
 }
 
-func providerAccountFromDefaultSecretSource(cl client.Client, ns string, providerAccountRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
+func providerAccountFromDefaultSecretSource(cl client.Client, ns string, providerAccountRef *capabilitiesv1beta1.ProviderAccountReference, logger logr.Logger) (*ProviderAccount, error) {
 	// if exists, fiels are required.
 	defaulSecret, err := helper.GetSecret(providerAccountDefaultSecretName, ns, cl)
 	if err == nil {
@@ -89,7 +246,13 @@ func providerAccountFromDefaultSecretSource(cl client.Client, ns string, provide
 			return nil, fmt.Errorf("providerAccountFromDefaultSecretSource: Secret field '%s' is required in secret '%s'", providerAccountSecretTokenFieldName, defaulSecret.Name)
 		}
 
-		return &ProviderAccount{AdminURLStr: *adminURLStr, Token: *token}, nil
+		providerAccount := &ProviderAccount{AdminURLStr: *adminURLStr, Token: *token}
+		err = ReadProviderAccountTLSConfig(providerAccount, defaulSecret)
+		if err != nil {
+			return nil, fmt.Errorf("providerAccountFromDefaultSecretSource: %w", err)
+		}
+
+		return providerAccount, nil
 	} else if err != nil && !apierrors.IsNotFound(err) {
 		return nil, fmt.Errorf("providerAccountFromDefaultSecretSource: %w", err)
 	}
@@ -98,7 +261,7 @@ func providerAccountFromDefaultSecretSource(cl client.Client, ns string, provide
 }
 
 // Lookup default provider account for the 3scale deployment in the current namespace
-func providerAccountFromLocal3scaleSource(cl client.Client, ns string, providerAccountRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
+func providerAccountFromLocal3scaleSource(cl client.Client, ns string, providerAccountRef *capabilitiesv1beta1.ProviderAccountReference, logger logr.Logger) (*ProviderAccount, error) {
 	// Read credentials and tenant url for default provider account of 3scale
 	listOps := []client.ListOption{client.InNamespace(ns)}
 	apimanagerList := &appsv1alpha1.APIManagerList{}
@@ -127,5 +290,40 @@ func providerAccountFromLocal3scaleSource(cl client.Client, ns string, providerA
 	if err != nil {
 		return nil, fmt.Errorf("providerAccountFromLocal3scaleSource: %w", err)
 	}
-	return &ProviderAccount{AdminURLStr: adminURL, Token: accessToken}, nil
+
+	providerAccount := &ProviderAccount{AdminURLStr: adminURL, Token: accessToken}
+
+	// TLS settings are optional
+	tlsSecret, err := helper.GetSecret(providerAccountLocal3scaleTLSSecretName, ns, cl)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("providerAccountFromLocal3scaleSource: %w", err)
+	}
+
+	if err == nil {
+		err = ReadProviderAccountTLSConfig(providerAccount, tlsSecret)
+		if err != nil {
+			return nil, fmt.Errorf("providerAccountFromLocal3scaleSource: %w", err)
+		}
+	}
+
+	return providerAccount, nil
+}
+
+// ReadProviderAccountTLSConfig reads the optional TLS settings of the provider account from the secret,
+// the `caBundle` and `insecureSkipVerify` fields
+func ReadProviderAccountTLSConfig(providerAccount *ProviderAccount, secret *corev1.Secret) error {
+	if caBundle, ok := secret.Data[providerAccountSecretCABundleFieldName]; ok && len(caBundle) > 0 {
+		providerAccount.CABundle = caBundle
+	}
+
+	insecureSkipVerify := helper.GetSecretDataValue(secret.Data, providerAccountSecretInsecureSkipVerifyFieldName)
+	if insecureSkipVerify != nil && *insecureSkipVerify != "" {
+		value, err := strconv.ParseBool(*insecureSkipVerify)
+		if err != nil {
+			return fmt.Errorf("Secret field '%s' in secret '%s' is not a boolean: %w", providerAccountSecretInsecureSkipVerifyFieldName, secret.Name, err)
+		}
+		providerAccount.InsecureSkipVerify = value
+	}
+
+	return nil
 }
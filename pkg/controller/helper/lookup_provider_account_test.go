@@ -2,10 +2,13 @@ package helper
 
 import (
 	"errors"
+	"os"
 	"testing"
 
 	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/common"
 
 	logrtesting "github.com/go-logr/logr/testing"
 	corev1 "k8s.io/api/core/v1"
@@ -26,7 +29,7 @@ func TestLookupProviderAccountSecretReference(t *testing.T) {
 	}
 	providerSecret := GetTestSecret(ns, secretName, data)
 
-	providerAccountRef := &corev1.LocalObjectReference{
+	providerAccountRef := &capabilitiesv1beta1.ProviderAccountReference{
 		Name: secretName,
 	}
 
@@ -98,3 +101,177 @@ func TestLookupProviderAccountNotFoundError(t *testing.T) {
 	_, err := LookupProviderAccount(cl, ns, nil, logrtesting.NullLogger{})
 	equals(t, errors.New("LookupProviderAccount: no provider account found"), err)
 }
+
+func TestLookupProviderAccountSecretReferenceTLSConfig(t *testing.T) {
+	ns := "some_namespace"
+	secretName := "provideraccount"
+
+	data := map[string]string{
+		providerAccountSecretURLFieldName:                "https://example.com",
+		providerAccountSecretTokenFieldName:              "12345",
+		providerAccountSecretCABundleFieldName:           "some CA",
+		providerAccountSecretInsecureSkipVerifyFieldName: "true",
+	}
+	cl := fake.NewFakeClient(GetTestSecret(ns, secretName, data))
+
+	providerAccount, err := LookupProviderAccount(cl, ns, &capabilitiesv1beta1.ProviderAccountReference{Name: secretName}, logrtesting.NullLogger{})
+	ok(t, err)
+	equals(t, []byte("some CA"), providerAccount.CABundle)
+	equals(t, true, providerAccount.InsecureSkipVerify)
+}
+
+func TestLookupProviderAccountInvalidInsecureSkipVerify(t *testing.T) {
+	ns := "some_namespace"
+
+	data := map[string]string{
+		providerAccountSecretURLFieldName:                "https://example.com",
+		providerAccountSecretTokenFieldName:              "12345",
+		providerAccountSecretInsecureSkipVerifyFieldName: "maybe",
+	}
+	cl := fake.NewFakeClient(GetTestSecret(ns, providerAccountDefaultSecretName, data))
+
+	_, err := LookupProviderAccount(cl, ns, nil, logrtesting.NullLogger{})
+	assert(t, err != nil, "error should not be nil")
+}
+
+func TestLookupProviderAccountLocal3scaleTLSConfig(t *testing.T) {
+	ns := "some_namespace"
+	tenantName := "testaccount"
+
+	s := scheme.Scheme
+	err := appsv1alpha1.AddToScheme(s)
+	ok(t, err)
+
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "somename", Namespace: ns},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				WildcardDomain: "example.com",
+				TenantName:     &tenantName,
+			},
+		},
+	}
+
+	seedSecret := GetTestSecret(ns, component.SystemSecretSystemSeedSecretName, map[string]string{
+		component.SystemSecretSystemSeedAdminAccessTokenFieldName: "12345",
+	})
+	tlsSecret := GetTestSecret(ns, providerAccountLocal3scaleTLSSecretName, map[string]string{
+		providerAccountSecretCABundleFieldName: "some CA",
+	})
+
+	cl := fake.NewFakeClient(apimanager, seedSecret, tlsSecret)
+
+	providerAccount, err := LookupProviderAccount(cl, ns, nil, logrtesting.NullLogger{})
+	ok(t, err)
+	equals(t, []byte("some CA"), providerAccount.CABundle)
+	equals(t, false, providerAccount.InsecureSkipVerify)
+}
+
+func TestLookupProviderAccountCustomResource(t *testing.T) {
+	ns := "some_namespace"
+
+	s := scheme.Scheme
+	err := capabilitiesv1beta1.AddToScheme(s)
+	ok(t, err)
+
+	providerAccountCR := &capabilitiesv1beta1.ProviderAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "provideraccount", Namespace: ns},
+		Spec: capabilitiesv1beta1.ProviderAccountSpec{
+			AdminURL:           "https://example.com",
+			TokenSecretRef:     corev1.LocalObjectReference{Name: "token"},
+			CABundleSecretRef:  &corev1.LocalObjectReference{Name: "ca"},
+			InsecureSkipVerify: true,
+		},
+	}
+	tokenSecret := GetTestSecret(ns, "token", map[string]string{ProviderAccountTokenSecretTokenFieldName: "12345"})
+	caSecret := GetTestSecret(ns, "ca", map[string]string{ProviderAccountCABundleSecretCABundleFieldName: "some CA"})
+	// A secret with the same name is ignored
+	legacySecret := GetTestSecret(ns, "provideraccount", map[string]string{
+		providerAccountSecretURLFieldName:   "https://legacy.example.com",
+		providerAccountSecretTokenFieldName: "67890",
+	})
+
+	cl := fake.NewFakeClient(providerAccountCR, tokenSecret, caSecret, legacySecret)
+
+	providerAccount, err := LookupProviderAccount(cl, ns, &capabilitiesv1beta1.ProviderAccountReference{Name: "provideraccount"}, logrtesting.NullLogger{})
+	ok(t, err)
+	equals(t, "https://example.com", providerAccount.AdminURLStr)
+	equals(t, "12345", providerAccount.Token)
+	equals(t, []byte("some CA"), providerAccount.CABundle)
+	equals(t, true, providerAccount.InsecureSkipVerify)
+}
+
+func TestLookupProviderAccountInvalidCustomResource(t *testing.T) {
+	ns := "some_namespace"
+
+	s := scheme.Scheme
+	err := capabilitiesv1beta1.AddToScheme(s)
+	ok(t, err)
+
+	providerAccountCR := &capabilitiesv1beta1.ProviderAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "provideraccount", Namespace: ns},
+		Spec: capabilitiesv1beta1.ProviderAccountSpec{
+			AdminURL:       "https://example.com",
+			TokenSecretRef: corev1.LocalObjectReference{Name: "token"},
+		},
+		Status: capabilitiesv1beta1.ProviderAccountStatus{
+			Conditions: common.Conditions{
+				{Type: capabilitiesv1beta1.ProviderAccountInvalidConditionType, Status: corev1.ConditionTrue, Message: "token rejected"},
+			},
+		},
+	}
+	tokenSecret := GetTestSecret(ns, "token", map[string]string{ProviderAccountTokenSecretTokenFieldName: "12345"})
+
+	cl := fake.NewFakeClient(providerAccountCR, tokenSecret)
+
+	_, err = LookupProviderAccount(cl, ns, &capabilitiesv1beta1.ProviderAccountReference{Name: "provideraccount"}, logrtesting.NullLogger{})
+	equals(t, errors.New("provider account 'provideraccount' is invalid: token rejected"), err)
+}
+
+func TestLookupProviderAccountCrossNamespace(t *testing.T) {
+	s := scheme.Scheme
+	err := capabilitiesv1beta1.AddToScheme(s)
+	ok(t, err)
+
+	providerAccountCR := &capabilitiesv1beta1.ProviderAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "provideraccount", Namespace: "shared"},
+		Spec: capabilitiesv1beta1.ProviderAccountSpec{
+			AdminURL:          "https://example.com",
+			TokenSecretRef:    corev1.LocalObjectReference{Name: "token"},
+			AllowedNamespaces: []string{"allowed"},
+		},
+	}
+	tokenSecret := GetTestSecret("shared", "token", map[string]string{ProviderAccountTokenSecretTokenFieldName: "12345"})
+	cl := fake.NewFakeClient(providerAccountCR, tokenSecret)
+	providerAccountRef := &capabilitiesv1beta1.ProviderAccountReference{Name: "provideraccount", Namespace: "shared"}
+
+	tests := []struct {
+		name                string
+		operatorNamespaces  string
+		namespace           string
+		expectedErrorString string
+	}{
+		{"not enabled", "", "allowed", "provider account references to namespace 'shared' are not allowed by the operator"},
+		{"other namespace enabled", "other, another", "allowed", "provider account references to namespace 'shared' are not allowed by the operator"},
+		{"namespace not allowed", "other, shared", "denied", "provider account 'shared/provideraccount' does not allow references from namespace 'denied'"},
+		{"allowed", "other, shared", "allowed", ""},
+		{"all namespaces enabled", "*", "allowed", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(subT *testing.T) {
+			os.Setenv(CROSS_NAMESPACE_PROVIDER_ACCOUNTS_ENVVAR, tt.operatorNamespaces)
+			defer os.Unsetenv(CROSS_NAMESPACE_PROVIDER_ACCOUNTS_ENVVAR)
+
+			providerAccount, err := LookupProviderAccount(cl, tt.namespace, providerAccountRef, logrtesting.NullLogger{})
+			if tt.expectedErrorString != "" {
+				assert(subT, err != nil, "error should not be nil")
+				equals(subT, tt.expectedErrorString, err.Error())
+				return
+			}
+			ok(subT, err)
+			equals(subT, "https://example.com", providerAccount.AdminURLStr)
+			equals(subT, "12345", providerAccount.Token)
+		})
+	}
+}
@@ -79,7 +79,7 @@ func TestProductList(t *testing.T) {
 			&capabilitiesv1beta1.Product{
 				ObjectMeta: metav1.ObjectMeta{Name: "somename", Namespace: ns},
 				Spec: capabilitiesv1beta1.ProductSpec{
-					ProviderAccountRef: &corev1.LocalObjectReference{
+					ProviderAccountRef: &capabilitiesv1beta1.ProviderAccountReference{
 						Name: anotherProviderSecretName,
 					},
 				},
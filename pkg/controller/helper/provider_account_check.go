@@ -0,0 +1,38 @@
+package helper
+
+import (
+	"fmt"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+)
+
+// ProviderAccountTokenError is returned when the admin portal rejects the provider account token
+// or the token lacks the permissions required by the capability CRs
+type ProviderAccountTokenError struct {
+	Err error
+}
+
+func (e *ProviderAccountTokenError) Error() string {
+	return e.Err.Error()
+}
+
+// IsProviderAccountTokenError returns true when the error is a provider account token error
+func IsProviderAccountTokenError(err error) bool {
+	_, ok := err.(*ProviderAccountTokenError)
+	return ok
+}
+
+// CheckProviderAccount checks the admin portal is reachable and the token has read
+// permission on the Account Management API. Only read requests are sent, the admin API
+// does not expose the token permissions. Missing write permission is reported by the
+// synchronization of the custom resources using the provider account.
+func CheckProviderAccount(threescaleClient *threescaleapi.ThreeScaleClient) error {
+	_, err := threescaleClient.ListProducts()
+	if threescaleapi.IsUnauthorized(err) || threescaleapi.IsForbidden(err) {
+		return &ProviderAccountTokenError{
+			Err: fmt.Errorf("token rejected, Account Management API scope required: %w", err),
+		}
+	}
+
+	return err
+}
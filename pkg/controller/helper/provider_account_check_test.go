@@ -0,0 +1,61 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+)
+
+func TestCheckProviderAccount(t *testing.T) {
+	tests := []struct {
+		name           string
+		listStatusCode int
+		tokenError     bool
+		expectedError  bool
+	}{
+		{"read permission", http.StatusOK, false, false},
+		{"token rejected", http.StatusUnauthorized, true, true},
+		{"no account management scope", http.StatusForbidden, true, true},
+		{"admin portal error", http.StatusInternalServerError, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(subT *testing.T) {
+			httpClient := NewTestClient(func(req *http.Request) *http.Response {
+				// The check must not modify the tenant
+				assert(subT, req.Method == http.MethodGet, "unexpected %s request", req.Method)
+				return &http.Response{
+					StatusCode: tt.listStatusCode,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{"services":[]}`)),
+					Header:     make(http.Header),
+				}
+			})
+			threescaleClient := threescaleapi.NewThreeScale(NewTestAdminPortal(subT), "some token", httpClient)
+
+			err := CheckProviderAccount(threescaleClient)
+			equals(subT, tt.expectedError, err != nil)
+			equals(subT, tt.tokenError, IsProviderAccountTokenError(err))
+		})
+	}
+}
+
+func TestCheckProviderAccountUnreachable(t *testing.T) {
+	httpClient := &http.Client{Transport: roundTripErrorFunc(func(req *http.Request) error {
+		return errors.New("connection refused")
+	})}
+	threescaleClient := threescaleapi.NewThreeScale(NewTestAdminPortal(t), "some token", httpClient)
+
+	err := CheckProviderAccount(threescaleClient)
+	assert(t, err != nil, "error should not be nil")
+	assert(t, !IsProviderAccountTokenError(err), "unreachable admin portal should not be a token error")
+}
+
+type roundTripErrorFunc func(req *http.Request) error
+
+func (f roundTripErrorFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, f(req)
+}
@@ -2,8 +2,12 @@ package helper
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 
 	"github.com/3scale/3scale-operator/pkg/helper"
 
@@ -12,43 +16,150 @@ import (
 
 const (
 	HTTP_VERBOSE_ENVVAR = "THREESCALE_DEBUG"
+
+	// TRUSTED_CA_BUNDLE_FILE_ENVVAR is the path to the PEM encoded CA certificates
+	// trusted by every 3scale API client of the operator
+	TRUSTED_CA_BUNDLE_FILE_ENVVAR = "THREESCALE_TRUSTED_CA_BUNDLE_FILE"
 )
 
 type ProviderAccount struct {
 	AdminURLStr string
 	Token       string
+
+	// CABundle holds PEM encoded CA certificates trusted to verify the admin portal certificate
+	CABundle []byte
+	// InsecureSkipVerify disables the verification of the admin portal certificate
+	InsecureSkipVerify bool
 }
 
 // PortaClient instantiate porta_client.ThreeScaleClient from ProviderAccount object
 func PortaClient(providerAccount *ProviderAccount) (*threescaleapi.ThreeScaleClient, error) {
-	return PortaClientFromURLString(providerAccount.AdminURLStr, providerAccount.Token)
-}
+	adminURL, err := url.Parse(providerAccount.AdminURLStr)
+	if err != nil {
+		return nil, err
+	}
 
-func PortaClientFromURLString(adminURLStr, token string) (*threescaleapi.ThreeScaleClient, error) {
-	adminURL, err := url.Parse(adminURLStr)
+	transport, err := portaTransport(providerAccount)
 	if err != nil {
 		return nil, err
 	}
-	return PortaClientFromURL(adminURL, token)
+
+	return portaClientFromURL(adminURL, providerAccount.Token, transport)
+}
+
+func PortaClientFromURLString(adminURLStr, token string) (*threescaleapi.ThreeScaleClient, error) {
+	return PortaClient(&ProviderAccount{AdminURLStr: adminURLStr, Token: token})
 }
 
 // PortaClientFromURL instantiates porta_client.ThreeScaleClient from admin url object
 func PortaClientFromURL(url *url.URL, token string) (*threescaleapi.ThreeScaleClient, error) {
+	transport, err := portaTransport(&ProviderAccount{})
+	if err != nil {
+		return nil, err
+	}
+
+	return portaClientFromURL(url, token, transport)
+}
+
+// PortaClientWithChangeTracker instantiates porta_client.ThreeScaleClient from ProviderAccount object.
+// The returned ChangeTracker counts the requests of the client modifying 3scale.
+func PortaClientWithChangeTracker(providerAccount *ProviderAccount) (*threescaleapi.ThreeScaleClient, *ChangeTracker, error) {
+	adminURL, err := url.Parse(providerAccount.AdminURLStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transport, err := portaTransport(providerAccount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changeTracker := &ChangeTracker{Transport: transport}
+	threescaleAPIClient, err := portaClientFromURL(adminURL, providerAccount.Token, changeTracker)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return threescaleAPIClient, changeTracker, nil
+}
+
+// PortaClientWithChangePlanner instantiates porta_client.ThreeScaleClient from ProviderAccount object.
+// The requests of the client modifying 3scale are not sent, but recorded by the returned ChangePlanner.
+func PortaClientWithChangePlanner(providerAccount *ProviderAccount) (*threescaleapi.ThreeScaleClient, *ChangePlanner, error) {
+	adminURL, err := url.Parse(providerAccount.AdminURLStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transport, err := portaTransport(providerAccount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changePlanner := &ChangePlanner{Transport: transport}
+	threescaleAPIClient, err := portaClientFromURL(adminURL, providerAccount.Token, changePlanner)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return threescaleAPIClient, changePlanner, nil
+}
+
+func portaClientFromURL(url *url.URL, token string, transport http.RoundTripper) (*threescaleapi.ThreeScaleClient, error) {
 	adminPortal, err := threescaleapi.NewAdminPortal(url.Scheme, url.Hostname(), helper.PortFromURL(url))
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO By default should not skip verification
-	// Activated by some env var or Spec param
+	return threescaleapi.NewThreeScale(adminPortal, token, &http.Client{Transport: transport}), nil
+}
+
+func portaTransport(providerAccount *ProviderAccount) (http.RoundTripper, error) {
+	tlsConfig, err := portaTLSConfig(providerAccount)
+	if err != nil {
+		return nil, err
+	}
+
 	var transport http.RoundTripper = &http.Transport{
 		Proxy:           http.ProxyFromEnvironment,
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		TLSClientConfig: tlsConfig,
 	}
 
 	if helper.GetEnvVar(HTTP_VERBOSE_ENVVAR, "0") == "1" {
 		transport = &helper.Transport{Transport: transport}
 	}
 
-	return threescaleapi.NewThreeScale(adminPortal, token, &http.Client{Transport: transport}), nil
+	return transport, nil
+}
+
+// portaTLSConfig returns the TLS config verifying the admin portal certificate
+// with the system CAs, the operator trusted CAs and the provider account CAs
+func portaTLSConfig(providerAccount *ProviderAccount) (*tls.Config, error) {
+	if providerAccount.InsecureSkipVerify {
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+
+	trustedCABundleFile := helper.GetEnvVar(TRUSTED_CA_BUNDLE_FILE_ENVVAR, "")
+	if trustedCABundleFile != "" {
+		trustedCABundle, err := ioutil.ReadFile(trustedCABundleFile)
+		// The trusted CA configmap is optional
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read trusted CA bundle: %w", err)
+		}
+
+		if len(trustedCABundle) > 0 && !rootCAs.AppendCertsFromPEM(trustedCABundle) {
+			return nil, fmt.Errorf("no valid certificates found in trusted CA bundle %s", trustedCABundleFile)
+		}
+	}
+
+	if len(providerAccount.CABundle) > 0 && !rootCAs.AppendCertsFromPEM(providerAccount.CABundle) {
+		return nil, fmt.Errorf("no valid certificates found in provider account CA bundle")
+	}
+
+	return &tls.Config{RootCAs: rootCAs}, nil
 }
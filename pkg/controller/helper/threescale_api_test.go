@@ -1,8 +1,19 @@
 package helper
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestPortaClientInvalidURL(t *testing.T) {
@@ -32,3 +43,89 @@ func TestPortaClientFromURL(t *testing.T) {
 	_, err := PortaClientFromURL(url, "some token")
 	assert(t, err != nil, "error should not be nil")
 }
+
+func TestPortaClientWithChangeTrackerInvalidURL(t *testing.T) {
+	providerAccount := &ProviderAccount{AdminURLStr: ":foo", Token: "some token"}
+	_, _, err := PortaClientWithChangeTracker(providerAccount)
+	assert(t, err != nil, "error should not be nil")
+}
+
+func TestPortaClientWithChangeTracker(t *testing.T) {
+	providerAccount := &ProviderAccount{AdminURLStr: "http://somedomain.example.com", Token: "some token"}
+	_, changeTracker, err := PortaClientWithChangeTracker(providerAccount)
+	ok(t, err)
+	equals(t, 0, changeTracker.Changes())
+}
+
+func testCABundle(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ok(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	ok(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+}
+
+func TestPortaTLSConfigVerifiesByDefault(t *testing.T) {
+	tlsConfig, err := portaTLSConfig(&ProviderAccount{})
+	ok(t, err)
+	assert(t, !tlsConfig.InsecureSkipVerify, "certificate verification should be enabled")
+	assert(t, tlsConfig.RootCAs != nil, "root CAs should be set")
+}
+
+func TestPortaTLSConfigInsecureSkipVerify(t *testing.T) {
+	tlsConfig, err := portaTLSConfig(&ProviderAccount{InsecureSkipVerify: true})
+	ok(t, err)
+	assert(t, tlsConfig.InsecureSkipVerify, "certificate verification should be disabled")
+}
+
+func TestPortaTLSConfigProviderAccountCABundle(t *testing.T) {
+	caBundle := testCABundle(t)
+	tlsConfig, err := portaTLSConfig(&ProviderAccount{CABundle: caBundle})
+	ok(t, err)
+
+	block, _ := pem.Decode(caBundle)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	ok(t, err)
+	_, err = cert.Verify(x509.VerifyOptions{Roots: tlsConfig.RootCAs})
+	ok(t, err)
+
+	_, err = portaTLSConfig(&ProviderAccount{CABundle: []byte("not a certificate")})
+	assert(t, err != nil, "error should not be nil")
+}
+
+func TestPortaTLSConfigTrustedCABundleFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trusted-ca")
+	ok(t, err)
+	defer os.RemoveAll(dir)
+
+	caBundleFile := filepath.Join(dir, "ca-bundle.crt")
+	caBundle := testCABundle(t)
+	ok(t, ioutil.WriteFile(caBundleFile, caBundle, 0600))
+
+	os.Setenv(TRUSTED_CA_BUNDLE_FILE_ENVVAR, caBundleFile)
+	defer os.Unsetenv(TRUSTED_CA_BUNDLE_FILE_ENVVAR)
+
+	tlsConfig, err := portaTLSConfig(&ProviderAccount{})
+	ok(t, err)
+
+	block, _ := pem.Decode(caBundle)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	ok(t, err)
+	_, err = cert.Verify(x509.VerifyOptions{Roots: tlsConfig.RootCAs})
+	ok(t, err)
+
+	// the trusted CA configmap is optional
+	os.Setenv(TRUSTED_CA_BUNDLE_FILE_ENVVAR, filepath.Join(dir, "missing.crt"))
+	_, err = portaTLSConfig(&ProviderAccount{})
+	ok(t, err)
+}
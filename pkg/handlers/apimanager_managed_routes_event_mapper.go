@@ -6,8 +6,10 @@ import (
 	appscommon "github.com/3scale/3scale-operator/apis/apps"
 	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/common"
 	"github.com/go-logr/logr"
 	appsv1 "github.com/openshift/api/apps/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -65,32 +67,40 @@ func (h *APIManagerRoutesEventMapper) getAPIManagerOwnerReconcileRequest(object
 			return request
 		}
 
-		// If the OwnerReference of the received object is a DeploymentConfig and
-		// its name is Zync Que's name then we fetch that Object and recursively
+		// If the OwnerReference of the received object is a DeploymentConfig or
+		// a Deployment and its name is Zync Que's name then we fetch that Object and recursively
 		// try to find an OwnerReference that is an APIManager. If it is found
 		// we return it.
-		zyncQueKind := "DeploymentConfig"
-		zyncQueGroup := appsv1.GroupVersion.Group
 		// An alternative to hardcode Zync-Que name would be just try to recurse
 		// OwnerReferences until there are no more of them. That would be
 		// potentially more costly.
-		zyncQueDeploymentName := component.ZyncQueDeploymentName
-		if ref.Kind == zyncQueKind && refGV.Group == zyncQueGroup && ref.Name == zyncQueDeploymentName {
-			h.Logger.V(2).Info("OwnerReference to Zync-Que detected. Recursively looking for APIManager OwnerReferences...")
-			existing := &appsv1.DeploymentConfig{}
-			getErr := h.K8sClient.Get(context.Background(), types.NamespacedName{Name: ref.Name, Namespace: object.GetNamespace()}, existing)
-			if getErr != nil {
-				// If there's an error getting the object it might be due to
-				// it might have been deleted already or any other kind of error.
-				// In both cases we log it and ignore it and we continue the processing.
-				h.Logger.Error(err, "Could not get object",
-					"Kind", ref.Kind, "APIVersion", ref.APIVersion, "Name", ref.Name, "Namespace", object.GetNamespace())
-			} else {
-				// Recursively try to find an APIManager OwnerReference
-				request := h.getAPIManagerOwnerReconcileRequest(existing)
-				if request != nil {
-					return request
-				}
+		if ref.Name != component.ZyncQueDeploymentName {
+			continue
+		}
+
+		var existing common.KubernetesObject
+		switch {
+		case ref.Kind == "DeploymentConfig" && refGV.Group == appsv1.GroupVersion.Group:
+			existing = &appsv1.DeploymentConfig{}
+		case ref.Kind == "Deployment" && refGV.Group == k8sappsv1.SchemeGroupVersion.Group:
+			existing = &k8sappsv1.Deployment{}
+		default:
+			continue
+		}
+
+		h.Logger.V(2).Info("OwnerReference to Zync-Que detected. Recursively looking for APIManager OwnerReferences...")
+		getErr := h.K8sClient.Get(context.Background(), types.NamespacedName{Name: ref.Name, Namespace: object.GetNamespace()}, existing)
+		if getErr != nil {
+			// If there's an error getting the object it might be due to
+			// it might have been deleted already or any other kind of error.
+			// In both cases we log it and ignore it and we continue the processing.
+			h.Logger.Error(getErr, "Could not get object",
+				"Kind", ref.Kind, "APIVersion", ref.APIVersion, "Name", ref.Name, "Namespace", object.GetNamespace())
+		} else {
+			// Recursively try to find an APIManager OwnerReference
+			request := h.getAPIManagerOwnerReconcileRequest(existing)
+			if request != nil {
+				return request
 			}
 		}
 	}
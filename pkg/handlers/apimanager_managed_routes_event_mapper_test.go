@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	logrtesting "github.com/go-logr/logr/testing"
+	k8sappsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -54,7 +55,25 @@ func TestAPIManagerRoutesEventMapperMap(t *testing.T) {
 		},
 	}
 
-	objs := []runtime.Object{zyncQue}
+	zyncQueDeployment := &k8sappsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      component.ZyncQueDeploymentName,
+			Namespace: apimanagerNamespace,
+			OwnerReferences: []metav1.OwnerReference{
+				metav1.OwnerReference{
+					APIVersion: appsv1alpha1.GroupVersion.String(),
+					Kind:       appscommon.APIManagerKind,
+					Name:       apimanager.Name,
+				},
+			},
+		},
+	}
+
+	objs := []runtime.Object{zyncQue, zyncQueDeployment}
 
 	s := scheme.Scheme
 	s.AddKnownTypes(appsv1alpha1.GroupVersion, apimanager)
@@ -145,6 +164,32 @@ func TestAPIManagerRoutesEventMapperMap(t *testing.T) {
 				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: apimanagerNamespace, Name: apimanagerName}},
 			},
 		},
+		{
+			testName: "Event with route owned by zync-que deployment managed by APIManager is converted to an APIManager event",
+			input: func() *handler.MapObject {
+				zyncManagedRoute := &routev1.Route{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Route",
+						APIVersion: "route.openshift.io/v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "routeManagedByZyncQueDeployment",
+						Namespace: apimanagerNamespace,
+						OwnerReferences: []metav1.OwnerReference{
+							metav1.OwnerReference{
+								APIVersion: k8sappsv1.SchemeGroupVersion.String(),
+								Kind:       "Deployment",
+								Name:       component.ZyncQueDeploymentName,
+							},
+						},
+					},
+				}
+				return &handler.MapObject{Meta: zyncManagedRoute, Object: zyncManagedRoute}
+			},
+			expected: []reconcile.Request{
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: apimanagerNamespace, Name: apimanagerName}},
+			},
+		},
 		{
 			testName: "Event with route without OwnerReferences is discarded",
 			input: func() *handler.MapObject {
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ handler.Mapper = &APIManagerNamespaceEventMapper{}
+
+// APIManagerNamespaceEventMapper is an EventHandler that maps an object
+// to the APIManagers of its namespace. Used for objects that APIManagers
+// read but do not own, like the Products and Tenants exposed by ingresses.
+type APIManagerNamespaceEventMapper struct {
+	K8sClient client.Client
+	Logger    logr.Logger
+}
+
+func (h *APIManagerNamespaceEventMapper) Map(mapObject handler.MapObject) []reconcile.Request {
+	apimanagerList := &appsv1alpha1.APIManagerList{}
+	err := h.K8sClient.List(context.Background(), apimanagerList, client.InNamespace(mapObject.Meta.GetNamespace()))
+	if err != nil {
+		h.Logger.Error(err, "Could not list APIManagers", "Namespace", mapObject.Meta.GetNamespace())
+		return nil
+	}
+
+	var res []reconcile.Request
+	for idx := range apimanagerList.Items {
+		apimanager := &apimanagerList.Items[idx]
+		h.Logger.V(2).Info("Reenqueuing as APIManager event", "APIManager name", apimanager.Name,
+			"Name", mapObject.Meta.GetName(), "Namespace", mapObject.Meta.GetNamespace())
+		res = append(res, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      apimanager.Name,
+			Namespace: apimanager.Namespace,
+		}})
+	}
+	return res
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	logrtesting "github.com/go-logr/logr/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+)
+
+func TestAPIManagerNamespaceEventMapperMap(t *testing.T) {
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "apimanager", Namespace: "examplenamespace"},
+	}
+	otherAPIManager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "apimanager", Namespace: "othernamespace"},
+	}
+
+	s := runtime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := appsv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	cl := fake.NewFakeClientWithScheme(s, apimanager, otherAPIManager)
+
+	mapper := APIManagerNamespaceEventMapper{
+		K8sClient: cl,
+		Logger:    logrtesting.NullLogger{},
+	}
+
+	cases := []struct {
+		testName  string
+		namespace string
+		expected  []reconcile.Request
+	}{
+		{
+			"Event in the APIManager namespace is converted to an APIManager event", "examplenamespace",
+			[]reconcile.Request{{NamespacedName: types.NamespacedName{Name: "apimanager", Namespace: "examplenamespace"}}},
+		},
+		{"Event in a namespace without APIManager is discarded", "emptynamespace", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			product := &capabilitiesv1beta1.Product{
+				ObjectMeta: metav1.ObjectMeta{Name: "product", Namespace: tc.namespace},
+			}
+			res := mapper.Map(handler.MapObject{Meta: product, Object: product})
+			if !reflect.DeepEqual(res, tc.expected) {
+				subT.Errorf("Unexpected result: %v. Expected: %v", res, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ handler.Mapper = &IndexedFieldEventMapper{}
+
+// IndexedFieldEventMapper is an EventHandler that maps an object to the
+// objects referencing it. The referencing objects are looked up with a field
+// index registered in the manager for the List type.
+type IndexedFieldEventMapper struct {
+	K8sClient client.Client
+	Logger    logr.Logger
+	// List is an empty list of the referencing objects
+	List runtime.Object
+	// IndexField is the name of the field index of the referencing objects
+	IndexField string
+	// IndexValue returns the index value of the watched object
+	IndexValue func(handler.MapObject) string
+}
+
+func (h *IndexedFieldEventMapper) Map(mapObject handler.MapObject) []reconcile.Request {
+	indexValue := h.IndexValue(mapObject)
+	if indexValue == "" {
+		return nil
+	}
+
+	h.Logger.V(2).Info("Processing object", "Name", mapObject.Meta.GetName(), "Namespace", mapObject.Meta.GetNamespace(), "IndexField", h.IndexField, "IndexValue", indexValue)
+
+	list := h.List.DeepCopyObject()
+	err := h.K8sClient.List(context.Background(), list, client.MatchingFields{h.IndexField: indexValue})
+	if err != nil {
+		h.Logger.Error(err, "Could not list referencing objects", "IndexField", h.IndexField, "IndexValue", indexValue)
+		return nil
+	}
+
+	items, err := apimeta.ExtractList(list)
+	if err != nil {
+		h.Logger.Error(err, "Could not extract referencing objects")
+		return nil
+	}
+
+	var res []reconcile.Request
+	for _, item := range items {
+		object, err := apimeta.Accessor(item)
+		if err != nil {
+			h.Logger.Error(err, "Could not access object metadata")
+			continue
+		}
+
+		h.Logger.V(2).Info("Referencing object detected. Reenqueuing", "Name", object.GetName(), "Namespace", object.GetNamespace())
+		res = append(res, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      object.GetName(),
+			Namespace: object.GetNamespace(),
+		}})
+	}
+
+	return res
+}
+
+// NamespacedNameIndexValue returns the namespace/name index value of the object
+func NamespacedNameIndexValue(mapObject handler.MapObject) string {
+	return types.NamespacedName{Name: mapObject.Meta.GetName(), Namespace: mapObject.Meta.GetNamespace()}.String()
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	logrtesting "github.com/go-logr/logr/testing"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testIndexField = "secretRef"
+
+// indexedClient filters the listed objects by field index like the manager cache does.
+// The fake client ignores field selectors
+type indexedClient struct {
+	client.Client
+	indexer client.IndexerFunc
+}
+
+func (c *indexedClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	err := c.Client.List(ctx, list, opts...)
+	if err != nil {
+		return err
+	}
+
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	value, ok := listOpts.FieldSelector.RequiresExactMatch(testIndexField)
+	if !ok {
+		return nil
+	}
+
+	items, err := apimeta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	var filtered []runtime.Object
+	for _, item := range items {
+		for _, itemValue := range c.indexer(item) {
+			if itemValue == value {
+				filtered = append(filtered, item)
+				break
+			}
+		}
+	}
+
+	return apimeta.SetList(list, filtered)
+}
+
+func TestIndexedFieldEventMapperMap(t *testing.T) {
+	referencing := func(name, secretName string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Data:       map[string]string{"secret": secretName},
+		}
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "mysecret", Namespace: "ns"}}
+
+	objs := []runtime.Object{
+		referencing("a", "mysecret"),
+		referencing("b", "other"),
+		referencing("c", "mysecret"),
+	}
+
+	s := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cl := &indexedClient{
+		Client: fake.NewFakeClientWithScheme(s, objs...),
+		indexer: func(obj runtime.Object) []string {
+			configMap := obj.(*corev1.ConfigMap)
+			return []string{types.NamespacedName{Name: configMap.Data["secret"], Namespace: configMap.Namespace}.String()}
+		},
+	}
+
+	mapper := &IndexedFieldEventMapper{
+		K8sClient:  cl,
+		Logger:     logrtesting.NullLogger{},
+		List:       &corev1.ConfigMapList{},
+		IndexField: testIndexField,
+		IndexValue: NamespacedNameIndexValue,
+	}
+
+	requests := mapper.Map(handler.MapObject{Meta: secret, Object: secret})
+
+	expected := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "a", Namespace: "ns"}},
+		{NamespacedName: types.NamespacedName{Name: "c", Namespace: "ns"}},
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("unexpected requests: got %v, expected %v", requests, expected)
+	}
+
+	unreferenced := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unreferenced", Namespace: "ns"}}
+	requests = mapper.Map(handler.MapObject{Meta: unreferenced, Object: unreferenced})
+	if len(requests) != 0 {
+		t.Fatalf("unexpected requests for unreferenced secret: %v", requests)
+	}
+}
@@ -0,0 +1,30 @@
+package helper
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IsDeploymentAvailable returns true when the provided Deployment
+// has the "Available" condition set to true
+func IsDeploymentAvailable(d *appsv1.Deployment) bool {
+	for _, condition := range d.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDeploymentRolledOut returns true when all the replicas of the provided
+// Deployment run the current pod template and are available
+func IsDeploymentRolledOut(d *appsv1.Deployment) bool {
+	var replicas int32 = 1
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.Replicas == replicas &&
+		d.Status.AvailableReplicas == replicas
+}
@@ -0,0 +1,11 @@
+package helper
+
+import (
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+)
+
+// IsIngressReady returns true when the ingress controller has published
+// at least one load balancer endpoint in the Ingress status
+func IsIngressReady(ingress *networkingv1beta1.Ingress) bool {
+	return len(ingress.Status.LoadBalancer.Ingress) > 0
+}
@@ -2,13 +2,31 @@ package helper
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"regexp"
 	"strings"
 	"text/template"
 
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
+)
+
+// 3scale OpenAPI vendor extensions
+const (
+	// OpenAPIMetricsExtension declares product metrics at document level
+	OpenAPIMetricsExtension = "x-3scale-metrics"
+	// OpenAPIPlansExtension declares product application plans at document level
+	OpenAPIPlansExtension = "x-3scale-plans"
+	// OpenAPIMetricExtension sets the metric increased by the operation mapping rule
+	OpenAPIMetricExtension = "x-3scale-metric"
+	// OpenAPIIncrementExtension sets the increment of the operation mapping rule
+	OpenAPIIncrementExtension = "x-3scale-increment"
+	// OpenAPISkipExtension excludes the operation from the import
+	OpenAPISkipExtension = "x-3scale-skip"
 )
 
 var (
@@ -22,6 +40,89 @@ var (
 	NonAlphanumRegexp = regexp.MustCompile(`[^0-9A-Za-z]`)
 )
 
+// LoadOpenAPIDocument parses an OpenAPI 3.0 or a Swagger 2.0 document in
+// json or yaml format. Swagger 2.0 documents are converted to OpenAPI 3.0.
+// When location is not nil, relative references are resolved from it
+func LoadOpenAPIDocument(data []byte, location *url.URL) (*openapi3.T, error) {
+	versionObj := &struct {
+		Swagger string `json:"swagger"`
+	}{}
+	if err := yaml.Unmarshal(data, versionObj); err != nil {
+		return nil, err
+	}
+
+	if versionObj.Swagger != "" {
+		if !strings.HasPrefix(versionObj.Swagger, "2.") {
+			return nil, fmt.Errorf("unsupported swagger version: %s", versionObj.Swagger)
+		}
+
+		openapi3Data, err := convertSwagger2Document(data)
+		if err != nil {
+			return nil, err
+		}
+		data = openapi3Data
+	}
+
+	loader := openapi3.NewLoader()
+	if location != nil {
+		return loader.LoadFromDataWithPath(data, location)
+	}
+
+	return loader.LoadFromData(data)
+}
+
+// convertSwagger2Document converts a Swagger 2.0 document to an OpenAPI 3.0
+// json document
+func convertSwagger2Document(data []byte) ([]byte, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc2 := &openapi2.T{}
+	if err := json.Unmarshal(jsonData, doc2); err != nil {
+		return nil, err
+	}
+
+	doc3, err := openapi2conv.ToV3(doc2)
+	if err != nil {
+		return nil, err
+	}
+
+	// Servers are only generated when the host is set. The base path
+	// alone is a relative server URL
+	if len(doc3.Servers) == 0 && doc2.BasePath != "" {
+		doc3.AddServer(&openapi3.Server{URL: doc2.BasePath})
+	}
+
+	return json.Marshal(doc3)
+}
+
+// OpenAPIExtension decodes the value of the named vendor extension into target.
+// Returns false when the extension is not set
+func OpenAPIExtension(props openapi3.ExtensionProps, name string, target interface{}) (bool, error) {
+	value, ok := props.Extensions[name]
+	if !ok {
+		return false, nil
+	}
+
+	rawValue, ok := value.(json.RawMessage)
+	if !ok {
+		// Extensions set programmatically
+		var err error
+		rawValue, err = json.Marshal(value)
+		if err != nil {
+			return true, err
+		}
+	}
+
+	if err := json.Unmarshal(rawValue, target); err != nil {
+		return true, fmt.Errorf("invalid %s extension: %w", name, err)
+	}
+
+	return true, nil
+}
+
 func SystemNameFromOpenAPITitle(obj *openapi3.T) string {
 	openapiTitle := obj.Info.Title
 	openapiTitleToLower := strings.ToLower(openapiTitle)
@@ -130,6 +231,12 @@ func OpenAPIGlobalSecurityRequirements(openapiObj *openapi3.T) []*ExtendedSecuri
 	return extendedSecRequirements
 }
 
+// OIDCIssuerFromDiscoveryURL returns the issuer of an OpenID Connect
+// discovery URL, i.e. the URL without the well-known path
+func OIDCIssuerFromDiscoveryURL(discoveryURL string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(discoveryURL, "/.well-known/openid-configuration"), "/")
+}
+
 func MethodNameFromOpenAPIOperation(path, opVerb string, op *openapi3.Operation) string {
 	sanitizedPath := NonWordCharRegexp.ReplaceAllString(path, "")
 
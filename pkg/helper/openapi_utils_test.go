@@ -0,0 +1,132 @@
+package helper
+
+import (
+	"context"
+	"testing"
+)
+
+const swagger2YAML = `
+swagger: "2.0"
+info:
+  title: "Petstore"
+  version: "1.0.0"
+host: petstore.example.com
+basePath: /v1
+schemes:
+  - http
+securityDefinitions:
+  api_key:
+    type: apiKey
+    name: api_key
+    in: header
+security:
+  - api_key: []
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          type: string
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: "#/definitions/Pet"
+definitions:
+  Pet:
+    type: object
+    properties:
+      name:
+        type: string
+`
+
+const swagger2JSONWithoutHost = `{
+  "swagger": "2.0",
+  "info": {"title": "Petstore", "version": "1.0.0"},
+  "basePath": "/v2",
+  "paths": {
+    "/pets": {
+      "get": {"responses": {"200": {"description": "OK"}}}
+    }
+  }
+}`
+
+func TestLoadOpenAPIDocumentSwagger2(t *testing.T) {
+	openapiObj, err := LoadOpenAPIDocument([]byte(swagger2YAML), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := openapiObj.Validate(context.TODO()); err != nil {
+		t.Fatalf("converted document is not valid: %v", err)
+	}
+
+	baseURL, err := BaseURLFromOpenAPI(openapiObj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if baseURL != "http://petstore.example.com" {
+		t.Errorf("unexpected base url: %s", baseURL)
+	}
+
+	basePath, err := BasePathFromOpenAPI(openapiObj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if basePath != "/v1" {
+		t.Errorf("unexpected base path: %s", basePath)
+	}
+
+	secRequirements := OpenAPIGlobalSecurityRequirements(openapiObj)
+	if len(secRequirements) != 1 {
+		t.Fatalf("expected 1 security requirement, got %d", len(secRequirements))
+	}
+	if secRequirements[0].Value.Type != "apiKey" || secRequirements[0].Value.Name != "api_key" || secRequirements[0].Value.In != "header" {
+		t.Errorf("unexpected security scheme: %+v", secRequirements[0].Value)
+	}
+
+	pathItem := openapiObj.Paths.Find("/pets/{petId}")
+	if pathItem == nil || pathItem.Get == nil || pathItem.Get.OperationID != "getPet" {
+		t.Fatalf("operation getPet not found")
+	}
+	schemaRef := pathItem.Get.Responses["200"].Value.Content.Get("application/json").Schema
+	if schemaRef.Value == nil || schemaRef.Value.Properties["name"] == nil {
+		t.Errorf("response schema reference not resolved")
+	}
+}
+
+func TestLoadOpenAPIDocumentSwagger2BasePathOnly(t *testing.T) {
+	openapiObj, err := LoadOpenAPIDocument([]byte(swagger2JSONWithoutHost), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	basePath, err := BasePathFromOpenAPI(openapiObj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if basePath != "/v2" {
+		t.Errorf("unexpected base path: %s", basePath)
+	}
+}
+
+func TestLoadOpenAPIDocumentOpenAPI3(t *testing.T) {
+	data := `{"openapi": "3.0.2", "info": {"title": "Petstore", "version": "1.0.0"}, "paths": {}}`
+	openapiObj, err := LoadOpenAPIDocument([]byte(data), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if openapiObj.OpenAPI != "3.0.2" {
+		t.Errorf("unexpected openapi version: %s", openapiObj.OpenAPI)
+	}
+}
+
+func TestLoadOpenAPIDocumentUnsupportedSwaggerVersion(t *testing.T) {
+	data := `{"swagger": "1.2", "info": {"title": "Petstore", "version": "1.0.0"}}`
+	if _, err := LoadOpenAPIDocument([]byte(data), nil); err == nil {
+		t.Error("expected error for swagger 1.2 document")
+	}
+}
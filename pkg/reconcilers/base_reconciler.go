@@ -10,7 +10,10 @@ import (
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/go-logr/logr"
 	grafanav1alpha1 "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
 	consolev1 "github.com/openshift/api/console/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -175,6 +178,24 @@ func (b *BaseReconciler) HasConsoleLink() (bool, error) {
 		consolev1.GroupVersion.String(), "ConsoleLink")
 }
 
+//HasDeploymentConfigs checks if the DeploymentConfig kind is supported in current cluster
+func (b *BaseReconciler) HasDeploymentConfigs() (bool, error) {
+	return resourceExists(b.DiscoveryClient(),
+		appsv1.GroupVersion.String(), "DeploymentConfig")
+}
+
+//HasRoutes checks if the Route kind is supported in current cluster
+func (b *BaseReconciler) HasRoutes() (bool, error) {
+	return resourceExists(b.DiscoveryClient(),
+		routev1.GroupVersion.String(), "Route")
+}
+
+//HasIngresses checks if the networking.k8s.io/v1beta1 Ingress kind is supported in current cluster
+func (b *BaseReconciler) HasIngresses() (bool, error) {
+	return resourceExists(b.DiscoveryClient(),
+		networkingv1beta1.SchemeGroupVersion.String(), "Ingress")
+}
+
 //HasGrafanaDashboards checks if the GrafanaDashboard CRD is supported in current cluster
 func (b *BaseReconciler) HasGrafanaDashboards() (bool, error) {
 	return resourceExists(b.DiscoveryClient(),
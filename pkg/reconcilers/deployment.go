@@ -0,0 +1,146 @@
+package reconcilers
+
+import (
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "github.com/openshift/api/apps/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeploymentMutateFn is a function which mutates the existing Deployment into it's desired state.
+type DeploymentMutateFn func(desired, existing *k8sappsv1.Deployment) bool
+
+func DeploymentMutator(opts ...DeploymentMutateFn) MutateFn {
+	return func(existingObj, desiredObj common.KubernetesObject) (bool, error) {
+		existing, ok := existingObj.(*k8sappsv1.Deployment)
+		if !ok {
+			return false, fmt.Errorf("%T is not a *k8sappsv1.Deployment", existingObj)
+		}
+		desired, ok := desiredObj.(*k8sappsv1.Deployment)
+		if !ok {
+			return false, fmt.Errorf("%T is not a *k8sappsv1.Deployment", desiredObj)
+		}
+
+		update := false
+
+		// Loop through each option
+		for _, opt := range opts {
+			tmpUpdate := opt(desired, existing)
+			update = update || tmpUpdate
+		}
+
+		return update, nil
+	}
+}
+
+// DeploymentFromDeploymentConfigMutator runs a DeploymentConfig mutator
+// against Deployments. DeploymentConfig mutators only read and write
+// object metadata, replicas and the pod template, which are shared by both kinds.
+// Additional Deployment specific mutators can be passed in opts.
+func DeploymentFromDeploymentConfigMutator(dcMutator MutateFn, opts ...DeploymentMutateFn) MutateFn {
+	deploymentMutator := DeploymentMutator(opts...)
+
+	return func(existingObj, desiredObj common.KubernetesObject) (bool, error) {
+		existing, ok := existingObj.(*k8sappsv1.Deployment)
+		if !ok {
+			return false, fmt.Errorf("%T is not a *k8sappsv1.Deployment", existingObj)
+		}
+		desired, ok := desiredObj.(*k8sappsv1.Deployment)
+		if !ok {
+			return false, fmt.Errorf("%T is not a *k8sappsv1.Deployment", desiredObj)
+		}
+
+		existingDC := deploymentConfigView(existing)
+		update, err := dcMutator(existingDC, deploymentConfigView(desired))
+		if err != nil {
+			return false, err
+		}
+
+		if update {
+			existing.ObjectMeta = existingDC.ObjectMeta
+			existing.Spec.Replicas = &existingDC.Spec.Replicas
+			existing.Spec.Template = *existingDC.Spec.Template
+		}
+
+		tmpUpdate, err := deploymentMutator(existing, desired)
+		if err != nil {
+			return false, err
+		}
+
+		return update || tmpUpdate, nil
+	}
+}
+
+// deploymentConfigView returns a DeploymentConfig sharing the pod template of the deployment
+func deploymentConfigView(deployment *k8sappsv1.Deployment) *appsv1.DeploymentConfig {
+	var replicas int32
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	var selector map[string]string
+	if deployment.Spec.Selector != nil {
+		selector = deployment.Spec.Selector.MatchLabels
+	}
+
+	return &appsv1.DeploymentConfig{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: deployment.ObjectMeta,
+		Spec: appsv1.DeploymentConfigSpec{
+			Replicas:        replicas,
+			Selector:        selector,
+			MinReadySeconds: deployment.Spec.MinReadySeconds,
+			Template:        &deployment.Spec.Template,
+		},
+	}
+}
+
+// DeploymentImageMutator ensures containers and init containers run the desired images.
+// DeploymentConfigs get their images from ImageStreams, Deployments reference them directly.
+func DeploymentImageMutator(desired, existing *k8sappsv1.Deployment) bool {
+	desiredName := common.ObjectInfo(desired)
+
+	update := containerImagesMutator(desiredName, "initContainers",
+		desired.Spec.Template.Spec.InitContainers, existing.Spec.Template.Spec.InitContainers)
+	tmpUpdate := containerImagesMutator(desiredName, "containers",
+		desired.Spec.Template.Spec.Containers, existing.Spec.Template.Spec.Containers)
+
+	return update || tmpUpdate
+}
+
+func containerImagesMutator(desiredName, fieldName string, desired, existing []v1.Container) bool {
+	update := false
+
+	for _, desiredContainer := range desired {
+		for idx := range existing {
+			if existing[idx].Name == desiredContainer.Name && existing[idx].Image != desiredContainer.Image {
+				log.Info(fmt.Sprintf("%s spec.template.spec.%s[%s].image has changed: %s -> %s",
+					desiredName, fieldName, desiredContainer.Name, existing[idx].Image, desiredContainer.Image))
+				existing[idx].Image = desiredContainer.Image
+				update = true
+			}
+		}
+	}
+
+	return update
+}
+
+// DeploymentPodTemplateLabelsMutator ensures the pod template has the desired labels.
+// Pod template labels include the release version, DeploymentConfigs get them updated by the upgrade procedure.
+func DeploymentPodTemplateLabelsMutator(desired, existing *k8sappsv1.Deployment) bool {
+	update := false
+
+	diff := cmp.Diff(existing.Spec.Template.Labels, desired.Spec.Template.Labels)
+	helper.MergeMapStringString(&update, &existing.Spec.Template.Labels, desired.Spec.Template.Labels)
+	if update {
+		log.Info(fmt.Sprintf("%s spec.template.metadata.labels have changed: %s", common.ObjectInfo(desired), diff))
+	}
+
+	return update
+}
@@ -0,0 +1,133 @@
+package reconcilers
+
+import (
+	"testing"
+
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func deploymentFactory() *k8sappsv1.Deployment {
+	return &k8sappsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myDeployment",
+			Namespace: "myNS",
+		},
+		Spec: k8sappsv1.DeploymentSpec{
+			Replicas: &[]int32{3}[0],
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"deploymentConfig": "myDeployment"},
+			},
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					InitContainers: []v1.Container{{Name: "init", Image: "init:1"}},
+					Containers:     []v1.Container{{Name: "main", Image: "main:1"}},
+				},
+			},
+		},
+	}
+}
+
+func TestDeploymentFromDeploymentConfigMutator(t *testing.T) {
+	mutator := DeploymentFromDeploymentConfigMutator(
+		DeploymentConfigMutator(DeploymentConfigReplicasMutator, DeploymentConfigTolerationsMutator),
+	)
+
+	cases := []struct {
+		testName       string
+		desired        func() *k8sappsv1.Deployment
+		expectedResult bool
+	}{
+		{"NothingToReconcile", deploymentFactory, false},
+		{"ReplicasReconcile",
+			func() *k8sappsv1.Deployment {
+				desired := deploymentFactory()
+				desired.Spec.Replicas = &[]int32{5}[0]
+				return desired
+			}, true,
+		},
+		{"TolerationsReconcile",
+			func() *k8sappsv1.Deployment {
+				desired := deploymentFactory()
+				desired.Spec.Template.Spec.Tolerations = []v1.Toleration{{Key: "key1", Operator: v1.TolerationOpExists}}
+				return desired
+			}, true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			existing := deploymentFactory()
+			desired := tc.desired()
+			update, err := mutator(existing, desired)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if update != tc.expectedResult {
+				subT.Fatalf("result failed, expected: %t, got: %t", tc.expectedResult, update)
+			}
+			if *existing.Spec.Replicas != *desired.Spec.Replicas {
+				subT.Fatalf("replica reconciliation failed, existing: %d, desired: %d", *existing.Spec.Replicas, *desired.Spec.Replicas)
+			}
+			if len(existing.Spec.Template.Spec.Tolerations) != len(desired.Spec.Template.Spec.Tolerations) {
+				subT.Fatalf("tolerations reconciliation failed, existing: %v, desired: %v",
+					existing.Spec.Template.Spec.Tolerations, desired.Spec.Template.Spec.Tolerations)
+			}
+		})
+	}
+}
+
+func TestDeploymentFromDeploymentConfigMutatorWrongType(t *testing.T) {
+	mutator := DeploymentFromDeploymentConfigMutator(DeploymentConfigMutator())
+	_, err := mutator(&v1.ConfigMap{}, deploymentFactory())
+	if err == nil {
+		t.Fatal("expected error mutating non deployment object")
+	}
+}
+
+func TestDeploymentImageMutator(t *testing.T) {
+	cases := []struct {
+		testName          string
+		desired           func() *k8sappsv1.Deployment
+		expectedResult    bool
+		expectedInitImage string
+		expectedMainImage string
+	}{
+		{"NothingToReconcile", deploymentFactory, false, "init:1", "main:1"},
+		{"InitContainerImageReconcile",
+			func() *k8sappsv1.Deployment {
+				desired := deploymentFactory()
+				desired.Spec.Template.Spec.InitContainers[0].Image = "init:2"
+				return desired
+			}, true, "init:2", "main:1",
+		},
+		{"ContainerImageReconcile",
+			func() *k8sappsv1.Deployment {
+				desired := deploymentFactory()
+				desired.Spec.Template.Spec.Containers[0].Image = "main:2"
+				return desired
+			}, true, "init:1", "main:2",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			existing := deploymentFactory()
+			update := DeploymentImageMutator(tc.desired(), existing)
+			if update != tc.expectedResult {
+				subT.Fatalf("result failed, expected: %t, got: %t", tc.expectedResult, update)
+			}
+			if existing.Spec.Template.Spec.InitContainers[0].Image != tc.expectedInitImage {
+				subT.Fatalf("init container image, expected: %s, got: %s", tc.expectedInitImage, existing.Spec.Template.Spec.InitContainers[0].Image)
+			}
+			if existing.Spec.Template.Spec.Containers[0].Image != tc.expectedMainImage {
+				subT.Fatalf("container image, expected: %s, got: %s", tc.expectedMainImage, existing.Spec.Template.Spec.Containers[0].Image)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package reconcilers
+
+import (
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+func GenericHPAMutator(existingObj, desiredObj common.KubernetesObject) (bool, error) {
+	existing, ok := existingObj.(*autoscalingv2beta2.HorizontalPodAutoscaler)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *autoscalingv2beta2.HorizontalPodAutoscaler", existingObj)
+	}
+	desired, ok := desiredObj.(*autoscalingv2beta2.HorizontalPodAutoscaler)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *autoscalingv2beta2.HorizontalPodAutoscaler", desiredObj)
+	}
+
+	updated := false
+	// Semantic comparison, metric target quantities may be serialized differently
+	if !equality.Semantic.DeepEqual(desired.Spec, existing.Spec) {
+		existing.Spec = desired.Spec
+		updated = true
+	}
+
+	return updated, nil
+}
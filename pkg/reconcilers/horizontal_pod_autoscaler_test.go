@@ -0,0 +1,69 @@
+package reconcilers
+
+import (
+	"testing"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func hpaTestFactory(maxReplicas int32, averageValue string) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	return &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myHPA",
+			Namespace: "someNs",
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "myDeployment",
+			},
+			MinReplicas: &[]int32{1}[0],
+			MaxReplicas: maxReplicas,
+			Metrics: []autoscalingv2beta2.MetricSpec{
+				{
+					Type: autoscalingv2beta2.PodsMetricSourceType,
+					Pods: &autoscalingv2beta2.PodsMetricSource{
+						Metric: autoscalingv2beta2.MetricIdentifier{Name: "requests_per_second"},
+						Target: autoscalingv2beta2.MetricTarget{
+							Type:         autoscalingv2beta2.AverageValueMetricType,
+							AverageValue: &[]resource.Quantity{resource.MustParse(averageValue)}[0],
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGenericHPAMutator(t *testing.T) {
+	existing := hpaTestFactory(3, "1k")
+	desired := hpaTestFactory(5, "1k")
+
+	update, err := GenericHPAMutator(existing, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !update {
+		t.Fatal("when max replicas changed, reconciler reported no update needed")
+	}
+
+	if existing.Spec.MaxReplicas != 5 {
+		t.Fatalf("MaxReplicas not reconciled. Expected: %d, got: %d", 5, existing.Spec.MaxReplicas)
+	}
+}
+
+func TestGenericHPAMutatorEquivalentQuantities(t *testing.T) {
+	existing := hpaTestFactory(3, "1000")
+	desired := hpaTestFactory(3, "1k")
+
+	update, err := GenericHPAMutator(existing, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update {
+		t.Fatal("equivalent metric targets should not be reconciled")
+	}
+}
@@ -0,0 +1,33 @@
+package reconcilers
+
+import (
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+func GenericIngressMutator(existingObj, desiredObj common.KubernetesObject) (bool, error) {
+	existing, ok := existingObj.(*networkingv1beta1.Ingress)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *networkingv1beta1.Ingress", existingObj)
+	}
+	desired, ok := desiredObj.(*networkingv1beta1.Ingress)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *networkingv1beta1.Ingress", desiredObj)
+	}
+
+	// Labels and annotations, like the ingress class or TLS annotations, are merged
+	// keeping the ones added to the existing ingress
+	updated := helper.EnsureObjectMeta(existing, desired)
+
+	if !equality.Semantic.DeepEqual(desired.Spec, existing.Spec) {
+		existing.Spec = desired.Spec
+		updated = true
+	}
+
+	return updated, nil
+}
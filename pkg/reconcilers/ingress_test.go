@@ -0,0 +1,95 @@
+package reconcilers
+
+import (
+	"testing"
+
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func ingressTestFactory(host string) *networkingv1beta1.Ingress {
+	return &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myIngress",
+			Namespace: "someNs",
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: networkingv1beta1.IngressRuleValue{
+						HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+							Paths: []networkingv1beta1.HTTPIngressPath{
+								{
+									Path: "/",
+									Backend: networkingv1beta1.IngressBackend{
+										ServiceName: "mySvc",
+										ServicePort: intstr.FromString("http"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGenericIngressMutator(t *testing.T) {
+	cases := []struct {
+		testName       string
+		desiredHost    string
+		expectedResult bool
+	}{
+		{"NothingToReconcile", "a.example.com", false},
+		{"HostReconcile", "b.example.com", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			existing := ingressTestFactory("a.example.com")
+			update, err := GenericIngressMutator(existing, ingressTestFactory(tc.desiredHost))
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if update != tc.expectedResult {
+				subT.Fatalf("result failed, expected: %t, got: %t", tc.expectedResult, update)
+			}
+			if existing.Spec.Rules[0].Host != tc.desiredHost {
+				subT.Fatalf("host not reconciled, expected: %s, got: %s", tc.desiredHost, existing.Spec.Rules[0].Host)
+			}
+		})
+	}
+}
+
+func TestGenericIngressMutatorObjectMeta(t *testing.T) {
+	existing := ingressTestFactory("a.example.com")
+	existing.Annotations = map[string]string{"user-annotation": "keep"}
+	desired := ingressTestFactory("a.example.com")
+	desired.Labels = map[string]string{"app": "3scale-api-management"}
+	desired.Annotations = map[string]string{"kubernetes.io/ingress.class": "nginx"}
+
+	update, err := GenericIngressMutator(existing, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !update {
+		t.Fatal("labels and annotations changes not detected")
+	}
+	if existing.Labels["app"] != "3scale-api-management" {
+		t.Errorf("labels not reconciled: %v", existing.Labels)
+	}
+	if existing.Annotations["kubernetes.io/ingress.class"] != "nginx" || existing.Annotations["user-annotation"] != "keep" {
+		t.Errorf("annotations not merged: %v", existing.Annotations)
+	}
+
+	update, err = GenericIngressMutator(existing, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update {
+		t.Error("unexpected update of reconciled ingress")
+	}
+}
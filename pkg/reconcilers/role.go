@@ -0,0 +1,29 @@
+package reconcilers
+
+import (
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+func RoleRulesMutator(existingObj, desiredObj common.KubernetesObject) (bool, error) {
+	existing, ok := existingObj.(*rbacv1.Role)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *rbacv1.Role", existingObj)
+	}
+	desired, ok := desiredObj.(*rbacv1.Role)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *rbacv1.Role", desiredObj)
+	}
+
+	updated := false
+	if !equality.Semantic.DeepEqual(desired.Rules, existing.Rules) {
+		existing.Rules = desired.Rules
+		updated = true
+	}
+
+	return updated, nil
+}
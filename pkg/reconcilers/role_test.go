@@ -0,0 +1,58 @@
+package reconcilers
+
+import (
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func roleTestFactory(resources ...string) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myRole",
+			Namespace: "someNs",
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: resources,
+				Verbs:     []string{"get", "list"},
+			},
+		},
+	}
+}
+
+func TestRoleRulesMutator(t *testing.T) {
+	cases := []struct {
+		testName         string
+		desiredResources []string
+		expectedResult   bool
+	}{
+		{"NothingToReconcile", []string{"secrets"}, false},
+		{"RulesReconcile", []string{"secrets", "pods"}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			existing := roleTestFactory("secrets")
+			update, err := RoleRulesMutator(existing, roleTestFactory(tc.desiredResources...))
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if update != tc.expectedResult {
+				subT.Fatalf("result failed, expected: %t, got: %t", tc.expectedResult, update)
+			}
+			if len(existing.Rules[0].Resources) != len(tc.desiredResources) {
+				subT.Fatalf("rules not reconciled, expected: %v, got: %v", tc.desiredResources, existing.Rules[0].Resources)
+			}
+		})
+	}
+}
+
+func TestRoleRulesMutatorWrongType(t *testing.T) {
+	_, err := RoleRulesMutator(ingressTestFactory("a.example.com"), roleTestFactory("secrets"))
+	if err == nil {
+		t.Fatal("expected error when existing object is not a Role")
+	}
+}
@@ -356,11 +356,16 @@ func (b *APIManagerRestore) createAPIManagerSharedSecretContainerArgs() string {
   APIMANAGER_BACKUP_SUBDDIR="${BASEPATH}/apimanager";
   SECRET_TO_SHARE='%s';
   APIMANAGER_BACKUP_FILENAME="%s";
-  oc create secret generic ${SECRET_TO_SHARE} --from-file=${APIMANAGER_BACKUP_SUBDDIR}/${APIMANAGER_BACKUP_FILENAME};
+  DATA_ARTIFACTS_SUBDIR="${BASEPATH}/%s";
+  DATA_ARTIFACTS_LIST="/tmp/%s";
+  ls -1 ${DATA_ARTIFACTS_SUBDIR} 2>/dev/null | grep -v '\.tmp$' > ${DATA_ARTIFACTS_LIST} || true;
+  oc create secret generic ${SECRET_TO_SHARE} --from-file=${APIMANAGER_BACKUP_SUBDDIR}/${APIMANAGER_BACKUP_FILENAME} --from-file=${DATA_ARTIFACTS_LIST};
 `,
 		RestorePVCMountPath,
 		b.SecretToShareName(),
 		backup.APIManagerSerializedBackupFileName,
+		backup.DataArtifactsSubdir,
+		DataArtifactsSecretKey,
 	)
 }
 
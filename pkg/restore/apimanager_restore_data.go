@@ -0,0 +1,228 @@
+package restore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/backup"
+	"github.com/3scale/3scale-operator/pkg/helper"
+)
+
+// DataArtifactsSecretKey is the key of the shared secret listing the
+// data artifacts found in the restore source
+const DataArtifactsSecretKey = "data-artifacts"
+
+type dataArtifactRestore struct {
+	jobPrefix      string
+	deploymentName string
+	// restoreCommand runs in a pod of the deployment and reads the
+	// artifact from the standard input
+	restoreCommand string
+	// managed returns true when the database is deployed by the operator
+	managed func(*appsv1alpha1.APIManager) bool
+	// setStatus records the artifact in the restore status
+	setStatus func(*appsv1alpha1.APIManagerDataArtifacts, *string)
+}
+
+const pgRestoreCommand = `PGPASSWORD="${POSTGRESQL_PASSWORD}" pg_restore -h 127.0.0.1 -U "${POSTGRESQL_USER}" -d "${POSTGRESQL_DATABASE}" --clean --if-exists --no-owner`
+
+// redisRestoreCommand loads the RDB snapshot in a temporary Redis instance
+// and makes the running instance replicate from it. That way the dataset is
+// replaced without restarting the pod and the append only file is rewritten
+// by Redis itself
+const redisRestoreCommand = `
+RESTORE_DIR=/tmp/apimanager-restore;
+rm -rf ${RESTORE_DIR} && mkdir -p ${RESTORE_DIR};
+cat > ${RESTORE_DIR}/dump.rdb;
+redis-server --port 6380 --bind 127.0.0.1 --dir ${RESTORE_DIR} --dbfilename dump.rdb --appendonly no --save "" --daemonize yes --pidfile ${RESTORE_DIR}/redis.pid --logfile ${RESTORE_DIR}/redis.log;
+until [ "$(redis-cli -p 6380 ping)" = "PONG" ]; do sleep 1; done;
+redis-cli slaveof 127.0.0.1 6380;
+until redis-cli info replication | grep -q "master_link_status:up"; do sleep 1; done;
+redis-cli slaveof no one;
+redis-cli -p 6380 shutdown nosave || true;
+rm -rf ${RESTORE_DIR};
+`
+
+var dataArtifactRestores = map[string]dataArtifactRestore{
+	backup.SystemMySQLDataArtifactFileName: {
+		jobPrefix:      "restore-system-db",
+		deploymentName: component.SystemMySQLDeploymentName,
+		restoreCommand: `MYSQL_PWD="${MYSQL_ROOT_PASSWORD}" mysql -h 127.0.0.1 -u root "${MYSQL_DATABASE}"`,
+		managed:        func(a *appsv1alpha1.APIManager) bool { return a.IsSystemMysqlEnabled() },
+		setStatus:      func(s *appsv1alpha1.APIManagerDataArtifacts, p *string) { s.SystemDatabase = p },
+	},
+	backup.SystemPostgreSQLDataArtifactFileName: {
+		jobPrefix:      "restore-system-db",
+		deploymentName: component.SystemPostgreSQLDeploymentName,
+		restoreCommand: pgRestoreCommand,
+		managed:        func(a *appsv1alpha1.APIManager) bool { return a.IsSystemPostgreSQLEnabled() },
+		setStatus:      func(s *appsv1alpha1.APIManagerDataArtifacts, p *string) { s.SystemDatabase = p },
+	},
+	backup.ZyncDatabaseDataArtifactFileName: {
+		jobPrefix:      "restore-zync-db",
+		deploymentName: component.ZyncDatabaseDeploymentName,
+		restoreCommand: pgRestoreCommand,
+		managed:        func(a *appsv1alpha1.APIManager) bool { return !a.IsExternal(appsv1alpha1.ZyncDatabase) },
+		setStatus:      func(s *appsv1alpha1.APIManagerDataArtifacts, p *string) { s.ZyncDatabase = p },
+	},
+	backup.BackendRedisDataArtifactFileName: {
+		jobPrefix:      "restore-backend-redis",
+		deploymentName: component.BackendRedisDeploymentName,
+		restoreCommand: redisRestoreCommand,
+		managed:        func(a *appsv1alpha1.APIManager) bool { return !a.IsExternal(appsv1alpha1.BackendRedis) },
+		setStatus:      func(s *appsv1alpha1.APIManagerDataArtifacts, p *string) { s.BackendRedis = p },
+	},
+	backup.SystemRedisDataArtifactFileName: {
+		jobPrefix:      "restore-system-redis",
+		deploymentName: component.SystemRedisDeploymentName,
+		restoreCommand: redisRestoreCommand,
+		managed:        func(a *appsv1alpha1.APIManager) bool { return !a.IsExternal(appsv1alpha1.SystemRedis) },
+		setStatus:      func(s *appsv1alpha1.APIManagerDataArtifacts, p *string) { s.SystemRedis = p },
+	},
+}
+
+// ParseDataArtifacts parses the data artifacts listing of the shared
+// secret. Unknown entries are ignored
+func ParseDataArtifacts(listing []byte) []string {
+	res := []string{}
+	for _, artifact := range strings.Split(string(listing), "\n") {
+		artifact = strings.TrimSpace(artifact)
+		if _, ok := dataArtifactRestores[artifact]; ok {
+			res = append(res, artifact)
+		}
+	}
+	return res
+}
+
+// IsDataArtifactRestorable returns true when the database the artifact
+// belongs to is deployed by the operator for the given APIManager
+func IsDataArtifactRestorable(apimanager *appsv1alpha1.APIManager, artifact string) bool {
+	restore, ok := dataArtifactRestores[artifact]
+	return ok && restore.managed(apimanager)
+}
+
+// DataArtifactDeploymentName returns the name of the deployment whose
+// data is restored from the artifact
+func DataArtifactDeploymentName(artifact string) string {
+	return dataArtifactRestores[artifact].deploymentName
+}
+
+// RestoredDataArtifacts returns the restore status of the given artifacts
+func RestoredDataArtifacts(artifacts []string) *appsv1alpha1.APIManagerDataArtifacts {
+	if len(artifacts) == 0 {
+		return nil
+	}
+
+	res := &appsv1alpha1.APIManagerDataArtifacts{}
+	for _, artifact := range artifacts {
+		restore, ok := dataArtifactRestores[artifact]
+		if !ok {
+			continue
+		}
+		artifactPath := backup.DataArtifactPath(artifact)
+		restore.setStatus(res, &artifactPath)
+	}
+	return res
+}
+
+// RestoreDataFromPVCJob returns the Job restoring the given data artifact.
+// Nil for unknown artifacts
+func (b *APIManagerRestore) RestoreDataFromPVCJob(artifact string) *batchv1.Job {
+	if b.options.APIManagerRestorePVCOptions == nil {
+		return nil
+	}
+
+	restore, ok := dataArtifactRestores[artifact]
+	if !ok {
+		return nil
+	}
+
+	jobName, err := helper.UIDBasedJobName(restore.jobPrefix, b.options.APIManagerRestoreUID)
+	if err != nil {
+		panic(err)
+	}
+
+	var completions int32 = 1
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: b.options.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			Completions: &completions,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Volumes: []v1.Volume{
+						b.restoreSourcePVCPodVolume(),
+					},
+					Containers: []v1.Container{
+						v1.Container{
+							Name:  restore.jobPrefix,
+							Image: b.options.OCCLIImageURL,
+							Command: []string{
+								"/bin/bash",
+							},
+							Args: []string{
+								"-c",
+								"-e",
+								b.restoreDataContainerArgs(artifact, restore),
+							},
+							VolumeMounts: []v1.VolumeMount{
+								b.restoreSourcePVCContainerVolumeMount(),
+							},
+						},
+					},
+					RestartPolicy:      v1.RestartPolicyNever, // Only "Never" or "OnFailure" are accepted in Kubernetes Jobs
+					ServiceAccountName: ServiceAccountName,
+				},
+			},
+		},
+	}
+}
+
+// DataRestoreJobs returns all the Jobs that might be created to restore
+// data artifacts
+func (b *APIManagerRestore) DataRestoreJobs() []*batchv1.Job {
+	jobs := []*batchv1.Job{}
+	jobNames := map[string]bool{}
+	artifacts := make([]string, 0, len(dataArtifactRestores))
+	for artifact := range dataArtifactRestores {
+		artifacts = append(artifacts, artifact)
+	}
+	sort.Strings(artifacts)
+
+	for _, artifact := range artifacts {
+		job := b.RestoreDataFromPVCJob(artifact)
+		if job == nil || jobNames[job.Name] {
+			continue
+		}
+		jobNames[job.Name] = true
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+func (b *APIManagerRestore) restoreDataContainerArgs(artifact string, restore dataArtifactRestore) string {
+	return fmt.Sprintf(`
+BASEPATH='%s';
+ARTIFACT="${BASEPATH}/%s";
+%s
+oc exec -i ${PODNAME} -- bash -c -e '%s' < ${ARTIFACT};
+`,
+		RestorePVCMountPath,
+		backup.DataArtifactPath(artifact),
+		backup.PodLookupScript(restore.deploymentName),
+		restore.restoreCommand,
+	)
+}
@@ -0,0 +1,93 @@
+package restore
+
+import (
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/backup"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testRestore() *APIManagerRestore {
+	options := NewAPIManagerRestoreOptions()
+	options.Namespace = "restore-unittest"
+	options.APIManagerRestoreName = "example-restore"
+	options.APIManagerRestoreUID = types.UID("0b7f1f0e-7d8d-4f5a-9a8e-3a7c2f9e6b10")
+	options.OCCLIImageURL = "oc-cli"
+	options.APIManagerRestorePVCOptions = &APIManagerRestorePVCOptions{
+		PersistentVolumeClaimVolumeSource: v1.PersistentVolumeClaimVolumeSource{ClaimName: "example-backup"},
+	}
+	return NewAPIManagerRestore(options)
+}
+
+func TestParseDataArtifacts(t *testing.T) {
+	listing := []byte("backend-redis.rdb\nunknown.file\nsystem-mysql.sql\n\n")
+	artifacts := ParseDataArtifacts(listing)
+	if len(artifacts) != 2 || artifacts[0] != backup.BackendRedisDataArtifactFileName || artifacts[1] != backup.SystemMySQLDataArtifactFileName {
+		t.Errorf("unexpected artifacts: %v", artifacts)
+	}
+
+	if len(ParseDataArtifacts(nil)) != 0 {
+		t.Error("empty listing should not have artifacts")
+	}
+}
+
+func TestRestoreDataFromPVCJob(t *testing.T) {
+	r := testRestore()
+
+	if r.RestoreDataFromPVCJob("unknown.file") != nil {
+		t.Error("unknown artifacts should not be restored")
+	}
+
+	args := r.RestoreDataFromPVCJob(backup.SystemPostgreSQLDataArtifactFileName).Spec.Template.Spec.Containers[0].Args[2]
+	if !strings.Contains(args, "pg_restore") || !strings.Contains(args, "deploymentConfig=system-postgresql") ||
+		!strings.Contains(args, "${BASEPATH}/data/system-postgresql.dump") {
+		t.Errorf("unexpected system database restore script: %s", args)
+	}
+
+	args = r.RestoreDataFromPVCJob(backup.SystemRedisDataArtifactFileName).Spec.Template.Spec.Containers[0].Args[2]
+	if !strings.Contains(args, "slaveof 127.0.0.1 6380") || !strings.Contains(args, "deploymentConfig=system-redis") {
+		t.Errorf("unexpected system redis restore script: %s", args)
+	}
+
+	// system database MySQL and PostgreSQL restores share the job name
+	if len(r.DataRestoreJobs()) != 4 {
+		t.Errorf("expected 4 data restore jobs, got %d", len(r.DataRestoreJobs()))
+	}
+}
+
+func TestIsDataArtifactRestorable(t *testing.T) {
+	apimanager := &appsv1alpha1.APIManager{
+		Spec: appsv1alpha1.APIManagerSpec{System: &appsv1alpha1.SystemSpec{}},
+	}
+	if !IsDataArtifactRestorable(apimanager, backup.SystemMySQLDataArtifactFileName) {
+		t.Error("system mysql should be restorable")
+	}
+	if IsDataArtifactRestorable(apimanager, backup.SystemPostgreSQLDataArtifactFileName) {
+		t.Error("system postgresql should not be restorable when mysql is used")
+	}
+
+	apimanager.Spec.ExternalComponents = appsv1alpha1.AllComponentsExternal()
+	if IsDataArtifactRestorable(apimanager, backup.BackendRedisDataArtifactFileName) {
+		t.Error("external backend redis should not be restorable")
+	}
+}
+
+func TestRestoredDataArtifacts(t *testing.T) {
+	if RestoredDataArtifacts(nil) != nil {
+		t.Error("expected nil status without restored artifacts")
+	}
+
+	status := RestoredDataArtifacts([]string{backup.ZyncDatabaseDataArtifactFileName, backup.SystemMySQLDataArtifactFileName})
+	if status.ZyncDatabase == nil || *status.ZyncDatabase != "data/zync-database.dump" {
+		t.Errorf("unexpected zync database status: %v", status.ZyncDatabase)
+	}
+	if status.SystemDatabase == nil || *status.SystemDatabase != "data/system-mysql.sql" {
+		t.Errorf("unexpected system database status: %v", status.SystemDatabase)
+	}
+	if status.BackendRedis != nil || status.SystemRedis != nil {
+		t.Errorf("unexpected redis status: %v", status)
+	}
+}
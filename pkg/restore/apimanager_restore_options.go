@@ -11,6 +11,7 @@ type APIManagerRestoreOptions struct {
 	APIManagerRestoreUID  types.UID `validate:"required"` // UID of the APIManagerRestore CR
 
 	APIManagerRestorePVCOptions *APIManagerRestorePVCOptions `validate:"required"`
+	APIManagerRestoreS3Options  *APIManagerRestoreS3Options  // Optional. Set when the backup data is downloaded from S3. APIManagerRestorePVCOptions then references the work PVC
 	OCCLIImageURL               string                       `validate:"required"`
 }
 
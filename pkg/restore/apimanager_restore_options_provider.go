@@ -5,7 +5,9 @@ import (
 
 	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/backup"
 	"github.com/3scale/3scale-operator/pkg/helper"
+	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -29,7 +31,17 @@ func (a *APIManagerRestoreOptionsProvider) Options() (*APIManagerRestoreOptions,
 
 	res.OCCLIImageURL = a.ocCLIImageURL()
 
-	pvcOptions, err := a.pvcRestoreOptions()
+	source := a.APIManagerRestoreCR.Spec.RestoreSource
+	if source.PersistentVolumeClaim != nil && source.S3 != nil {
+		return nil, fmt.Errorf("Only one restore source can be specified")
+	}
+
+	s3Options, err := a.s3RestoreOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	pvcOptions, err := a.pvcRestoreOptions(s3Options)
 	if err != nil {
 		return nil, err
 	}
@@ -40,11 +52,22 @@ func (a *APIManagerRestoreOptionsProvider) Options() (*APIManagerRestoreOptions,
 	}
 
 	res.APIManagerRestorePVCOptions = pvcOptions
+	res.APIManagerRestoreS3Options = s3Options
 
 	return res, res.Validate()
 }
 
-func (a *APIManagerRestoreOptionsProvider) pvcRestoreOptions() (*APIManagerRestorePVCOptions, error) {
+func (a *APIManagerRestoreOptionsProvider) pvcRestoreOptions(s3Options *APIManagerRestoreS3Options) (*APIManagerRestorePVCOptions, error) {
+	// With S3 sources the backup data is read from the work PVC
+	// it has been downloaded to
+	if s3Options != nil {
+		res := NewAPIManagerRestorePVCOptions()
+		res.PersistentVolumeClaimVolumeSource = v1.PersistentVolumeClaimVolumeSource{
+			ClaimName: s3Options.WorkPVC.Name,
+		}
+		return res, res.Validate()
+	}
+
 	if a.APIManagerRestoreCR.Spec.RestoreSource.PersistentVolumeClaim == nil {
 		return nil, nil
 	}
@@ -55,6 +78,36 @@ func (a *APIManagerRestoreOptionsProvider) pvcRestoreOptions() (*APIManagerResto
 	return res, res.Validate()
 }
 
+func (a *APIManagerRestoreOptionsProvider) s3RestoreOptions() (*APIManagerRestoreS3Options, error) {
+	s3Source := a.APIManagerRestoreCR.Spec.RestoreSource.S3
+	if s3Source == nil {
+		return nil, nil
+	}
+
+	s3Options := backup.NewS3OptionsFromLocation(&s3Source.S3Location)
+	if err := s3Options.Validate(); err != nil {
+		return nil, err
+	}
+
+	res := NewAPIManagerRestoreS3Options()
+	res.S3Options = s3Options
+	res.BackupName = s3Source.BackupName
+	res.WorkPVC.Name = fmt.Sprintf("apimanager-restore-%s", a.APIManagerRestoreCR.Name)
+	defaultWorkPVCSize := backup.DefaultS3WorkVolumeSize.DeepCopy()
+	res.WorkPVC.StorageRequests = &defaultWorkPVCSize
+	if workVolume := s3Source.WorkVolume; workVolume != nil {
+		res.WorkPVC.StorageClass = workVolume.StorageClass
+		res.WorkPVC.VolumeName = workVolume.VolumeName
+		if workVolume.Resources != nil {
+			res.WorkPVC.StorageRequests = &workVolume.Resources.Requests
+		} else if workVolume.VolumeName != nil {
+			res.WorkPVC.StorageRequests = nil
+		}
+	}
+
+	return res, res.Validate()
+}
+
 func (a *APIManagerRestoreOptionsProvider) ocCLIImageURL() string {
 	return helper.GetEnvVar("RELATED_IMAGE_OC_CLI", component.OCCLIImageURL())
 }
@@ -0,0 +1,137 @@
+package restore
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/3scale/3scale-operator/pkg/backup"
+	"github.com/3scale/3scale-operator/pkg/helper"
+)
+
+// RestoreSourceS3ObjectURL returns the URL of the backup data archive to
+// restore. Empty when the restore source is not S3
+func (b *APIManagerRestore) RestoreSourceS3ObjectURL() string {
+	if b.options.APIManagerRestoreS3Options == nil {
+		return ""
+	}
+	s3Options := b.options.APIManagerRestoreS3Options
+	return s3Options.S3Options.ArchiveURL(s3Options.BackupName)
+}
+
+// RestoreWorkPVC returns the PVC the backup data archive downloaded from S3
+// is extracted to. Nil when the restore source is not S3
+func (b *APIManagerRestore) RestoreWorkPVC() *v1.PersistentVolumeClaim {
+	if b.options.APIManagerRestoreS3Options == nil {
+		return nil
+	}
+
+	workPVC := b.options.APIManagerRestoreS3Options.WorkPVC
+	volName := ""
+	if workPVC.VolumeName != nil {
+		volName = *workPVC.VolumeName
+	}
+
+	res := &v1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "PersistentVolumeClaim",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workPVC.Name,
+			Namespace: b.options.Namespace,
+			Annotations: map[string]string{
+				"apiManagerRestoreName": b.options.APIManagerRestoreName,
+				"apiManagerRestoreUID":  string(b.options.APIManagerRestoreUID),
+			},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{
+				v1.ReadWriteOnce,
+			},
+			StorageClassName: workPVC.StorageClass,
+			VolumeName:       volName,
+		},
+	}
+
+	if workPVC.StorageRequests != nil {
+		res.Spec.Resources.Requests = v1.ResourceList{
+			v1.ResourceStorage: *workPVC.StorageRequests,
+		}
+	}
+
+	return res
+}
+
+// DownloadBackupFromS3Job returns the Job downloading the backup data
+// archive from S3 and extracting it in the work PVC. Nil when the restore
+// source is not S3
+func (b *APIManagerRestore) DownloadBackupFromS3Job() *batchv1.Job {
+	if b.options.APIManagerRestoreS3Options == nil {
+		return nil
+	}
+	s3Options := b.options.APIManagerRestoreS3Options.S3Options
+
+	jobName, err := helper.UIDBasedJobName("restore-s3-download", b.options.APIManagerRestoreUID)
+	if err != nil {
+		panic(err)
+	}
+
+	volumes := append([]v1.Volume{b.restoreSourcePVCPodVolume()}, s3Options.PodVolumes()...)
+
+	var completions int32 = 1
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: b.options.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			Completions: &completions,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Volumes: volumes,
+					// The archive is downloaded before the extract
+					// container starts
+					InitContainers: []v1.Container{
+						s3Options.AWSCLIContainer("restore-s3-download", "cp", "--only-show-errors", b.RestoreSourceS3ObjectURL(), backup.ArchiveFilePath()),
+					},
+					Containers: []v1.Container{
+						v1.Container{
+							Name:  "restore-s3-extract",
+							Image: b.options.OCCLIImageURL,
+							Command: []string{
+								"/bin/bash",
+							},
+							Args: []string{
+								"-c",
+								"-e",
+								b.extractBackupContainerArgs(),
+							},
+							VolumeMounts: []v1.VolumeMount{
+								b.restoreSourcePVCContainerVolumeMount(),
+								backup.ArchiveContainerVolumeMount(),
+							},
+						},
+					},
+					RestartPolicy:      v1.RestartPolicyNever, // Only "Never" or "OnFailure" are accepted in Kubernetes Jobs
+					ServiceAccountName: ServiceAccountName,
+				},
+			},
+		},
+	}
+}
+
+func (b *APIManagerRestore) extractBackupContainerArgs() string {
+	return fmt.Sprintf(`
+tar -xzf '%s' -C '%s';
+`,
+		backup.ArchiveFilePath(),
+		RestorePVCMountPath,
+	)
+}
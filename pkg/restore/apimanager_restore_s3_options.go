@@ -0,0 +1,32 @@
+package restore
+
+import (
+	validator "github.com/go-playground/validator/v10"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/3scale/3scale-operator/pkg/backup"
+)
+
+type APIManagerRestoreS3Options struct {
+	S3Options  *backup.S3Options `validate:"required"`
+	BackupName string            `validate:"required"`
+	WorkPVC    RestoreWorkPVC    `validate:"required"`
+}
+
+// RestoreWorkPVC describes the PVC the backup data archive downloaded from
+// S3 is extracted to
+type RestoreWorkPVC struct {
+	Name            string `validate:"required"`
+	StorageClass    *string
+	VolumeName      *string
+	StorageRequests *resource.Quantity
+}
+
+func NewAPIManagerRestoreS3Options() *APIManagerRestoreS3Options {
+	return &APIManagerRestoreS3Options{}
+}
+
+func (a *APIManagerRestoreS3Options) Validate() error {
+	validate := validator.New()
+	return validate.Struct(a)
+}
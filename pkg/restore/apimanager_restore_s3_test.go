@@ -0,0 +1,94 @@
+package restore
+
+import (
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/backup"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testAPIManagerRestoreCR(source appsv1alpha1.APIManagerRestoreSource) *appsv1alpha1.APIManagerRestore {
+	return &appsv1alpha1.APIManagerRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-restore",
+			Namespace: "restore-unittest",
+			UID:       types.UID("0b7f1f0e-7d8d-4f5a-9a8e-3a7c2f9e6b10"),
+		},
+		Spec: appsv1alpha1.APIManagerRestoreSpec{
+			RestoreSource: source,
+		},
+	}
+}
+
+func testS3RestoreSource() *appsv1alpha1.S3RestoreSource {
+	return &appsv1alpha1.S3RestoreSource{
+		S3Location: appsv1alpha1.S3Location{
+			Bucket:               "mybucket",
+			CredentialsSecretRef: v1.LocalObjectReference{Name: "s3-credentials"},
+		},
+		BackupName: "example-backup",
+	}
+}
+
+func TestAPIManagerRestoreS3Source(t *testing.T) {
+	cr := testAPIManagerRestoreCR(appsv1alpha1.APIManagerRestoreSource{S3: testS3RestoreSource()})
+	options, err := NewAPIManagerRestoreOptionsProvider(cr, nil).Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewAPIManagerRestore(options)
+
+	workPVC := r.RestoreWorkPVC()
+	if workPVC == nil {
+		t.Fatal("work PVC expected")
+	}
+	if options.APIManagerRestorePVCOptions.PersistentVolumeClaimVolumeSource.ClaimName != workPVC.Name {
+		t.Errorf("restore jobs do not read from the work PVC: %s", options.APIManagerRestorePVCOptions.PersistentVolumeClaimVolumeSource.ClaimName)
+	}
+	storage := workPVC.Spec.Resources.Requests[v1.ResourceStorage]
+	if storage.Cmp(backup.DefaultS3WorkVolumeSize) != 0 {
+		t.Errorf("unexpected work PVC size: %s", storage.String())
+	}
+
+	if r.RestoreSourceS3ObjectURL() != "s3://mybucket/example-backup.tar.gz" {
+		t.Errorf("unexpected object url: %s", r.RestoreSourceS3ObjectURL())
+	}
+
+	job := r.DownloadBackupFromS3Job()
+	if job == nil {
+		t.Fatal("download job expected")
+	}
+	if len(job.Spec.Template.Spec.InitContainers) != 1 {
+		t.Fatalf("unexpected init containers: %v", job.Spec.Template.Spec.InitContainers)
+	}
+	downloadArgs := strings.Join(job.Spec.Template.Spec.InitContainers[0].Args, " ")
+	if !strings.HasSuffix(downloadArgs, r.RestoreSourceS3ObjectURL()+" "+backup.ArchiveFilePath()) {
+		t.Errorf("unexpected download args: %s", downloadArgs)
+	}
+}
+
+func TestAPIManagerRestorePVCSourceHasNoS3Resources(t *testing.T) {
+	r := testRestore()
+	if r.RestoreWorkPVC() != nil {
+		t.Error("unexpected work PVC")
+	}
+	if r.DownloadBackupFromS3Job() != nil {
+		t.Error("unexpected download job")
+	}
+}
+
+func TestAPIManagerRestoreMultipleSources(t *testing.T) {
+	cr := testAPIManagerRestoreCR(appsv1alpha1.APIManagerRestoreSource{
+		PersistentVolumeClaim: &appsv1alpha1.PersistentVolumeClaimRestoreSource{
+			ClaimSource: v1.PersistentVolumeClaimVolumeSource{ClaimName: "example-backup"},
+		},
+		S3: testS3RestoreSource(),
+	})
+	if _, err := NewAPIManagerRestoreOptionsProvider(cr, nil).Options(); err == nil {
+		t.Error("error expected with multiple restore sources")
+	}
+}
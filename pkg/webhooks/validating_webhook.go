@@ -0,0 +1,95 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/api/admission/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidateFunc returns the validation errors of the admitted object.
+// oldObj is the object before the update, nil on creation
+type ValidateFunc func(ctx context.Context, obj, oldObj runtime.Object) field.ErrorList
+
+var _ admission.Handler = &ValidatingWebhook{}
+
+// ValidatingWebhook is an admission handler rejecting created and updated objects
+// with validation errors. Deletions and updates of objects being deleted are always allowed,
+// so finalizers can be removed whatever the state of the objects they depend on.
+type ValidatingWebhook struct {
+	newObject func() runtime.Object
+	validate  ValidateFunc
+	decoder   *admission.Decoder
+	logger    logr.Logger
+}
+
+// NewValidatingWebhook returns a ValidatingWebhook decoding the admitted objects
+// into the objects returned by newObject
+func NewValidatingWebhook(scheme *runtime.Scheme, newObject func() runtime.Object, validate ValidateFunc, logger logr.Logger) (*ValidatingWebhook, error) {
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ValidatingWebhook{
+		newObject: newObject,
+		validate:  validate,
+		decoder:   decoder,
+		logger:    logger,
+	}, nil
+}
+
+func (w *ValidatingWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != v1beta1.Create && req.Operation != v1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	obj := w.newObject()
+	err := w.decoder.Decode(req, obj)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if objMeta.GetDeletionTimestamp() != nil {
+		return admission.Allowed("")
+	}
+
+	var oldObj runtime.Object
+	if req.Operation == v1beta1.Update {
+		oldObj = w.newObject()
+		err = w.decoder.DecodeRaw(req.OldObject, oldObj)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	w.logger.V(1).Info("Validating object", "kind", req.Kind.Kind, "name", req.Name, "namespace", req.Namespace, "operation", req.Operation)
+
+	fieldErrors := w.validate(ctx, obj, oldObj)
+	if len(fieldErrors) == 0 {
+		return admission.Allowed("")
+	}
+
+	statusErr := apierrors.NewInvalid(schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}, req.Name, fieldErrors)
+	resp := admission.Denied(statusErr.Error())
+	resp.Result = &statusErr.ErrStatus
+	return resp
+}
+
+// ValidatePath returns the path the validating webhook of the kind is served at
+func ValidatePath(gvk schema.GroupVersionKind) string {
+	return fmt.Sprintf("/validate-%s-%s-%s", strings.Replace(gvk.Group, ".", "-", -1), gvk.Version, strings.ToLower(gvk.Kind))
+}
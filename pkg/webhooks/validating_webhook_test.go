@@ -0,0 +1,103 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	logrtesting "github.com/go-logr/logr/testing"
+	"k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func configMapRequest(t *testing.T, operation v1beta1.Operation, data map[string]string) admission.Request {
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Data:       data,
+	}
+	raw, err := json.Marshal(configMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+		Name:      configMap.Name,
+		Namespace: configMap.Namespace,
+		Operation: operation,
+		Object:    runtime.RawExtension{Raw: raw},
+		OldObject: runtime.RawExtension{Raw: raw},
+	}}
+}
+
+func TestValidatingWebhookHandle(t *testing.T) {
+	s := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validate := func(_ context.Context, obj, _ runtime.Object) field.ErrorList {
+		configMap := obj.(*corev1.ConfigMap)
+		if _, ok := configMap.Data["required"]; !ok {
+			return field.ErrorList{field.Required(field.NewPath("data").Key("required"), "required key")}
+		}
+		return nil
+	}
+
+	hook, err := NewValidatingWebhook(s, func() runtime.Object { return &corev1.ConfigMap{} }, validate, logrtesting.NullLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := hook.Handle(context.TODO(), configMapRequest(t, v1beta1.Create, map[string]string{"required": "yes"}))
+	if !resp.Allowed {
+		t.Fatalf("valid object not allowed: %v", resp.Result)
+	}
+
+	resp = hook.Handle(context.TODO(), configMapRequest(t, v1beta1.Update, map[string]string{}))
+	if resp.Allowed {
+		t.Fatal("invalid object allowed")
+	}
+	if resp.Result.Code != http.StatusUnprocessableEntity || resp.Result.Reason != metav1.StatusReasonInvalid {
+		t.Fatalf("unexpected result: %v", resp.Result)
+	}
+	if resp.Result.Details == nil || len(resp.Result.Details.Causes) != 1 || resp.Result.Details.Causes[0].Field != "data[required]" {
+		t.Fatalf("unexpected result details: %v", resp.Result.Details)
+	}
+
+	resp = hook.Handle(context.TODO(), configMapRequest(t, v1beta1.Delete, map[string]string{}))
+	if !resp.Allowed {
+		t.Fatal("delete not allowed")
+	}
+
+	// Objects being deleted are not validated, so finalizers can be removed
+	req := configMapRequest(t, v1beta1.Update, map[string]string{})
+	now := metav1.Now()
+	req.Object.Raw, err = json.Marshal(&corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns", DeletionTimestamp: &now},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = hook.Handle(context.TODO(), req)
+	if !resp.Allowed {
+		t.Fatalf("update of object being deleted not allowed: %v", resp.Result)
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "capabilities.3scale.net", Version: "v1beta1", Kind: "Product"}
+	if path := ValidatePath(gvk); path != "/validate-capabilities-3scale-net-v1beta1-product" {
+		t.Fatalf("unexpected path %s", path)
+	}
+}
@@ -20,6 +20,13 @@ import (
 // Missing fields path omissions
 const (
 	backupDestinationPVCResourceRequestsPath = "/spec/backupDestination/persistentVolumeClaim/resources/requests"
+	backupS3WorkVolumeResourceRequestsPath   = "/spec/backupDestination/s3/workVolume/resources/requests"
+	restoreS3WorkVolumeResourceRequestsPath  = "/spec/restoreSource/s3/workVolume/resources/requests"
+	scheduleBackupPVCResourceRequestsPath    = "/spec/backupTemplate/spec/backupDestination/persistentVolumeClaim/resources/requests"
+	scheduleBackupS3WorkVolumeRequestsPath   = "/spec/backupTemplate/spec/backupDestination/s3/workVolume/resources/requests"
+	lastScheduleTimePath                     = "/status/lastScheduleTime"
+	nextScheduleTimePath                     = "/status/nextScheduleTime"
+	lastSuccessfulTimePath                   = "/status/lastSuccessfulTime"
 	startTimePath                            = "/status/startTime"
 	completionTimePath                       = "/status/completionTime"
 	lastTransitionTimePath                   = "/status/conditions/lastTransitionTime"
@@ -28,6 +35,14 @@ const (
 	systemPostgreSQLPVCResourceRequestsPath  = "/spec/system/database/postgresql/persistentVolumeClaim/resources/requests"
 	productPoliciesConfigurationPath         = "/spec/policies/configuration"
 	policyConfigurationPath                  = "/spec/schema/configuration"
+	apicastProductionHPAMetricsPath          = "/spec/apicast/productionSpec/hpa/metrics"
+	backendListenerHPAMetricsPath            = "/spec/backend/listenerSpec/hpa/metrics"
+	backendWorkerHPAMetricsPath              = "/spec/backend/workerSpec/hpa/metrics"
+	systemAppHPAMetricsPath                  = "/spec/system/appSpec/hpa/metrics"
+	resyncPeriodPath                         = "/spec/resyncPeriod"
+	openapiRefreshIntervalPath               = "/spec/openapiRef/refreshInterval"
+	openapiLastFetchTimePath                 = "/status/openapiRef/lastFetchTime"
+	backendPrivateServicePortPath            = "/spec/privateServiceRef/port"
 )
 
 type testCRInfo struct {
@@ -55,6 +70,10 @@ func TestSampleCustomResources(t *testing.T) {
 			crPrefix:   "apps_v1alpha1_apimanagerrestore.yaml",
 			apiVersion: apps.GroupVersion.Version,
 		},
+		"apps.3scale.net_apimanagerbackupschedules.yaml": testCRInfo{
+			crPrefix:   "apps_v1alpha1_apimanagerbackupschedule.yaml",
+			apiVersion: apps.GroupVersion.Version,
+		},
 		"capabilities.3scale.net_tenants.yaml": testCRInfo{
 			crPrefix:   "capabilities_v1alpha1_tenant",
 			apiVersion: capabilitiesv1alpha1.GroupVersion.Version,
@@ -87,6 +106,10 @@ func TestSampleCustomResources(t *testing.T) {
 			crPrefix:   "capabilities_v1beta1_developeruser",
 			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
 		},
+		"capabilities.3scale.net_applications.yaml": testCRInfo{
+			crPrefix:   "capabilities_v1beta1_application",
+			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
+		},
 	}
 
 	for crd, elem := range crdCrMap {
@@ -136,6 +159,10 @@ func TestCompleteCRD(t *testing.T) {
 			obj:        &apps.APIManagerRestore{},
 			apiVersion: apps.GroupVersion.Version,
 		},
+		"apps.3scale.net_apimanagerbackupschedules.yaml": testCRDInfo{
+			obj:        &apps.APIManagerBackupSchedule{},
+			apiVersion: apps.GroupVersion.Version,
+		},
 		"capabilities.3scale.net_tenants.yaml": testCRDInfo{
 			obj:        &capabilitiesv1alpha1.Tenant{},
 			apiVersion: capabilitiesv1alpha1.GroupVersion.Version,
@@ -168,10 +195,21 @@ func TestCompleteCRD(t *testing.T) {
 			obj:        &capabilitiesv1beta1.DeveloperUser{},
 			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
 		},
+		"capabilities.3scale.net_applications.yaml": testCRDInfo{
+			obj:        &capabilitiesv1beta1.Application{},
+			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
+		},
 	}
 
 	pathOmissions := []string{
 		backupDestinationPVCResourceRequestsPath,
+		backupS3WorkVolumeResourceRequestsPath,
+		restoreS3WorkVolumeResourceRequestsPath,
+		scheduleBackupPVCResourceRequestsPath,
+		scheduleBackupS3WorkVolumeRequestsPath,
+		lastScheduleTimePath,
+		nextScheduleTimePath,
+		lastSuccessfulTimePath,
 		startTimePath,
 		completionTimePath,
 		lastTransitionTimePath,
@@ -180,6 +218,14 @@ func TestCompleteCRD(t *testing.T) {
 		systemPostgreSQLPVCResourceRequestsPath,
 		productPoliciesConfigurationPath,
 		policyConfigurationPath,
+		apicastProductionHPAMetricsPath,
+		backendListenerHPAMetricsPath,
+		backendWorkerHPAMetricsPath,
+		systemAppHPAMetricsPath,
+		resyncPeriodPath,
+		openapiRefreshIntervalPath,
+		openapiLastFetchTimePath,
+		backendPrivateServicePortPath,
 	}
 
 	for crd, elem := range crdStructMap {